@@ -13,6 +13,8 @@ var jwtSecret = []byte(getJWTSecret())
 type Claims struct {
 	UserID   uuid.UUID `json:"user_id"`
 	Username string    `json:"username"`
+	// TokenVersion сверяется с models.User.TokenVersion: отзыв токенов ее увеличивает
+	TokenVersion int `json:"token_version,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -24,12 +26,13 @@ func getJWTSecret() string {
 	return secret
 }
 
-func GenerateToken(userID uuid.UUID, username string) (string, error) {
+func GenerateToken(userID uuid.UUID, username string, tokenVersion int) (string, error) {
 	expirationTime := time.Now().Add(24 * time.Hour)
 
 	claims := &Claims{
-		UserID:   userID,
-		Username: username,
+		UserID:       userID,
+		Username:     username,
+		TokenVersion: tokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,157 @@
+package automation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"task-flow-backend/metrics"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// execute по порядку выполняет действия правила над task, начиная с from,
+// и возвращает их описания для журнала. На первой ошибке выполнение
+// останавливается; уже выполненные действия не откатываются. Действие
+// с задержкой и все следующие откладываются, кроме действия from отложенного
+// запуска (delayed), срок которого уже наступил.
+func (e *Engine) execute(ctx context.Context, rule *models.AutomationRule, event Event, task *models.Task, from int, delayed bool) ([]string, error) {
+	var performed []string
+	for i := from; i < len(rule.Actions); i++ {
+		action := rule.Actions[i]
+		if action.DelayHours > 0 && (i > from || !delayed) {
+			runAt := e.now().Add(time.Duration(action.DelayHours) * time.Hour)
+			delay := &models.AutomationDelay{RuleID: rule.ID, TaskID: task.ID, Event: event.Type, ActionIndex: i, RunAt: runAt}
+			if err := e.repos.AutomationDelays.Schedule(ctx, delay); err != nil {
+				return performed, fmt.Errorf("action %d (%s): %w", i+1, action.Type, err)
+			}
+			return append(performed, fmt.Sprintf("%s delayed until %s", action.Type, runAt.UTC().Format(time.RFC3339))), nil
+		}
+		done, err := e.executeAction(ctx, rule, event, task, action)
+		if err != nil {
+			return performed, fmt.Errorf("action %d (%s): %w", i+1, action.Type, err)
+		}
+		performed = append(performed, done)
+	}
+	return performed, nil
+}
+
+func (e *Engine) executeAction(ctx context.Context, rule *models.AutomationRule, event Event, task *models.Task, action models.AutomationAction) (string, error) {
+	switch action.Type {
+	case models.AutomationActionSetField, models.AutomationActionAssign:
+		field := action.Field
+		if action.Type == models.AutomationActionAssign {
+			field = FieldAssignee
+		}
+		if err := setField(task, field, action.Value, e.now()); err != nil {
+			return "", err
+		}
+		if err := e.repos.Tasks.Update(ctx, task); err != nil {
+			return "", err
+		}
+		e.taskChanged(ctx, "task_updated", task)
+		value, _ := fieldValue(task, field)
+		return fmt.Sprintf("%s=%q", field, value), nil
+
+	case models.AutomationActionMove:
+		if task.Status == action.Value {
+			return "already in " + action.Value, nil
+		}
+		// Колонка, переходы доски и блокировки проверяются так же, как в API
+		if err := e.repos.CheckMoveAllowed(ctx, task, action.Value); err != nil {
+			return "", err
+		}
+		if err := e.repos.Tasks.Move(ctx, task.ID, action.Value); err != nil {
+			return "", err
+		}
+		task.Status = action.Value
+		metrics.TasksMoved.Inc()
+		e.taskChanged(ctx, "task_moved", task)
+		return "moved to " + action.Value, nil
+
+	case models.AutomationActionArchive:
+		if task.ArchivedAt != nil {
+			return "already archived", nil
+		}
+		if err := e.repos.Tasks.Archive(ctx, task.ID); err != nil {
+			return "", err
+		}
+		now := e.now()
+		task.ArchivedAt = &now
+		e.taskChanged(ctx, "task_archived", task)
+		return "archived", nil
+
+	case models.AutomationActionComment:
+		comment := &models.TaskComment{TaskID: task.ID, RuleID: &rule.ID, Body: action.Value}
+		if err := e.repos.Comments.Create(ctx, comment); err != nil {
+			return "", err
+		}
+		if e.notifier != nil {
+			e.notifier.CommentCreated(ctx, task.BoardID, comment)
+		}
+		return "commented", nil
+
+	case models.AutomationActionWebhook:
+		status, err := e.callWebhook(ctx, action.URL, rule, event, task)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("webhook %d", status), nil
+	}
+	return "", fmt.Errorf("%w: unknown action type %q", ErrInvalidRule, action.Type)
+}
+
+func (e *Engine) taskChanged(ctx context.Context, eventType string, task *models.Task) {
+	if e.notifier != nil {
+		e.notifier.TaskChanged(ctx, eventType, task)
+	}
+}
+
+// webhookPayload - тело POST-запроса действия webhook
+type webhookPayload struct {
+	RuleID   uuid.UUID    `json:"rule_id"`
+	RuleName string       `json:"rule_name"`
+	BoardID  uuid.UUID    `json:"board_id"`
+	Event    string       `json:"event"`
+	Task     *models.Task `json:"task"`
+	Previous *models.Task `json:"previous,omitempty"`
+}
+
+// callWebhook отправляет событие POST-запросом; ответ не из 2xx - ошибка
+func (e *Engine) callWebhook(ctx context.Context, url string, rule *models.AutomationRule, event Event, task *models.Task) (int, error) {
+	body, err := json.Marshal(webhookPayload{
+		RuleID:   rule.ID,
+		RuleName: rule.Name,
+		BoardID:  rule.BoardID,
+		Event:    event.Type,
+		Task:     task,
+		Previous: event.Previous,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TaskFlow-Automation")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, errors.New("webhook responded " + resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,288 @@
+// Package automation выполняет правила автоматизации досок: когда с задачей
+// происходит событие (создание, перемещение, изменение поля, приближение
+// срока) и задача подходит под условия правила, выполняются его действия.
+package automation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"task-flow-backend/logging"
+	"task-flow-backend/metrics"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxDepth ограничивает цепочку правил, запускающих друг друга своими действиями
+const MaxDepth = 5
+
+const (
+	defaultQueueSize = 256
+	delayedBatchSize = 100
+	webhookTimeout   = 10 * time.Second
+)
+
+// Event - событие задачи, на которое реагируют правила
+type Event struct {
+	// Type - один из models.AutomationTrigger*
+	Type string
+	Task models.Task
+	// Previous - задача до изменения для task_moved и field_changed
+	Previous *models.Task
+
+	// chain - правила, действия которых породили событие
+	chain []uuid.UUID
+}
+
+// Notifier сообщает клиентам доски об изменениях, сделанных правилами
+type Notifier interface {
+	TaskChanged(ctx context.Context, eventType string, task *models.Task)
+	CommentCreated(ctx context.Context, boardID uuid.UUID, comment *models.TaskComment)
+}
+
+// Engine выполняет правила. События из Dispatch обрабатываются по очереди
+// в Run, поэтому медленные действия (webhook) не задерживают запросы API.
+type Engine struct {
+	repos    repository.Repositories
+	client   *http.Client
+	queue    chan Event
+	notifier Notifier
+	now      func() time.Time
+}
+
+func NewEngine(repos repository.Repositories) *Engine {
+	return &Engine{
+		repos:  repos,
+		client: newWebhookClient(publicAddress),
+		queue:  make(chan Event, defaultQueueSize),
+		now:    time.Now,
+	}
+}
+
+// SetNotifier задает получателя уведомлений; вызывается до Run
+func (e *Engine) SetNotifier(notifier Notifier) {
+	e.notifier = notifier
+}
+
+// Dispatch ставит событие в очередь; при переполненной очереди событие
+// отбрасывается, чтобы не блокировать обработчик запроса
+func (e *Engine) Dispatch(event Event) {
+	select {
+	case e.queue <- event:
+	default:
+		metrics.AutomationEventsDropped.Inc()
+		logging.FromContext(context.Background()).Warn("Automation queue is full, event dropped",
+			"event", event.Type, "task_id", event.Task.ID)
+	}
+}
+
+// Run обрабатывает события из очереди до отмены ctx
+func (e *Engine) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-e.queue:
+			if err := e.Process(ctx, event); err != nil {
+				logging.FromContext(ctx).Error("Automation failed", "event", event.Type, "task_id", event.Task.ID, "error", err)
+			}
+		}
+	}
+}
+
+// Process синхронно выполняет правила доски, подходящие под событие.
+// Ошибки действий пишутся в журнал, возвращаются только ошибки хранилища.
+func (e *Engine) Process(ctx context.Context, event Event) error {
+	rules, err := e.repos.AutomationRules.ListByBoard(ctx, event.Task.BoardID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, rule := range rules {
+		if !rule.Enabled || !triggerMatches(rule.Trigger, event) {
+			continue
+		}
+		if err := e.apply(ctx, &rule, event); err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", rule.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckDueSoon запускает правила due_soon для задач, срок которых наступит
+// в пределах within_hours. Для одного срока задачи правило выполняется один
+// раз; если срок перенесли, правило сработает снова.
+func (e *Engine) CheckDueSoon(ctx context.Context) error {
+	rules, err := e.repos.AutomationRules.ListByTrigger(ctx, models.AutomationTriggerDueSoon)
+	if err != nil {
+		return err
+	}
+
+	now := e.now()
+	tasks := map[uuid.UUID][]models.Task{}
+	var errs []error
+	for _, rule := range rules {
+		boardTasks, ok := tasks[rule.BoardID]
+		if !ok {
+			boardTasks, err = e.repos.Tasks.ListByBoard(ctx, rule.BoardID, repository.ListOptions{})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			tasks[rule.BoardID] = boardTasks
+		}
+
+		within := time.Duration(rule.Trigger.WithinHours) * time.Hour
+		for _, task := range boardTasks {
+			if task.DueAt == nil || !task.DueAt.After(now) || task.DueAt.After(now.Add(within)) {
+				continue
+			}
+			last, err := e.repos.AutomationRuns.LastRun(ctx, rule.ID, task.ID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if last != nil && !last.Before(task.DueAt.Add(-within)) {
+				continue
+			}
+			event := Event{Type: models.AutomationTriggerDueSoon, Task: task}
+			if err := e.apply(ctx, &rule, event); err != nil {
+				errs = append(errs, fmt.Errorf("rule %s: %w", rule.ID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// apply проверяет защиту от зацикливания и условия правила и выполняет его
+// для задачи события
+func (e *Engine) apply(ctx context.Context, rule *models.AutomationRule, event Event) error {
+	// Защита от зацикливания: правило выполняется не больше одного раза
+	// в цепочке, а цепочка не длиннее MaxDepth
+	if slices.Contains(event.chain, rule.ID) {
+		return e.record(ctx, rule, event, models.AutomationRunSkipped, "loop detected: rule already ran in this chain")
+	}
+	if len(event.chain) >= MaxDepth {
+		return e.record(ctx, rule, event, models.AutomationRunSkipped, fmt.Sprintf("chain depth limit %d reached", MaxDepth))
+	}
+
+	task, err := e.repos.Tasks.GetByID(ctx, event.Task.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !conditionsMatch(rule.Conditions, task) {
+		return nil
+	}
+	return e.run(ctx, rule, event, task, 0, false)
+}
+
+// run выполняет действия правила начиная с from, записывает запуск в журнал
+// и обрабатывает события, которые породили действия
+func (e *Engine) run(ctx context.Context, rule *models.AutomationRule, event Event, task *models.Task, from int, delayed bool) error {
+	before := *task
+	performed, actionErr := e.execute(ctx, rule, event, task, from, delayed)
+	status, message := models.AutomationRunSuccess, strings.Join(performed, "; ")
+	if actionErr != nil {
+		status, message = models.AutomationRunFailed, actionErr.Error()
+	}
+	if err := e.record(ctx, rule, event, status, message); err != nil {
+		return err
+	}
+
+	chain := append(slices.Clone(event.chain), rule.ID)
+	var errs []error
+	for _, derived := range Changes(&before, task) {
+		derived.chain = chain
+		if err := e.Process(ctx, derived); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunDelayed выполняет отложенные действия, срок которых наступил. Перед
+// выполнением правило и задача читаются заново: если правило выключили или
+// изменили после откладывания либо задача больше не подходит под триггер
+// и условия, запуск пропускается.
+func (e *Engine) RunDelayed(ctx context.Context) error {
+	delays, err := e.repos.AutomationDelays.ListDue(ctx, e.now(), delayedBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, delay := range delays {
+		if err := e.resume(ctx, delay); err != nil {
+			errs = append(errs, fmt.Errorf("delayed run %s: %w", delay.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Engine) resume(ctx context.Context, delay models.AutomationDelay) error {
+	// Запуск удаляется до выполнения, чтобы действия (например, webhook)
+	// не повторились после сбоя
+	if err := e.repos.AutomationDelays.Delete(ctx, delay.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	rule, err := e.repos.AutomationRules.GetByID(ctx, delay.RuleID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	task, err := e.repos.Tasks.GetByID(ctx, delay.TaskID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	event := Event{Type: delay.Event, Task: *task}
+	switch {
+	case !rule.Enabled:
+		return e.record(ctx, rule, event, models.AutomationRunSkipped, "delayed actions skipped: rule is disabled")
+	case rule.UpdatedAt.After(delay.CreatedAt) || delay.ActionIndex >= len(rule.Actions):
+		return e.record(ctx, rule, event, models.AutomationRunSkipped, "delayed actions skipped: rule changed after they were scheduled")
+	case !stillMatches(rule, task):
+		return e.record(ctx, rule, event, models.AutomationRunSkipped, "delayed actions skipped: task no longer matches the rule")
+	}
+	return e.run(ctx, rule, event, task, delay.ActionIndex, true)
+}
+
+func (e *Engine) record(ctx context.Context, rule *models.AutomationRule, event Event, status, message string) error {
+	taskID := event.Task.ID
+	run := &models.AutomationRun{
+		RuleID:  rule.ID,
+		BoardID: rule.BoardID,
+		TaskID:  &taskID,
+		Event:   event.Type,
+		Status:  status,
+		Message: message,
+	}
+	metrics.AutomationRuns.WithLabelValues(status).Inc()
+	logging.FromContext(ctx).Info("Automation rule ran",
+		"rule_id", rule.ID, "task_id", taskID, "event", event.Type, "status", status, "message", message)
+
+	// Правило удалили, пока оно выполнялось
+	if err := e.repos.AutomationRuns.Create(ctx, run); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,442 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/repository/memory"
+	"task-flow-backend/templates"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (n *recordingNotifier) TaskChanged(ctx context.Context, eventType string, task *models.Task) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, eventType)
+}
+
+func (n *recordingNotifier) CommentCreated(ctx context.Context, boardID uuid.UUID, comment *models.TaskComment) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, "comment_created")
+}
+
+type fixture struct {
+	repos    repository.Repositories
+	engine   *Engine
+	notifier *recordingNotifier
+	board    *models.Board
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	repos := memory.NewRepositories()
+	board := &models.Board{Name: "Ops"}
+	require.NoError(t, repos.CreateBoard(context.Background(), board, templates.Default()))
+
+	engine := NewEngine(repos)
+	notifier := &recordingNotifier{}
+	engine.SetNotifier(notifier)
+	return &fixture{repos: repos, engine: engine, notifier: notifier, board: board}
+}
+
+func (f *fixture) addRule(t *testing.T, rule models.AutomationRule) *models.AutomationRule {
+	t.Helper()
+	rule.BoardID = f.board.ID
+	rule.Enabled = true
+	require.NoError(t, Validate(&rule, []string{"plan", "analysis", "development", "testing", "closed"}))
+	require.NoError(t, f.repos.AutomationRules.Create(context.Background(), &rule))
+	return &rule
+}
+
+func (f *fixture) addTask(t *testing.T, task models.Task) *models.Task {
+	t.Helper()
+	task.BoardID = f.board.ID
+	require.NoError(t, f.repos.Tasks.Create(context.Background(), &task))
+	return &task
+}
+
+// move перемещает задачу так же, как обработчик API, и обрабатывает событие
+func (f *fixture) move(t *testing.T, task *models.Task, status string) *models.Task {
+	t.Helper()
+	ctx := context.Background()
+	previous, err := f.repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	require.NoError(t, f.repos.Tasks.Move(ctx, task.ID, status))
+	moved, err := f.repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+
+	for _, event := range Changes(previous, moved) {
+		require.NoError(t, f.engine.Process(ctx, event))
+	}
+	moved, err = f.repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	return moved
+}
+
+func (f *fixture) runs(t *testing.T) []models.AutomationRun {
+	t.Helper()
+	runs, err := f.repos.AutomationRuns.List(context.Background(), repository.AutomationRunFilter{BoardID: f.board.ID})
+	require.NoError(t, err)
+	return runs
+}
+
+func TestValidate(t *testing.T) {
+	statuses := []string{"plan", "closed"}
+	valid := models.AutomationRule{
+		Name:    "Close",
+		Trigger: models.AutomationTrigger{Type: models.AutomationTriggerTaskCreated},
+		Actions: []models.AutomationAction{{Type: models.AutomationActionMove, Value: "closed"}},
+	}
+	require.NoError(t, Validate(&valid, statuses))
+
+	tests := map[string]func(rule *models.AutomationRule){
+		"no name":           func(r *models.AutomationRule) { r.Name = " " },
+		"unknown trigger":   func(r *models.AutomationRule) { r.Trigger.Type = "task_exploded" },
+		"unknown to status": func(r *models.AutomationRule) { r.Trigger = models.AutomationTrigger{Type: "task_moved", To: "qa"} },
+		"status field": func(r *models.AutomationRule) {
+			r.Trigger = models.AutomationTrigger{Type: "field_changed", Field: "status"}
+		},
+		"due soon window": func(r *models.AutomationRule) { r.Trigger = models.AutomationTrigger{Type: "due_soon"} },
+		"unknown operator": func(r *models.AutomationRule) {
+			r.Conditions = []models.AutomationCondition{{Field: "title", Operator: "like"}}
+		},
+		"unknown field": func(r *models.AutomationRule) {
+			r.Conditions = []models.AutomationCondition{{Field: "color", Operator: "equals"}}
+		},
+		"no actions":      func(r *models.AutomationRule) { r.Actions = nil },
+		"move to unknown": func(r *models.AutomationRule) { r.Actions[0].Value = "qa" },
+		"bad priority": func(r *models.AutomationRule) {
+			r.Actions[0] = models.AutomationAction{Type: "set_field", Field: "priority", Value: "urgent"}
+		},
+		"bad due offset": func(r *models.AutomationRule) {
+			r.Actions[0] = models.AutomationAction{Type: "set_field", Field: "due_at", Value: "+soon"}
+		},
+		"empty comment":    func(r *models.AutomationRule) { r.Actions[0] = models.AutomationAction{Type: "comment"} },
+		"relative webhook": func(r *models.AutomationRule) { r.Actions[0] = models.AutomationAction{Type: "webhook", URL: "/hook"} },
+		"unknown action":   func(r *models.AutomationRule) { r.Actions[0] = models.AutomationAction{Type: "explode"} },
+		"negative delay":   func(r *models.AutomationRule) { r.Actions[0].DelayHours = -1 },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			rule := valid
+			rule.Actions = append([]models.AutomationAction(nil), valid.Actions...)
+			mutate(&rule)
+			assert.ErrorIs(t, Validate(&rule, statuses), ErrInvalidRule)
+		})
+	}
+}
+
+func TestProcess(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	f.addRule(t, models.AutomationRule{
+		Name:    "Assign QA",
+		Trigger: models.AutomationTrigger{Type: models.AutomationTriggerTaskMoved, To: "testing"},
+		Actions: []models.AutomationAction{{Type: models.AutomationActionAssign, Value: "qa"}},
+	})
+	f.addRule(t, models.AutomationRule{
+		Name:       "Urgent bugs",
+		Trigger:    models.AutomationTrigger{Type: models.AutomationTriggerFieldChanged, Field: FieldPriority, To: "high"},
+		Conditions: []models.AutomationCondition{{Field: FieldTitle, Operator: OpContains, Value: "bug"}},
+		Actions: []models.AutomationAction{
+			{Type: models.AutomationActionComment, Value: "Urgent, take it first"},
+			{Type: models.AutomationActionSetField, Field: FieldDueAt, Value: "+24h"},
+		},
+	})
+
+	task := f.addTask(t, models.Task{Title: "Login bug", Status: "development"})
+	task = f.move(t, task, "testing")
+	require.NotNil(t, task.Assignee)
+	assert.Equal(t, "qa", *task.Assignee)
+	assert.Equal(t, []string{"task_updated"}, f.notifier.events)
+
+	t.Run("Conditions", func(t *testing.T) {
+		other := f.addTask(t, models.Task{Title: "Update docs", Status: "plan"})
+		previous := *other
+		high := "high"
+		other.Priority = &high
+		require.NoError(t, f.repos.Tasks.Update(ctx, other))
+		for _, event := range Changes(&previous, other) {
+			require.NoError(t, f.engine.Process(ctx, event))
+		}
+		comments, err := f.repos.Comments.ListByTask(ctx, other.ID)
+		require.NoError(t, err)
+		assert.Empty(t, comments, "Expected rule to skip task not matching conditions")
+	})
+
+	previous := *task
+	high := "high"
+	task.Priority = &high
+	require.NoError(t, f.repos.Tasks.Update(ctx, task))
+	for _, event := range Changes(&previous, task) {
+		require.NoError(t, f.engine.Process(ctx, event))
+	}
+
+	comments, err := f.repos.Comments.ListByTask(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "Urgent, take it first", comments[0].Body)
+	assert.NotNil(t, comments[0].RuleID)
+	stored, err := f.repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.DueAt)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), *stored.DueAt, time.Minute)
+
+	runs := f.runs(t)
+	require.Len(t, runs, 2)
+	assert.Equal(t, models.AutomationRunSuccess, runs[0].Status)
+	assert.Equal(t, models.AutomationTriggerFieldChanged, runs[0].Event)
+	assert.Equal(t, models.AutomationTriggerTaskMoved, runs[1].Event)
+	assert.Equal(t, `assignee="qa"`, runs[1].Message)
+}
+
+func TestLoopProtection(t *testing.T) {
+	f := newFixture(t)
+
+	back := f.addRule(t, models.AutomationRule{
+		Name:    "Back to plan",
+		Trigger: models.AutomationTrigger{Type: models.AutomationTriggerTaskMoved, To: "analysis"},
+		Actions: []models.AutomationAction{{Type: models.AutomationActionMove, Value: "plan"}},
+	})
+	f.addRule(t, models.AutomationRule{
+		Name:    "Forward to analysis",
+		Trigger: models.AutomationTrigger{Type: models.AutomationTriggerTaskMoved, To: "plan"},
+		Actions: []models.AutomationAction{{Type: models.AutomationActionMove, Value: "analysis"}},
+	})
+
+	task := f.addTask(t, models.Task{Title: "Ping-pong", Status: "plan"})
+	task = f.move(t, task, "analysis")
+	assert.Equal(t, "analysis", task.Status, "Expected chain to stop after both rules ran once")
+
+	runs := f.runs(t)
+	require.Len(t, runs, 3)
+	statuses := map[string]int{}
+	for _, run := range runs {
+		statuses[run.Status]++
+		if run.Status == models.AutomationRunSkipped {
+			assert.Equal(t, back.ID, run.RuleID)
+			assert.Contains(t, run.Message, "loop detected")
+		}
+	}
+	assert.Equal(t, map[string]int{models.AutomationRunSuccess: 2, models.AutomationRunSkipped: 1}, statuses)
+}
+
+func TestDelayedActions(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	now := time.Now()
+	f.engine.now = func() time.Time { return now }
+
+	f.addRule(t, models.AutomationRule{
+		Name:    "Archive closed",
+		Trigger: models.AutomationTrigger{Type: models.AutomationTriggerTaskMoved, To: "closed"},
+		Actions: []models.AutomationAction{
+			{Type: models.AutomationActionComment, Value: "Will be archived in a week"},
+			{Type: models.AutomationActionArchive, DelayHours: 7 * 24},
+		},
+	})
+
+	done := f.addTask(t, models.Task{Title: "Shipped", Status: "testing"})
+	done = f.move(t, done, "closed")
+	assert.Nil(t, done.ArchivedAt, "Expected archiving to wait")
+	comments, err := f.repos.Comments.ListByTask(ctx, done.ID)
+	require.NoError(t, err)
+	assert.Len(t, comments, 1, "Expected actions before the delay to run immediately")
+	runs := f.runs(t)
+	require.Len(t, runs, 1)
+	assert.Equal(t, models.AutomationRunSuccess, runs[0].Status)
+	assert.Contains(t, runs[0].Message, "archive delayed until")
+
+	reopened := f.addTask(t, models.Task{Title: "Reopened", Status: "testing"})
+	f.move(t, reopened, "closed")
+	f.move(t, reopened, "development")
+
+	require.NoError(t, f.engine.RunDelayed(ctx))
+	stored, err := f.repos.Tasks.GetByID(ctx, done.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.ArchivedAt, "Expected nothing to run before the delay passes")
+
+	now = now.Add(7*24*time.Hour + time.Minute)
+	require.NoError(t, f.engine.RunDelayed(ctx))
+	stored, err = f.repos.Tasks.GetByID(ctx, done.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.ArchivedAt)
+	comments, err = f.repos.Comments.ListByTask(ctx, done.ID)
+	require.NoError(t, err)
+	assert.Len(t, comments, 1, "Expected actions before the delay not to repeat")
+
+	stored, err = f.repos.Tasks.GetByID(ctx, reopened.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.ArchivedAt, "Expected a task moved out of closed to stay")
+
+	messages := map[uuid.UUID]string{}
+	for _, run := range f.runs(t) {
+		if _, ok := messages[*run.TaskID]; !ok {
+			messages[*run.TaskID] = run.Message
+		}
+	}
+	assert.Equal(t, "archived", messages[done.ID])
+	assert.Equal(t, "delayed actions skipped: task no longer matches the rule", messages[reopened.ID])
+
+	due, err := f.repos.AutomationDelays.ListDue(ctx, now.Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due, "Expected delayed runs to be removed once handled")
+}
+
+func TestWebhook(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	var payload webhookPayload
+	status := http.StatusNoContent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	// Тестовый сервер слушает loopback, запрещенный для webhook
+	f.engine.client = server.Client()
+
+	rule := f.addRule(t, models.AutomationRule{
+		Name:    "Notify",
+		Trigger: models.AutomationTrigger{Type: models.AutomationTriggerTaskCreated},
+		Actions: []models.AutomationAction{
+			{Type: models.AutomationActionWebhook, URL: server.URL},
+			{Type: models.AutomationActionArchive},
+		},
+	})
+
+	task := f.addTask(t, models.Task{Title: "Deploy", Status: "plan"})
+	require.NoError(t, f.engine.Process(ctx, Event{Type: models.AutomationTriggerTaskCreated, Task: *task}))
+	assert.Equal(t, rule.ID, payload.RuleID)
+	assert.Equal(t, models.AutomationTriggerTaskCreated, payload.Event)
+	require.NotNil(t, payload.Task)
+	assert.Equal(t, task.ID, payload.Task.ID)
+	stored, err := f.repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.ArchivedAt)
+
+	status = http.StatusInternalServerError
+	failing := f.addTask(t, models.Task{Title: "Rollback", Status: "plan"})
+	require.NoError(t, f.engine.Process(ctx, Event{Type: models.AutomationTriggerTaskCreated, Task: *failing}))
+	stored, err = f.repos.Tasks.GetByID(ctx, failing.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.ArchivedAt, "Expected actions after a failed webhook to be skipped")
+
+	runs := f.runs(t)
+	require.Len(t, runs, 2)
+	assert.Equal(t, models.AutomationRunFailed, runs[0].Status)
+	assert.Contains(t, runs[0].Message, "500")
+	assert.Equal(t, "webhook 204; archived", runs[1].Message)
+}
+
+func TestWebhookInternalAddress(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+
+	var hits atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer target.Close()
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	hook := f.addRule(t, models.AutomationRule{
+		Name:    "Hook",
+		Trigger: models.AutomationTrigger{Type: models.AutomationTriggerTaskCreated},
+		Actions: []models.AutomationAction{{Type: models.AutomationActionWebhook, URL: target.URL}},
+	})
+	task := f.addTask(t, models.Task{Title: "Probe", Status: "plan"})
+	require.NoError(t, f.engine.Process(ctx, Event{Type: models.AutomationTriggerTaskCreated, Task: *task}))
+	runs := f.runs(t)
+	require.Len(t, runs, 1)
+	assert.Equal(t, models.AutomationRunFailed, runs[0].Status)
+	assert.Contains(t, runs[0].Message, ErrForbiddenAddress.Error())
+
+	// Перенаправление не выполняется, даже если первый адрес разрешен
+	f.engine.client = newWebhookClient(func(netip.Addr) bool { return true })
+	hook.Actions[0].URL = redirect.URL
+	require.NoError(t, f.repos.AutomationRules.Update(ctx, hook))
+	require.NoError(t, f.engine.Process(ctx, Event{Type: models.AutomationTriggerTaskCreated, Task: *task}))
+	runs = f.runs(t)
+	require.Len(t, runs, 2)
+	assert.Equal(t, models.AutomationRunFailed, runs[0].Status)
+	assert.Contains(t, runs[0].Message, "302")
+	assert.Zero(t, hits.Load(), "Expected the internal target never to be called")
+}
+
+func TestPublicAddress(t *testing.T) {
+	for _, addr := range []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1",
+		"0.0.0.0", "255.255.255.255", "224.0.0.1", "::1", "::", "fc00::1", "fe80::1", "::ffff:127.0.0.1",
+	} {
+		assert.False(t, publicAddress(netip.MustParseAddr(addr)), addr)
+	}
+	for _, addr := range []string{"8.8.8.8", "93.184.216.34", "2606:4700:4700::1111"} {
+		assert.True(t, publicAddress(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestCheckDueSoon(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	now := time.Now()
+	f.engine.now = func() time.Time { return now }
+
+	f.addRule(t, models.AutomationRule{
+		Name:    "Remind",
+		Trigger: models.AutomationTrigger{Type: models.AutomationTriggerDueSoon, WithinHours: 24},
+		Actions: []models.AutomationAction{{Type: models.AutomationActionComment, Value: "Due tomorrow"}},
+	})
+
+	soon := now.Add(2 * time.Hour)
+	later := now.Add(72 * time.Hour)
+	task := f.addTask(t, models.Task{Title: "Renew certificate", Status: "plan", DueAt: &soon})
+	f.addTask(t, models.Task{Title: "Quarterly review", Status: "plan", DueAt: &later})
+	f.addTask(t, models.Task{Title: "No due date", Status: "plan"})
+
+	comments := func() int {
+		list, err := f.repos.Comments.ListByTask(ctx, task.ID)
+		require.NoError(t, err)
+		return len(list)
+	}
+
+	require.NoError(t, f.engine.CheckDueSoon(ctx))
+	require.NoError(t, f.engine.CheckDueSoon(ctx))
+	assert.Equal(t, 1, comments(), "Expected one reminder per due date")
+	assert.Len(t, f.runs(t), 1)
+
+	// Срок перенесли на три дня: напоминание придет снова, когда он приблизится
+	moved := now.Add(74 * time.Hour)
+	task.DueAt = &moved
+	require.NoError(t, f.repos.Tasks.Update(ctx, task))
+	require.NoError(t, f.engine.CheckDueSoon(ctx))
+	assert.Equal(t, 1, comments())
+
+	now = now.Add(60 * time.Hour)
+	require.NoError(t, f.engine.CheckDueSoon(ctx))
+	assert.Equal(t, 2, comments())
+}
@@ -0,0 +1,311 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"task-flow-backend/models"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("invalid automation rule")
+
+// Поля задачи, доступные условиям и триггеру field_changed
+const (
+	FieldTitle           = "title"
+	FieldDescription     = "description"
+	FieldStatus          = "status"
+	FieldPriority        = "priority"
+	FieldAssignee        = "assignee"
+	FieldEstimateMinutes = "estimate_minutes"
+	FieldDueAt           = "due_at"
+)
+
+// Операторы условий
+const (
+	OpEquals     = "equals"
+	OpNotEquals  = "not_equals"
+	OpContains   = "contains"
+	OpIsEmpty    = "is_empty"
+	OpIsNotEmpty = "is_not_empty"
+)
+
+// settableFields - поля, которые меняет set_field; статус меняет move
+var settableFields = []string{FieldTitle, FieldDescription, FieldPriority, FieldAssignee, FieldEstimateMinutes, FieldDueAt}
+
+// MaxDelayHours ограничивает задержку действия годом
+const MaxDelayHours = 365 * 24
+
+var priorities = []string{"low", "medium", "high"}
+
+// Validate проверяет правило доски со статусами statuses (status_id колонок)
+func Validate(rule *models.AutomationRule, statuses []string) error {
+	if strings.TrimSpace(rule.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRule)
+	}
+	if err := validateTrigger(rule.Trigger, statuses); err != nil {
+		return err
+	}
+	for i, condition := range rule.Conditions {
+		if err := validateCondition(condition); err != nil {
+			return fmt.Errorf("condition %d: %w", i+1, err)
+		}
+	}
+	if len(rule.Actions) == 0 {
+		return fmt.Errorf("%w: at least one action is required", ErrInvalidRule)
+	}
+	for i, action := range rule.Actions {
+		if err := validateAction(action, statuses); err != nil {
+			return fmt.Errorf("action %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func validateTrigger(trigger models.AutomationTrigger, statuses []string) error {
+	switch trigger.Type {
+	case models.AutomationTriggerTaskCreated:
+	case models.AutomationTriggerTaskMoved:
+		for _, status := range []string{trigger.From, trigger.To} {
+			if status != "" && !slices.Contains(statuses, status) {
+				return fmt.Errorf("%w: trigger status %q does not match any column", ErrInvalidRule, status)
+			}
+		}
+	case models.AutomationTriggerFieldChanged:
+		if !slices.Contains(settableFields, trigger.Field) {
+			return fmt.Errorf("%w: trigger field must be one of %s", ErrInvalidRule, strings.Join(settableFields, ", "))
+		}
+	case models.AutomationTriggerDueSoon:
+		if trigger.WithinHours <= 0 {
+			return fmt.Errorf("%w: within_hours must be positive", ErrInvalidRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown trigger type %q", ErrInvalidRule, trigger.Type)
+	}
+	return nil
+}
+
+func validateCondition(condition models.AutomationCondition) error {
+	if _, ok := fieldValue(&models.Task{}, condition.Field); !ok {
+		return fmt.Errorf("%w: unknown field %q", ErrInvalidRule, condition.Field)
+	}
+	switch condition.Operator {
+	case OpEquals, OpNotEquals, OpContains, OpIsEmpty, OpIsNotEmpty:
+		return nil
+	}
+	return fmt.Errorf("%w: unknown operator %q", ErrInvalidRule, condition.Operator)
+}
+
+func validateAction(action models.AutomationAction, statuses []string) error {
+	if action.DelayHours < 0 || action.DelayHours > MaxDelayHours {
+		return fmt.Errorf("%w: delay_hours must be between 0 and %d", ErrInvalidRule, MaxDelayHours)
+	}
+	switch action.Type {
+	case models.AutomationActionSetField:
+		if !slices.Contains(settableFields, action.Field) {
+			return fmt.Errorf("%w: field must be one of %s", ErrInvalidRule, strings.Join(settableFields, ", "))
+		}
+		return setField(&models.Task{}, action.Field, action.Value, time.Now())
+	case models.AutomationActionMove:
+		if !slices.Contains(statuses, action.Value) {
+			return fmt.Errorf("%w: status %q does not match any column", ErrInvalidRule, action.Value)
+		}
+	case models.AutomationActionAssign:
+	case models.AutomationActionComment:
+		if strings.TrimSpace(action.Value) == "" {
+			return fmt.Errorf("%w: comment text is required", ErrInvalidRule)
+		}
+	case models.AutomationActionArchive:
+	case models.AutomationActionWebhook:
+		u, err := url.Parse(action.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: webhook url must be an absolute http(s) URL", ErrInvalidRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown action type %q", ErrInvalidRule, action.Type)
+	}
+	return nil
+}
+
+// triggerMatches проверяет, запускает ли событие триггер правила
+func triggerMatches(trigger models.AutomationTrigger, event Event) bool {
+	if trigger.Type != event.Type {
+		return false
+	}
+	switch trigger.Type {
+	case models.AutomationTriggerTaskMoved:
+		if trigger.From != "" && (event.Previous == nil || event.Previous.Status != trigger.From) {
+			return false
+		}
+		return trigger.To == "" || event.Task.Status == trigger.To
+	case models.AutomationTriggerFieldChanged:
+		if event.Previous == nil {
+			return false
+		}
+		before, _ := fieldValue(event.Previous, trigger.Field)
+		after, _ := fieldValue(&event.Task, trigger.Field)
+		return before != after && (trigger.To == "" || after == trigger.To)
+	}
+	return true
+}
+
+// stillMatches проверяет перед отложенными действиями, что задача по-прежнему
+// подходит под правило: условия и новое значение (to) триггера task_moved
+// и field_changed
+func stillMatches(rule *models.AutomationRule, task *models.Task) bool {
+	if rule.Trigger.To != "" {
+		switch rule.Trigger.Type {
+		case models.AutomationTriggerTaskMoved:
+			if task.Status != rule.Trigger.To {
+				return false
+			}
+		case models.AutomationTriggerFieldChanged:
+			if value, _ := fieldValue(task, rule.Trigger.Field); value != rule.Trigger.To {
+				return false
+			}
+		}
+	}
+	return conditionsMatch(rule.Conditions, task)
+}
+
+// conditionsMatch возвращает true, если задача подходит под все условия
+func conditionsMatch(conditions []models.AutomationCondition, task *models.Task) bool {
+	for _, condition := range conditions {
+		value, _ := fieldValue(task, condition.Field)
+		var ok bool
+		switch condition.Operator {
+		case OpEquals:
+			ok = value == condition.Value
+		case OpNotEquals:
+			ok = value != condition.Value
+		case OpContains:
+			ok = strings.Contains(strings.ToLower(value), strings.ToLower(condition.Value))
+		case OpIsEmpty:
+			ok = value == ""
+		case OpIsNotEmpty:
+			ok = value != ""
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldValue возвращает поле задачи строкой; пустое поле - пустая строка
+func fieldValue(task *models.Task, field string) (string, bool) {
+	switch field {
+	case FieldTitle:
+		return task.Title, true
+	case FieldDescription:
+		return task.Description, true
+	case FieldStatus:
+		return task.Status, true
+	case FieldPriority:
+		return deref(task.Priority), true
+	case FieldAssignee:
+		return deref(task.Assignee), true
+	case FieldEstimateMinutes:
+		if task.EstimateMinutes == nil {
+			return "", true
+		}
+		return strconv.Itoa(*task.EstimateMinutes), true
+	case FieldDueAt:
+		if task.DueAt == nil {
+			return "", true
+		}
+		return task.DueAt.UTC().Format(time.RFC3339), true
+	}
+	return "", false
+}
+
+// setField меняет поле задачи. Пустое значение очищает поле (кроме title);
+// due_at принимает RFC3339 или смещение от now вида +48h.
+func setField(task *models.Task, field, value string, now time.Time) error {
+	switch field {
+	case FieldTitle:
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("%w: title must not be empty", ErrInvalidRule)
+		}
+		task.Title = value
+	case FieldDescription:
+		task.Description = value
+	case FieldPriority:
+		if value != "" && !slices.Contains(priorities, value) {
+			return fmt.Errorf("%w: priority must be one of %s", ErrInvalidRule, strings.Join(priorities, ", "))
+		}
+		task.Priority = optional(value)
+	case FieldAssignee:
+		task.Assignee = optional(value)
+	case FieldEstimateMinutes:
+		if value == "" {
+			task.EstimateMinutes = nil
+			return nil
+		}
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes <= 0 {
+			return fmt.Errorf("%w: estimate_minutes must be a positive integer", ErrInvalidRule)
+		}
+		task.EstimateMinutes = &minutes
+	case FieldDueAt:
+		if value == "" {
+			task.DueAt = nil
+			return nil
+		}
+		due, err := parseDue(value, now)
+		if err != nil {
+			return err
+		}
+		task.DueAt = &due
+	default:
+		return fmt.Errorf("%w: field %q cannot be set", ErrInvalidRule, field)
+	}
+	return nil
+}
+
+func parseDue(value string, now time.Time) (time.Time, error) {
+	if offset, ok := strings.CutPrefix(value, "+"); ok {
+		d, err := time.ParseDuration(offset)
+		if err == nil && d > 0 {
+			return now.Add(d), nil
+		}
+	} else if due, err := time.Parse(time.RFC3339, value); err == nil {
+		return due, nil
+	}
+	return time.Time{}, fmt.Errorf("%w: due_at must be RFC3339 or an offset like +48h", ErrInvalidRule)
+}
+
+// Changes возвращает события изменения задачи: task_moved, если сменился
+// статус, и field_changed, если изменились другие поля
+func Changes(previous, task *models.Task) []Event {
+	var events []Event
+	if previous.Status != task.Status {
+		events = append(events, Event{Type: models.AutomationTriggerTaskMoved, Task: *task, Previous: previous})
+	}
+	for _, field := range settableFields {
+		before, _ := fieldValue(previous, field)
+		after, _ := fieldValue(task, field)
+		if before != after {
+			events = append(events, Event{Type: models.AutomationTriggerFieldChanged, Task: *task, Previous: previous})
+			break
+		}
+	}
+	return events
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
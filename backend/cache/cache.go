@@ -5,13 +5,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"task-flow-backend/metrics"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
 var Client *redis.Client
-var ctx = context.Background()
+
+// Store кэширует списки задач. Обработчики работают с ним через интерфейс,
+// чтобы без Redis можно было подставить NopStore.
+type Store interface {
+	GetTasksByBoardID(ctx context.Context, boardID string) ([]byte, error)
+	SetTasksByBoardID(ctx context.Context, boardID string, tasks interface{}, expiration time.Duration) error
+	GetAllTasks(ctx context.Context) ([]byte, error)
+	SetAllTasks(ctx context.Context, tasks interface{}, expiration time.Duration) error
+	InvalidateBoardTasks(ctx context.Context, boardID string) error
+	InvalidateAllTasks(ctx context.Context) error
+}
 
 func Init() error {
 	host := os.Getenv("REDIS_HOST")
@@ -30,7 +41,7 @@ func Init() error {
 		DB:       0,
 	})
 
-	_, err := Client.Ping(ctx).Result()
+	_, err := Client.Ping(context.Background()).Result()
 	if err != nil {
 		return fmt.Errorf("failed to connect to Redis: %w", err)
 	}
@@ -38,68 +49,103 @@ func Init() error {
 	return nil
 }
 
-func GetTasksByBoardID(boardID string) ([]byte, error) {
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) GetTasksByBoardID(ctx context.Context, boardID string) ([]byte, error) {
 	key := fmt.Sprintf("tasks:board:%s", boardID)
-	val, err := Client.Get(ctx, key).Result()
-	if err == redis.Nil {
-		return nil, nil
-	} else if err != nil {
-		return nil, err
-	}
+	return s.get(ctx, key, "tasks_board")
+}
 
-	return []byte(val), nil
+func (s *RedisStore) SetTasksByBoardID(ctx context.Context, boardID string, tasks interface{}, expiration time.Duration) error {
+	key := fmt.Sprintf("tasks:board:%s", boardID)
+	return s.set(ctx, key, tasks, expiration)
+}
+
+func (s *RedisStore) GetAllTasks(ctx context.Context) ([]byte, error) {
+	return s.get(ctx, "tasks:all", "tasks_all")
+}
+
+func (s *RedisStore) SetAllTasks(ctx context.Context, tasks interface{}, expiration time.Duration) error {
+	return s.set(ctx, "tasks:all", tasks, expiration)
 }
 
-func SetTasksByBoardID(boardID string, tasks interface{}, expiration time.Duration) error {
+func (s *RedisStore) InvalidateBoardTasks(ctx context.Context, boardID string) error {
 	key := fmt.Sprintf("tasks:board:%s", boardID)
-	data, err := json.Marshal(tasks)
+	if err := s.client.Del(ctx, key).Err(); err != nil {
+		metrics.CacheErrors.WithLabelValues("invalidate").Inc()
+		return err
+	}
+	return nil
+}
+
+func (s *RedisStore) InvalidateAllTasks(ctx context.Context) error {
+	if err := s.client.Del(ctx, "tasks:all").Err(); err != nil {
+		metrics.CacheErrors.WithLabelValues("invalidate").Inc()
+		return err
+	}
+
+	keys, err := s.client.Keys(ctx, "tasks:board:*").Result()
 	if err != nil {
+		metrics.CacheErrors.WithLabelValues("invalidate").Inc()
 		return err
 	}
 
-	return Client.Set(ctx, key, data, expiration).Err()
+	if len(keys) > 0 {
+		if err := s.client.Del(ctx, keys...).Err(); err != nil {
+			metrics.CacheErrors.WithLabelValues("invalidate").Inc()
+			return err
+		}
+	}
+
+	return nil
 }
 
-func GetAllTasks() ([]byte, error) {
-	key := "tasks:all"
-	val, err := Client.Get(ctx, key).Result()
+func (s *RedisStore) get(ctx context.Context, key, metricKey string) ([]byte, error) {
+	val, err := s.client.Get(ctx, key).Result()
 	if err == redis.Nil {
+		metrics.CacheMisses.WithLabelValues(metricKey).Inc()
 		return nil, nil
 	} else if err != nil {
+		metrics.CacheErrors.WithLabelValues("get").Inc()
 		return nil, err
 	}
 
+	metrics.CacheHits.WithLabelValues(metricKey).Inc()
 	return []byte(val), nil
 }
 
-func SetAllTasks(tasks interface{}, expiration time.Duration) error {
-	key := "tasks:all"
-	data, err := json.Marshal(tasks)
+func (s *RedisStore) set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
 
-	return Client.Set(ctx, key, data, expiration).Err()
-}
-
-func InvalidateBoardTasks(boardID string) error {
-	key := fmt.Sprintf("tasks:board:%s", boardID)
-	return Client.Del(ctx, key).Err()
-}
-
-func InvalidateAllTasks() error {
-	if err := Client.Del(ctx, "tasks:all").Err(); err != nil {
+	if err := s.client.Set(ctx, key, data, expiration).Err(); err != nil {
+		metrics.CacheErrors.WithLabelValues("set").Inc()
 		return err
 	}
+	return nil
+}
 
-	keys, err := Client.Keys(ctx, "tasks:board:*").Result()
-	if err != nil {
-		return err
-	}
+// NopStore ничего не кэширует; используется, когда Redis недоступен, и в тестах
+type NopStore struct{}
 
-	if len(keys) > 0 {
-		return Client.Del(ctx, keys...).Err()
-	}
+func (NopStore) GetTasksByBoardID(context.Context, string) ([]byte, error) { return nil, nil }
 
+func (NopStore) SetTasksByBoardID(context.Context, string, interface{}, time.Duration) error {
 	return nil
 }
+
+func (NopStore) GetAllTasks(context.Context) ([]byte, error) { return nil, nil }
+
+func (NopStore) SetAllTasks(context.Context, interface{}, time.Duration) error { return nil }
+
+func (NopStore) InvalidateBoardTasks(context.Context, string) error { return nil }
+
+func (NopStore) InvalidateAllTasks(context.Context) error { return nil }
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func newBoardCmd(a *admin) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "board",
+		Short: "Manage boards",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "transfer-ownership BOARD_ID USERNAME",
+		Short: "Make a user the owner of a board; previous owners stay members",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			boardID, err := uuid.Parse(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid board ID %q", args[0])
+			}
+			user, err := a.user(cmd.Context(), args[1])
+			if err != nil {
+				return err
+			}
+			err = a.repos.TransferBoardOwnership(cmd.Context(), boardID, user.ID)
+			if errors.Is(err, repository.ErrNotFound) {
+				return fmt.Errorf("board %s not found", boardID)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Board %s is now owned by %s\n", boardID, user.Username)
+			return nil
+		},
+	})
+	return cmd
+}
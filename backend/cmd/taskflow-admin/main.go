@@ -0,0 +1,84 @@
+// Команда taskflow-admin управляет пользователями и досками напрямую через
+// базу данных: создание учетных записей, блокировка, сброс паролей, отзыв
+// токенов и заполнение базы для разработки
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/repository/postgres"
+
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// admin хранит репозитории, общие для команд. В тестах они задаются заранее,
+// иначе открывается соединение с Postgres из переменных DB_*
+type admin struct {
+	repos repository.Repositories
+	close func()
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := newRootCmd(&admin{}).ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCmd(a *admin) *cobra.Command {
+	root := &cobra.Command{
+		Use:           "taskflow-admin",
+		Short:         "Administer Task Flow users and boards",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return a.open()
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			if a.close != nil {
+				a.close()
+			}
+		},
+	}
+	root.AddCommand(
+		newUserCmd(a),
+		newBoardCmd(a),
+		newTokenCmd(a),
+		newSeedCmd(a),
+	)
+	return root
+}
+
+func (a *admin) open() error {
+	if a.repos.Users != nil {
+		return nil
+	}
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("load .env: %w", err)
+	}
+	if err := database.Init(); err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	a.repos = postgres.NewRepositories(database.DB)
+	a.close = func() { database.DB.Close() }
+	return nil
+}
+
+// user находит пользователя по имени с понятной ошибкой
+func (a *admin) user(ctx context.Context, username string) (*models.User, error) {
+	user, err := a.repos.Users.GetByUsername(ctx, username)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("user %q not found", username)
+	}
+	return user, err
+}
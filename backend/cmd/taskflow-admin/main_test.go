@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/repository/memory"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runAdmin(repos repository.Repositories, stdin string, args ...string) (string, error) {
+	cmd := newRootCmd(&admin{repos: repos})
+	var out bytes.Buffer
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func TestUserCommands(t *testing.T) {
+	repos := memory.NewRepositories()
+	ctx := context.Background()
+	run := func(stdin string, args ...string) string {
+		t.Helper()
+		out, err := runAdmin(repos, stdin, args...)
+		require.NoError(t, err)
+		return out
+	}
+	get := func(username string) *models.User {
+		t.Helper()
+		user, err := repos.Users.GetByUsername(ctx, username)
+		require.NoError(t, err)
+		return user
+	}
+
+	run("first-secret\n", "user", "create", "alice", "--email", "alice@test.com", "--admin", "--password-stdin")
+	run("second-secret\n", "user", "create", "bob", "--email", "bob@test.com", "--password-stdin")
+	alice := get("alice")
+	assert.Equal(t, models.UserRoleAdmin, alice.Role)
+	assert.True(t, repository.VerifyPassword(alice.PasswordHash, "first-secret"))
+
+	_, err := runAdmin(repos, "another-secret\n", "user", "create", "alice", "--email", "other@test.com", "--password-stdin")
+	assert.ErrorContains(t, err, "already exists")
+	_, err = runAdmin(repos, "short\n", "user", "create", "carol", "--email", "carol@test.com", "--password-stdin")
+	assert.ErrorContains(t, err, "at least 6 characters")
+	_, err = runAdmin(repos, "", "user", "create", "carol", "--email", "carol@test.com")
+	assert.ErrorContains(t, err, "--password-stdin", "Expected no password to be invented without a terminal")
+
+	var users []models.User
+	require.NoError(t, json.Unmarshal([]byte(run("", "user", "list", "--json")), &users))
+	require.Len(t, users, 2)
+	assert.Equal(t, "alice", users[0].Username)
+	assert.Contains(t, run("", "user", "list"), "bob@test.com")
+
+	run("", "user", "promote", "bob")
+	assert.Equal(t, models.UserRoleAdmin, get("bob").Role)
+	run("", "user", "demote", "bob")
+	assert.Equal(t, models.UserRoleUser, get("bob").Role)
+
+	run("", "user", "disable", "bob")
+	bob := get("bob")
+	assert.Equal(t, models.UserStatusDisabled, bob.Status)
+	assert.Equal(t, 1, bob.TokenVersion, "Expected disabling to revoke tokens")
+	run("", "user", "enable", "bob")
+	assert.Equal(t, models.UserStatusActive, get("bob").Status)
+
+	run("new-secret\n", "user", "reset-password", "bob", "--password-stdin")
+	bob = get("bob")
+	assert.True(t, repository.VerifyPassword(bob.PasswordHash, "new-secret"))
+	assert.Equal(t, 2, bob.TokenVersion)
+
+	run("", "token", "revoke", "alice", "bob")
+	assert.Equal(t, 1, get("alice").TokenVersion)
+	assert.Equal(t, 3, get("bob").TokenVersion)
+
+	_, err = runAdmin(repos, "", "user", "disable", "ghost")
+	assert.ErrorContains(t, err, `user "ghost" not found`)
+}
+
+func TestTransferOwnership(t *testing.T) {
+	repos := memory.NewRepositories()
+	_, err := runAdmin(repos, "", "seed", "--from", "seed.example.yaml")
+	require.NoError(t, err)
+
+	boards, err := repos.Boards.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, boards, 1)
+
+	out, err := runAdmin(repos, "", "board", "transfer-ownership", boards[0].ID.String(), "bob")
+	require.NoError(t, err)
+	assert.Contains(t, out, "now owned by bob")
+
+	board, err := repos.Boards.GetByID(context.Background(), boards[0].ID)
+	require.NoError(t, err)
+	bob, err := repos.Users.GetByUsername(context.Background(), "bob")
+	require.NoError(t, err)
+	assert.Equal(t, bob.ID, *board.UserID)
+
+	_, err = runAdmin(repos, "", "board", "transfer-ownership", "not-a-board", "bob")
+	assert.ErrorContains(t, err, "invalid board ID")
+
+	out, err = runAdmin(repos, "", "user", "delete", "bob", "--transfer-to", "alice")
+	require.NoError(t, err)
+	assert.Contains(t, out, "User bob has been deleted")
+	board, err = repos.Boards.GetByID(context.Background(), boards[0].ID)
+	require.NoError(t, err)
+	alice, err := repos.Users.GetByUsername(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, alice.ID, *board.UserID)
+	_, err = runAdmin(repos, "", "user", "delete", "alice", "--transfer-to", "alice")
+	assert.ErrorContains(t, err, "another user")
+}
+
+func TestSeed(t *testing.T) {
+	repos := memory.NewRepositories()
+	ctx := context.Background()
+
+	out, err := runAdmin(repos, "", "seed", "--from", "seed.example.yaml")
+	require.NoError(t, err)
+	passwords := regexp.MustCompile(`Created user (\S+) with password (\S+)`).FindAllStringSubmatch(out, -1)
+	require.Len(t, passwords, 3, out)
+	for _, match := range passwords {
+		user, err := repos.Users.GetByUsername(ctx, match[1])
+		require.NoError(t, err)
+		assert.True(t, repository.VerifyPassword(user.PasswordHash, match[2]))
+	}
+	admin, err := repos.Users.GetByUsername(ctx, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, models.UserRoleAdmin, admin.Role)
+
+	boards, err := repos.Boards.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, boards, 1)
+	tasks, err := repos.Tasks.ListByBoard(ctx, boards[0].ID, repository.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	members, err := repos.Members.ListByBoard(ctx, boards[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	// Повторный запуск ничего не создает
+	out, err = runAdmin(repos, "", "seed", "--from", "seed.example.yaml")
+	require.NoError(t, err)
+	assert.NotContains(t, out, "Created")
+	boards, err = repos.Boards.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, boards, 1)
+
+	_, err = runAdmin(repos, "", "seed")
+	assert.ErrorContains(t, err, "--from is required")
+	_, err = runAdmin(repos, "users:\n  - username: x\n    emial: x@test.com\n", "seed", "--from", "-")
+	assert.ErrorContains(t, err, "emial")
+
+	// Ошибка в доске откатывает и созданных пользователей
+	broken := `
+users:
+  - username: dave
+    email: dave@test.com
+boards:
+  - name: Broken
+    owner: dave
+    tasks:
+      - title: Lost
+        status: nowhere
+`
+	_, err = runAdmin(repos, broken, "seed", "--from", "-")
+	assert.ErrorContains(t, err, "nowhere")
+	_, err = repos.Users.GetByUsername(ctx, "dave")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	// Повторяющиеся участники и владелец в members пропускаются без ошибки
+	repeated := `
+users:
+  - username: erin
+    email: erin@test.com
+  - username: frank
+    email: frank@test.com
+boards:
+  - name: Repeated
+    owner: erin
+    members: [frank, erin, frank]
+`
+	_, err = runAdmin(repos, repeated, "seed", "--from", "-")
+	require.NoError(t, err)
+	boards, err = repos.Boards.List(ctx)
+	require.NoError(t, err)
+	var repeatedBoard *models.Board
+	for i := range boards {
+		if boards[i].Name == "Repeated" {
+			repeatedBoard = &boards[i]
+		}
+	}
+	require.NotNil(t, repeatedBoard)
+	members, err = repos.Members.ListByBoard(ctx, repeatedBoard.ID)
+	require.NoError(t, err)
+	assert.Len(t, members, 2, "Expected the owner and frank once each")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// minPasswordLength совпадает с проверкой handlers.Register
+const minPasswordLength = 6
+
+// readPassword читает новый пароль: с --password-stdin - первую строку stdin,
+// иначе запрашивает его дважды в терминале без эха
+func readPassword(cmd *cobra.Command, fromStdin bool) (string, error) {
+	var password string
+	if fromStdin {
+		line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		password = strings.TrimRight(line, "\r\n")
+	} else {
+		file, ok := cmd.InOrStdin().(*os.File)
+		if !ok || !term.IsTerminal(int(file.Fd())) {
+			return "", errors.New("stdin is not a terminal: use --password-stdin")
+		}
+		first, err := prompt(cmd, file, "New password: ")
+		if err != nil {
+			return "", err
+		}
+		second, err := prompt(cmd, file, "Repeat password: ")
+		if err != nil {
+			return "", err
+		}
+		if first != second {
+			return "", errors.New("passwords do not match")
+		}
+		password = first
+	}
+
+	if len(password) < minPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", minPasswordLength)
+	}
+	return password, nil
+}
+
+func prompt(cmd *cobra.Command, file *os.File, label string) (string, error) {
+	fmt.Fprint(cmd.ErrOrStderr(), label)
+	password, err := term.ReadPassword(int(file.Fd()))
+	fmt.Fprintln(cmd.ErrOrStderr())
+	return string(password), err
+}
+
+// generatePassword возвращает случайный пароль для фикстур без пароля
+func generatePassword() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/templates"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// seedFile - фикстуры для разработки (пример - seed.example.yaml)
+type seedFile struct {
+	Users  []seedUser  `yaml:"users"`
+	Boards []seedBoard `yaml:"boards"`
+}
+
+type seedUser struct {
+	Username string `yaml:"username"`
+	Email    string `yaml:"email"`
+	Role     string `yaml:"role"`
+	// Password можно не указывать: тогда пароль генерируется и печатается
+	Password string `yaml:"password"`
+}
+
+type seedBoard struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Owner       string `yaml:"owner"`
+	// Template - ключ встроенного шаблона, по умолчанию templates.DefaultID
+	Template string     `yaml:"template"`
+	Locale   string     `yaml:"locale"`
+	Members  []string   `yaml:"members"`
+	Tasks    []seedTask `yaml:"tasks"`
+}
+
+type seedTask struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	// Status - ID статуса колонки, по умолчанию первая колонка доски
+	Status   string `yaml:"status"`
+	Priority string `yaml:"priority"`
+	Assignee string `yaml:"assignee"`
+}
+
+func newSeedCmd(a *admin) *cobra.Command {
+	var from string
+
+	cmd := &cobra.Command{
+		Use:   "seed --from FILE",
+		Short: "Create users and boards from a YAML fixture file for development",
+		Long: "Creates the users and boards described in a YAML file in one transaction. " +
+			"Existing users and boards with the same name and owner are skipped, so the command can be rerun. " +
+			"Users without a password get a random one, printed once.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" {
+				return errors.New("--from is required")
+			}
+			var data []byte
+			var err error
+			if from == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(from)
+			}
+			if err != nil {
+				return err
+			}
+			seed, err := parseSeed(data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", from, err)
+			}
+
+			var report bytes.Buffer
+			err = a.repos.Tx.WithinTx(cmd.Context(), func(ctx context.Context) error {
+				report.Reset()
+				return a.seed(ctx, seed, &report)
+			})
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(report.Bytes())
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "", "YAML fixture file, - for stdin (required)")
+	return cmd
+}
+
+// parseSeed разбирает файл строго: опечатка в имени поля - ошибка
+func parseSeed(data []byte) (*seedFile, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var seed seedFile
+	if err := decoder.Decode(&seed); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	for i, user := range seed.Users {
+		if user.Username == "" || user.Email == "" {
+			return nil, fmt.Errorf("users[%d]: username and email are required", i)
+		}
+		switch user.Role {
+		case "", models.UserRoleUser, models.UserRoleAdmin:
+		default:
+			return nil, fmt.Errorf("users[%d]: unknown role %q", i, user.Role)
+		}
+		if user.Password != "" && len(user.Password) < minPasswordLength {
+			return nil, fmt.Errorf("users[%d]: password must be at least %d characters", i, minPasswordLength)
+		}
+	}
+	for i, board := range seed.Boards {
+		if board.Name == "" || board.Owner == "" {
+			return nil, fmt.Errorf("boards[%d]: name and owner are required", i)
+		}
+		for j, task := range board.Tasks {
+			if task.Title == "" {
+				return nil, fmt.Errorf("boards[%d].tasks[%d]: title is required", i, j)
+			}
+		}
+	}
+	return &seed, nil
+}
+
+func (a *admin) seed(ctx context.Context, seed *seedFile, report io.Writer) error {
+	for _, u := range seed.Users {
+		if _, err := a.repos.Users.GetByUsername(ctx, u.Username); err == nil {
+			fmt.Fprintf(report, "User %s already exists, skipped\n", u.Username)
+			continue
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+
+		password, generated := u.Password, false
+		if password == "" {
+			var err error
+			if password, err = generatePassword(); err != nil {
+				return err
+			}
+			generated = true
+		}
+		now := time.Now()
+		user := &models.User{Username: u.Username, Email: u.Email, Role: u.Role, EmailVerifiedAt: &now}
+		if err := a.repos.Users.Create(ctx, user, password); err != nil {
+			return fmt.Errorf("create user %s: %w", u.Username, err)
+		}
+		if generated {
+			fmt.Fprintf(report, "Created user %s with password %s\n", user.Username, password)
+		} else {
+			fmt.Fprintf(report, "Created user %s\n", user.Username)
+		}
+	}
+
+	existing, err := a.repos.Boards.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, b := range seed.Boards {
+		owner, err := a.user(ctx, b.Owner)
+		if err != nil {
+			return fmt.Errorf("board %q: %w", b.Name, err)
+		}
+		if hasBoard(existing, b.Name, owner) {
+			fmt.Fprintf(report, "Board %q of %s already exists, skipped\n", b.Name, owner.Username)
+			continue
+		}
+		board, err := a.seedBoard(ctx, b, owner)
+		if err != nil {
+			return fmt.Errorf("board %q: %w", b.Name, err)
+		}
+		fmt.Fprintf(report, "Created board %q (%s)\n", board.Name, board.ID)
+	}
+	return nil
+}
+
+func (a *admin) seedBoard(ctx context.Context, b seedBoard, owner *models.User) (*models.Board, error) {
+	templateID, locale := b.Template, b.Locale
+	if templateID == "" {
+		templateID = templates.DefaultID
+	}
+	if locale == "" {
+		locale = templates.DefaultLocale
+	}
+	tpl, ok := templates.Builtin(templateID, locale)
+	if !ok {
+		return nil, fmt.Errorf("unknown template %q", templateID)
+	}
+
+	board := &models.Board{Name: b.Name, UserID: &owner.ID}
+	if b.Description != "" {
+		board.Description = &b.Description
+	}
+	if err := a.repos.CreateBoard(ctx, board, tpl); err != nil {
+		return nil, err
+	}
+
+	// Повторы отбрасываются заранее: в Postgres ошибка вставки прервала бы
+	// транзакцию всего seed
+	added := map[uuid.UUID]bool{owner.ID: true}
+	for _, username := range b.Members {
+		user, err := a.user(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+		if added[user.ID] {
+			continue
+		}
+		added[user.ID] = true
+		member := &models.BoardMember{BoardID: board.ID, UserID: user.ID, Role: models.BoardRoleMember}
+		if err := a.repos.Members.Add(ctx, member); err != nil {
+			return nil, err
+		}
+	}
+
+	columns, err := a.repos.Columns.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range b.Tasks {
+		task := &models.Task{BoardID: board.ID, Title: t.Title, Description: t.Description, Status: t.Status, CreatedBy: &owner.ID}
+		if task.Status == "" && len(columns) > 0 {
+			task.Status = columns[0].StatusID
+		}
+		if err := a.repos.ValidateStatus(ctx, board.ID, task.Status); err != nil {
+			return nil, fmt.Errorf("task %q: %w", t.Title, err)
+		}
+		if t.Priority != "" {
+			task.Priority = &t.Priority
+		}
+		if t.Assignee != "" {
+			if _, err := a.user(ctx, t.Assignee); err != nil {
+				return nil, fmt.Errorf("task %q: %w", t.Title, err)
+			}
+			task.Assignee = &t.Assignee
+		}
+		if err := a.repos.Tasks.Create(ctx, task); err != nil {
+			return nil, fmt.Errorf("task %q: %w", t.Title, err)
+		}
+	}
+	return board, nil
+}
+
+func hasBoard(boards []models.Board, name string, owner *models.User) bool {
+	for _, board := range boards {
+		if board.Name == name && board.UserID != nil && *board.UserID == owner.ID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newTokenCmd(a *admin) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage access tokens",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "revoke USERNAME...",
+		Short: "Revoke all tokens issued to the users; they have to log in again",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, username := range args {
+				user, err := a.user(cmd.Context(), username)
+				if err != nil {
+					return err
+				}
+				if err := a.repos.Users.RevokeTokens(cmd.Context(), user.ID); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Revoked tokens of %s\n", user.Username)
+			}
+			return nil
+		},
+	})
+	return cmd
+}
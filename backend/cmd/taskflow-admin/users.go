@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func newUserCmd(a *admin) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage user accounts",
+	}
+	cmd.AddCommand(
+		newUserCreateCmd(a),
+		newUserListCmd(a),
+		newUserStatusCmd(a, "disable", "Disable an account and revoke its tokens", models.UserStatusDisabled),
+		newUserStatusCmd(a, "enable", "Enable a disabled account", models.UserStatusActive),
+		newUserResetPasswordCmd(a),
+		newUserDeleteCmd(a),
+		newUserRoleCmd(a, "promote", "Give a user the admin role", models.UserRoleAdmin),
+		newUserRoleCmd(a, "demote", "Take the admin role away from a user", models.UserRoleUser),
+	)
+	return cmd
+}
+
+func newUserCreateCmd(a *admin) *cobra.Command {
+	var email string
+	var isAdmin, passwordStdin bool
+
+	cmd := &cobra.Command{
+		Use:   "create USERNAME",
+		Short: "Create a user; the password is prompted for or read from stdin",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if email == "" {
+				return errors.New("--email is required")
+			}
+			password, err := readPassword(cmd, passwordStdin)
+			if err != nil {
+				return err
+			}
+
+			// Адрес, заданный администратором, не требует подтверждения по почте
+			now := time.Now()
+			user := &models.User{Username: args[0], Email: email, Role: models.UserRoleUser, EmailVerifiedAt: &now}
+			if isAdmin {
+				user.Role = models.UserRoleAdmin
+			}
+			if err := a.repos.Users.Create(cmd.Context(), user, password); err != nil {
+				if errors.Is(err, repository.ErrConflict) {
+					return fmt.Errorf("user %q or email %q already exists", user.Username, user.Email)
+				}
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Created %s %s (%s) with ID %s\n", user.Role, user.Username, user.Email, user.ID)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&email, "email", "", "email address (required)")
+	cmd.Flags().BoolVar(&isAdmin, "admin", false, "create the user with the admin role")
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read the password from the first line of stdin")
+	return cmd
+}
+
+func newUserListCmd(a *admin) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List users",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			users, err := a.repos.Users.List(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(users)
+			}
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(tw, "ID\tUSERNAME\tEMAIL\tROLE\tSTATUS\tCREATED")
+			for _, user := range users {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					user.ID, user.Username, user.Email, user.Role, user.Status, user.CreatedAt.Format("2006-01-02"))
+			}
+			return tw.Flush()
+		},
+	}
+	cmd.Flags().BoolVar(&asJSON, "json", false, "print users as JSON")
+	return cmd
+}
+
+// newUserStatusCmd меняет состояние учетной записи. Блокировка отзывает
+// токены, чтобы после разблокировки старые токены не заработали снова.
+func newUserStatusCmd(a *admin, use, short, status string) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " USERNAME",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := a.updateUser(cmd.Context(), args[0], func(ctx context.Context, user *models.User) error {
+				user.Status = status
+				user.LockedUntil = nil
+				if status == models.UserStatusDisabled {
+					return a.repos.Users.RevokeTokens(ctx, user.ID)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "User %s is now %s\n", args[0], status)
+			return nil
+		},
+	}
+}
+
+func newUserRoleCmd(a *admin, use, short, role string) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " USERNAME",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := a.updateUser(cmd.Context(), args[0], func(ctx context.Context, user *models.User) error {
+				user.Role = role
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "User %s now has the %s role\n", args[0], role)
+			return nil
+		},
+	}
+}
+
+func newUserResetPasswordCmd(a *admin) *cobra.Command {
+	var passwordStdin bool
+
+	cmd := &cobra.Command{
+		Use:   "reset-password USERNAME",
+		Short: "Set a new password and revoke the user's tokens",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := a.user(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			password, err := readPassword(cmd, passwordStdin)
+			if err != nil {
+				return err
+			}
+			err = a.repos.Tx.WithinTx(cmd.Context(), func(ctx context.Context) error {
+				if err := a.repos.Users.SetPassword(ctx, user.ID, password); err != nil {
+					return err
+				}
+				return a.repos.Users.RevokeTokens(ctx, user.ID)
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Password of %s has been reset, existing sessions are revoked\n", user.Username)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read the password from the first line of stdin")
+	return cmd
+}
+
+func newUserDeleteCmd(a *admin) *cobra.Command {
+	var transferTo string
+
+	cmd := &cobra.Command{
+		Use:   "delete USERNAME",
+		Short: "Delete a user; their tasks, comments and boards stay without an author",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			user, err := a.user(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			var heirID *uuid.UUID
+			if transferTo != "" {
+				heir, err := a.user(cmd.Context(), transferTo)
+				if err != nil {
+					return err
+				}
+				if heir.ID == user.ID {
+					return errors.New("--transfer-to must name another user")
+				}
+				heirID = &heir.ID
+			}
+			if err := a.repos.DeleteUser(cmd.Context(), user.ID, heirID); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "User %s has been deleted\n", user.Username)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&transferTo, "transfer-to", "", "give the boards created by the user to this user")
+	return cmd
+}
+
+// updateUser меняет пользователя в change и сохраняет его в одной транзакции
+func (a *admin) updateUser(ctx context.Context, username string, change func(ctx context.Context, user *models.User) error) error {
+	return a.repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		user, err := a.user(ctx, username)
+		if err != nil {
+			return err
+		}
+		if err := change(ctx, user); err != nil {
+			return err
+		}
+		return a.repos.Users.Update(ctx, user)
+	})
+}
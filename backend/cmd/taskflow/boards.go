@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+func newBoardsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "boards",
+		Short: "Work with boards",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List boards",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			boards, err := a.client().Boards(cmd.Context())
+			if err != nil {
+				return err
+			}
+			return a.print(cmd.OutOrStdout(), boards, func(w io.Writer) {
+				fmt.Fprintln(w, "ID\tNAME\tDESCRIPTION")
+				for _, board := range boards {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", board.ID, board.Name, orDash(board.Description))
+				}
+			})
+		},
+	})
+	return cmd
+}
+
+// completeBoards дополняет ID досок, показывая их названия
+func (a *app) completeBoards(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	boards, err := a.client().Boards(completionContext(cmd))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	completions := make([]string, 0, len(boards))
+	for _, board := range boards {
+		completions = append(completions, board.ID.String()+"\t"+board.Name)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionContext - контекст для запросов дополнения: cobra передает его не
+// во всех версиях
+func completionContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
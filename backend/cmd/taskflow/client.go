@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// errNotLoggedIn возвращается командами, которым нужен токен
+var errNotLoggedIn = errors.New("not logged in: run `taskflow login` first")
+
+// Client обращается к REST API Task Flow
+type Client struct {
+	Server string
+	Token  string
+	HTTP   *http.Client
+}
+
+// APIError - ответ API с кодом 4xx/5xx
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", e.Message, e.Status)
+}
+
+// do отправляет body в JSON и декодирует ответ в out, если он не nil
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.Server, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return readAPIError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// readAPIError достает сообщение из {"error": ...} или текста http.Error
+func readAPIError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var body struct {
+		Error string `json:"error"`
+	}
+	message := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &body) == nil && body.Error != "" {
+		message = body.Error
+	}
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+	return &APIError{Status: resp.StatusCode, Message: message}
+}
+
+func (c *Client) requireToken() error {
+	if c.Token == "" {
+		return errNotLoggedIn
+	}
+	return nil
+}
+
+func (c *Client) Login(ctx context.Context, username, password string) (*models.AuthResponse, error) {
+	var resp models.AuthResponse
+	req := models.LoginRequest{Username: username, Password: password}
+	if err := c.do(ctx, http.MethodPost, "/api/auth/login", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) Boards(ctx context.Context) ([]models.Board, error) {
+	var boards []models.Board
+	err := c.do(ctx, http.MethodGet, "/api/boards", nil, &boards)
+	return boards, err
+}
+
+func (c *Client) Columns(ctx context.Context, boardID uuid.UUID) ([]models.Column, error) {
+	var columns []models.Column
+	err := c.do(ctx, http.MethodGet, "/api/columns?board_id="+url.QueryEscape(boardID.String()), nil, &columns)
+	return columns, err
+}
+
+func (c *Client) Tasks(ctx context.Context, boardID uuid.UUID) ([]models.Task, error) {
+	var tasks []models.Task
+	err := c.do(ctx, http.MethodGet, "/api/tasks?board_id="+url.QueryEscape(boardID.String()), nil, &tasks)
+	return tasks, err
+}
+
+func (c *Client) Task(ctx context.Context, id uuid.UUID) (*models.Task, error) {
+	var task models.Task
+	if err := c.do(ctx, http.MethodGet, "/api/tasks/"+id.String(), nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (c *Client) CreateTask(ctx context.Context, req models.CreateTaskRequest) (*models.Task, error) {
+	if err := c.requireToken(); err != nil {
+		return nil, err
+	}
+	var task models.Task
+	if err := c.do(ctx, http.MethodPost, "/api/tasks", req, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (c *Client) UpdateTask(ctx context.Context, id uuid.UUID, req models.UpdateTaskRequest) (*models.Task, error) {
+	if err := c.requireToken(); err != nil {
+		return nil, err
+	}
+	var task models.Task
+	if err := c.do(ctx, http.MethodPut, "/api/tasks/"+id.String(), req, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (c *Client) MoveTask(ctx context.Context, id uuid.UUID, status string) (*models.Task, error) {
+	if err := c.requireToken(); err != nil {
+		return nil, err
+	}
+	var task models.Task
+	body := map[string]string{"status": status}
+	if err := c.do(ctx, http.MethodPatch, "/api/tasks/"+id.String()+"/move", body, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// resolveStatus принимает ID статуса или название колонки без учета регистра
+func (c *Client) resolveStatus(ctx context.Context, boardID uuid.UUID, value string) (string, error) {
+	columns, err := c.Columns(ctx, boardID)
+	if err != nil {
+		return "", err
+	}
+	for _, column := range columns {
+		if column.StatusID == value {
+			return value, nil
+		}
+	}
+	for _, column := range columns {
+		if strings.EqualFold(column.Title, value) {
+			return column.StatusID, nil
+		}
+	}
+	return "", fmt.Errorf("unknown status %q", value)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// defaultServer - адрес API, если он не задан флагом, окружением или конфигом
+const defaultServer = "http://localhost:8080"
+
+// Config хранится в JSON-файле и содержит токен, поэтому пишется с правами 0600
+type Config struct {
+	Server   string `json:"server,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// defaultConfigPath возвращает $XDG_CONFIG_HOME/taskflow/config.json или его
+// аналог для текущей ОС
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".taskflow.json"
+	}
+	return filepath.Join(dir, "taskflow", "config.json")
+}
+
+// loadConfig читает конфиг; отсутствующий файл - пустой конфиг
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (c *Config) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Файл мог существовать с более широкими правами: WriteFile их не меняет
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
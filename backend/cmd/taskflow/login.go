@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func newLoginCmd(a *app) *cobra.Command {
+	var username string
+	var passwordStdin bool
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in and store the token in the config file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if username == "" {
+				return errors.New("--username is required")
+			}
+			password, err := readPassword(cmd, passwordStdin)
+			if err != nil {
+				return err
+			}
+
+			client := a.client()
+			resp, err := client.Login(cmd.Context(), username, password)
+			if err != nil {
+				return err
+			}
+
+			a.config.Server = client.Server
+			a.config.Token = resp.Token
+			a.config.Username = resp.User.Username
+			if err := a.config.save(a.configPath); err != nil {
+				return fmt.Errorf("save config: %w", err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Logged in to %s as %s\n", client.Server, resp.User.Username)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&username, "username", "u", "", "user name")
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read the password from stdin")
+	return cmd
+}
+
+// readPassword читает первую строку stdin или запрашивает пароль без эха
+func readPassword(cmd *cobra.Command, fromStdin bool) (string, error) {
+	in := cmd.InOrStdin()
+	if !fromStdin {
+		file, ok := in.(*os.File)
+		if !ok || !term.IsTerminal(int(file.Fd())) {
+			return "", errors.New("stdin is not a terminal: use --password-stdin")
+		}
+		fmt.Fprint(cmd.ErrOrStderr(), "Password: ")
+		password, err := term.ReadPassword(int(file.Fd()))
+		fmt.Fprintln(cmd.ErrOrStderr())
+		return string(password), err
+	}
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("empty password on stdin")
+	}
+	return password, nil
+}
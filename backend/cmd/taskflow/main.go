@@ -0,0 +1,126 @@
+// Команда taskflow - клиент REST API Task Flow для терминала
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// app - глобальные флаги и конфиг, общие для всех команд
+type app struct {
+	configPath string
+	server     string
+	output     string
+	config     *Config
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := newRootCmd().ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	a := &app{}
+	root := &cobra.Command{
+		Use:           "taskflow",
+		Short:         "Command-line client for the Task Flow API",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if a.output != outputTable && a.output != outputJSON {
+				return fmt.Errorf("unknown output format %q: use table or json", a.output)
+			}
+			return a.loadConfig()
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.configPath, "config", defaultConfigPath(), "path to the config file")
+	flags.StringVar(&a.server, "server", "", "API address (default $TASKFLOW_SERVER, the config or "+defaultServer+")")
+	flags.StringVarP(&a.output, "output", "o", outputTable, "output format: table or json")
+	root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{outputTable, outputJSON}, cobra.ShellCompDirectiveNoFileComp))
+
+	root.AddCommand(
+		newLoginCmd(a),
+		newBoardsCmd(a),
+		newTasksCmd(a),
+		newTaskCmd(a),
+		newWatchCmd(a),
+	)
+	return root
+}
+
+// loadConfig читает конфиг один раз: completion-функции вызываются без
+// PersistentPreRunE
+func (a *app) loadConfig() error {
+	if a.config != nil {
+		return nil
+	}
+	cfg, err := loadConfig(a.configPath)
+	if err != nil {
+		return fmt.Errorf("read config %s: %w", a.configPath, err)
+	}
+	a.config = cfg
+	return nil
+}
+
+// client собирает клиент API: флаг и переменные окружения важнее конфига
+func (a *app) client() *Client {
+	if err := a.loadConfig(); err != nil {
+		a.config = &Config{}
+	}
+	server := a.server
+	if server == "" {
+		server = os.Getenv("TASKFLOW_SERVER")
+	}
+	if server == "" {
+		server = a.config.Server
+	}
+	if server == "" {
+		server = defaultServer
+	}
+	token := os.Getenv("TASKFLOW_TOKEN")
+	if token == "" && strings.TrimRight(server, "/") == strings.TrimRight(a.config.Server, "/") {
+		// Токен одного сервера не отправляется на другой
+		token = a.config.Token
+	}
+	return &Client{Server: server, Token: token}
+}
+
+// print выводит v в JSON или таблицей, которую строит table
+func (a *app) print(w io.Writer, v interface{}, table func(w io.Writer)) error {
+	if a.output == outputJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// orDash заменяет пустые значения в таблицах
+func orDash(s *string) string {
+	if s == nil || *s == "" {
+		return "-"
+	}
+	return *s
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"task-flow-backend/handlers"
+	"task-flow-backend/models"
+	"task-flow-backend/repository/memory"
+	"task-flow-backend/websocket"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAPI поднимает API на памяти с WebSocket-лентой, как в main.go
+func newTestAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	hub := websocket.NewHub()
+	go hub.Run()
+
+	repos := memory.NewRepositories()
+	user := &models.User{Username: "alice", Email: "alice@test.com"}
+	require.NoError(t, repos.Users.Create(context.Background(), user, "secret123"))
+
+	router := mux.NewRouter()
+	handlers.NewServer(repos, handlers.WithHub(hub)).Routes(router)
+	router.HandleFunc("/ws/board/{board_id}", func(w http.ResponseWriter, r *http.Request) {
+		websocket.ServeWS(hub, w, r)
+	}).Methods("GET")
+
+	api := httptest.NewServer(router)
+	t.Cleanup(api.Close)
+	t.Setenv("TASKFLOW_SERVER", "")
+	t.Setenv("TASKFLOW_TOKEN", "")
+	return api
+}
+
+// safeBuffer - вывод команды, который читается, пока она работает
+type safeBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *safeBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *safeBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func runCLI(ctx context.Context, configPath, stdin string, out *safeBuffer, args ...string) error {
+	cmd := newRootCmd()
+	cmd.SetArgs(append([]string{"--config", configPath}, args...))
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetOut(out)
+	cmd.SetErr(&safeBuffer{})
+	return cmd.ExecuteContext(ctx)
+}
+
+func TestCLI(t *testing.T) {
+	api := newTestAPI(t)
+	configPath := filepath.Join(t.TempDir(), "taskflow", "config.json")
+	ctx := context.Background()
+
+	run := func(t *testing.T, stdin string, args ...string) string {
+		t.Helper()
+		var out safeBuffer
+		require.NoError(t, runCLI(ctx, configPath, stdin, &out, args...))
+		return out.String()
+	}
+	runJSON := func(t *testing.T, v interface{}, args ...string) {
+		t.Helper()
+		output := run(t, "", append([]string{"-o", "json"}, args...)...)
+		require.NoError(t, json.Unmarshal([]byte(output), v), output)
+	}
+
+	t.Run("login", func(t *testing.T) {
+		var out safeBuffer
+		err := runCLI(ctx, configPath, "wrong\n", &out, "--server", api.URL, "login", "-u", "alice", "--password-stdin")
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusUnauthorized, apiErr.Status)
+		assert.Equal(t, "Invalid username or password", apiErr.Message)
+
+		err = runCLI(ctx, configPath, "", &out, "--server", api.URL, "login", "-u", "alice")
+		assert.ErrorContains(t, err, "--password-stdin")
+
+		assert.Contains(t, run(t, "secret123\n", "--server", api.URL, "login", "-u", "alice", "--password-stdin"), "Logged in")
+
+		info, err := os.Stat(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		cfg, err := loadConfig(configPath)
+		require.NoError(t, err)
+		assert.Equal(t, api.URL, cfg.Server)
+		assert.Equal(t, "alice", cfg.Username)
+		assert.NotEmpty(t, cfg.Token)
+	})
+
+	// Дальше адрес и токен берутся из конфига
+	client := &Client{Server: api.URL}
+	cfg, err := loadConfig(configPath)
+	require.NoError(t, err)
+	client.Token = cfg.Token
+	var board models.Board
+	require.NoError(t, client.do(ctx, http.MethodPost, "/api/boards", models.CreateBoardRequest{Name: "CLI board"}, &board))
+	boardID := board.ID.String()
+
+	t.Run("boards ls", func(t *testing.T) {
+		output := run(t, "", "boards", "ls")
+		assert.Contains(t, output, "NAME")
+		assert.Contains(t, output, boardID)
+		assert.Contains(t, output, "CLI board")
+
+		var boards []models.Board
+		runJSON(t, &boards, "boards", "ls")
+		require.Len(t, boards, 1)
+		assert.Equal(t, board.ID, boards[0].ID)
+	})
+
+	var task models.Task
+	t.Run("task create and show", func(t *testing.T) {
+		runJSON(t, &task, "task", "create", "--board", boardID, "--title", "Write CLI", "--assignee", "me", "--priority", "high", "--due", "2026-03-01")
+		assert.Equal(t, "Write CLI", task.Title)
+		assert.Equal(t, "plan", task.Status)
+		require.NotNil(t, task.Assignee)
+		assert.Equal(t, "alice", *task.Assignee)
+		require.NotNil(t, task.DueAt)
+
+		run(t, "", "task", "create", "-b", boardID, "-t", "Unassigned", "-s", "development")
+
+		output := run(t, "", "task", "show", task.ID.String())
+		assert.Contains(t, output, "Write CLI")
+		assert.Contains(t, output, "high")
+		assert.Contains(t, output, "2026-03-01")
+
+		var out safeBuffer
+		assert.ErrorContains(t, runCLI(ctx, configPath, "", &out, "task", "create", "-b", boardID, "-t", "Bad", "-s", "nope"), `unknown status "nope"`)
+	})
+
+	t.Run("tasks ls filters", func(t *testing.T) {
+		var tasks []models.Task
+		runJSON(t, &tasks, "tasks", "ls", "--board", boardID)
+		assert.Len(t, tasks, 2)
+
+		runJSON(t, &tasks, "tasks", "ls", "--board", boardID, "--assignee", "me")
+		require.Len(t, tasks, 1)
+		assert.Equal(t, task.ID, tasks[0].ID)
+
+		runJSON(t, &tasks, "tasks", "ls", "--board", boardID, "--status", "development")
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "Unassigned", tasks[0].Title)
+
+		output := run(t, "", "tasks", "ls", "--board", boardID)
+		assert.Contains(t, output, "ASSIGNEE")
+		assert.Contains(t, output, "Write CLI")
+	})
+
+	t.Run("task move and edit", func(t *testing.T) {
+		var moved models.Task
+		runJSON(t, &moved, "task", "move", task.ID.String(), "development")
+		assert.Equal(t, "development", moved.Status)
+
+		var edited models.Task
+		runJSON(t, &edited, "task", "edit", task.ID.String(), "--title", "Ship CLI", "--points", "3", "--due", "")
+		assert.Equal(t, "Ship CLI", edited.Title)
+		assert.Equal(t, "development", edited.Status)
+		require.NotNil(t, edited.StoryPoints)
+		assert.Equal(t, 3, *edited.StoryPoints)
+		assert.Nil(t, edited.DueAt)
+		require.NotNil(t, edited.Priority)
+		assert.Equal(t, "high", *edited.Priority, "Expected unchanged fields to be kept")
+
+		var out safeBuffer
+		assert.ErrorContains(t, runCLI(ctx, configPath, "", &out, "task", "edit", task.ID.String()), "nothing to change")
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		watchCtx, cancel := context.WithCancel(ctx)
+		var out safeBuffer
+		done := make(chan error, 1)
+		go func() {
+			done <- runCLI(watchCtx, configPath, "", &out, "watch", "--board", boardID)
+		}()
+
+		// Клиент ленты регистрируется в хабе асинхронно, поэтому событие
+		// повторяется, пока не дойдет
+		status := "plan"
+		require.Eventually(t, func() bool {
+			if status == "plan" {
+				status = "development"
+			} else {
+				status = "plan"
+			}
+			_, err := client.MoveTask(ctx, task.ID, status)
+			require.NoError(t, err)
+			return strings.Contains(out.String(), "task_moved")
+		}, 5*time.Second, 50*time.Millisecond)
+		assert.Contains(t, out.String(), task.ID.String())
+		assert.Contains(t, out.String(), `"Ship CLI"`)
+
+		cancel()
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("watch did not stop after cancel")
+		}
+	})
+
+	t.Run("not logged in", func(t *testing.T) {
+		var out safeBuffer
+		other := filepath.Join(t.TempDir(), "config.json")
+		err := runCLI(ctx, other, "", &out, "--server", api.URL, "task", "create", "-b", boardID, "-t", "Anonymous")
+		assert.ErrorIs(t, err, errNotLoggedIn)
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	api := newTestAPI(t)
+	// Скрипты дополнения вызывают "taskflow __complete ..." без --config,
+	// поэтому адрес передается через окружение
+	t.Setenv("TASKFLOW_SERVER", api.URL)
+	configPath := filepath.Join(t.TempDir(), "config.json")
+
+	client := &Client{Server: api.URL}
+	auth, err := client.Login(context.Background(), "alice", "secret123")
+	require.NoError(t, err)
+	client.Token = auth.Token
+	var board models.Board
+	require.NoError(t, client.do(context.Background(), http.MethodPost, "/api/boards", models.CreateBoardRequest{Name: "Completed"}, &board))
+
+	var out safeBuffer
+	require.NoError(t, runCLI(context.Background(), configPath, "", &out, "__complete", "tasks", "ls", "--board", ""))
+	assert.Contains(t, out.String(), board.ID.String()+"\tCompleted")
+
+	out = safeBuffer{}
+	require.NoError(t, runCLI(context.Background(), configPath, "", &out, "__complete", "tasks", "ls", "--board", board.ID.String(), "--status", ""))
+	assert.Contains(t, out.String(), "development\t")
+
+	out = safeBuffer{}
+	require.NoError(t, runCLI(context.Background(), configPath, "", &out, "completion", "zsh"))
+	assert.Contains(t, out.String(), "#compdef taskflow")
+}
+
+func TestWebsocketURL(t *testing.T) {
+	u, err := websocketURL("https://tasks.example.com/", "/ws/board/1")
+	require.NoError(t, err)
+	assert.Equal(t, "wss://tasks.example.com/ws/board/1", u)
+
+	u, err = websocketURL("http://localhost:8080", "/ws/board/1")
+	require.NoError(t, err)
+	assert.Equal(t, "ws://localhost:8080/ws/board/1", u)
+
+	_, err = websocketURL("ftp://localhost", "/ws")
+	assert.Error(t, err)
+}
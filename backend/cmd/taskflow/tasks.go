@@ -0,0 +1,366 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func newTasksCmd(a *app) *cobra.Command {
+	var board, status, assignee string
+
+	cmd := &cobra.Command{
+		Use:   "tasks",
+		Short: "Work with board tasks",
+	}
+	ls := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List board tasks",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			boardID, err := parseID("board", board)
+			if err != nil {
+				return err
+			}
+			client := a.client()
+			tasks, err := client.Tasks(cmd.Context(), boardID)
+			if err != nil {
+				return err
+			}
+
+			wantStatus := status
+			if wantStatus != "" {
+				if wantStatus, err = client.resolveStatus(cmd.Context(), boardID, wantStatus); err != nil {
+					return err
+				}
+			}
+			wantAssignee := a.assignee(assignee)
+			filtered := make([]models.Task, 0, len(tasks))
+			for _, task := range tasks {
+				if wantStatus != "" && task.Status != wantStatus {
+					continue
+				}
+				if wantAssignee != "" && (task.Assignee == nil || *task.Assignee != wantAssignee) {
+					continue
+				}
+				filtered = append(filtered, task)
+			}
+
+			return a.print(cmd.OutOrStdout(), filtered, func(w io.Writer) {
+				fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tPRIORITY\tASSIGNEE\tDUE")
+				for _, task := range filtered {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+						task.ID, task.Title, task.Status, orDash(task.Priority), orDash(task.Assignee), formatDue(task.DueAt))
+				}
+			})
+		},
+	}
+	ls.Flags().StringVarP(&board, "board", "b", "", "board ID (required)")
+	ls.Flags().StringVarP(&status, "status", "s", "", "status ID or column title")
+	ls.Flags().StringVarP(&assignee, "assignee", "a", "", "assignee user name, \"me\" for the logged in user")
+	ls.MarkFlagRequired("board")
+	ls.RegisterFlagCompletionFunc("board", a.completeBoards)
+	ls.RegisterFlagCompletionFunc("status", a.completeStatuses)
+	cmd.AddCommand(ls)
+	return cmd
+}
+
+func newTaskCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "task",
+		Short: "Show, create, edit and move a task",
+	}
+	cmd.AddCommand(newTaskShowCmd(a), newTaskCreateCmd(a), newTaskEditCmd(a), newTaskMoveCmd(a))
+	return cmd
+}
+
+func newTaskShowCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show TASK_ID",
+		Short: "Show a task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID("task", args[0])
+			if err != nil {
+				return err
+			}
+			task, err := a.client().Task(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+			return a.printTask(cmd, task)
+		},
+	}
+}
+
+// taskFields - флаги полей задачи, общие для create и edit
+type taskFields struct {
+	title, description, status, priority, assignee, due string
+	points                                              int
+}
+
+func (f *taskFields) register(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.StringVarP(&f.title, "title", "t", "", "task title")
+	flags.StringVarP(&f.description, "description", "d", "", "task description")
+	flags.StringVarP(&f.status, "status", "s", "", "status ID or column title")
+	flags.StringVarP(&f.priority, "priority", "p", "", "priority: low, medium or high")
+	flags.StringVarP(&f.assignee, "assignee", "a", "", "assignee user name, \"me\" for the logged in user")
+	flags.StringVar(&f.due, "due", "", "due date: YYYY-MM-DD or RFC3339")
+	flags.IntVar(&f.points, "points", 0, "story points")
+	cmd.RegisterFlagCompletionFunc("priority", cobra.FixedCompletions([]string{"low", "medium", "high"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func newTaskCreateCmd(a *app) *cobra.Command {
+	var board string
+	var fields taskFields
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a task",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			boardID, err := parseID("board", board)
+			if err != nil {
+				return err
+			}
+			client := a.client()
+			req := models.CreateTaskRequest{BoardID: boardID, Title: fields.title, Description: fields.description}
+
+			flags := cmd.Flags()
+			if flags.Changed("status") {
+				if req.Status, err = client.resolveStatus(cmd.Context(), boardID, fields.status); err != nil {
+					return err
+				}
+			}
+			if flags.Changed("priority") {
+				req.Priority = &fields.priority
+			}
+			if flags.Changed("assignee") {
+				assignee := a.assignee(fields.assignee)
+				req.Assignee = &assignee
+			}
+			if flags.Changed("due") {
+				due, err := parseDue(fields.due)
+				if err != nil {
+					return err
+				}
+				req.DueAt = &due
+			}
+			if flags.Changed("points") {
+				req.StoryPoints = &fields.points
+			}
+
+			task, err := client.CreateTask(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+			return a.printTask(cmd, task)
+		},
+	}
+	fields.register(cmd)
+	cmd.Flags().StringVarP(&board, "board", "b", "", "board ID (required)")
+	cmd.MarkFlagRequired("board")
+	cmd.MarkFlagRequired("title")
+	cmd.RegisterFlagCompletionFunc("board", a.completeBoards)
+	cmd.RegisterFlagCompletionFunc("status", a.completeStatuses)
+	return cmd
+}
+
+func newTaskEditCmd(a *app) *cobra.Command {
+	var fields taskFields
+
+	cmd := &cobra.Command{
+		Use:   "edit TASK_ID",
+		Short: "Change task fields; only the given flags are sent",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID("task", args[0])
+			if err != nil {
+				return err
+			}
+			client := a.client()
+			var req models.UpdateTaskRequest
+			changed := false
+
+			flags := cmd.Flags()
+			set := func(name string, value string, field **string) {
+				if flags.Changed(name) {
+					*field = &value
+					changed = true
+				}
+			}
+			set("title", fields.title, &req.Title)
+			set("description", fields.description, &req.Description)
+			set("priority", fields.priority, &req.Priority)
+			set("assignee", a.assignee(fields.assignee), &req.Assignee)
+			if flags.Changed("status") {
+				task, err := client.Task(cmd.Context(), id)
+				if err != nil {
+					return err
+				}
+				status, err := client.resolveStatus(cmd.Context(), task.BoardID, fields.status)
+				if err != nil {
+					return err
+				}
+				req.Status = &status
+				changed = true
+			}
+			if flags.Changed("due") {
+				// Пустое значение снимает срок
+				due := ""
+				if fields.due != "" {
+					parsed, err := parseDue(fields.due)
+					if err != nil {
+						return err
+					}
+					due = parsed.Format(time.RFC3339)
+				}
+				req.DueAt = &due
+				changed = true
+			}
+			if flags.Changed("points") {
+				req.StoryPoints = &fields.points
+				changed = true
+			}
+			if !changed {
+				return errors.New("nothing to change: pass at least one field flag")
+			}
+
+			task, err := client.UpdateTask(cmd.Context(), id, req)
+			if err != nil {
+				return err
+			}
+			return a.printTask(cmd, task)
+		},
+	}
+	fields.register(cmd)
+	return cmd
+}
+
+func newTaskMoveCmd(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "move TASK_ID STATUS",
+		Short: "Move a task to another column by status ID or column title",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseID("task", args[0])
+			if err != nil {
+				return err
+			}
+			client := a.client()
+			task, err := client.Task(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+			status, err := client.resolveStatus(cmd.Context(), task.BoardID, args[1])
+			if err != nil {
+				return err
+			}
+			if task, err = client.MoveTask(cmd.Context(), id, status); err != nil {
+				return err
+			}
+			return a.printTask(cmd, task)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 1 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			id, err := uuid.Parse(args[0])
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			task, err := a.client().Task(completionContext(cmd), id)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+			return a.statusCompletions(cmd, task.BoardID)
+		},
+	}
+}
+
+// printTask выводит задачу построчно "поле: значение"
+func (a *app) printTask(cmd *cobra.Command, task *models.Task) error {
+	return a.print(cmd.OutOrStdout(), task, func(w io.Writer) {
+		fmt.Fprintf(w, "ID:\t%s\n", task.ID)
+		fmt.Fprintf(w, "Title:\t%s\n", task.Title)
+		fmt.Fprintf(w, "Status:\t%s\n", task.Status)
+		fmt.Fprintf(w, "Priority:\t%s\n", orDash(task.Priority))
+		fmt.Fprintf(w, "Assignee:\t%s\n", orDash(task.Assignee))
+		fmt.Fprintf(w, "Due:\t%s\n", formatDue(task.DueAt))
+		if task.StoryPoints != nil {
+			fmt.Fprintf(w, "Points:\t%d\n", *task.StoryPoints)
+		}
+		if task.Progress.Total > 0 {
+			fmt.Fprintf(w, "Progress:\t%d/%d\n", task.Progress.Done, task.Progress.Total)
+		}
+		fmt.Fprintf(w, "Board:\t%s\n", task.BoardID)
+		fmt.Fprintf(w, "Updated:\t%s\n", task.UpdatedAt.Local().Format("2006-01-02 15:04"))
+		if task.Description != "" {
+			fmt.Fprintf(w, "\n%s\n", task.Description)
+		}
+	})
+}
+
+// assignee подставляет имя вошедшего пользователя вместо "me"
+func (a *app) assignee(value string) string {
+	if value == "me" && a.config != nil && a.config.Username != "" {
+		return a.config.Username
+	}
+	return value
+}
+
+// completeStatuses дополняет статусы доски из флага --board
+func (a *app) completeStatuses(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	boardID, err := uuid.Parse(cmd.Flag("board").Value.String())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return a.statusCompletions(cmd, boardID)
+}
+
+func (a *app) statusCompletions(cmd *cobra.Command, boardID uuid.UUID) ([]string, cobra.ShellCompDirective) {
+	columns, err := a.client().Columns(completionContext(cmd), boardID)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	completions := make([]string, 0, len(columns))
+	for _, column := range columns {
+		completions = append(completions, column.StatusID+"\t"+column.Title)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func parseID(kind, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid %s ID %q", kind, value)
+	}
+	return id, nil
+}
+
+// parseDue принимает дату YYYY-MM-DD (конец дня по местному времени) или RFC3339
+func parseDue(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(value), time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid due date %q: use YYYY-MM-DD or RFC3339", value)
+	}
+	return day.Add(24*time.Hour - time.Second), nil
+}
+
+func formatDue(due *time.Time) string {
+	if due == nil {
+		return "-"
+	}
+	return due.Local().Format("2006-01-02")
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cobra"
+)
+
+// boardEvent - сообщение WebSocket-ленты доски (websocket.Message)
+type boardEvent struct {
+	Type    string          `json:"type"`
+	BoardID string          `json:"board_id,omitempty"`
+	Data    json.RawMessage `json:"data"`
+}
+
+func newWatchCmd(a *app) *cobra.Command {
+	var board string
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Print board events as they happen",
+		Long:  "Tails the WebSocket feed of a board until interrupted. With -o json every event is printed as one JSON line.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			boardID, err := parseID("board", board)
+			if err != nil {
+				return err
+			}
+			feedURL, err := websocketURL(a.client().Server, "/ws/board/"+boardID.String())
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			conn, _, err := websocket.DefaultDialer.DialContext(ctx, feedURL, nil)
+			if err != nil {
+				return fmt.Errorf("connect to %s: %w", feedURL, err)
+			}
+			defer conn.Close()
+			// Закрытие соединения прерывает ReadMessage при отмене контекста
+			stop := make(chan struct{})
+			defer close(stop)
+			go func() {
+				select {
+				case <-ctx.Done():
+					conn.Close()
+				case <-stop:
+				}
+			}()
+			fmt.Fprintf(cmd.ErrOrStderr(), "Watching board %s, press Ctrl+C to stop\n", boardID)
+
+			out := cmd.OutOrStdout()
+			for {
+				_, data, err := conn.ReadMessage()
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					var closeErr *websocket.CloseError
+					if errors.As(err, &closeErr) {
+						return fmt.Errorf("board feed closed: %w", err)
+					}
+					return err
+				}
+				if a.output == outputJSON {
+					fmt.Fprintln(out, strings.TrimSpace(string(data)))
+					continue
+				}
+				var event boardEvent
+				if err := json.Unmarshal(data, &event); err != nil {
+					continue
+				}
+				fmt.Fprintln(out, formatEvent(time.Now(), event))
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&board, "board", "b", "", "board ID (required)")
+	cmd.MarkFlagRequired("board")
+	cmd.RegisterFlagCompletionFunc("board", a.completeBoards)
+	return cmd
+}
+
+// formatEvent печатает время, тип события и узнаваемые поля его данных
+func formatEvent(at time.Time, event boardEvent) string {
+	var data struct {
+		ID       string `json:"id"`
+		TaskID   string `json:"task_id"`
+		Title    string `json:"title"`
+		Status   string `json:"status"`
+		Assignee string `json:"assignee"`
+	}
+	json.Unmarshal(event.Data, &data)
+
+	parts := []string{at.Format("15:04:05"), event.Type}
+	if data.TaskID != "" {
+		parts = append(parts, data.TaskID)
+	} else if data.ID != "" {
+		parts = append(parts, data.ID)
+	}
+	if data.Title != "" {
+		parts = append(parts, fmt.Sprintf("%q", data.Title))
+	}
+	if data.Status != "" {
+		parts = append(parts, "status="+data.Status)
+	}
+	if data.Assignee != "" {
+		parts = append(parts, "assignee="+data.Assignee)
+	}
+	return strings.Join(parts, "  ")
+}
+
+// websocketURL переводит адрес API на схему ws/wss
+func websocketURL(server, path string) (string, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return "", fmt.Errorf("invalid server address %q: %w", server, err)
+	}
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	case "ws", "wss":
+	default:
+		return "", fmt.Errorf("invalid server address %q: expected http or https", server)
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + path
+	return u.String(), nil
+}
@@ -7,7 +7,9 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var DB *sql.DB
@@ -42,7 +44,10 @@ func Init() error {
 		host, port, user, password, dbname, sslmode)
 
 	var err error
-	DB, err = sql.Open("postgres", psqlInfo)
+	DB, err = otelsql.Open("postgres", psqlInfo,
+		otelsql.WithAttributes(attribute.String("db.system", "postgresql")),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitRows: true}),
+	)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -64,7 +69,30 @@ func runMigrations() error {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	migrations := []string{"001_init.sql", "002_add_users.sql"}
+	migrations := []string{
+		"001_init.sql",
+		"002_add_users.sql",
+		"003_board_members.sql",
+		"004_board_templates.sql",
+		"005_soft_delete.sql",
+		"006_archiving.sql",
+		"007_subtasks.sql",
+		"008_task_links.sql",
+		"009_attachments.sql",
+		"010_time_tracking.sql",
+		"011_recurring_tasks.sql",
+		"012_automation.sql",
+		"013_workflow.sql",
+		"014_sprints.sql",
+		"015_status_history.sql",
+		"016_burndown.sql",
+		"017_import_jobs.sql",
+		"018_user_admin.sql",
+		"019_user_lifecycle.sql",
+		"020_email_tokens.sql",
+		"021_automation_delays.sql",
+		"022_task_pages.sql",
+	}
 
 	for _, migrationFile := range migrations {
 		possiblePaths := []string{
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"sync"
+)
+
+// LeaderLock выбирает одну реплику-лидера через сессионную advisory-блокировку
+// Postgres. Лидер держит отдельное соединение, пока оно живо; если соединение
+// рвется, Postgres снимает блокировку и лидером становится другая реплика.
+type LeaderLock struct {
+	db  *sql.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewLeaderLock создает блокировку с ключом, вычисленным из name, чтобы
+// разные задачи выбирали лидера независимо
+func NewLeaderLock(db *sql.DB, name string) *LeaderLock {
+	h := fnv.New64a()
+	h.Write([]byte("taskflow:" + name))
+	return &LeaderLock{db: db, key: int64(h.Sum64())}
+}
+
+// IsLeader подтверждает лидерство этой реплики или пытается его получить
+func (l *LeaderLock) IsLeader(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		_, err := l.conn.ExecContext(ctx, "SELECT 1")
+		if err == nil {
+			return true, nil
+		}
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		// Соединение потеряно, а вместе с ним и блокировка
+		l.conn.Close()
+		l.conn = nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&locked); err != nil {
+		conn.Close()
+		return false, err
+	}
+	if !locked {
+		conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Release отдает лидерство, например при остановке сервера
+func (l *LeaderLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	l.conn.Close()
+	l.conn = nil
+	return err
+}
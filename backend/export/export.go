@@ -0,0 +1,492 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatXLSX = "xlsx"
+)
+
+const (
+	// Schema и SchemaVersion записываются в JSON-выгрузку; версия растет при
+	// несовместимых изменениях models.BoardExport
+	Schema        = "taskflow.board"
+	SchemaVersion = 1
+)
+
+// ContentTypes - Content-Type ответа для каждого формата
+var ContentTypes = map[string]string{
+	FormatCSV:  "text/csv; charset=utf-8",
+	FormatJSON: "application/json",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// taskHeader - столбцы задач в CSV и на листе Tasks. Порядок стабилен:
+// новые столбцы добавляются только в конец.
+var taskHeader = []string{
+	"id", "title", "description", "column", "status", "priority", "assignee",
+	"estimate_minutes", "story_points", "due_at", "sprint", "parent_task_id",
+	"created_by", "created_at", "updated_at", "archived_at",
+}
+
+// defaultPageSize - число задач, читаемых из хранилища за раз
+const defaultPageSize = 500
+
+// Options задает содержимое выгрузки
+type Options struct {
+	// Comments добавляет комментарии задач
+	Comments bool
+}
+
+// Board - доска, подготовленная к выгрузке. Колонки, метки и спринты
+// читаются заранее, а задачи - страницами во время записи вместе с их
+// чек-листами и комментариями, поэтому в памяти не держится выгрузка целиком.
+type Board struct {
+	repos    repository.Repositories
+	opts     Options
+	board    *models.Board
+	columns  []models.Column
+	labels   []models.Label
+	sprints  []models.Sprint
+	pageSize int
+	// usernames кэширует имена авторов задач и комментариев
+	usernames map[uuid.UUID]string
+}
+
+// taskPage - страница задач с чек-листами и комментариями
+type taskPage struct {
+	tasks      []models.Task
+	checklists map[uuid.UUID][]models.ChecklistItem
+	comments   map[uuid.UUID][]models.ExportedComment
+}
+
+// Load читает доску boardID со всеми колонками, включая архивные; задачи
+// читаются при записи. ErrNotFound, если доски нет.
+func Load(ctx context.Context, repos repository.Repositories, boardID uuid.UUID, opts Options) (*Board, error) {
+	board, err := repos.Boards.GetByID(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := repos.Columns.ListByBoard(ctx, boardID, repository.ListOptions{IncludeArchived: true})
+	if err != nil {
+		return nil, err
+	}
+	labels, err := repos.Labels.ListByBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	sprints, err := repos.Sprints.ListByBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Board{
+		repos:     repos,
+		opts:      opts,
+		board:     board,
+		columns:   nonNil(columns),
+		labels:    nonNil(labels),
+		sprints:   nonNil(sprints),
+		pageSize:  defaultPageSize,
+		usernames: make(map[uuid.UUID]string),
+	}, nil
+}
+
+// Name - имя файла выгрузки без расширения
+func (b *Board) Name() string {
+	return "board-" + b.board.ID.String()
+}
+
+// Write пишет выгрузку в формате format
+func (b *Board) Write(ctx context.Context, w io.Writer, format string) error {
+	switch format {
+	case FormatCSV:
+		return b.WriteCSV(ctx, w)
+	case FormatJSON:
+		return b.WriteJSON(ctx, w)
+	case FormatXLSX:
+		return b.WriteXLSX(ctx, w)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+// WriteJSON пишет models.BoardExport, кодируя задачи по одной
+func (b *Board) WriteJSON(ctx context.Context, w io.Writer) error {
+	board := *b.board
+	board.Tasks = nil
+	header, err := json.Marshal(models.BoardExport{
+		Schema:     Schema,
+		Version:    SchemaVersion,
+		ExportedAt: time.Now().UTC(),
+		Board:      board,
+		Columns:    b.columns,
+		Labels:     b.labels,
+		Sprints:    b.sprints,
+	})
+	if err != nil {
+		return err
+	}
+	// Tasks - последнее поле: вместо null дописываются задачи
+	header = bytes.TrimSuffix(header, []byte("null}"))
+	if _, err := w.Write(append(header, '[')); err != nil {
+		return err
+	}
+
+	first := true
+	err = b.eachPage(ctx, true, func(page *taskPage) error {
+		for _, task := range page.tasks {
+			exported := models.ExportedTask{Task: task, Checklist: nonNil(page.checklists[task.ID])}
+			if b.opts.Comments {
+				exported.Comments = nonNil(page.comments[task.ID])
+			}
+			data, err := json.Marshal(exported)
+			if err != nil {
+				return err
+			}
+			if !first {
+				data = append([]byte{','}, data...)
+			}
+			first = false
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "]}\n")
+	return err
+}
+
+// WriteCSV пишет строку на задачу; с Options.Comments добавляется столбец
+// comments с комментариями задачи по одному на строку
+func (b *Board) WriteCSV(ctx context.Context, w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(b.taskHeader()); err != nil {
+		return err
+	}
+	err := b.eachPage(ctx, false, func(page *taskPage) error {
+		for _, task := range page.tasks {
+			row, err := b.taskRow(ctx, task, page.comments[task.ID])
+			if err != nil {
+				return err
+			}
+			record := make([]string, len(row))
+			for i, value := range row {
+				record[i] = formatCell(value)
+			}
+			if err := out.Write(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteXLSX пишет книгу с листами Tasks, Columns, Labels и Sprints через
+// потоковую запись excelize, которая сбрасывает большие листы во временные файлы
+func (b *Board) WriteXLSX(ctx context.Context, w io.Writer) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", "Tasks"); err != nil {
+		return err
+	}
+	err := writeSheet(f, "Tasks", b.taskHeader(), func(write func([]any) error) error {
+		return b.eachPage(ctx, false, func(page *taskPage) error {
+			for _, task := range page.tasks {
+				row, err := b.taskRow(ctx, task, page.comments[task.ID])
+				if err != nil {
+					return err
+				}
+				if err := write(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeSheet(f, "Columns", []string{"id", "title", "status", "position", "wip_limit", "archived_at"}, func(write func([]any) error) error {
+		for _, column := range b.columns {
+			if err := write([]any{column.ID.String(), column.Title, column.StatusID, column.Position, intOrNil(column.WIPLimit), timeOrNil(column.ArchivedAt)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeSheet(f, "Labels", []string{"id", "name", "color"}, func(write func([]any) error) error {
+		for _, label := range b.labels {
+			if err := write([]any{label.ID.String(), label.Name, label.Color}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeSheet(f, "Sprints", []string{"id", "name", "goal", "state", "starts_at", "ends_at", "completed_at"}, func(write func([]any) error) error {
+		for _, sprint := range b.sprints {
+			if err := write([]any{sprint.ID.String(), sprint.Name, sprint.Goal, sprint.State, timeOrNil(sprint.StartsAt), timeOrNil(sprint.EndsAt), timeOrNil(sprint.CompletedAt)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return f.Write(w)
+}
+
+// writeSheet создает лист name, пишет заголовок и строки, которые rows
+// передает в write по одной
+func writeSheet(f *excelize.File, name string, header []string, rows func(write func([]any) error) error) error {
+	index, err := f.GetSheetIndex(name)
+	if err != nil {
+		return err
+	}
+	if index == -1 {
+		if _, err := f.NewSheet(name); err != nil {
+			return err
+		}
+	}
+	sw, err := f.NewStreamWriter(name)
+	if err != nil {
+		return err
+	}
+
+	cells := make([]any, len(header))
+	for i, title := range header {
+		cells[i] = title
+	}
+	if err := sw.SetRow("A1", cells); err != nil {
+		return err
+	}
+	next := 2
+	err = rows(func(values []any) error {
+		cell, err := excelize.CoordinatesToCellName(1, next)
+		if err != nil {
+			return err
+		}
+		next++
+		return sw.SetRow(cell, values)
+	})
+	if err != nil {
+		return err
+	}
+	return sw.Flush()
+}
+
+func (b *Board) taskHeader() []string {
+	if b.opts.Comments {
+		return append(append([]string{}, taskHeader...), "comments")
+	}
+	return taskHeader
+}
+
+// taskRow возвращает значения столбцов taskHeader; пустые значения - nil
+func (b *Board) taskRow(ctx context.Context, task models.Task, comments []models.ExportedComment) ([]any, error) {
+	var sprint, parent, createdBy any
+	for _, s := range b.sprints {
+		if task.SprintID != nil && s.ID == *task.SprintID {
+			sprint = s.Name
+		}
+	}
+	if task.ParentTaskID != nil {
+		parent = task.ParentTaskID.String()
+	}
+	if task.CreatedBy != nil {
+		name, err := b.username(ctx, *task.CreatedBy)
+		if err != nil {
+			return nil, err
+		}
+		createdBy = name
+	}
+
+	row := []any{
+		task.ID.String(), task.Title, task.Description, b.columnTitle(task.Status), task.Status,
+		stringOrNil(task.Priority), stringOrNil(task.Assignee), intOrNil(task.EstimateMinutes), intOrNil(task.StoryPoints),
+		timeOrNil(task.DueAt), sprint, parent, createdBy, task.CreatedAt.UTC(), task.UpdatedAt.UTC(), timeOrNil(task.ArchivedAt),
+	}
+	if !b.opts.Comments {
+		return row, nil
+	}
+
+	lines := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		author := comment.Author
+		if author == "" {
+			author = "automation"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s: %s", comment.CreatedAt.UTC().Format(time.RFC3339), author, comment.Body))
+	}
+	return append(row, strings.Join(lines, "\n")), nil
+}
+
+// eachPage читает задачи доски, включая архивные, страницами по
+// (created_at, id) и передает каждую страницу в fn вместе с комментариями
+// (если они выгружаются) и чек-листами (если нужны checklists). Чек-листы
+// и комментарии читаются одним запросом на страницу.
+func (b *Board) eachPage(ctx context.Context, checklists bool, fn func(page *taskPage) error) error {
+	var after *repository.TaskCursor
+	for {
+		tasks, err := b.repos.Tasks.ListPage(ctx, b.board.ID, after, b.pageSize, repository.ListOptions{IncludeArchived: true})
+		if err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+
+		page := &taskPage{tasks: tasks}
+		ids := make([]uuid.UUID, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+		if checklists {
+			if page.checklists, err = b.repos.Checklists.ListByTasks(ctx, ids); err != nil {
+				return err
+			}
+		}
+		if b.opts.Comments {
+			if page.comments, err = b.comments(ctx, ids); err != nil {
+				return err
+			}
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+
+		if len(tasks) < b.pageSize {
+			return nil
+		}
+		last := tasks[len(tasks)-1]
+		after = &repository.TaskCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// comments возвращает комментарии задач taskIDs с именами авторов
+func (b *Board) comments(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID][]models.ExportedComment, error) {
+	comments, err := b.repos.Comments.ListByTasks(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	exported := make(map[uuid.UUID][]models.ExportedComment, len(comments))
+	for taskID, taskComments := range comments {
+		for _, comment := range taskComments {
+			author := ""
+			if comment.UserID != nil {
+				if author, err = b.username(ctx, *comment.UserID); err != nil {
+					return nil, err
+				}
+			}
+			exported[taskID] = append(exported[taskID], models.ExportedComment{TaskComment: comment, Author: author})
+		}
+	}
+	return exported, nil
+}
+
+// username возвращает имя пользователя; удаленный пользователь - пустое имя
+func (b *Board) username(ctx context.Context, id uuid.UUID) (string, error) {
+	if name, ok := b.usernames[id]; ok {
+		return name, nil
+	}
+	user, err := b.repos.Users.GetByID(ctx, id)
+	if err != nil && err != repository.ErrNotFound {
+		return "", err
+	}
+	name := ""
+	if user != nil {
+		name = user.Username
+	}
+	b.usernames[id] = name
+	return name, nil
+}
+
+// columnTitle возвращает название колонки статуса; статус без колонки - пусто
+func (b *Board) columnTitle(status string) any {
+	for _, column := range b.columns {
+		if column.StatusID == status {
+			return column.Title
+		}
+	}
+	return nil
+}
+
+// formatCell переводит значение строки в текст CSV; время - в RFC3339 UTC
+func formatCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
+
+func stringOrNil(value *string) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func intOrNil(value *int) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func timeOrNil(value *time.Time) any {
+	if value == nil {
+		return nil
+	}
+	return value.UTC()
+}
+
+// nonNil заменяет nil на пустой срез, чтобы в JSON были [] вместо null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"task-flow-backend/models"
+	"task-flow-backend/repository/memory"
+	"task-flow-backend/templates"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestWritePages(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	tpl := templates.Default()
+	tpl.Tasks = nil
+	board := &models.Board{Name: "Pages"}
+	require.NoError(t, repos.CreateBoard(ctx, board, tpl))
+
+	var ids []uuid.UUID
+	for i := 0; i < 5; i++ {
+		task := &models.Task{BoardID: board.ID, Title: fmt.Sprintf("Task %d", i), Status: "plan"}
+		require.NoError(t, repos.Tasks.Create(ctx, task))
+		require.NoError(t, repos.Checklists.Create(ctx, &models.ChecklistItem{TaskID: task.ID, Title: fmt.Sprintf("Item %d", i)}))
+		require.NoError(t, repos.Comments.Create(ctx, &models.TaskComment{TaskID: task.ID, Body: fmt.Sprintf("Comment %d", i)}))
+		ids = append(ids, task.ID)
+	}
+	require.NoError(t, repos.Tasks.Archive(ctx, ids[3]))
+
+	exp, err := Load(ctx, repos, board.ID, Options{Comments: true})
+	require.NoError(t, err)
+	exp.pageSize = 2
+
+	var buf bytes.Buffer
+	require.NoError(t, exp.WriteJSON(ctx, &buf))
+	var exported models.BoardExport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &exported))
+	require.Len(t, exported.Tasks, 5, "Expected every page, including archived tasks")
+	for i, task := range exported.Tasks {
+		assert.Equal(t, ids[i], task.ID)
+		require.Len(t, task.Checklist, 1)
+		assert.Equal(t, fmt.Sprintf("Item %d", i), task.Checklist[0].Title)
+		require.Len(t, task.Comments, 1)
+		assert.Equal(t, fmt.Sprintf("Comment %d", i), task.Comments[0].Body)
+	}
+
+	buf.Reset()
+	require.NoError(t, exp.WriteCSV(ctx, &buf))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 6)
+	for i, record := range records[1:] {
+		assert.Equal(t, ids[i].String(), record[0])
+		assert.Contains(t, record[len(record)-1], fmt.Sprintf("Comment %d", i))
+	}
+
+	buf.Reset()
+	require.NoError(t, exp.WriteXLSX(ctx, &buf))
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err := f.GetRows("Tasks")
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+	assert.Equal(t, ids[4].String(), rows[5][0])
+}
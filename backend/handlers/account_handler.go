@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"task-flow-backend/logging"
+	"task-flow-backend/mail"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/templates"
+	"time"
+)
+
+// DefaultAppURL - адрес фронтенда для ссылок в письмах, если он не задан
+const DefaultAppURL = "http://localhost:5173"
+
+// minPasswordLength - минимальная длина пароля при регистрации и сбросе
+const minPasswordLength = 6
+
+// mailTimeout ограничивает выдачу токена и отправку одного письма
+const mailTimeout = 30 * time.Second
+
+// accountMails - письма со ссылками на страницы фронтенда по целям токенов
+var accountMails = map[string]struct {
+	template string
+	ttl      time.Duration
+	path     string
+}{
+	models.UserTokenVerifyEmail:   {mail.TemplateVerifyEmail, 48 * time.Hour, "/verify-email"},
+	models.UserTokenResetPassword: {mail.TemplateResetPassword, time.Hour, "/reset-password"},
+}
+
+// VerifyEmail подтверждает email по токену из письма и возвращает пользователя
+func (s *Server) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req models.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := s.repos.VerifyEmail(r.Context(), req.Token)
+	if errors.Is(err, repository.ErrNotFound) {
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// ResendVerification повторно отправляет письмо подтверждения. Ответ всегда
+// 202, чтобы по нему нельзя было узнать, зарегистрирован ли адрес.
+func (s *Server) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.userByEmail(w, r)
+	if !ok {
+		return
+	}
+	if user != nil && user.EmailVerifiedAt == nil {
+		s.sendAccountMail(r, user, models.UserTokenVerifyEmail)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ForgotPassword отправляет ссылку сброса пароля. Как и ResendVerification,
+// отвечает 202 независимо от того, есть ли такой пользователь.
+func (s *Server) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.userByEmail(w, r)
+	if !ok {
+		return
+	}
+	if user != nil && user.Status != models.UserStatusDisabled {
+		s.sendAccountMail(r, user, models.UserTokenResetPassword)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword задает новый пароль по токену из письма; выданные JWT отзываются
+func (s *Server) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.Password) < minPasswordLength {
+		http.Error(w, "Password must be at least 6 characters", http.StatusBadRequest)
+		return
+	}
+
+	user, err := s.repos.ResetPassword(r.Context(), req.Token, req.Password)
+	if errors.Is(err, repository.ErrNotFound) {
+		http.Error(w, "Invalid or expired token", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	logging.FromContext(r.Context()).Info("Password reset by email", "reset_user_id", user.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userByEmail читает EmailRequest и ищет пользователя; nil без ошибки - адрес не найден
+func (s *Server) userByEmail(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	var req models.EmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Email) == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+
+	user, err := s.repos.Users.GetByEmail(r.Context(), strings.TrimSpace(req.Email))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, true
+	}
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return user, true
+}
+
+// sendAccountMail выдает токен purpose и отправляет письмо со ссылкой в фоне,
+// чтобы время ответа не выдавало, существует ли адрес. Язык письма берется
+// из Accept-Language запроса.
+func (s *Server) sendAccountMail(r *http.Request, user *models.User, purpose string) {
+	if s.mail == nil {
+		return
+	}
+	locale := templates.Locale(r.Header.Get("Accept-Language"))
+	ctx := context.WithoutCancel(r.Context())
+	recipient := *user
+
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, mailTimeout)
+		defer cancel()
+		if err := s.deliverAccountMail(ctx, &recipient, purpose, locale); err != nil {
+			logging.FromContext(ctx).Error("Failed to send account email", "purpose", purpose, "recipient_id", recipient.ID, "error", err)
+		}
+	}()
+}
+
+func (s *Server) deliverAccountMail(ctx context.Context, user *models.User, purpose, locale string) error {
+	kind := accountMails[purpose]
+	token, err := s.repos.IssueUserToken(ctx, user.ID, purpose, kind.ttl)
+	if err != nil {
+		return err
+	}
+
+	msg, err := mail.Render(kind.template, locale, mail.Data{
+		Username: user.Username,
+		Link:     strings.TrimRight(s.appURL, "/") + kind.path + "?token=" + url.QueryEscape(token),
+		Hours:    int(kind.ttl / time.Hour),
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = user.Email
+	return s.mail.Send(ctx, msg)
+}
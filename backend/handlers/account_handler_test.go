@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"task-flow-backend/mail"
+	"task-flow-backend/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mailbox - mail.Sender, складывающий письма в канал
+type mailbox chan mail.Message
+
+func (m mailbox) Send(ctx context.Context, msg mail.Message) error {
+	m <- msg
+	return nil
+}
+
+var linkPattern = regexp.MustCompile(`https?://\S+`)
+
+// receiveToken ждет письмо для to и возвращает токен из ссылки в нем
+func (m mailbox) receiveToken(t *testing.T, to, path string) string {
+	t.Helper()
+	select {
+	case msg := <-m:
+		assert.Equal(t, to, msg.To)
+		link, err := url.Parse(linkPattern.FindString(msg.Text))
+		require.NoError(t, err)
+		assert.Equal(t, path, link.Path)
+		return link.Query().Get("token")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected an email to %s", to)
+		return ""
+	}
+}
+
+func TestEmailVerification(t *testing.T) {
+	box := make(mailbox, 4)
+	_, router := newTestServer(t, WithMail(box, "https://tf.example.com/"), WithEmailVerification(true))
+
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, err := http.NewRequest("POST", path, bytes.NewReader(data))
+		require.NoError(t, err)
+		req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	login := models.LoginRequest{Username: "newbie", Password: "secret123"}
+
+	rr := post("/api/auth/register", models.RegisterRequest{Username: "newbie", Email: "newbie@test.com", Password: "secret123"})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var resp models.AuthResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Token, "Expected no token before the email is verified")
+	assert.True(t, resp.VerificationRequired)
+
+	first := box.receiveToken(t, "newbie@test.com", "/verify-email")
+	assert.Equal(t, http.StatusForbidden, post("/api/auth/login", login).Code)
+
+	assert.Equal(t, http.StatusAccepted, post("/api/auth/verify-email/resend", models.EmailRequest{Email: "newbie@test.com"}).Code)
+	token := box.receiveToken(t, "newbie@test.com", "/verify-email")
+	assert.Equal(t, http.StatusBadRequest, post("/api/auth/verify-email", models.VerifyEmailRequest{Token: first}).Code,
+		"Expected a resent email to invalidate the previous link")
+
+	rr = post("/api/auth/verify-email", models.VerifyEmailRequest{Token: token})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var user models.User
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &user))
+	assert.NotNil(t, user.EmailVerifiedAt)
+	assert.Equal(t, http.StatusBadRequest, post("/api/auth/verify-email", models.VerifyEmailRequest{Token: token}).Code)
+
+	rr = post("/api/auth/login", login)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	// Для подтвержденного адреса повторное письмо не отправляется
+	assert.Equal(t, http.StatusAccepted, post("/api/auth/verify-email/resend", models.EmailRequest{Email: "newbie@test.com"}).Code)
+	select {
+	case msg := <-box:
+		t.Fatalf("Unexpected email %q", msg.Subject)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	box := make(mailbox, 2)
+	server, router := newTestServer(t, WithMail(box, ""))
+	userID := createTestUser(t, server)
+
+	post := func(path string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, err := http.NewRequest("POST", path, bytes.NewReader(data))
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	me := func() int {
+		req, err := http.NewRequest("GET", "/api/auth/me", nil)
+		require.NoError(t, err)
+		authorize(t, req, userID)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	require.Equal(t, http.StatusOK, me())
+
+	assert.Equal(t, http.StatusAccepted, post("/api/auth/forgot-password", models.EmailRequest{Email: "nobody@test.com"}).Code,
+		"Expected unknown addresses to get the same response")
+	assert.Equal(t, http.StatusAccepted, post("/api/auth/forgot-password", models.EmailRequest{Email: "testuser@test.com"}).Code)
+	token := box.receiveToken(t, "testuser@test.com", "/reset-password")
+
+	assert.Equal(t, http.StatusBadRequest, post("/api/auth/reset-password", models.ResetPasswordRequest{Token: token, Password: "123"}).Code)
+	assert.Equal(t, http.StatusBadRequest, post("/api/auth/reset-password", models.ResetPasswordRequest{Token: "forged", Password: "newpass123"}).Code)
+	require.Equal(t, http.StatusNoContent, post("/api/auth/reset-password", models.ResetPasswordRequest{Token: token, Password: "newpass123"}).Code)
+	assert.Equal(t, http.StatusBadRequest, post("/api/auth/reset-password", models.ResetPasswordRequest{Token: token, Password: "other123"}).Code)
+
+	assert.Equal(t, http.StatusUnauthorized, me(), "Expected the reset to revoke issued tokens")
+	assert.Equal(t, http.StatusUnauthorized, post("/api/auth/login", models.LoginRequest{Username: "testuser", Password: "testpass123"}).Code)
+	assert.Equal(t, http.StatusOK, post("/api/auth/login", models.LoginRequest{Username: "testuser", Password: "newpass123"}).Code)
+
+	select {
+	case msg := <-box:
+		t.Fatalf("Unexpected email %q", msg.Subject)
+	default:
+	}
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"task-flow-backend/logging"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListUsers возвращает учетные записи по имени; ?role= и ?status= фильтруют список
+func (s *Server) ListUsers(w http.ResponseWriter, r *http.Request) {
+	role, status := r.URL.Query().Get("role"), r.URL.Query().Get("status")
+	if role != "" && !validUserRole(role) {
+		http.Error(w, "role must be user or admin", http.StatusBadRequest)
+		return
+	}
+	if status != "" && !validUserStatus(status) {
+		http.Error(w, "status must be active, disabled or locked", http.StatusBadRequest)
+		return
+	}
+
+	users, err := s.repos.Users.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filtered := []models.User{}
+	for _, user := range users {
+		if (role == "" || user.Role == role) && (status == "" || user.Status == status) {
+			filtered = append(filtered, user)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(filtered)
+}
+
+func (s *Server) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.userFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// UpdateUser меняет email, роль и состояние учетной записи. Блокировка
+// (disabled или locked) отзывает токены пользователя. Свои роль и состояние
+// администратор менять не может, а последнего администратора нельзя
+// понизить или заблокировать, поэтому без администратора система не останется.
+func (s *Server) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.userFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Email != nil {
+		user.Email = strings.TrimSpace(*req.Email)
+		if user.Email == "" {
+			http.Error(w, "email cannot be empty", http.StatusBadRequest)
+			return
+		}
+	}
+	if req.Role != nil {
+		if !validUserRole(*req.Role) {
+			http.Error(w, "role must be user or admin", http.StatusBadRequest)
+			return
+		}
+		user.Role = *req.Role
+	}
+	if req.Status != nil {
+		if !validUserStatus(*req.Status) {
+			http.Error(w, "status must be active, disabled or locked", http.StatusBadRequest)
+			return
+		}
+		user.Status = *req.Status
+		user.LockedUntil = nil
+	}
+	if req.LockedUntil != nil {
+		if user.Status != models.UserStatusLocked {
+			http.Error(w, "locked_until requires status locked", http.StatusBadRequest)
+			return
+		}
+		user.LockedUntil = req.LockedUntil
+	}
+
+	if currentID, _ := userIDFromContext(r.Context()); user.ID == currentID &&
+		(user.Role != models.UserRoleAdmin || user.Status != models.UserStatusActive) {
+		http.Error(w, "Admins cannot demote, disable or lock themselves", http.StatusBadRequest)
+		return
+	}
+
+	revoke := req.Status != nil && *req.Status != models.UserStatusActive
+	err := s.repos.Tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		if user.Role != models.UserRoleAdmin || user.Status == models.UserStatusDisabled {
+			if err := s.repos.RequireOtherAdmin(ctx, user.ID); err != nil {
+				return err
+			}
+		}
+		if err := s.repos.Users.Update(ctx, user); err != nil {
+			return err
+		}
+		if revoke {
+			return s.repos.Users.RevokeTokens(ctx, user.ID)
+		}
+		return nil
+	})
+	if errors.Is(err, repository.ErrLastAdmin) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, repository.ErrConflict) {
+		http.Error(w, "Email already exists", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeRepoError(w, err, "User not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// DeleteUser удаляет чужую учетную запись. ?transfer_to= передает созданные
+// пользователем доски другому пользователю, иначе они остаются без владельца.
+func (s *Server) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.userFromPath(w, r)
+	if !ok {
+		return
+	}
+	if currentID, _ := userIDFromContext(r.Context()); user.ID == currentID {
+		http.Error(w, "Use DELETE /api/auth/me to delete your own account", http.StatusBadRequest)
+		return
+	}
+
+	var heirID *uuid.UUID
+	if value := r.URL.Query().Get("transfer_to"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil || id == user.ID {
+			http.Error(w, "Invalid transfer_to user ID", http.StatusBadRequest)
+			return
+		}
+		if _, err := s.repos.Users.GetByID(r.Context(), id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				http.Error(w, "transfer_to user not found", http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		heirID = &id
+	}
+
+	err := s.repos.Tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		if err := s.repos.RequireOtherAdmin(ctx, user.ID); err != nil {
+			return err
+		}
+		return s.repos.DeleteUser(ctx, user.ID, heirID)
+	})
+	if errors.Is(err, repository.ErrLastAdmin) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeRepoError(w, err, "User not found")
+		return
+	}
+
+	logging.FromContext(r.Context()).Info("User deleted by admin", "deleted_user_id", user.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeUserTokens завершает все сеансы пользователя
+func (s *Server) RevokeUserTokens(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.userFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.repos.Users.RevokeTokens(r.Context(), user.ID); err != nil {
+		writeRepoError(w, err, "User not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ExportUser выгружает данные пользователя по его запросу, переданному администратору
+func (s *Server) ExportUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := s.userFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	s.writeUserExport(w, r, user.ID)
+}
+
+func (s *Server) userFromPath(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	user, err := s.repos.Users.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "User not found")
+		return nil, false
+	}
+	return user, true
+}
+
+func validUserRole(role string) bool {
+	return role == models.UserRoleUser || role == models.UserRoleAdmin
+}
+
+func validUserStatus(status string) bool {
+	switch status {
+	case models.UserStatusActive, models.UserStatusDisabled, models.UserStatusLocked:
+		return true
+	}
+	return false
+}
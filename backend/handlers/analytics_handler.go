@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// defaultAnalyticsPeriod - период аналитики без from
+	defaultAnalyticsPeriod = 30 * 24 * time.Hour
+	// maxAnalyticsPeriod ограничивает размер накопительной диаграммы
+	maxAnalyticsPeriod = 366 * 24 * time.Hour
+)
+
+// GetBoardAnalytics возвращает метрики потока доски: накопительную
+// диаграмму по дням, время выполнения и цикла, пропускную способность по
+// неделям и возраст задач в работе. Период задается from и to, как в
+// отчете по времени (по умолчанию - последние 30 дней), колонки - start_status
+// и done_status. С ?format=csv отдает раздел section: cumulative_flow (по
+// умолчанию), completed, throughput или aging_wip.
+func (s *Server) GetBoardAnalytics(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	now := time.Now().UTC()
+	opts := repository.AnalyticsOptions{
+		To:          now,
+		StartStatus: query.Get("start_status"),
+		DoneStatus:  query.Get("done_status"),
+		Now:         now,
+	}
+	from, err := parseReportTime(query.Get("from"), false)
+	if err != nil {
+		http.Error(w, "Invalid from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := parseReportTime(query.Get("to"), true)
+	if err != nil {
+		http.Error(w, "Invalid to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if to != nil {
+		opts.To = to.UTC()
+	}
+	opts.From = opts.To.Add(-defaultAnalyticsPeriod)
+	if from != nil {
+		opts.From = from.UTC()
+	}
+	if !opts.To.After(opts.From) {
+		http.Error(w, "to must be after from", http.StatusBadRequest)
+		return
+	}
+	if opts.To.Sub(opts.From) > maxAnalyticsPeriod {
+		http.Error(w, "Period must not exceed 366 days", http.StatusBadRequest)
+		return
+	}
+
+	section := query.Get("section")
+	csvFormat := query.Get("format") == "csv" || strings.Contains(r.Header.Get("Accept"), "text/csv")
+	if csvFormat && section == "" {
+		section = "cumulative_flow"
+	}
+	switch section {
+	case "", "cumulative_flow", "completed", "throughput", "aging_wip":
+	default:
+		http.Error(w, "section must be cumulative_flow, completed, throughput or aging_wip", http.StatusBadRequest)
+		return
+	}
+
+	analytics, err := s.repos.BoardAnalytics(r.Context(), boardID, opts)
+	if errors.Is(err, repository.ErrUnknownStatus) || errors.Is(err, repository.ErrAnalyticsColumns) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	if csvFormat {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="analytics-%s.csv"`, section))
+		writeAnalyticsCSV(csv.NewWriter(w), analytics, section)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(analytics)
+}
+
+// writeAnalyticsCSV пишет раздел аналитики: накопительная диаграмма - строка
+// на день со столбцом на каждый статус доски
+func writeAnalyticsCSV(out *csv.Writer, analytics *models.BoardAnalytics, section string) {
+	formatDays := func(value float64) string { return strconv.FormatFloat(value, 'f', 2, 64) }
+
+	switch section {
+	case "cumulative_flow":
+		out.Write(append([]string{"date"}, analytics.Statuses...))
+		for _, day := range analytics.CumulativeFlow {
+			row := []string{day.Date}
+			for _, status := range analytics.Statuses {
+				row = append(row, strconv.Itoa(day.Counts[status]))
+			}
+			out.Write(row)
+		}
+	case "completed":
+		out.Write([]string{"task_id", "title", "created_at", "started_at", "done_at", "lead_time_days", "cycle_time_days"})
+		for _, task := range analytics.Completed {
+			out.Write([]string{
+				task.TaskID.String(), task.Title, task.CreatedAt.UTC().Format(time.RFC3339),
+				task.StartedAt.UTC().Format(time.RFC3339), task.DoneAt.UTC().Format(time.RFC3339),
+				formatDays(task.LeadTimeDays), formatDays(task.CycleTimeDays),
+			})
+		}
+	case "throughput":
+		out.Write([]string{"week_start", "count"})
+		for _, week := range analytics.Throughput {
+			out.Write([]string{week.WeekStart, strconv.Itoa(week.Count)})
+		}
+	case "aging_wip":
+		out.Write([]string{"task_id", "title", "status", "started_at", "age_days"})
+		for _, task := range analytics.AgingWIP {
+			out.Write([]string{
+				task.TaskID.String(), task.Title, task.Status,
+				task.StartedAt.UTC().Format(time.RFC3339), formatDays(task.AgeDays),
+			})
+		}
+	}
+	out.Flush()
+}
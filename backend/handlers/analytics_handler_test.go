@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task-flow-backend/models"
+	"task-flow-backend/templates"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardAnalytics(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+	ctx := context.Background()
+
+	tpl := templates.Default()
+	tpl.Tasks = nil
+	board := &models.Board{Name: "Analytics", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(ctx, board, tpl))
+
+	done := &models.Task{BoardID: board.ID, Title: "Shipped", Status: "plan"}
+	wip := &models.Task{BoardID: board.ID, Title: "In progress", Status: "plan"}
+	waiting := &models.Task{BoardID: board.ID, Title: "Waiting", Status: "plan"}
+	for _, task := range []*models.Task{done, wip, waiting} {
+		require.NoError(t, server.repos.Tasks.Create(ctx, task))
+	}
+	for _, status := range []string{"analysis", "development", "closed"} {
+		require.NoError(t, server.repos.Tasks.Move(ctx, done.ID, status))
+	}
+	require.NoError(t, server.repos.Tasks.Move(ctx, wip.ID, "development"))
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "/api/boards/"+board.ID.String()+"/analytics"+query, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := get("")
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var analytics models.BoardAnalytics
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &analytics))
+
+	assert.Equal(t, "analysis", analytics.StartStatus)
+	assert.Equal(t, "closed", analytics.DoneStatus)
+	assert.Equal(t, []string{"plan", "analysis", "development", "testing", "closed"}, analytics.Statuses)
+	require.NotEmpty(t, analytics.CumulativeFlow)
+	today := analytics.CumulativeFlow[len(analytics.CumulativeFlow)-1]
+	assert.Equal(t, time.Now().UTC().Format(time.DateOnly), today.Date)
+	assert.Equal(t, map[string]int{"plan": 1, "analysis": 0, "development": 1, "testing": 0, "closed": 1}, today.Counts)
+
+	assert.Equal(t, 1, analytics.LeadTime.Count)
+	assert.Equal(t, 1, analytics.CycleTime.Count)
+	require.Len(t, analytics.Completed, 1)
+	assert.Equal(t, done.ID, analytics.Completed[0].TaskID)
+	total := 0
+	for _, week := range analytics.Throughput {
+		total += week.Count
+	}
+	assert.Equal(t, 1, total)
+	require.Len(t, analytics.AgingWIP, 1)
+	assert.Equal(t, wip.ID, analytics.AgingWIP[0].TaskID)
+
+	// Начало работы в testing исключает задачу в development из WIP
+	rr = get("?start_status=testing")
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &analytics))
+	assert.Empty(t, analytics.AgingWIP)
+
+	rr = get("?format=csv")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	records, err := csv.NewReader(strings.NewReader(rr.Body.String())).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"date", "plan", "analysis", "development", "testing", "closed"}, records[0])
+	assert.Equal(t, []string{today.Date, "1", "0", "1", "0", "1"}, records[len(records)-1])
+
+	rr = get("?format=csv&section=completed")
+	require.Equal(t, http.StatusOK, rr.Code)
+	records, err = csv.NewReader(strings.NewReader(rr.Body.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "Shipped", records[1][1])
+
+	cases := map[string]string{
+		"unknown column":  "?done_status=nope",
+		"reversed":        "?start_status=closed&done_status=analysis",
+		"bad section":     "?format=csv&section=nope",
+		"reversed period": "?from=2026-03-10&to=2026-03-01",
+		"long period":     "?from=2024-01-01&to=2026-01-01",
+	}
+	for name, query := range cases {
+		assert.Equal(t, http.StatusBadRequest, get(query).Code, name)
+	}
+
+	req, err := http.NewRequest("GET", "/api/boards/"+uuid.NewString()+"/analytics", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
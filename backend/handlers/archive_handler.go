@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) ArchiveTask(w http.ResponseWriter, r *http.Request) {
+	s.setTaskArchived(w, r, true)
+}
+
+func (s *Server) UnarchiveTask(w http.ResponseWriter, r *http.Request) {
+	s.setTaskArchived(w, r, false)
+}
+
+func (s *Server) setTaskArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	eventType := "task_unarchived"
+	change := s.repos.Tasks.Unarchive
+	if archived {
+		eventType = "task_archived"
+		change = s.repos.Tasks.Archive
+	}
+
+	// Повторная архивация (или разархивация) считается отсутствием задачи в нужном состоянии
+	if err := change(r.Context(), id); err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+
+	task, err := s.repos.Tasks.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+
+	s.invalidateTasksCache(r.Context(), task.BoardID)
+	s.broadcast(task.BoardID.String(), eventType, task)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+func (s *Server) ArchiveColumn(w http.ResponseWriter, r *http.Request) {
+	s.setColumnArchived(w, r, true)
+}
+
+func (s *Server) UnarchiveColumn(w http.ResponseWriter, r *http.Request) {
+	s.setColumnArchived(w, r, false)
+}
+
+func (s *Server) setColumnArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid column ID", http.StatusBadRequest)
+		return
+	}
+
+	eventType := "column_unarchived"
+	change := s.repos.UnarchiveColumn
+	if archived {
+		eventType = "column_archived"
+		change = s.repos.ArchiveColumn
+	}
+
+	column, taskIDs, err := change(r.Context(), id)
+	if errors.Is(err, repository.ErrConflict) {
+		if archived {
+			http.Error(w, "Column is already archived", http.StatusConflict)
+		} else {
+			http.Error(w, "Column is not archived", http.StatusConflict)
+		}
+		return
+	}
+	if err != nil {
+		writeRepoError(w, err, "Column not found")
+		return
+	}
+
+	if len(taskIDs) > 0 {
+		s.invalidateTasksCache(r.Context(), column.BoardID)
+	}
+	s.broadcast(column.BoardID.String(), eventType, map[string]interface{}{
+		"column":   column,
+		"task_ids": taskIDs,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(column)
+}
+
+func (s *Server) ArchiveDoneTasks(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ArchiveDoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.OlderThanDays < 0 {
+		http.Error(w, "older_than_days must not be negative", http.StatusBadRequest)
+		return
+	}
+
+	olderThan := time.Duration(req.OlderThanDays) * 24 * time.Hour
+	taskIDs, err := s.repos.ArchiveDone(r.Context(), boardID, req.Status, olderThan)
+	if errors.Is(err, repository.ErrUnknownStatus) {
+		http.Error(w, "status does not match any column of the board", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	if len(taskIDs) > 0 {
+		s.invalidateTasksCache(r.Context(), boardID)
+		s.broadcast(boardID.String(), "tasks_archived", map[string]interface{}{"task_ids": taskIDs})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"archived": len(taskIDs)})
+}
+
+func (s *Server) GetArchiveRules(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.repos.Boards.GetByID(r.Context(), boardID); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	rules, err := s.repos.ArchiveRules.ListByBoard(r.Context(), boardID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rules == nil {
+		rules = []models.ArchiveRule{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// UpdateArchiveRules заменяет все правила автоархивации доски
+func (s *Server) UpdateArchiveRules(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	var rules []models.ArchiveRule
+	if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	for i := range rules {
+		if rules[i].Status == "" {
+			http.Error(w, "status is required", http.StatusBadRequest)
+			return
+		}
+		if rules[i].AfterDays < 0 {
+			http.Error(w, "after_days must not be negative", http.StatusBadRequest)
+			return
+		}
+		rules[i].BoardID = boardID
+	}
+
+	if _, err := s.repos.Boards.GetByID(r.Context(), boardID); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	if err := s.repos.ArchiveRules.Replace(r.Context(), boardID, rules); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			http.Error(w, "Duplicate status in archive rules", http.StatusConflict)
+			return
+		}
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+	if rules == nil {
+		rules = []models.ArchiveRule{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"task-flow-backend/models"
+	"task-flow-backend/templates"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	hub := &recordingHub{}
+	server, router := newTestServer(t, WithHub(hub))
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Release", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+	task := &models.Task{BoardID: board.ID, Title: "Shipped", Status: "closed"}
+	require.NoError(t, server.repos.Tasks.Create(context.Background(), task))
+
+	listTasks := func(t *testing.T, query string) []models.Task {
+		t.Helper()
+		rr := doRequest(t, router, userID, "GET", "/api/tasks?board_id="+board.ID.String()+query, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var tasks []models.Task
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &tasks))
+		return tasks
+	}
+
+	t.Run("Archived task is hidden from listings", func(t *testing.T) {
+		require.Len(t, listTasks(t, ""), 1)
+
+		hub.events = nil
+		rr := doRequest(t, router, userID, "POST", "/api/tasks/"+task.ID.String()+"/archive", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []recordedEvent{{boardID: board.ID.String(), eventType: "task_archived"}}, hub.events)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "POST", "/api/tasks/"+task.ID.String()+"/archive", "").Code)
+
+		assert.Empty(t, listTasks(t, ""))
+		assert.Len(t, listTasks(t, "&include_archived=true"), 1)
+
+		require.Equal(t, http.StatusOK, doRequest(t, router, userID, "POST", "/api/tasks/"+task.ID.String()+"/unarchive", "").Code)
+		assert.Len(t, listTasks(t, ""), 1)
+	})
+
+	t.Run("Archive done tasks", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", "/api/boards/"+board.ID.String()+"/archive-done", `{"older_than_days":-1}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		rr = doRequest(t, router, userID, "POST", "/api/boards/"+board.ID.String()+"/archive-done", `{"status":"unknown"}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		rr = doRequest(t, router, userID, "POST", "/api/boards/"+board.ID.String()+"/archive-done", `{"older_than_days":7}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"archived":0}`, rr.Body.String(), "Recently updated tasks stay on the board")
+	})
+
+	t.Run("Archive rules", func(t *testing.T) {
+		path := "/api/boards/" + board.ID.String() + "/archive-rules"
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "PUT", path, `[{"status":"closed","after_days":-1}]`).Code)
+		assert.Equal(t, http.StatusConflict, doRequest(t, router, userID, "PUT", path, `[{"status":"closed","after_days":1},{"status":"closed","after_days":2}]`).Code)
+		require.Equal(t, http.StatusOK, doRequest(t, router, userID, "PUT", path, `[{"status":"closed","after_days":14}]`).Code)
+
+		rr := doRequest(t, router, userID, "GET", path, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var rules []models.ArchiveRule
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &rules))
+		require.Len(t, rules, 1)
+		assert.Equal(t, 14, rules[0].AfterDays)
+	})
+}
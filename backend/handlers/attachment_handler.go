@@ -0,0 +1,386 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"task-flow-backend/logging"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/storage"
+	"task-flow-backend/thumbnail"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// AttachmentLimits ограничивают загружаемые вложения
+type AttachmentLimits struct {
+	// MaxSize - наибольший размер файла в байтах
+	MaxSize int64
+	// AllowedTypes - разрешенные MIME-типы; "image/*" разрешает все изображения
+	AllowedTypes []string
+}
+
+var DefaultAttachmentLimits = AttachmentLimits{
+	MaxSize: 10 << 20,
+	AllowedTypes: []string{
+		"image/*",
+		"text/plain",
+		"application/pdf",
+		"application/zip",
+		"application/x-gzip",
+		"video/mp4",
+		"video/webm",
+	},
+}
+
+// AttachmentLimitsFromEnv читает ATTACHMENT_MAX_SIZE (байты) и
+// ATTACHMENT_ALLOWED_TYPES (через запятую); пустые значения заменяются значениями по умолчанию.
+func AttachmentLimitsFromEnv() AttachmentLimits {
+	limits := DefaultAttachmentLimits
+	if value := os.Getenv("ATTACHMENT_MAX_SIZE"); value != "" {
+		if size, err := strconv.ParseInt(value, 10, 64); err == nil && size > 0 {
+			limits.MaxSize = size
+		}
+	}
+	if value := os.Getenv("ATTACHMENT_ALLOWED_TYPES"); value != "" {
+		limits.AllowedTypes = nil
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				limits.AllowedTypes = append(limits.AllowedTypes, t)
+			}
+		}
+	}
+	return limits
+}
+
+// allowed проверяет тип содержимого без параметров (charset и т.п.)
+func (l AttachmentLimits) allowed(mediaType string) bool {
+	for _, t := range l.AllowedTypes {
+		if t == mediaType || (strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(t, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) GetAttachments(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	attachments, err := s.repos.Attachments.ListByTask(r.Context(), task.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if attachments == nil {
+		attachments = []models.Attachment{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attachments)
+}
+
+// UploadAttachment принимает multipart/form-data с файлом в поле "file".
+// Тип содержимого определяется по самому файлу, а не по заголовкам клиента.
+func (s *Server) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	if !s.requireStorage(w) {
+		return
+	}
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	// Запас на заголовки multipart сверх размера файла
+	r.Body = http.MaxBytesReader(w, r.Body, s.attachmentLimits.MaxSize+1<<20)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Expected multipart/form-data request", http.StatusBadRequest)
+		return
+	}
+
+	var part io.Reader
+	var filename string
+	for {
+		p, err := reader.NextPart()
+		if err != nil {
+			writeUploadError(w, err, "Missing file field")
+			return
+		}
+		if p.FormName() == "file" {
+			part, filename = p, p.FileName()
+			break
+		}
+	}
+
+	tmp, err := os.CreateTemp("", "taskflow-upload-*")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(part, s.attachmentLimits.MaxSize+1))
+	if err != nil {
+		writeUploadError(w, err, "Invalid multipart body")
+		return
+	}
+	if size > s.attachmentLimits.MaxSize {
+		http.Error(w, "File exceeds maximum size of "+strconv.FormatInt(s.attachmentLimits.MaxSize, 10)+" bytes", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if size == 0 {
+		http.Error(w, "File is empty", http.StatusBadRequest)
+		return
+	}
+
+	head := make([]byte, 512)
+	n, _ := tmp.ReadAt(head, 0)
+	contentType := http.DetectContentType(head[:n])
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !s.attachmentLimits.allowed(mediaType) {
+		http.Error(w, "File type "+mediaType+" is not allowed", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	attachment := &models.Attachment{
+		TaskID:      task.ID,
+		Filename:    cleanFilename(filename),
+		ContentType: contentType,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+	}
+	if userID, ok := userIDFromContext(r.Context()); ok {
+		attachment.UploadedBy = &userID
+	}
+
+	if err := s.saveAttachment(r.Context(), attachment, tmp, size, mediaType); err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+
+	s.broadcast(task.BoardID.String(), "attachment_created", attachment)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// saveAttachment записывает файл в хранилище, только если такого содержимого
+// еще нет, и сохраняет вложение
+func (s *Server) saveAttachment(ctx context.Context, attachment *models.Attachment, file *os.File, size int64, mediaType string) error {
+	var blob *models.AttachmentBlob
+	_, err := s.repos.Attachments.GetBlob(ctx, attachment.Checksum)
+	if errors.Is(err, repository.ErrNotFound) {
+		blob, err = s.storeBlob(ctx, attachment.Checksum, file, size, mediaType)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.repos.AddAttachment(ctx, attachment, blob)
+	if errors.Is(err, repository.ErrNotFound) && blob == nil {
+		// Файл удалили как неиспользуемый между проверкой и сохранением - записываем заново
+		if blob, err = s.storeBlob(ctx, attachment.Checksum, file, size, mediaType); err != nil {
+			return err
+		}
+		err = s.repos.AddAttachment(ctx, attachment, blob)
+	}
+	return err
+}
+
+// storeBlob записывает файл и, для изображений, его миниатюру
+func (s *Server) storeBlob(ctx context.Context, checksum string, file *os.File, size int64, mediaType string) (*models.AttachmentBlob, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := s.storage.Put(ctx, storage.BlobKey(checksum), file, mediaType); err != nil {
+		return nil, err
+	}
+	blob := &models.AttachmentBlob{Checksum: checksum, Size: size}
+
+	if thumbnail.Supported(mediaType) {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		data, err := thumbnail.Generate(file)
+		if err != nil {
+			// Вложение сохраняется и без миниатюры
+			logging.FromContext(ctx).Warn("Failed to generate thumbnail", "checksum", checksum, "error", err)
+			return blob, nil
+		}
+		if err := s.storage.Put(ctx, storage.ThumbnailKey(checksum), bytes.NewReader(data), "image/png"); err != nil {
+			return nil, err
+		}
+		blob.HasThumbnail = true
+	}
+	return blob, nil
+}
+
+// DownloadAttachment отдает файл с исходным именем в Content-Disposition
+func (s *Server) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	attachment, ok := s.attachmentFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	disposition := "attachment"
+	if r.URL.Query().Get("inline") == "true" && strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	s.serveObject(w, r, attachment, storage.BlobKey(attachment.Checksum), attachment.ContentType)
+}
+
+func (s *Server) GetAttachmentThumbnail(w http.ResponseWriter, r *http.Request) {
+	attachment, ok := s.attachmentFromPath(w, r)
+	if !ok {
+		return
+	}
+	if !attachment.HasThumbnail {
+		http.Error(w, "Thumbnail not available", http.StatusNotFound)
+		return
+	}
+
+	s.serveObject(w, r, attachment, storage.ThumbnailKey(attachment.Checksum), "image/png")
+}
+
+// serveObject копирует объект хранилища в ответ. Содержимое неизменно для
+// контрольной суммы, поэтому она служит ETag.
+func (s *Server) serveObject(w http.ResponseWriter, r *http.Request, attachment *models.Attachment, key, contentType string) {
+	etag := `"` + attachment.Checksum + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	body, err := s.storage.Get(r.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, "Attachment content not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	if _, err := io.Copy(w, body); err != nil {
+		logging.FromContext(r.Context()).Warn("Failed to send attachment", "attachment_id", attachment.ID, "error", err)
+	}
+}
+
+// DeleteAttachment удаляет вложение; файл удаляется из хранилища, если
+// на него больше не ссылаются другие вложения
+func (s *Server) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	attachment, ok := s.attachmentFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.repos.Attachments.Delete(r.Context(), attachment.ID); err != nil {
+		writeRepoError(w, err, "Attachment not found")
+		return
+	}
+
+	released, err := s.repos.ReleaseBlob(r.Context(), attachment.Checksum)
+	if err == nil && released {
+		err = storage.RemoveBlob(r.Context(), s.storage, attachment.Checksum)
+	}
+	if err != nil {
+		logging.FromContext(r.Context()).Warn("Failed to remove attachment content", "checksum", attachment.Checksum, "error", err)
+	}
+
+	if task, err := s.repos.Tasks.GetByID(r.Context(), attachment.TaskID); err == nil {
+		s.broadcast(task.BoardID.String(), "attachment_deleted", map[string]string{"id": attachment.ID.String(), "task_id": task.ID.String()})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// attachmentFromPath читает вложение задачи из пути и при ошибке сам пишет ответ
+func (s *Server) attachmentFromPath(w http.ResponseWriter, r *http.Request) (*models.Attachment, bool) {
+	if !s.requireStorage(w) {
+		return nil, false
+	}
+	vars := mux.Vars(r)
+	taskID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return nil, false
+	}
+	attachmentID, err := uuid.Parse(vars["attachment_id"])
+	if err != nil {
+		http.Error(w, "Invalid attachment ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	attachment, err := s.repos.Attachments.GetByID(r.Context(), attachmentID)
+	if err == nil && attachment.TaskID != taskID {
+		err = repository.ErrNotFound
+	}
+	if err != nil {
+		writeRepoError(w, err, "Attachment not found")
+		return nil, false
+	}
+	return attachment, true
+}
+
+func (s *Server) requireStorage(w http.ResponseWriter) bool {
+	if s.storage == nil {
+		http.Error(w, "Attachment storage is not configured", http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// writeUploadError отвечает 413, если тело запроса превысило лимит, иначе 400
+func writeUploadError(w http.ResponseWriter, err error, message string) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, message, http.StatusBadRequest)
+}
+
+// cleanFilename оставляет только имя файла без пути и управляющих символов
+func cleanFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	return name
+}
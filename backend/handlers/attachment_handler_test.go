@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"task-flow-backend/models"
+	"task-flow-backend/storage"
+	"task-flow-backend/templates"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// multipartFile собирает тело multipart/form-data с одним файлом в поле field
+func multipartFile(t *testing.T, field, filename string, content []byte) (*bytes.Buffer, string) {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile(field, filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return &body, writer.FormDataContentType()
+}
+
+func TestAttachments(t *testing.T) {
+	hub := &recordingHub{}
+	store, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	server, router := newTestServer(t, WithHub(hub), WithStorage(store),
+		WithAttachmentLimits(AttachmentLimits{MaxSize: 64 << 10, AllowedTypes: []string{"image/*", "text/plain"}}))
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Bugs", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+	task := &models.Task{BoardID: board.ID, Title: "Crash on save", Status: "plan"}
+	require.NoError(t, server.repos.Tasks.Create(context.Background(), task))
+	other := &models.Task{BoardID: board.ID, Title: "Crash on load", Status: "plan"}
+	require.NoError(t, server.repos.Tasks.Create(context.Background(), other))
+	attachmentsPath := "/api/tasks/" + task.ID.String() + "/attachments"
+
+	var screenshot bytes.Buffer
+	require.NoError(t, png.Encode(&screenshot, image.NewRGBA(image.Rect(0, 0, 600, 300))))
+
+	upload := func(path, field, filename string, content []byte) *httptest.ResponseRecorder {
+		body, contentType := multipartFile(t, field, filename, content)
+		req, err := http.NewRequest("POST", path, body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", contentType)
+		authorize(t, req, userID)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	decode := func(rr *httptest.ResponseRecorder) models.Attachment {
+		var attachment models.Attachment
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &attachment))
+		return attachment
+	}
+
+	hub.events = nil
+	rr := upload(attachmentsPath, "file", "C:\\Users\\qa\\screen.png", screenshot.Bytes())
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	image1 := decode(rr)
+	assert.Equal(t, "screen.png", image1.Filename)
+	assert.Equal(t, "image/png", image1.ContentType)
+	assert.Equal(t, int64(screenshot.Len()), image1.Size)
+	assert.True(t, image1.HasThumbnail)
+	assert.Equal(t, []recordedEvent{{boardID: board.ID.String(), eventType: "attachment_created"}}, hub.events)
+
+	rr = upload(attachmentsPath, "file", "отчёт об ошибке.log", []byte("panic: nil map\ngoroutine 1 [running]:\n"))
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	log := decode(rr)
+	assert.Equal(t, "text/plain; charset=utf-8", log.ContentType)
+	assert.False(t, log.HasThumbnail)
+
+	t.Run("Limits", func(t *testing.T) {
+		rr := upload(attachmentsPath, "file", "big.log", bytes.Repeat([]byte("a"), 64<<10+1))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+		rr = upload(attachmentsPath, "file", "tool.exe", []byte("MZ\x90\x00\x03\x00\x00\x00"))
+		assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+		assert.Equal(t, http.StatusBadRequest, upload(attachmentsPath, "upload", "a.log", []byte("x")).Code)
+		assert.Equal(t, http.StatusBadRequest, upload(attachmentsPath, "file", "empty.log", nil).Code)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		rr := get(attachmentsPath, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var attachments []models.Attachment
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &attachments))
+		require.Len(t, attachments, 2)
+		assert.Equal(t, image1.ID, attachments[0].ID)
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		rr := get(attachmentsPath+"/"+log.ID.String(), nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "panic: nil map\ngoroutine 1 [running]:\n", rr.Body.String())
+		assert.Equal(t, "attachment; filename*=utf-8''%D0%BE%D1%82%D1%87%D1%91%D1%82%20%D0%BE%D0%B1%20%D0%BE%D1%88%D0%B8%D0%B1%D0%BA%D0%B5.log",
+			rr.Header().Get("Content-Disposition"))
+		assert.Equal(t, "nosniff", rr.Header().Get("X-Content-Type-Options"))
+
+		etag := rr.Header().Get("ETag")
+		assert.Equal(t, http.StatusNotModified, get(attachmentsPath+"/"+log.ID.String(), http.Header{"If-None-Match": {etag}}).Code)
+
+		rr = get(attachmentsPath+"/"+image1.ID.String()+"?inline=true", nil)
+		assert.Equal(t, `inline; filename=screen.png`, rr.Header().Get("Content-Disposition"))
+
+		assert.Equal(t, http.StatusNotFound, get("/api/tasks/"+other.ID.String()+"/attachments/"+log.ID.String(), nil).Code)
+	})
+
+	t.Run("Thumbnail", func(t *testing.T) {
+		rr := get(attachmentsPath+"/"+image1.ID.String()+"/thumbnail", nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "image/png", rr.Header().Get("Content-Type"))
+		thumb, err := png.Decode(rr.Body)
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 256, 128), thumb.Bounds())
+
+		assert.Equal(t, http.StatusNotFound, get(attachmentsPath+"/"+log.ID.String()+"/thumbnail", nil).Code)
+	})
+
+	t.Run("Deduplication and deletion", func(t *testing.T) {
+		rr := upload("/api/tasks/"+other.ID.String()+"/attachments", "file", "same.png", screenshot.Bytes())
+		require.Equal(t, http.StatusCreated, rr.Code)
+		copyOf := decode(rr)
+		assert.Equal(t, image1.Checksum, copyOf.Checksum)
+		assert.True(t, copyOf.HasThumbnail)
+
+		del := func(path string) int {
+			req, err := http.NewRequest("DELETE", path, nil)
+			require.NoError(t, err)
+			authorize(t, req, userID)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			return rr.Code
+		}
+		stored := func() bool {
+			body, err := store.Get(context.Background(), storage.BlobKey(image1.Checksum))
+			if err != nil {
+				return false
+			}
+			body.Close()
+			return true
+		}
+
+		require.Equal(t, http.StatusNoContent, del(attachmentsPath+"/"+image1.ID.String()))
+		assert.True(t, stored(), "Expected file shared with another attachment to be kept")
+
+		require.Equal(t, http.StatusNoContent, del("/api/tasks/"+other.ID.String()+"/attachments/"+copyOf.ID.String()))
+		assert.False(t, stored())
+		_, err := store.Get(context.Background(), storage.ThumbnailKey(image1.Checksum))
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
+
+func TestAttachmentsWithoutStorage(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "No storage", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+	task := &models.Task{BoardID: board.ID, Title: "Task", Status: "plan"}
+	require.NoError(t, server.repos.Tasks.Create(context.Background(), task))
+
+	body, contentType := multipartFile(t, "file", "a.log", []byte("log"))
+	req, err := http.NewRequest("POST", "/api/tasks/"+task.ID.String()+"/attachments", body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	authorize(t, req, userID)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+	assert.Contains(t, rr.Body.String(), "not configured")
+}
@@ -1,16 +1,22 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"task-flow-backend/auth"
+	"task-flow-backend/logging"
 	"task-flow-backend/models"
 	"task-flow-backend/repository"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-func Login(w http.ResponseWriter, r *http.Request) {
+func (s *Server) Login(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	
 	var req models.LoginRequest
@@ -20,26 +26,51 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := repository.GetUserByUsername(req.Username)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "Internal server error"})
+	// Неудачные входы блокируют и IP, и имя: перебор паролей одной учетной
+	// записи с разных адресов тоже упирается в блокировку
+	ipKey := "login:ip:" + s.clientIP(r)
+	userKey := "login:user:" + strings.ToLower(req.Username)
+	if s.lockedOut(w, r, ipKey, userKey) {
 		return
 	}
 
-	if user == nil {
+	user, err := s.repos.Users.GetByUsername(r.Context(), req.Username)
+	if errors.Is(err, repository.ErrNotFound) {
+		s.recordFailure(r, ipKey, userKey)
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid username or password"})
 		return
 	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Internal server error"})
+		return
+	}
 
 	if !repository.VerifyPassword(user.PasswordHash, req.Password) {
+		s.recordFailure(r, ipKey, userKey)
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Invalid username or password"})
 		return
 	}
 
-	token, err := auth.GenerateToken(user.ID, user.Username)
+	// Сбрасываются только неудачи имени: вход в свою учетную запись не должен
+	// давать IP новые попытки подбора чужих паролей
+	s.resetFailures(r, userKey)
+
+	// Пароль проверяется раньше, чтобы не раскрывать состояние чужих учетных записей
+	if reason := accountBlocked(user, time.Now()); reason != "" {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{"error": reason})
+		return
+	}
+	if s.requireVerifiedEmail && user.EmailVerifiedAt == nil {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Email is not verified"})
+		return
+	}
+
+	token, err := auth.GenerateToken(user.ID, user.Username, user.TokenVersion)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to generate token"})
@@ -57,7 +88,7 @@ func Login(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-func Register(w http.ResponseWriter, r *http.Request) {
+func (s *Server) Register(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	
 	var req models.RegisterRequest
@@ -73,31 +104,39 @@ func Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(req.Password) < 6 {
+	if len(req.Password) < minPasswordLength {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Password must be at least 6 characters"})
 		return
 	}
 
-	existingUser, err := repository.GetUserByUsername(req.Username)
-	if err != nil {
+	// Ответ 409 раскрывает занятые имена и email, поэтому их перебор блокирует IP
+	ipKey := "register:ip:" + s.clientIP(r)
+	if s.lockedOut(w, r, ipKey) {
+		return
+	}
+
+	_, err := s.repos.Users.GetByUsername(r.Context(), req.Username)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Internal server error"})
 		return
 	}
-	if existingUser != nil {
+	if err == nil {
+		s.recordFailure(r, ipKey)
 		w.WriteHeader(http.StatusConflict)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Username already exists"})
 		return
 	}
 
-	existingUser, err = repository.GetUserByEmail(req.Email)
-	if err != nil {
+	_, err = s.repos.Users.GetByEmail(r.Context(), req.Email)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Internal server error"})
 		return
 	}
-	if existingUser != nil {
+	if err == nil {
+		s.recordFailure(r, ipKey)
 		w.WriteHeader(http.StatusConflict)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Email already exists"})
 		return
@@ -108,21 +147,35 @@ func Register(w http.ResponseWriter, r *http.Request) {
 		Email:    req.Email,
 	}
 
-	if err := repository.CreateUser(user, req.Password); err != nil {
+	if err := s.repos.Users.Create(r.Context(), user, req.Password); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			s.recordFailure(r, ipKey)
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Username or email already exists"})
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to create user: " + err.Error()})
 		return
 	}
 
-	token, err := auth.GenerateToken(user.ID, user.Username)
+	s.sendAccountMail(r, user, models.UserTokenVerifyEmail)
+	user.PasswordHash = ""
+
+	// Без подтверждения email токен не выдается: войти можно после перехода по ссылке
+	if s.requireVerifiedEmail {
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(models.AuthResponse{User: *user, VerificationRequired: true})
+		return
+	}
+
+	token, err := auth.GenerateToken(user.ID, user.Username, user.TokenVersion)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": "Failed to generate token"})
 		return
 	}
 
-	user.PasswordHash = ""
-
 	response := models.AuthResponse{
 		Token: token,
 		User:  *user,
@@ -133,27 +186,96 @@ func Register(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-func GetCurrentUser(w http.ResponseWriter, r *http.Request) {
-	userID, ok := r.Context().Value("userID").(uuid.UUID)
+func (s *Server) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r.Context())
 	if !ok {
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	user, err := repository.GetUserByID(userID)
+	user, err := s.repos.Users.GetByID(r.Context(), userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	if user == nil {
-		http.Error(w, "User not found", http.StatusNotFound)
+	user.PasswordHash = ""
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// ExportCurrentUser выгружает все данные текущего пользователя в JSON (GDPR)
+func (s *Server) ExportCurrentUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	user.PasswordHash = ""
+	s.writeUserExport(w, r, userID)
+}
+
+// DeleteCurrentUser удаляет учетную запись текущего пользователя после
+// подтверждения паролем. Его задачи и комментарии остаются без автора.
+func (s *Server) DeleteCurrentUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := s.repos.Users.GetByID(r.Context(), userID)
+	if err != nil {
+		writeRepoError(w, err, "User not found")
+		return
+	}
+	if !repository.VerifyPassword(user.PasswordHash, req.Password) {
+		http.Error(w, "Invalid password", http.StatusForbidden)
+		return
+	}
+
+	err = s.repos.Tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		if err := s.repos.RequireOtherAdmin(ctx, user.ID); err != nil {
+			return err
+		}
+		return s.repos.DeleteUser(ctx, user.ID, nil)
+	})
+	if errors.Is(err, repository.ErrLastAdmin) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeRepoError(w, err, "User not found")
+		return
+	}
+
+	logging.FromContext(r.Context()).Info("Account deleted by its owner", "user_id", user.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeUserExport отдает выгрузку данных пользователя userID файлом
+func (s *Server) writeUserExport(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	export, err := s.repos.ExportUserData(r.Context(), userID)
+	if err != nil {
+		writeRepoError(w, err, "User not found")
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(user)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="taskflow-%s.json"`, export.User.ID))
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(export)
 }
 
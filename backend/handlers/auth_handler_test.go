@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-flow-backend/auth"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/templates"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountStatusAndTokenRevocation(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+	ctx := context.Background()
+
+	login := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.LoginRequest{Username: "testuser", Password: "testpass123"})
+		req, err := http.NewRequest("POST", "/api/auth/login", bytes.NewReader(body))
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	me := func(token string) int {
+		req, err := http.NewRequest("GET", "/api/auth/me", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	rr := login()
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var resp models.AuthResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, models.UserRoleUser, resp.User.Role)
+	assert.Equal(t, http.StatusOK, me(resp.Token))
+
+	user, err := server.repos.Users.GetByID(ctx, userID)
+	require.NoError(t, err)
+	user.Status = models.UserStatusDisabled
+	require.NoError(t, server.repos.Users.Update(ctx, user))
+	assert.Equal(t, http.StatusForbidden, login().Code)
+	assert.Equal(t, http.StatusForbidden, me(resp.Token), "Expected tokens of a disabled account to stop working")
+
+	user.Status = models.UserStatusActive
+	require.NoError(t, server.repos.Users.Update(ctx, user))
+	assert.Equal(t, http.StatusOK, me(resp.Token))
+
+	until := time.Now().Add(time.Hour)
+	user.Status, user.LockedUntil = models.UserStatusLocked, &until
+	require.NoError(t, server.repos.Users.Update(ctx, user))
+	assert.Equal(t, http.StatusForbidden, login().Code)
+	assert.Equal(t, http.StatusForbidden, me(resp.Token))
+	expired := time.Now().Add(-time.Minute)
+	user.LockedUntil = &expired
+	require.NoError(t, server.repos.Users.Update(ctx, user))
+	assert.Equal(t, http.StatusOK, me(resp.Token), "Expected an expired lock to stop applying")
+
+	require.NoError(t, server.repos.Users.RevokeTokens(ctx, userID))
+	assert.Equal(t, http.StatusUnauthorized, me(resp.Token))
+
+	rr = login()
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, me(resp.Token))
+}
+
+func TestAdminUsers(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+	ctx := context.Background()
+
+	admin := &models.User{Username: "root", Email: "root@test.com", Role: models.UserRoleAdmin}
+	require.NoError(t, server.repos.Users.Create(ctx, admin, "rootpass123"))
+	adminToken, err := auth.GenerateToken(admin.ID, admin.Username, 0)
+	require.NoError(t, err)
+
+	do := func(method, path, token string, body any) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, err := http.NewRequest(method, path, bytes.NewReader(data))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	userToken, err := auth.GenerateToken(userID, "testuser", 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusForbidden, do("GET", "/api/admin/users", userToken, nil).Code)
+
+	rr := do("GET", "/api/admin/users?role=admin", adminToken, nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var users []models.User
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &users))
+	require.Len(t, users, 1)
+	assert.Equal(t, "root", users[0].Username)
+	assert.NotContains(t, rr.Body.String(), "password")
+	assert.Equal(t, http.StatusBadRequest, do("GET", "/api/admin/users?status=gone", adminToken, nil).Code)
+
+	userPath := "/api/admin/users/" + userID.String()
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	rr = do("PATCH", userPath, adminToken, map[string]any{"status": "locked", "locked_until": until})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var updated models.User
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &updated))
+	assert.Equal(t, models.UserStatusLocked, updated.Status)
+	require.NotNil(t, updated.LockedUntil)
+	assert.True(t, until.Equal(*updated.LockedUntil))
+	assert.Equal(t, http.StatusUnauthorized, do("GET", "/api/auth/me", userToken, nil).Code, "Expected locking to revoke tokens")
+
+	assert.Equal(t, http.StatusBadRequest, do("PATCH", userPath, adminToken, map[string]any{"status": "active", "locked_until": until}).Code)
+	assert.Equal(t, http.StatusBadRequest, do("PATCH", userPath, adminToken, map[string]any{"role": "owner"}).Code)
+	assert.Equal(t, http.StatusConflict, do("PATCH", userPath, adminToken, map[string]any{"email": "root@test.com"}).Code)
+	rr = do("PATCH", userPath, adminToken, map[string]any{"status": "active", "role": "admin"})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	stored, err := server.repos.Users.GetByID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, models.UserRoleAdmin, stored.Role)
+	assert.Nil(t, stored.LockedUntil)
+
+	adminPath := "/api/admin/users/" + admin.ID.String()
+	assert.Equal(t, http.StatusBadRequest, do("PATCH", adminPath, adminToken, map[string]any{"status": "disabled"}).Code)
+	assert.Equal(t, http.StatusBadRequest, do("DELETE", adminPath, adminToken, nil).Code)
+
+	rr = do("POST", adminPath+"/revoke-tokens", adminToken, nil)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Equal(t, http.StatusUnauthorized, do("GET", "/api/admin/users", adminToken, nil).Code)
+	adminToken, err = auth.GenerateToken(admin.ID, admin.Username, 1)
+	require.NoError(t, err)
+
+	board := &models.Board{Name: "Inherited", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(ctx, board, templates.Default()))
+	assert.Equal(t, http.StatusBadRequest, do("DELETE", userPath+"?transfer_to="+uuid.NewString(), adminToken, nil).Code)
+	rr = do("DELETE", userPath+"?transfer_to="+admin.ID.String(), adminToken, nil)
+	require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+	_, err = server.repos.Users.GetByID(ctx, userID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	inherited, err := server.repos.Boards.GetByID(ctx, board.ID)
+	require.NoError(t, err)
+	assert.Equal(t, admin.ID, *inherited.UserID)
+	assert.Equal(t, http.StatusNotFound, do("GET", userPath, adminToken, nil).Code)
+}
+
+func TestAccountExportAndDeletion(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+	ctx := context.Background()
+
+	board := &models.Board{Name: "Mine", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(ctx, board, templates.Default()))
+	task := &models.Task{BoardID: board.ID, Title: "Assigned", Status: "plan", Assignee: stringPtr("testuser")}
+	require.NoError(t, server.repos.Tasks.Create(ctx, task))
+
+	req, err := http.NewRequest("GET", "/api/auth/me/export", nil)
+	require.NoError(t, err)
+	authorize(t, req, userID)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "attachment")
+	var export models.UserDataExport
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &export))
+	assert.Equal(t, "testuser", export.User.Username)
+	require.Len(t, export.Boards, 1)
+	require.Len(t, export.Tasks, 1)
+	assert.Equal(t, task.ID, export.Tasks[0].ID)
+	assert.Empty(t, export.Comments)
+
+	deleteAccount := func(password string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(models.DeleteAccountRequest{Password: password})
+		req, err := http.NewRequest("DELETE", "/api/auth/me", bytes.NewReader(body))
+		require.NoError(t, err)
+		authorize(t, req, userID)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Единственного администратора удалить нельзя
+	user, err := server.repos.Users.GetByID(ctx, userID)
+	require.NoError(t, err)
+	user.Role = models.UserRoleAdmin
+	require.NoError(t, server.repos.Users.Update(ctx, user))
+	assert.Equal(t, http.StatusForbidden, deleteAccount("wrong-password").Code)
+	assert.Equal(t, http.StatusConflict, deleteAccount("testpass123").Code)
+
+	user.Role = models.UserRoleUser
+	require.NoError(t, server.repos.Users.Update(ctx, user))
+	rr = deleteAccount("testpass123")
+	require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+
+	stored, err := server.repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.Assignee)
+	assert.Equal(t, http.StatusUnauthorized, deleteAccount("testpass123").Code)
+}
@@ -2,12 +2,24 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 	"task-flow-backend/auth"
+	"task-flow-backend/logging"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func AuthMiddleware(next http.Handler) http.Handler {
+// AuthMiddleware пропускает запросы с действующим JWT. Пользователь токена
+// должен существовать и не быть заблокирован, а версия токена - совпадать с
+// его TokenVersion, поэтому отзыв токенов и блокировка действуют сразу.
+func (s *Server) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
@@ -29,13 +41,52 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), "userID", claims.UserID)
-		ctx = context.WithValue(ctx, "username", claims.Username)
+		user, err := s.repos.Users.GetByID(r.Context(), claims.UserID)
+		if errors.Is(err, repository.ErrNotFound) || (err == nil && user.TokenVersion != claims.TokenVersion) {
+			http.Error(w, "Token has been revoked", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if reason := accountBlocked(user, time.Now()); reason != "" {
+			http.Error(w, reason, http.StatusForbidden)
+			return
+		}
+
+		ctx := withClaims(r.Context(), claims)
+		ctx = context.WithValue(ctx, "userRole", user.Role)
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// AdminMiddleware пропускает только администраторов; ставится после AuthMiddleware
+func (s *Server) AdminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if role, _ := r.Context().Value("userRole").(string); role != models.UserRoleAdmin {
+			http.Error(w, "Admin role required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// accountBlocked возвращает причину, по которой учетная запись не может
+// работать с API, или пустую строку. Срок блокировки locked истекает сам.
+func accountBlocked(user *models.User, now time.Time) string {
+	switch user.Status {
+	case models.UserStatusDisabled:
+		return "Account is disabled"
+	case models.UserStatusLocked:
+		if user.LockedUntil == nil || now.Before(*user.LockedUntil) {
+			return "Account is locked"
+		}
+	}
+	return ""
+}
+
 func OptionalAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
@@ -45,9 +96,7 @@ func OptionalAuthMiddleware(next http.Handler) http.Handler {
 				tokenString := parts[1]
 				claims, err := auth.ValidateToken(tokenString)
 				if err == nil {
-					ctx := context.WithValue(r.Context(), "userID", claims.UserID)
-					ctx = context.WithValue(ctx, "username", claims.Username)
-					r = r.WithContext(ctx)
+					r = r.WithContext(withClaims(r.Context(), claims))
 				}
 			}
 		}
@@ -55,3 +104,18 @@ func OptionalAuthMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// withClaims кладет пользователя в контекст и добавляет его в логи и трассировку запроса
+func withClaims(ctx context.Context, claims *auth.Claims) context.Context {
+	ctx = context.WithValue(ctx, "userID", claims.UserID)
+	ctx = context.WithValue(ctx, "username", claims.Username)
+
+	logging.With(ctx, "user_id", claims.UserID.String())
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("enduser.id", claims.UserID.String()))
+
+	return ctx
+}
+
+func userIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value("userID").(uuid.UUID)
+	return userID, ok
+}
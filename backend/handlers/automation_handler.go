@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"task-flow-backend/automation"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultAutomationRunsLimit = 50
+	maxAutomationRunsLimit     = 200
+)
+
+func (s *Server) GetAutomationRules(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.repos.Boards.GetByID(r.Context(), boardID); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	rules, err := s.repos.AutomationRules.ListByBoard(r.Context(), boardID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rules == nil {
+		rules = []models.AutomationRule{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// CreateAutomationRule создает правило доски; без enabled правило включено
+func (s *Server) CreateAutomationRule(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateAutomationRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rule := &models.AutomationRule{
+		BoardID:    boardID,
+		Name:       req.Name,
+		Enabled:    req.Enabled == nil || *req.Enabled,
+		Trigger:    req.Trigger,
+		Conditions: req.Conditions,
+		Actions:    req.Actions,
+	}
+	if rule.Conditions == nil {
+		rule.Conditions = []models.AutomationCondition{}
+	}
+	if userID, ok := userIDFromContext(r.Context()); ok {
+		rule.CreatedBy = &userID
+	}
+
+	if !s.validateAutomationRule(w, r, rule) {
+		return
+	}
+
+	if err := s.repos.AutomationRules.Create(r.Context(), rule); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+func (s *Server) GetAutomationRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := s.automationRuleFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+func (s *Server) UpdateAutomationRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := s.automationRuleFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateAutomationRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if req.Trigger != nil {
+		rule.Trigger = *req.Trigger
+	}
+	if req.Conditions != nil {
+		rule.Conditions = *req.Conditions
+	}
+	if req.Actions != nil {
+		rule.Actions = *req.Actions
+	}
+	if rule.Conditions == nil {
+		rule.Conditions = []models.AutomationCondition{}
+	}
+
+	if !s.validateAutomationRule(w, r, rule) {
+		return
+	}
+
+	if err := s.repos.AutomationRules.Update(r.Context(), rule); err != nil {
+		writeRepoError(w, err, "Automation rule not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+func (s *Server) DeleteAutomationRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := s.automationRuleFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.repos.AutomationRules.Delete(r.Context(), rule.ID); err != nil {
+		writeRepoError(w, err, "Automation rule not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetAutomationRuns возвращает журнал выполнения правил доски от новых
+// записей к старым; rule_id оставляет записи одного правила
+func (s *Server) GetAutomationRuns(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	filter := repository.AutomationRunFilter{BoardID: boardID, Limit: defaultAutomationRunsLimit}
+	query := r.URL.Query()
+	if value := query.Get("rule_id"); value != "" {
+		ruleID, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid rule ID", http.StatusBadRequest)
+			return
+		}
+		filter.RuleID = &ruleID
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxAutomationRunsLimit {
+			http.Error(w, "limit must be between 1 and "+strconv.Itoa(maxAutomationRunsLimit), http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	if _, err := s.repos.Boards.GetByID(r.Context(), boardID); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	runs, err := s.repos.AutomationRuns.List(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if runs == nil {
+		runs = []models.AutomationRun{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(runs)
+}
+
+// automationRuleFromPath читает правило из пути и при ошибке сам пишет ответ
+func (s *Server) automationRuleFromPath(w http.ResponseWriter, r *http.Request) (*models.AutomationRule, bool) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid automation rule ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	rule, err := s.repos.AutomationRules.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Automation rule not found")
+		return nil, false
+	}
+	return rule, true
+}
+
+// validateAutomationRule проверяет правило по колонкам его доски
+func (s *Server) validateAutomationRule(w http.ResponseWriter, r *http.Request, rule *models.AutomationRule) bool {
+	columns, err := s.repos.Columns.ListByBoard(r.Context(), rule.BoardID, repository.ListOptions{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if len(columns) == 0 {
+		http.Error(w, "Board not found or has no columns", http.StatusNotFound)
+		return false
+	}
+
+	statuses := make([]string, 0, len(columns))
+	for _, column := range columns {
+		statuses = append(statuses, column.StatusID)
+	}
+	if err := automation.Validate(rule, statuses); err != nil {
+		if errors.Is(err, automation.ErrInvalidRule) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return false
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"task-flow-backend/automation"
+	"task-flow-backend/models"
+	"task-flow-backend/templates"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutomationRules(t *testing.T) {
+	server, router := newTestServer(t)
+	engine := automation.NewEngine(server.repos)
+	server.automation = engine
+	engine.SetNotifier(server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go engine.Run(ctx)
+
+	userID := createTestUser(t, server)
+	board := &models.Board{Name: "Automation", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+
+	rulesPath := "/api/boards/" + board.ID.String() + "/automation-rules"
+
+	t.Run("Validation", func(t *testing.T) {
+		cases := map[string]string{
+			"no name":        `{"trigger":{"type":"task_created"},"actions":[{"type":"archive"}]}`,
+			"no actions":     `{"name":"Empty","trigger":{"type":"task_created"}}`,
+			"unknown status": `{"name":"QA","trigger":{"type":"task_moved","to":"nope"},"actions":[{"type":"archive"}]}`,
+			"bad webhook":    `{"name":"Hook","trigger":{"type":"task_created"},"actions":[{"type":"webhook","url":"ftp://x"}]}`,
+		}
+		for name, body := range cases {
+			assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "POST", rulesPath, body).Code, name)
+		}
+	})
+
+	var rule models.AutomationRule
+	rulePath := func() string { return "/api/automation-rules/" + rule.ID.String() }
+	t.Run("Create rule", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", rulesPath, `{"name":"Assign QA","trigger":{"type":"task_moved","to":"testing"},"actions":[{"type":"assign","value":"qa"},{"type":"comment","value":"Ready for QA"}]}`)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &rule))
+		assert.True(t, rule.Enabled, "Expected rule to be enabled by default")
+		require.NotNil(t, rule.CreatedBy)
+		assert.Equal(t, userID, *rule.CreatedBy)
+	})
+
+	var task models.Task
+	t.Run("Moved task triggers actions", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", "/api/tasks", `{"board_id":"`+board.ID.String()+`","title":"Login form"}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &task))
+
+		rr = doRequest(t, router, userID, "PATCH", "/api/tasks/"+task.ID.String()+"/move", `{"status":"testing"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		require.Eventually(t, func() bool {
+			got, err := server.repos.Tasks.GetByID(context.Background(), task.ID)
+			return err == nil && got.Assignee != nil && *got.Assignee == "qa"
+		}, 2*time.Second, 10*time.Millisecond)
+
+		require.Eventually(t, func() bool {
+			rr := doRequest(t, router, userID, "GET", "/api/tasks/"+task.ID.String()+"/comments", "")
+			var comments []models.TaskComment
+			return json.Unmarshal(rr.Body.Bytes(), &comments) == nil && len(comments) == 1
+		}, 2*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Run log", func(t *testing.T) {
+		runsPath := "/api/boards/" + board.ID.String() + "/automation-runs"
+		var runs []models.AutomationRun
+		require.Eventually(t, func() bool {
+			rr := doRequest(t, router, userID, "GET", runsPath+"?rule_id="+rule.ID.String(), "")
+			return json.Unmarshal(rr.Body.Bytes(), &runs) == nil && len(runs) == 1
+		}, 2*time.Second, 10*time.Millisecond)
+		assert.Equal(t, models.AutomationRunSuccess, runs[0].Status)
+		assert.Equal(t, models.AutomationTriggerTaskMoved, runs[0].Event)
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "GET", runsPath+"?limit=1000", "").Code)
+	})
+
+	t.Run("Comment on task", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", "/api/tasks/"+task.ID.String()+"/comments", `{"body":"Looks good"}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "POST", "/api/tasks/"+task.ID.String()+"/comments", `{"body":" "}`).Code)
+	})
+
+	t.Run("Disable rule", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "PUT", rulePath(), `{"enabled":false}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &rule))
+		assert.False(t, rule.Enabled)
+		assert.Len(t, rule.Actions, 2, "Expected omitted fields to stay unchanged")
+
+		rr = doRequest(t, router, userID, "GET", rulesPath, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var rules []models.AutomationRule
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &rules))
+		assert.Len(t, rules, 1)
+	})
+
+	t.Run("Delete rule", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, doRequest(t, router, userID, "DELETE", rulePath(), "").Code)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "GET", rulePath(), "").Code)
+	})
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"io"
 	"net/http"
+	"task-flow-backend/logging"
+	"task-flow-backend/metrics"
 	"task-flow-backend/models"
 	"task-flow-backend/repository"
 
@@ -11,8 +14,8 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func GetBoards(w http.ResponseWriter, r *http.Request) {
-	boards, err := repository.GetAllBoards()
+func (s *Server) GetBoards(w http.ResponseWriter, r *http.Request) {
+	boards, err := s.repos.Boards.List(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -22,7 +25,7 @@ func GetBoards(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(boards)
 }
 
-func GetBoard(w http.ResponseWriter, r *http.Request) {
+func (s *Server) GetBoard(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.Parse(vars["id"])
 	if err != nil {
@@ -30,9 +33,9 @@ func GetBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	board, err := repository.GetBoardByID(id)
+	board, err := s.repos.Boards.GetByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Board not found", http.StatusNotFound)
+		writeRepoError(w, err, "Board not found")
 		return
 	}
 
@@ -40,16 +43,16 @@ func GetBoard(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(board)
 }
 
-func CreateBoard(w http.ResponseWriter, r *http.Request) {
+func (s *Server) CreateBoard(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
 	var req models.CreateBoardRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Printf("Error decoding request: %v", err)
+		logger.Warn("Failed to decode request", "error", err)
 		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	log.Printf("CreateBoard request: Name=%s, Description=%v", req.Name, req.Description)
-
 	if req.Name == "" {
 		http.Error(w, "Board name is required", http.StatusBadRequest)
 		return
@@ -61,34 +64,47 @@ func CreateBoard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Получаем userID из контекста
-	userID, ok := r.Context().Value("userID").(uuid.UUID)
 	var boardUserID *uuid.UUID
-	if ok {
+	if userID, ok := userIDFromContext(r.Context()); ok {
 		boardUserID = &userID
 	}
 
+	tpl, err := s.resolveTemplate(r, req.TemplateID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			http.Error(w, "Unknown template_id", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	board := &models.Board{
 		Name:        req.Name,
 		Description: description,
 		UserID:      boardUserID,
 	}
+	if req.EnforceDependencies != nil {
+		board.EnforceDependencies = *req.EnforceDependencies
+	}
 
-	if err := repository.CreateBoard(board); err != nil {
-		log.Printf("Error creating board: %v", err)
+	if err := s.repos.CreateBoard(r.Context(), board, tpl); err != nil {
+		logger.Error("Failed to create board", "error", err)
 		http.Error(w, "Failed to create board: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("Board created successfully: ID=%s, Name=%s", board.ID, board.Name)
+	logger.Info("Board created", "board_id", board.ID, "template_id", tpl.ID)
+	metrics.BoardsCreated.Inc()
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(board); err != nil {
-		log.Printf("Error encoding response: %v", err)
+		logger.Warn("Failed to encode response", "error", err)
 	}
 }
 
-func UpdateBoard(w http.ResponseWriter, r *http.Request) {
+func (s *Server) UpdateBoard(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.Parse(vars["id"])
 	if err != nil {
@@ -102,14 +118,24 @@ func UpdateBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	current, err := s.repos.Boards.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
 	board := &models.Board{
-		ID:          id,
-		Name:        req.Name,
-		Description: req.Description,
+		ID:                  id,
+		Name:                req.Name,
+		Description:         req.Description,
+		EnforceDependencies: current.EnforceDependencies,
+	}
+	if req.EnforceDependencies != nil {
+		board.EnforceDependencies = *req.EnforceDependencies
 	}
 
-	if err := repository.UpdateBoard(board); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := s.repos.Boards.Update(r.Context(), board); err != nil {
+		writeRepoError(w, err, "Board not found")
 		return
 	}
 
@@ -117,7 +143,7 @@ func UpdateBoard(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(board)
 }
 
-func DeleteBoard(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteBoard(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.Parse(vars["id"])
 	if err != nil {
@@ -125,10 +151,89 @@ func DeleteBoard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := repository.DeleteBoard(id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := s.repos.Boards.Delete(r.Context(), id); err != nil {
+		writeRepoError(w, err, "Board not found")
 		return
 	}
 
+	s.invalidateTasksCache(r.Context(), id)
+
 	w.WriteHeader(http.StatusNoContent)
 }
+
+func (s *Server) GetBoardMembers(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	members, err := s.repos.Members.ListByBoard(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members)
+}
+
+func (s *Server) GetBoardLabels(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	labels, err := s.repos.Labels.ListByBoard(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(labels)
+}
+
+func (s *Server) DuplicateBoard(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	// Тело запроса необязательно: без него копируются только колонки
+	var req models.DuplicateBoardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	board := &models.Board{Name: req.Name}
+	if userID, ok := userIDFromContext(r.Context()); ok {
+		board.UserID = &userID
+	}
+
+	opts := repository.DuplicateOptions{
+		IncludeTasks:    req.IncludeTasks,
+		IncludeComments: req.IncludeComments,
+		IncludeLabels:   req.IncludeLabels,
+		IncludeMembers:  req.IncludeMembers,
+	}
+	if err := s.repos.DuplicateBoard(r.Context(), id, board, opts); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	logger.Info("Board duplicated", "source_board_id", id, "board_id", board.ID)
+	metrics.BoardsCreated.Inc()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(board)
+}
@@ -1,30 +1,31 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"testing"
 	"task-flow-backend/models"
 	"task-flow-backend/repository"
+	"testing"
 
 	"github.com/google/uuid"
-	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestGetBoards(t *testing.T) {
-	setupTestDB(t)
-	
-	userID := createTestUser(t)
+	server, router := newTestServer(t)
+
+	userID := createTestUser(t, server)
 
 	board := &models.Board{
 		Name:        "Test Board",
 		Description: stringPtr("Test Description"),
 		UserID:      &userID,
 	}
-	err := repository.CreateBoard(board)
+	err := server.repos.Boards.Create(context.Background(), board)
 	require.NoError(t, err, "Failed to create test board")
 
 	t.Run("Get all boards", func(t *testing.T) {
@@ -32,8 +33,7 @@ func TestGetBoards(t *testing.T) {
 		require.NoError(t, err)
 
 		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(GetBoards)
-		handler.ServeHTTP(rr, req)
+		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusOK, rr.Code, "Expected status 200")
 		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
@@ -42,7 +42,7 @@ func TestGetBoards(t *testing.T) {
 		err = json.Unmarshal(rr.Body.Bytes(), &boards)
 		require.NoError(t, err, "Failed to unmarshal response")
 		assert.GreaterOrEqual(t, len(boards), 1, "Expected at least one board")
-		
+
 		found := false
 		for _, b := range boards {
 			if b.ID == board.ID {
@@ -60,8 +60,6 @@ func TestGetBoards(t *testing.T) {
 		require.NoError(t, err)
 
 		rr := httptest.NewRecorder()
-		router := mux.NewRouter()
-		router.HandleFunc("/api/boards/{id}", GetBoard)
 		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusOK, rr.Code, "Expected status 200")
@@ -79,8 +77,6 @@ func TestGetBoards(t *testing.T) {
 		require.NoError(t, err)
 
 		rr := httptest.NewRecorder()
-		router := mux.NewRouter()
-		router.HandleFunc("/api/boards/{id}", GetBoard)
 		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code, "Expected status 400")
@@ -92,17 +88,69 @@ func TestGetBoards(t *testing.T) {
 		require.NoError(t, err)
 
 		rr := httptest.NewRecorder()
-		router := mux.NewRouter()
-		router.HandleFunc("/api/boards/{id}", GetBoard)
 		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusNotFound, rr.Code, "Expected status 404")
 	})
-
-	repository.DeleteBoard(board.ID)
 }
 
-func stringPtr(s string) *string {
-	return &s
-}
+func TestCreateAndDeleteBoard(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+
+	t.Run("Create board requires token", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/api/boards", bytes.NewBufferString(`{"name":"Board"}`))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, "Expected status 401")
+	})
+
+	var created models.Board
+	t.Run("Create board with default columns", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "/api/boards", bytes.NewBufferString(`{"name":"Board","description":"Desc"}`))
+		require.NoError(t, err)
+		authorize(t, req, userID)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusCreated, rr.Code, "Expected status 201")
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+		assert.Equal(t, "Board", created.Name)
+		require.NotNil(t, created.UserID)
+		assert.Equal(t, userID, *created.UserID)
 
+		columns, err := server.repos.Columns.ListByBoard(context.Background(), created.ID, repository.ListOptions{})
+		require.NoError(t, err)
+		assert.Len(t, columns, 5, "Expected default columns")
+	})
+
+	t.Run("Delete board", func(t *testing.T) {
+		req, err := http.NewRequest("DELETE", "/api/boards/"+created.ID.String(), nil)
+		require.NoError(t, err)
+		authorize(t, req, userID)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code, "Expected status 204")
+
+		columns, err := server.repos.Columns.ListByBoard(context.Background(), created.ID, repository.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, columns, "Expected columns to be deleted with the board")
+	})
+
+	t.Run("Delete non-existent board", func(t *testing.T) {
+		req, err := http.NewRequest("DELETE", "/api/boards/"+uuid.NewString(), nil)
+		require.NoError(t, err)
+		authorize(t, req, userID)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code, "Expected status 404")
+	})
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetSprintBurndown возвращает burndown спринта: объем, выполнение и остаток
+// в story points и задачах на конец каждого дня, идеальную линию и
+// изменения объема после старта. 409, если спринт еще не начат.
+func (s *Server) GetSprintBurndown(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid sprint ID", http.StatusBadRequest)
+		return
+	}
+
+	burndown, err := s.repos.SprintBurndown(r.Context(), id, time.Now())
+	if errors.Is(err, repository.ErrNotFound) {
+		writeRepoError(w, err, "Sprint not found")
+		return
+	}
+	if err != nil {
+		writeSprintError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(burndown)
+}
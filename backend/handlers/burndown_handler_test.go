@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"task-flow-backend/models"
+	"task-flow-backend/templates"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSprintBurndown(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+	ctx := context.Background()
+
+	tpl := templates.Default()
+	tpl.Tasks = nil
+	board := &models.Board{Name: "Burndown", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(ctx, board, tpl))
+
+	startsAt, endsAt := time.Now().Add(-48*time.Hour), time.Now().Add(12*24*time.Hour)
+	sprint := &models.Sprint{BoardID: board.ID, Name: "Sprint", State: models.SprintStateActive, StartsAt: &startsAt, EndsAt: &endsAt}
+	require.NoError(t, server.repos.Sprints.Create(ctx, sprint))
+	planned := &models.Sprint{BoardID: board.ID, Name: "Next", State: models.SprintStatePlanned}
+	require.NoError(t, server.repos.Sprints.Create(ctx, planned))
+
+	getBurndown := func(t *testing.T) models.SprintBurndown {
+		t.Helper()
+		rr := doRequest(t, router, userID, "GET", "/api/sprints/"+sprint.ID.String()+"/burndown", "")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var burndown models.SprintBurndown
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &burndown))
+		return burndown
+	}
+
+	taskBody := `{"board_id":"` + board.ID.String() + `","sprint_id":"` + sprint.ID.String() + `"`
+	var task models.Task
+	t.Run("Story points", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "POST", "/api/tasks", taskBody+`,"title":"Bad","story_points":-1}`).Code)
+		rr := doRequest(t, router, userID, "POST", "/api/tasks", taskBody+`,"title":"Login","story_points":5}`)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &task))
+		require.NotNil(t, task.StoryPoints)
+		assert.Equal(t, 5, *task.StoryPoints)
+		rr = doRequest(t, router, userID, "POST", "/api/tasks", taskBody+`,"title":"Logout","story_points":3}`)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		require.Equal(t, http.StatusOK, doRequest(t, router, userID, "PUT", "/api/tasks/"+task.ID.String(), `{"status":"closed"}`).Code)
+	})
+
+	t.Run("Burndown of active sprint", func(t *testing.T) {
+		burndown := getBurndown(t)
+		assert.Equal(t, "closed", burndown.DoneStatus)
+		require.Len(t, burndown.Days, 3)
+		today := burndown.Days[2]
+		assert.Equal(t, time.Now().UTC().Format(time.DateOnly), today.Date)
+		assert.Equal(t, 8, today.ScopePoints)
+		assert.Equal(t, 5, today.CompletedPoints)
+		assert.Equal(t, 3, today.RemainingPoints)
+		assert.Equal(t, 1, today.RemainingTasks)
+		assert.Zero(t, burndown.Days[0].ScopePoints, "Expected past days to come from history")
+		assert.NotEmpty(t, burndown.Ideal)
+		require.Len(t, burndown.ScopeChanges, 2)
+		assert.Equal(t, "Login", burndown.ScopeChanges[0].Title)
+		assert.Equal(t, 5, burndown.ScopeChanges[0].PointsDelta)
+	})
+
+	t.Run("Removed estimate reduces scope", func(t *testing.T) {
+		// "story_points": 0 снимает оценку и уменьшает объем спринта
+		rr := doRequest(t, router, userID, "PUT", "/api/tasks/"+task.ID.String(), `{"story_points":0}`)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var updated models.Task
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &updated))
+		assert.Nil(t, updated.StoryPoints)
+		burndown := getBurndown(t)
+		require.Len(t, burndown.Days, 3)
+		assert.Equal(t, 3, burndown.Days[2].ScopePoints)
+		assert.Equal(t, -5, burndown.ScopeChanges[len(burndown.ScopeChanges)-1].PointsDelta)
+	})
+
+	t.Run("Burndown of unavailable sprint", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, doRequest(t, router, userID, "GET", "/api/sprints/"+planned.ID.String()+"/burndown", "").Code)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "GET", "/api/sprints/"+uuid.NewString()+"/burndown", "").Code)
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "GET", "/api/sprints/nope/burndown", "").Code)
+	})
+}
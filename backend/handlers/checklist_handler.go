@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// checklistEvent - данные WebSocket событий чек-листа. Progress - прогресс
+// задачи после изменения, чтобы клиенту не нужно было перечитывать задачу.
+type checklistEvent struct {
+	TaskID   uuid.UUID              `json:"task_id"`
+	Item     *models.ChecklistItem  `json:"item,omitempty"`
+	ItemID   *uuid.UUID             `json:"id,omitempty"`
+	Items    []models.ChecklistItem `json:"items,omitempty"`
+	Progress models.TaskProgress    `json:"progress"`
+}
+
+func (s *Server) GetChecklist(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := s.repos.Checklists.ListByTask(r.Context(), task.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if items == nil {
+		items = []models.ChecklistItem{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+func (s *Server) CreateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateChecklistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Title) == "" {
+		http.Error(w, "title is required", http.StatusBadRequest)
+		return
+	}
+
+	item := &models.ChecklistItem{TaskID: task.ID, Title: req.Title, Assignee: req.Assignee}
+	if err := s.repos.Checklists.Create(r.Context(), item); err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+
+	s.broadcastChecklist(r.Context(), task, "checklist_item_created", checklistEvent{Item: item})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+func (s *Server) UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	task, item, ok := s.checklistItem(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateChecklistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			http.Error(w, "title must not be empty", http.StatusBadRequest)
+			return
+		}
+		item.Title = *req.Title
+	}
+	if req.Checked != nil {
+		item.Checked = *req.Checked
+	}
+	if req.Assignee != nil {
+		// Пустая строка снимает исполнителя
+		item.Assignee = req.Assignee
+		if *req.Assignee == "" {
+			item.Assignee = nil
+		}
+	}
+
+	if err := s.repos.Checklists.Update(r.Context(), item); err != nil {
+		writeRepoError(w, err, "Checklist item not found")
+		return
+	}
+
+	s.broadcastChecklist(r.Context(), task, "checklist_item_updated", checklistEvent{Item: item})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+func (s *Server) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	task, item, ok := s.checklistItem(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.repos.Checklists.Delete(r.Context(), item.ID); err != nil {
+		writeRepoError(w, err, "Checklist item not found")
+		return
+	}
+
+	s.broadcastChecklist(r.Context(), task, "checklist_item_deleted", checklistEvent{ItemID: &item.ID})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) ReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.ReorderChecklistRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	items, err := s.repos.ReorderChecklist(r.Context(), task.ID, req.ItemIDs)
+	if errors.Is(err, repository.ErrChecklistOrder) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeRepoError(w, err, "Checklist item not found")
+		return
+	}
+
+	s.broadcastChecklist(r.Context(), task, "checklist_reordered", checklistEvent{Items: items})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// taskFromPath читает задачу из пути и при ошибке сам пишет ответ
+func (s *Server) taskFromPath(w http.ResponseWriter, r *http.Request) (*models.Task, bool) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	task, err := s.repos.Tasks.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Task not found")
+		return nil, false
+	}
+	return task, true
+}
+
+// checklistItem читает задачу и ее пункт чек-листа из пути
+func (s *Server) checklistItem(w http.ResponseWriter, r *http.Request) (*models.Task, *models.ChecklistItem, bool) {
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+
+	itemID, err := uuid.Parse(mux.Vars(r)["item_id"])
+	if err != nil {
+		http.Error(w, "Invalid checklist item ID", http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	item, err := s.repos.Checklists.GetByID(r.Context(), itemID)
+	if err == nil && item.TaskID != task.ID {
+		err = repository.ErrNotFound
+	}
+	if err != nil {
+		writeRepoError(w, err, "Checklist item not found")
+		return nil, nil, false
+	}
+	return task, item, true
+}
+
+// broadcastChecklist отправляет событие чек-листа с обновленным прогрессом задачи.
+// Прогресс входит в JSON задачи, поэтому кэш задач доски сбрасывается.
+func (s *Server) broadcastChecklist(ctx context.Context, task *models.Task, eventType string, event checklistEvent) {
+	s.invalidateTasksCache(ctx, task.BoardID)
+
+	event.TaskID = task.ID
+	event.Progress = task.Progress
+	if updated, err := s.repos.Tasks.GetByID(ctx, task.ID); err == nil {
+		event.Progress = updated.Progress
+	}
+	s.broadcast(task.BoardID.String(), eventType, event)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"task-flow-backend/models"
+	"task-flow-backend/templates"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecklistAndSubtasks(t *testing.T) {
+	hub := &recordingHub{}
+	server, router := newTestServer(t, WithHub(hub))
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Launch", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+	task := &models.Task{BoardID: board.ID, Title: "Launch site", Status: "plan"}
+	require.NoError(t, server.repos.Tasks.Create(context.Background(), task))
+	taskPath := "/api/tasks/" + task.ID.String()
+
+	progress := func(t *testing.T) models.TaskProgress {
+		t.Helper()
+		rr := doRequest(t, router, userID, "GET", taskPath, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var loaded models.Task
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &loaded))
+		return loaded.Progress
+	}
+
+	var item models.ChecklistItem
+	itemPath := func() string { return taskPath + "/checklist/" + item.ID.String() }
+
+	t.Run("Add checklist items", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "POST", taskPath+"/checklist", `{"title":" "}`).Code)
+
+		hub.events = nil
+		rr := doRequest(t, router, userID, "POST", taskPath+"/checklist", `{"title":"Buy domain","assignee":"alice"}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &item))
+		assert.Equal(t, []recordedEvent{{boardID: board.ID.String(), eventType: "checklist_item_created"}}, hub.events)
+		require.Equal(t, http.StatusCreated, doRequest(t, router, userID, "POST", taskPath+"/checklist", `{"title":"Deploy"}`).Code)
+		assert.Equal(t, models.TaskProgress{Done: 0, Total: 2}, progress(t))
+	})
+
+	t.Run("Checked item counts toward progress", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "PATCH", itemPath(), `{"checked":true}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, models.TaskProgress{Done: 1, Total: 2}, progress(t))
+
+		rr = doRequest(t, router, userID, "GET", taskPath+"/checklist", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var items []models.ChecklistItem
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &items))
+		require.Len(t, items, 2)
+		assert.True(t, items[0].Checked)
+	})
+
+	t.Run("Reorder checklist", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "GET", taskPath+"/checklist", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var items []models.ChecklistItem
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &items))
+		require.Len(t, items, 2)
+
+		body := `{"item_ids":["` + items[1].ID.String() + `","` + items[0].ID.String() + `"]}`
+		require.Equal(t, http.StatusOK, doRequest(t, router, userID, "PUT", taskPath+"/checklist/order", body).Code)
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "PUT", taskPath+"/checklist/order", `{"item_ids":[]}`).Code)
+	})
+
+	t.Run("Delete checklist item", func(t *testing.T) {
+		hub.events = nil
+		require.Equal(t, http.StatusNoContent, doRequest(t, router, userID, "DELETE", itemPath(), "").Code)
+		assert.Equal(t, []recordedEvent{{boardID: board.ID.String(), eventType: "checklist_item_deleted"}}, hub.events)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "PATCH", itemPath(), `{"checked":false}`).Code)
+	})
+
+	var subtask models.Task
+	t.Run("Create subtask", func(t *testing.T) {
+		body := `{"board_id":"` + board.ID.String() + `","title":"Write copy","parent_task_id":"` + task.ID.String() + `"}`
+		rr := doRequest(t, router, userID, "POST", "/api/tasks", body)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &subtask))
+		assert.Equal(t, models.TaskProgress{Done: 0, Total: 2}, progress(t))
+
+		rr = doRequest(t, router, userID, "GET", taskPath+"/subtasks", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var subtasks []models.Task
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &subtasks))
+		require.Len(t, subtasks, 1)
+	})
+
+	t.Run("Closed subtask counts toward parent progress", func(t *testing.T) {
+		hub.events = nil
+		require.Equal(t, http.StatusOK, doRequest(t, router, userID, "PATCH", "/api/tasks/"+subtask.ID.String()+"/move", `{"status":"closed"}`).Code)
+		assert.Equal(t, []recordedEvent{
+			{boardID: board.ID.String(), eventType: "task_moved"},
+			{boardID: board.ID.String(), eventType: "task_updated"},
+		}, hub.events, "Parent progress change is broadcast")
+		assert.Equal(t, models.TaskProgress{Done: 1, Total: 2}, progress(t))
+	})
+
+	t.Run("Reparent subtask", func(t *testing.T) {
+		cycle := `{"parent_task_id":"` + subtask.ID.String() + `"}`
+		assert.Equal(t, http.StatusConflict, doRequest(t, router, userID, "PUT", taskPath+"/parent", cycle).Code)
+		assert.Equal(t, http.StatusOK, doRequest(t, router, userID, "PUT", "/api/tasks/"+subtask.ID.String()+"/parent", `{"parent_task_id":null}`).Code)
+		assert.Equal(t, models.TaskProgress{Done: 0, Total: 1}, progress(t))
+	})
+}
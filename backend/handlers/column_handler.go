@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"task-flow-backend/models"
 	"task-flow-backend/repository"
@@ -10,7 +11,7 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func GetColumns(w http.ResponseWriter, r *http.Request) {
+func (s *Server) GetColumns(w http.ResponseWriter, r *http.Request) {
 	boardIDStr := r.URL.Query().Get("board_id")
 	if boardIDStr == "" {
 		http.Error(w, "board_id parameter is required", http.StatusBadRequest)
@@ -23,7 +24,7 @@ func GetColumns(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	columns, err := repository.GetColumnsByBoardID(boardID)
+	columns, err := s.repos.Columns.ListByBoard(r.Context(), boardID, listOptions(r))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -33,33 +34,43 @@ func GetColumns(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(columns)
 }
 
-func CreateColumn(w http.ResponseWriter, r *http.Request) {
+func (s *Server) CreateColumn(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateColumnRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
+	if req.WIPLimit != nil && *req.WIPLimit <= 0 {
+		http.Error(w, "wip_limit must be positive", http.StatusBadRequest)
+		return
+	}
+
 	column := &models.Column{
 		BoardID:  req.BoardID,
 		Title:    req.Title,
 		StatusID: req.StatusID,
 		Position: req.Position,
+		WIPLimit: req.WIPLimit,
 	}
 
-	if err := repository.CreateColumn(column); err != nil {
+	if err := s.repos.Columns.Create(r.Context(), column); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			http.Error(w, "Column with this status_id already exists", http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	BroadcastColumnUpdate(column.BoardID.String(), "column_created", column)
+	s.broadcast(column.BoardID.String(), "column_created", column)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(column)
 }
 
-func DeleteColumn(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteColumn(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.Parse(vars["id"])
 	if err != nil {
@@ -67,19 +78,27 @@ func DeleteColumn(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	column, err := repository.GetColumnByID(id)
-	if err != nil {
-		http.Error(w, "Column not found", http.StatusNotFound)
+	// Задачи удаляемой колонки переносятся в колонку move_to (по умолчанию - первую оставшуюся)
+	column, moved, err := s.repos.DeleteColumn(r.Context(), id, r.URL.Query().Get("move_to"))
+	switch {
+	case errors.Is(err, repository.ErrUnknownStatus):
+		http.Error(w, "move_to does not match any column of the board", http.StatusBadRequest)
 		return
-	}
-
-	if err := repository.DeleteColumn(id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	case errors.Is(err, repository.ErrColumnNotEmpty):
+		http.Error(w, "Cannot delete the last column while it has tasks", http.StatusConflict)
+		return
+	case err != nil:
+		writeRepoError(w, err, "Column not found")
 		return
 	}
 
-	BroadcastColumnUpdate(column.BoardID.String(), "column_deleted", map[string]string{"id": id.String()})
+	if len(moved) > 0 {
+		s.invalidateTasksCache(r.Context(), column.BoardID)
+	}
+	for i := range moved {
+		s.broadcast(column.BoardID.String(), "task_moved", &moved[i])
+	}
+	s.broadcast(column.BoardID.String(), "column_deleted", map[string]string{"id": id.String()})
 
 	w.WriteHeader(http.StatusNoContent)
 }
-
@@ -1,27 +1,30 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"testing"
 	"task-flow-backend/models"
 	"task-flow-backend/repository"
+	"task-flow-backend/templates"
+	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestGetColumns(t *testing.T) {
-	setupTestDB(t)
-	
-	userID := createTestUser(t)
+	server, router := newTestServer(t)
+
+	userID := createTestUser(t, server)
 
 	board := &models.Board{
 		Name:   "Test Board for Columns",
 		UserID: &userID,
 	}
-	err := repository.CreateBoard(board)
+	err := server.repos.Boards.Create(context.Background(), board)
 	require.NoError(t, err, "Failed to create test board")
 
 	column := &models.Column{
@@ -30,7 +33,7 @@ func TestGetColumns(t *testing.T) {
 		StatusID: "test-status",
 		Position: 0,
 	}
-	err = repository.CreateColumn(column)
+	err = server.repos.Columns.Create(context.Background(), column)
 	require.NoError(t, err, "Failed to create test column")
 
 	t.Run("Get columns by board_id", func(t *testing.T) {
@@ -38,8 +41,7 @@ func TestGetColumns(t *testing.T) {
 		require.NoError(t, err)
 
 		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(GetColumns)
-		handler.ServeHTTP(rr, req)
+		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusOK, rr.Code, "Expected status 200")
 		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
@@ -48,7 +50,7 @@ func TestGetColumns(t *testing.T) {
 		err = json.Unmarshal(rr.Body.Bytes(), &columns)
 		require.NoError(t, err, "Failed to unmarshal response")
 		assert.GreaterOrEqual(t, len(columns), 1, "Expected at least one column")
-		
+
 		found := false
 		for _, c := range columns {
 			if c.ID == column.ID {
@@ -66,8 +68,7 @@ func TestGetColumns(t *testing.T) {
 		require.NoError(t, err)
 
 		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(GetColumns)
-		handler.ServeHTTP(rr, req)
+		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code, "Expected status 400")
 	})
@@ -77,13 +78,52 @@ func TestGetColumns(t *testing.T) {
 		require.NoError(t, err)
 
 		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(GetColumns)
-		handler.ServeHTTP(rr, req)
+		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code, "Expected status 400")
 	})
 
-	repository.DeleteColumn(column.ID)
-	repository.DeleteBoard(board.ID)
+	t.Run("Create column with duplicate status_id", func(t *testing.T) {
+		body := `{"board_id":"` + board.ID.String() + `","title":"Again","status_id":"test-status","position":7}`
+		req, err := http.NewRequest("POST", "/api/columns", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		authorize(t, req, userID)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusConflict, rr.Code, "Expected status 409")
+	})
 }
 
+func TestDeleteColumnMovesTasks(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Board with tasks", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+
+	columns, err := server.repos.Columns.ListByBoard(context.Background(), board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	plan := columns[0]
+
+	task := &models.Task{BoardID: board.ID, Title: "Planned", Status: plan.StatusID}
+	require.NoError(t, server.repos.Tasks.Create(context.Background(), task))
+
+	deleteColumn := func(query string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("DELETE", "/api/columns/"+plan.ID.String()+query, nil)
+		require.NoError(t, err)
+		authorize(t, req, userID)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusBadRequest, deleteColumn("?move_to=unknown").Code, "Expected status 400 for unknown target")
+	assert.Equal(t, http.StatusNoContent, deleteColumn("?move_to=closed").Code, "Expected status 204")
+
+	moved, err := server.repos.Tasks.GetByID(context.Background(), task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "closed", moved.Status)
+}
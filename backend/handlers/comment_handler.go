@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"task-flow-backend/models"
+
+	"github.com/google/uuid"
+)
+
+func (s *Server) GetComments(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	comments, err := s.repos.Comments.ListByTask(r.Context(), task.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if comments == nil {
+		comments = []models.TaskComment{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(comments)
+}
+
+func (s *Server) CreateComment(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		http.Error(w, "body is required", http.StatusBadRequest)
+		return
+	}
+
+	comment := &models.TaskComment{TaskID: task.ID, Body: req.Body}
+	if userID, ok := userIDFromContext(r.Context()); ok {
+		comment.UserID = &userID
+	}
+	if err := s.repos.Comments.Create(r.Context(), comment); err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+
+	s.CommentCreated(r.Context(), task.BoardID, comment)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+}
+
+// CommentCreated рассылает comment_created; вызывается и для комментариев,
+// добавленных правилами автоматизации
+func (s *Server) CommentCreated(ctx context.Context, boardID uuid.UUID, comment *models.TaskComment) {
+	s.broadcast(boardID.String(), "comment_created", comment)
+}
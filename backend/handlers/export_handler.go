@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"task-flow-backend/export"
+	"task-flow-backend/logging"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetBoardExport выгружает доску со всеми колонками и задачами, включая
+// архивные. Формат задается format: json (по умолчанию, полная выгрузка по
+// схеме taskflow.board), csv (строка на задачу) или xlsx; с ?comments=true
+// добавляются комментарии. Ответ пишется потоком, поэтому ошибка после
+// начала записи только логируется.
+func (s *Server) GetBoardExport(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		format = export.FormatJSON
+	}
+	contentType, ok := export.ContentTypes[format]
+	if !ok {
+		http.Error(w, "format must be csv, json or xlsx", http.StatusBadRequest)
+		return
+	}
+
+	board, err := export.Load(r.Context(), s.repos, boardID, export.Options{Comments: query.Get("comments") == "true"})
+	if err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, board.Name(), format))
+	if err := board.Write(r.Context(), w, format); err != nil {
+		logging.FromContext(r.Context()).Warn("Failed to export board", "board_id", boardID, "format", format, "error", err)
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-flow-backend/models"
+	"task-flow-backend/templates"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestBoardExport(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+	ctx := context.Background()
+
+	tpl := templates.Default()
+	tpl.Tasks = nil
+	board := &models.Board{Name: "Export", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(ctx, board, tpl))
+	require.NoError(t, server.repos.Labels.Create(ctx, &models.Label{BoardID: board.ID, Name: "bug", Color: "#ff0000"}))
+
+	priority, assignee, points := "high", "alice", 3
+	dueAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	first := &models.Task{BoardID: board.ID, Title: "Login, \"SSO\"", Status: "development", Priority: &priority, Assignee: &assignee, StoryPoints: &points, DueAt: &dueAt, CreatedBy: &userID}
+	require.NoError(t, server.repos.Tasks.Create(ctx, first))
+	second := &models.Task{BoardID: board.ID, Title: "Docs", Status: "plan"}
+	require.NoError(t, server.repos.Tasks.Create(ctx, second))
+	require.NoError(t, server.repos.Checklists.Create(ctx, &models.ChecklistItem{TaskID: first.ID, Title: "Callback"}))
+	require.NoError(t, server.repos.Comments.Create(ctx, &models.TaskComment{TaskID: first.ID, UserID: &userID, Body: "Started"}))
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "/api/boards/"+board.ID.String()+"/export"+query, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("json", func(t *testing.T) {
+		rr := get("?comments=true")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Header().Get("Content-Disposition"), ".json")
+
+		var exported models.BoardExport
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &exported))
+		assert.Equal(t, "taskflow.board", exported.Schema)
+		assert.Equal(t, 1, exported.Version)
+		assert.Equal(t, board.ID, exported.Board.ID)
+		assert.Len(t, exported.Columns, 5)
+		require.Len(t, exported.Labels, 1)
+		require.Len(t, exported.Tasks, 2)
+		assert.Equal(t, first.ID, exported.Tasks[0].ID, "Expected tasks in creation order")
+		require.Len(t, exported.Tasks[0].Checklist, 1)
+		require.Len(t, exported.Tasks[0].Comments, 1)
+		assert.Equal(t, "Started", exported.Tasks[0].Comments[0].Body)
+		assert.NotEmpty(t, exported.Tasks[0].Comments[0].Author)
+		assert.NotNil(t, exported.Tasks[1].Checklist)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		rr := get("?format=csv")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+
+		records, err := csv.NewReader(bytes.NewReader(rr.Body.Bytes())).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{
+			"id", "title", "description", "column", "status", "priority", "assignee",
+			"estimate_minutes", "story_points", "due_at", "sprint", "parent_task_id",
+			"created_by", "created_at", "updated_at", "archived_at",
+		}, records[0])
+		assert.Equal(t, first.Title, records[1][1])
+		assert.Equal(t, "development", records[1][4])
+		assert.Equal(t, "high", records[1][5])
+		assert.Equal(t, "3", records[1][8])
+		assert.Equal(t, "2026-03-01T12:00:00Z", records[1][9])
+
+		rr = get("?format=csv&comments=true")
+		records, err = csv.NewReader(bytes.NewReader(rr.Body.Bytes())).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, "comments", records[0][len(records[0])-1])
+		assert.Contains(t, records[1][len(records[1])-1], "Started")
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		rr := get("?format=xlsx")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		f, err := excelize.OpenReader(bytes.NewReader(rr.Body.Bytes()))
+		require.NoError(t, err)
+		defer f.Close()
+		assert.Equal(t, []string{"Tasks", "Columns", "Labels", "Sprints"}, f.GetSheetList())
+		rows, err := f.GetRows("Tasks")
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, "title", rows[0][1])
+		assert.Equal(t, "Docs", rows[2][1])
+	})
+
+	assert.Equal(t, http.StatusBadRequest, get("?format=pdf").Code)
+	req, err := http.NewRequest("GET", "/api/boards/"+userID.String()+"/export", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"task-flow-backend/auth"
+	"task-flow-backend/models"
+	"task-flow-backend/repository/memory"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer создает Server поверх in-memory репозиториев и роутер со всеми маршрутами API
+func newTestServer(t *testing.T, opts ...Option) (*Server, *mux.Router) {
+	t.Helper()
+
+	server := NewServer(memory.NewRepositories(), opts...)
+	router := mux.NewRouter()
+	server.Routes(router)
+
+	return server, router
+}
+
+// createTestUser создает тестового пользователя для тестов
+func createTestUser(t *testing.T, s *Server) uuid.UUID {
+	t.Helper()
+
+	user := &models.User{
+		Username: "testuser",
+		Email:    "testuser@test.com",
+	}
+	err := s.repos.Users.Create(context.Background(), user, "testpass123")
+	require.NoError(t, err, "Failed to create test user")
+
+	return user.ID
+}
+
+// authorize добавляет в запрос JWT токен пользователя
+func authorize(t *testing.T, req *http.Request, userID uuid.UUID) {
+	t.Helper()
+
+	token, err := auth.GenerateToken(userID, "testuser", 0)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+// doRequest выполняет запрос к router от имени userID; uuid.Nil отправляет
+// запрос без токена, пустое body - без тела
+func doRequest(t *testing.T, router http.Handler, userID uuid.UUID, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader = http.NoBody
+	if body != "" {
+		reader = bytes.NewBufferString(body)
+	}
+	req, err := http.NewRequest(method, path, reader)
+	require.NoError(t, err)
+	if userID != uuid.Nil {
+		authorize(t, req, userID)
+	}
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
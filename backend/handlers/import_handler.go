@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"task-flow-backend/importer"
+	"task-flow-backend/logging"
+	"task-flow-backend/metrics"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// maxImportSize ограничивает размер файла импорта
+	maxImportSize = 50 << 20
+	// importProgressStep - через сколько задач сохраняется прогресс задания
+	importProgressStep = 100
+)
+
+// ImportBoard импортирует доску из поля file формы multipart: выгрузки
+// Trello (JSON), Jira (CSV) или Task Flow (JSON). Поле format задает формат
+// (по умолчанию определяется по содержимому), name - имя доски, user_mapping -
+// JSON-объект "пользователь источника": "имя пользователя Task Flow".
+// С dry_run=true отвечает предпросмотром и ничего не создает, иначе создает
+// задание импорта и отвечает 202: доска создается в фоне, состояние
+// задания отдает GetImportJob.
+func (s *Server) ImportBoard(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeUploadError(w, err, "Expected multipart/form-data request")
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing file field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxImportSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(data) > maxImportSize {
+		http.Error(w, "File exceeds maximum size of "+strconv.Itoa(maxImportSize)+" bytes", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var mapping map[string]string
+	if value := r.FormValue("user_mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+			http.Error(w, "Invalid user_mapping: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	plan, err := importer.Parse(r.FormValue("format"), data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if name := r.FormValue("name"); name != "" {
+		plan.Name = name
+	}
+
+	users, err := plan.ResolveUsers(r.Context(), s.repos, mapping)
+	if errors.Is(err, importer.ErrUserMapping) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.FormValue("dry_run") == "true" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(plan.Preview(users))
+		return
+	}
+
+	job := &models.ImportJob{
+		UserID:     &userID,
+		Format:     plan.Format,
+		BoardName:  plan.Name,
+		Status:     models.ImportJobPending,
+		TotalTasks: len(plan.Tasks),
+	}
+	if err := s.repos.ImportJobs.Create(r.Context(), job); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Импорт переживает завершение запроса, но сохраняет логгер и трассировку
+	go s.runImport(context.WithoutCancel(r.Context()), *job, plan, users)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/imports/"+job.ID.String())
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+}
+
+// runImport создает доску по плану и сохраняет прогресс и итог задания
+func (s *Server) runImport(ctx context.Context, job models.ImportJob, plan *importer.Plan, users importer.Users) {
+	logger := logging.FromContext(ctx).With("import_job_id", job.ID)
+
+	job.Status = models.ImportJobRunning
+	if err := s.repos.ImportJobs.Update(ctx, &job); err != nil {
+		logger.Error("Failed to update import job", "error", err)
+	}
+
+	board := &models.Board{UserID: job.UserID}
+	err := plan.Apply(ctx, s.repos, board, users, func(imported int) {
+		if imported%importProgressStep != 0 {
+			return
+		}
+		progress := job
+		progress.ImportedTasks = imported
+		if err := s.repos.ImportJobs.Update(ctx, &progress); err != nil {
+			logger.Warn("Failed to save import progress", "error", err)
+		}
+	})
+
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	if err != nil {
+		logger.Error("Board import failed", "error", err)
+		message := err.Error()
+		job.Status = models.ImportJobFailed
+		job.Error = &message
+	} else {
+		logger.Info("Board imported", "board_id", board.ID, "tasks", job.TotalTasks)
+		metrics.BoardsCreated.Inc()
+		job.Status = models.ImportJobCompleted
+		job.BoardID = &board.ID
+		job.ImportedTasks = job.TotalTasks
+	}
+	if err := s.repos.ImportJobs.Update(ctx, &job); err != nil {
+		logger.Error("Failed to update import job", "error", err)
+	}
+}
+
+// GetImportJob возвращает состояние задания импорта; чужие задания не видны
+func (s *Server) GetImportJob(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid import job ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := s.repos.ImportJobs.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Import job not found")
+		return
+	}
+	if userID, ok := userIDFromContext(r.Context()); !ok || job.UserID == nil || *job.UserID != userID {
+		http.Error(w, "Import job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
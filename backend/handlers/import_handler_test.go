@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const trelloBoardExport = `{
+	"name": "Roadmap",
+	"lists": [{"id": "l1", "name": "To Do", "pos": 1}, {"id": "l2", "name": "Done", "pos": 2}],
+	"cards": [
+		{"id": "c1", "name": "Login", "idList": "l1", "pos": 1, "idMembers": ["m1"]},
+		{"id": "c2", "name": "Signup", "idList": "l2", "pos": 1}
+	],
+	"members": [{"id": "m1", "username": "trello.alice"}],
+	"actions": [{"type": "commentCard", "date": "2026-02-01T10:00:00.000Z", "data": {"text": "Hi", "card": {"id": "c1"}}, "memberCreator": {"username": "trello.alice"}}]
+}`
+
+func TestImportBoard(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+	ctx := context.Background()
+
+	do := func(req *http.Request, userID uuid.UUID) *httptest.ResponseRecorder {
+		authorize(t, req, userID)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	upload := func(content string, fields map[string]string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for name, value := range fields {
+			require.NoError(t, writer.WriteField(name, value))
+		}
+		part, err := writer.CreateFormFile("file", "board.json")
+		require.NoError(t, err)
+		part.Write([]byte(content))
+		require.NoError(t, writer.Close())
+
+		req, err := http.NewRequest("POST", "/api/boards/import", &body)
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return do(req, userID)
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		rr := upload(trelloBoardExport, map[string]string{"dry_run": "true", "user_mapping": `{"trello.alice": "testuser"}`})
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var preview models.ImportPreview
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &preview))
+		assert.Equal(t, "trello", preview.Format)
+		assert.Equal(t, 2, preview.Tasks)
+		assert.Len(t, preview.Columns, 2)
+		require.Len(t, preview.Users, 1)
+		require.NotNil(t, preview.Users[0].Username)
+		assert.Equal(t, "testuser", *preview.Users[0].Username)
+
+		boards, err := server.repos.Boards.List(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, boards, "Expected dry run to create nothing")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, upload(`{"name": "Unknown"}`, nil).Code)
+		assert.Equal(t, http.StatusBadRequest, upload(trelloBoardExport, map[string]string{"user_mapping": `{"trello.alice": "ghost"}`}).Code)
+		assert.Equal(t, http.StatusBadRequest, upload(trelloBoardExport, map[string]string{"format": "asana"}).Code)
+
+		req, err := http.NewRequest("POST", "/api/boards/import", bytes.NewBufferString("{}"))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		assert.Equal(t, http.StatusBadRequest, do(req, userID).Code)
+	})
+
+	t.Run("import", func(t *testing.T) {
+		rr := upload(trelloBoardExport, map[string]string{"name": "Imported", "user_mapping": `{"trello.alice": "testuser"}`})
+		require.Equal(t, http.StatusAccepted, rr.Code, rr.Body.String())
+		var job models.ImportJob
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &job))
+		assert.Equal(t, "/api/imports/"+job.ID.String(), rr.Header().Get("Location"))
+		assert.Equal(t, 2, job.TotalTasks)
+
+		getJob := func(userID uuid.UUID) *httptest.ResponseRecorder {
+			req, err := http.NewRequest("GET", "/api/imports/"+job.ID.String(), nil)
+			require.NoError(t, err)
+			return do(req, userID)
+		}
+		require.Eventually(t, func() bool {
+			rr := getJob(userID)
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &job))
+			return job.Status == models.ImportJobCompleted || job.Status == models.ImportJobFailed
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, models.ImportJobCompleted, job.Status, job.Error)
+		require.NotNil(t, job.BoardID)
+		assert.Equal(t, 2, job.ImportedTasks)
+		assert.NotNil(t, job.FinishedAt)
+
+		board, err := server.repos.Boards.GetByID(ctx, *job.BoardID)
+		require.NoError(t, err)
+		assert.Equal(t, "Imported", board.Name)
+		tasks, err := server.repos.Tasks.ListByBoard(ctx, board.ID, repository.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		for _, task := range tasks {
+			if task.Title == "Login" {
+				require.NotNil(t, task.Assignee)
+				assert.Equal(t, "testuser", *task.Assignee)
+			}
+		}
+
+		other := &models.User{Username: "other", Email: "other@test.com"}
+		require.NoError(t, server.repos.Users.Create(ctx, other, "password"))
+		assert.Equal(t, http.StatusNotFound, getJob(other.ID).Code)
+	})
+}
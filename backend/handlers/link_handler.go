@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const dotContentType = "text/vnd.graphviz; charset=utf-8"
+
+func (s *Server) GetTaskLinks(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	links, err := s.repos.Links.ListByTask(r.Context(), task.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if links == nil {
+		links = []models.TaskLink{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(links)
+}
+
+// CreateTaskLink связывает задачу из пути (исходную) с target_task_id
+func (s *Server) CreateTaskLink(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateTaskLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	link := &models.TaskLink{SourceTaskID: id, TargetTaskID: req.TargetTaskID, Type: req.Type}
+	if userID, ok := userIDFromContext(r.Context()); ok {
+		link.CreatedBy = &userID
+	}
+
+	err = s.repos.LinkTasks(r.Context(), link)
+	switch {
+	case errors.Is(err, repository.ErrInvalidLink):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, repository.ErrLinkCycle):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, repository.ErrConflict):
+		http.Error(w, "Link already exists", http.StatusConflict)
+		return
+	case err != nil:
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+
+	s.broadcastLink(r, link, "task_link_created", link)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(link)
+}
+
+func (s *Server) DeleteTaskLink(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+	linkID, err := uuid.Parse(vars["link_id"])
+	if err != nil {
+		http.Error(w, "Invalid link ID", http.StatusBadRequest)
+		return
+	}
+
+	link, err := s.repos.Links.GetByID(r.Context(), linkID)
+	if err == nil && link.SourceTaskID != id && link.TargetTaskID != id {
+		err = repository.ErrNotFound
+	}
+	if err != nil {
+		writeRepoError(w, err, "Link not found")
+		return
+	}
+
+	if err := s.repos.Links.Delete(r.Context(), linkID); err != nil {
+		writeRepoError(w, err, "Link not found")
+		return
+	}
+
+	s.broadcastLink(r, link, "task_link_deleted", map[string]string{"id": linkID.String()})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDependencyGraph отдает граф связей доски в JSON или, с ?format=dot
+// либо Accept: text/vnd.graphviz, в формате Graphviz DOT.
+func (s *Server) GetDependencyGraph(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	graph, err := s.repos.DependencyGraph(r.Context(), boardID)
+	if err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" && strings.Contains(r.Header.Get("Accept"), "text/vnd.graphviz") {
+		format = "dot"
+	}
+
+	switch format {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(graph)
+	case "dot":
+		w.Header().Set("Content-Type", dotContentType)
+		writeDOT(w, boardID, graph)
+	default:
+		http.Error(w, "format must be json or dot", http.StatusBadRequest)
+	}
+}
+
+// writeDOT выводит граф: blocks - сплошная стрелка, relates_to - пунктир
+// без направления, duplicates - точечная стрелка. Заблокированные задачи
+// выделены красным.
+func writeDOT(w io.Writer, boardID uuid.UUID, graph *models.DependencyGraph) {
+	fmt.Fprintf(w, "digraph %s {\n", dotQuote("board "+boardID.String()))
+	fmt.Fprintln(w, "\trankdir=LR;")
+	fmt.Fprintln(w, "\tnode [shape=box];")
+
+	for _, node := range graph.Nodes {
+		attrs := "label=" + dotQuote(node.Title+"\n["+node.Status+"]")
+		if node.BoardID != boardID {
+			attrs += ", style=dashed"
+		}
+		if node.Blocked {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(w, "\t%s [%s];\n", dotQuote(node.ID.String()), attrs)
+	}
+
+	for _, edge := range graph.Edges {
+		attrs := "label=" + dotQuote(edge.Type)
+		switch edge.Type {
+		case models.LinkTypeRelatesTo:
+			attrs += ", style=dashed, dir=none"
+		case models.LinkTypeDuplicates:
+			attrs += ", style=dotted"
+		}
+		fmt.Fprintf(w, "\t%s -> %s [%s];\n", dotQuote(edge.Source.String()), dotQuote(edge.Target.String()), attrs)
+	}
+
+	fmt.Fprintln(w, "}")
+}
+
+// dotQuote экранирует строку для идентификатора DOT в кавычках
+func dotQuote(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "")
+	return `"` + replacer.Replace(s) + `"`
+}
+
+// broadcastLink отправляет событие на доски обеих задач связи
+func (s *Server) broadcastLink(r *http.Request, link *models.TaskLink, eventType string, data interface{}) {
+	boards := map[uuid.UUID]bool{}
+	for _, taskID := range []uuid.UUID{link.SourceTaskID, link.TargetTaskID} {
+		task, err := s.repos.Tasks.GetByID(r.Context(), taskID)
+		if err != nil || boards[task.BoardID] {
+			continue
+		}
+		boards[task.BoardID] = true
+		s.broadcast(task.BoardID.String(), eventType, data)
+	}
+}
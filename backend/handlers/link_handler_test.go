@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"task-flow-backend/models"
+	"task-flow-backend/templates"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskLinks(t *testing.T) {
+	hub := &recordingHub{}
+	server, router := newTestServer(t, WithHub(hub))
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Dependencies", UserID: &userID, EnforceDependencies: true}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+	blocker := &models.Task{BoardID: board.ID, Title: `Schema "v2"`, Status: "plan"}
+	require.NoError(t, server.repos.Tasks.Create(context.Background(), blocker))
+	blocked := &models.Task{BoardID: board.ID, Title: "Migration", Status: "plan"}
+	require.NoError(t, server.repos.Tasks.Create(context.Background(), blocked))
+
+	linksPath := "/api/tasks/" + blocker.ID.String() + "/links"
+	var link models.TaskLink
+	t.Run("Create link", func(t *testing.T) {
+		hub.events = nil
+		rr := doRequest(t, router, userID, "POST", linksPath, `{"target_task_id":"`+blocked.ID.String()+`","type":"blocks"}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &link))
+		assert.Equal(t, []recordedEvent{{boardID: board.ID.String(), eventType: "task_link_created"}}, hub.events)
+	})
+
+	t.Run("Reject cycle and unknown type", func(t *testing.T) {
+		reverse := `{"target_task_id":"` + blocker.ID.String() + `","type":"blocks"}`
+		assert.Equal(t, http.StatusConflict, doRequest(t, router, userID, "POST", "/api/tasks/"+blocked.ID.String()+"/links", reverse).Code)
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "POST", linksPath, `{"target_task_id":"`+blocked.ID.String()+`","type":"follows"}`).Code)
+	})
+
+	t.Run("Blocked task cannot be closed", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "PATCH", "/api/tasks/"+blocked.ID.String()+"/move", `{"status":"closed"}`)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Contains(t, rr.Body.String(), `Schema \"v2\"`)
+		assert.Equal(t, http.StatusConflict, doRequest(t, router, userID, "PUT", "/api/tasks/"+blocked.ID.String(), `{"status":"closed"}`).Code)
+		assert.Equal(t, http.StatusOK, doRequest(t, router, userID, "PATCH", "/api/tasks/"+blocked.ID.String()+"/move", `{"status":"testing"}`).Code)
+	})
+
+	t.Run("Dependency graph", func(t *testing.T) {
+		path := "/api/boards/" + board.ID.String() + "/dependency-graph"
+		rr := doRequest(t, router, userID, "GET", path, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var graph models.DependencyGraph
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &graph))
+		assert.Len(t, graph.Nodes, 2)
+		require.Len(t, graph.Edges, 1)
+		assert.Equal(t, models.GraphEdge{Source: blocker.ID, Target: blocked.ID, Type: models.LinkTypeBlocks}, graph.Edges[0])
+
+		rr = doRequest(t, router, userID, "GET", path+"?format=dot", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, dotContentType, rr.Header().Get("Content-Type"))
+		dot := rr.Body.String()
+		assert.True(t, strings.HasPrefix(dot, "digraph "))
+		assert.Contains(t, dot, `"`+blocker.ID.String()+`" -> "`+blocked.ID.String()+`" [label="blocks"];`)
+		assert.Contains(t, dot, `label="Schema \"v2\"\n[plan]"`)
+
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "GET", path+"?format=svg", "").Code)
+	})
+
+	t.Run("Removing the link unblocks the task", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "DELETE", "/api/tasks/"+board.ID.String()+"/links/"+link.ID.String(), "").Code)
+		require.Equal(t, http.StatusNoContent, doRequest(t, router, userID, "DELETE", linksPath+"/"+link.ID.String(), "").Code)
+		assert.Equal(t, http.StatusOK, doRequest(t, router, userID, "PATCH", "/api/tasks/"+blocked.ID.String()+"/move", `{"status":"closed"}`).Code)
+	})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"task-flow-backend/logging"
+	"task-flow-backend/tracing"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(logging.New(&buf, slog.LevelInfo, "json"))
+	defer slog.SetDefault(previous)
+
+	shutdown, err := tracing.Init(context.Background(), "task-flow-test")
+	require.NoError(t, err)
+	defer shutdown(context.Background())
+
+	router := mux.NewRouter()
+	router.Use(TracingMiddleware)
+	router.Use(RequestLoggingMiddleware)
+	router.HandleFunc("/api/boards/{id}", func(w http.ResponseWriter, r *http.Request) {
+		logging.With(r.Context(), "user_id", "user-1")
+		http.Error(w, "Board not found", http.StatusNotFound)
+	}).Methods("GET")
+
+	t.Run("Generates request ID", func(t *testing.T) {
+		buf.Reset()
+		req, err := http.NewRequest("GET", "/api/boards/123", nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		requestID := rr.Header().Get(RequestIDHeader)
+		assert.NotEmpty(t, requestID, "Expected generated request ID")
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, requestID, entry["request_id"])
+		assert.Equal(t, "/api/boards/{id}", entry["route"])
+		assert.Equal(t, "user-1", entry["user_id"])
+		assert.Equal(t, float64(http.StatusNotFound), entry["status"])
+		assert.NotEmpty(t, entry["trace_id"])
+	})
+
+	t.Run("Propagates incoming request and trace IDs", func(t *testing.T) {
+		buf.Reset()
+		req, err := http.NewRequest("GET", "/api/boards/123", nil)
+		require.NoError(t, err)
+		req.Header.Set(RequestIDHeader, "client-request-1")
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, "client-request-1", rr.Header().Get(RequestIDHeader))
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "client-request-1", entry["request_id"])
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry["trace_id"])
+	})
+}
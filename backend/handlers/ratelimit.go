@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"task-flow-backend/auth"
+	"task-flow-backend/logging"
+	"task-flow-backend/metrics"
+	"task-flow-backend/ratelimit"
+	"time"
+)
+
+// RateLimits - лимиты частоты запросов к API
+type RateLimits struct {
+	// Default действует на каждый запрос /api: с действительным токеном -
+	// на пользователя, без него - на IP
+	Default ratelimit.Limit
+	// Routes - дополнительные лимиты маршрутов по шаблону пути mux, с тем же ключом
+	Routes map[string]ratelimit.Limit
+	// Lockout блокирует IP и имя пользователя после неудачных входов и IP
+	// после попыток зарегистрироваться с занятыми именем или email
+	Lockout ratelimit.Lockout
+	// TrustProxy берет IP клиента из X-Forwarded-For (последний адрес,
+	// добавленный ближайшим прокси); включать только за обратным прокси
+	TrustProxy bool
+}
+
+var DefaultRateLimits = RateLimits{
+	Default: ratelimit.Limit{Requests: 300, Period: time.Minute},
+	Routes: map[string]ratelimit.Limit{
+		"/api/auth/login":               {Requests: 10, Period: time.Minute},
+		"/api/auth/register":            {Requests: 5, Period: time.Hour},
+		"/api/auth/verify-email":        {Requests: 10, Period: time.Hour},
+		"/api/auth/verify-email/resend": {Requests: 5, Period: time.Hour},
+		"/api/auth/forgot-password":     {Requests: 5, Period: time.Hour},
+		"/api/auth/reset-password":      {Requests: 10, Period: time.Hour},
+		"/api/boards/import":            {Requests: 20, Period: time.Hour},
+	},
+	Lockout: ratelimit.Lockout{Threshold: 5, Base: time.Minute, Max: time.Hour, Window: 15 * time.Minute},
+}
+
+// RateLimitsFromEnv читает RATE_LIMIT_* поверх DefaultRateLimits; неверные
+// значения пропускаются с предупреждением в логе
+func RateLimitsFromEnv() RateLimits {
+	limits := DefaultRateLimits
+	limits.Routes = maps.Clone(DefaultRateLimits.Routes)
+
+	if value := os.Getenv("RATE_LIMIT_DEFAULT"); value != "" {
+		if limit, err := ratelimit.ParseLimit(value); err != nil {
+			slog.Warn("Invalid rate limit, using default", "variable", "RATE_LIMIT_DEFAULT", "error", err)
+		} else {
+			limits.Default = limit
+		}
+	}
+	// RATE_LIMIT_ROUTES=/api/auth/login=5/1m,/api/boards/import=off
+	for _, entry := range strings.Split(os.Getenv("RATE_LIMIT_ROUTES"), ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		route, value, _ := strings.Cut(entry, "=")
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			slog.Warn("Invalid rate limit, ignoring", "variable", "RATE_LIMIT_ROUTES", "route", route, "error", err)
+			continue
+		}
+		limits.Routes[strings.TrimSpace(route)] = limit
+	}
+
+	if value := os.Getenv("RATE_LIMIT_LOCKOUT_THRESHOLD"); value != "" {
+		if threshold, err := strconv.Atoi(value); err == nil && threshold >= 0 {
+			limits.Lockout.Threshold = threshold
+		} else {
+			slog.Warn("Invalid lockout threshold, using default", "value", value)
+		}
+	}
+	for variable, field := range map[string]*time.Duration{
+		"RATE_LIMIT_LOCKOUT_BASE":   &limits.Lockout.Base,
+		"RATE_LIMIT_LOCKOUT_MAX":    &limits.Lockout.Max,
+		"RATE_LIMIT_LOCKOUT_WINDOW": &limits.Lockout.Window,
+	} {
+		value := os.Getenv(variable)
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			*field = d
+		} else {
+			slog.Warn("Invalid duration, using default", "variable", variable, "value", value)
+		}
+	}
+
+	limits.TrustProxy, _ = strconv.ParseBool(os.Getenv("RATE_LIMIT_TRUST_PROXY"))
+	return limits
+}
+
+// RateLimitMiddleware ограничивает частоту запросов /api лимитами Default и
+// Routes. Ответ получает заголовки RateLimit-* самого строгого из них, отказ -
+// 429 с Retry-After. Если хранилище лимитов недоступно, запрос пропускается.
+func (s *Server) RateLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := routeTemplate(r)
+		if s.limiter == nil || !strings.HasPrefix(route, "/api/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		subject := s.rateLimitSubject(r)
+		type check struct {
+			key   string
+			limit ratelimit.Limit
+		}
+		checks := []check{{"api:" + subject, s.rateLimits.Default}}
+		if limit, ok := s.rateLimits.Routes[route]; ok {
+			checks = append(checks, check{"route:" + route + ":" + subject, limit})
+		}
+
+		var strictest *ratelimit.Result
+		for _, check := range checks {
+			if !check.limit.Enabled() {
+				continue
+			}
+			res, err := s.limiter.Take(r.Context(), check.key, check.limit)
+			if err != nil {
+				logging.FromContext(r.Context()).Warn("Rate limit check failed, request allowed", "error", err)
+				continue
+			}
+			if strictest == nil || !res.Allowed || res.Remaining < strictest.Remaining {
+				strictest = &res
+			}
+			if !res.Allowed {
+				break
+			}
+		}
+
+		if strictest != nil {
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(strictest.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(strictest.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(seconds(strictest.Reset)))
+			if !strictest.Allowed {
+				tooManyRequests(w, route, "limit", strictest.RetryAfter)
+				http.Error(w, "Too many requests", http.StatusTooManyRequests)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitSubject - ключ клиента: пользователь по действительному токену, иначе IP.
+// Отзыв токена здесь не проверяется - для учета запросов достаточно подписи.
+func (s *Server) rateLimitSubject(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		if claims, err := auth.ValidateToken(token); err == nil {
+			return "user:" + claims.UserID.String()
+		}
+	}
+	return "ip:" + s.clientIP(r)
+}
+
+func (s *Server) clientIP(r *http.Request) string {
+	if s.rateLimits.TrustProxy {
+		if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+			addrs := strings.Split(values[len(values)-1], ",")
+			if addr := strings.TrimSpace(addrs[len(addrs)-1]); addr != "" {
+				return addr
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// lockedOut отвечает 429, если хотя бы один из keys заблокирован после неудачных попыток
+func (s *Server) lockedOut(w http.ResponseWriter, r *http.Request, keys ...string) bool {
+	if s.limiter == nil {
+		return false
+	}
+	var wait time.Duration
+	for _, key := range keys {
+		d, err := s.rateLimits.Lockout.Remaining(r.Context(), s.limiter, key)
+		if err != nil {
+			logging.FromContext(r.Context()).Warn("Lockout check failed, request allowed", "error", err)
+			continue
+		}
+		wait = max(wait, d)
+	}
+	if wait == 0 {
+		return false
+	}
+
+	tooManyRequests(w, routeTemplate(r), "lockout", wait)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]string{"error": "Too many failed attempts, try again later"})
+	return true
+}
+
+// recordFailure учитывает неудачную попытку для каждого из keys
+func (s *Server) recordFailure(r *http.Request, keys ...string) {
+	if s.limiter == nil {
+		return
+	}
+	for _, key := range keys {
+		d, err := s.rateLimits.Lockout.Fail(r.Context(), s.limiter, key)
+		if err != nil {
+			logging.FromContext(r.Context()).Warn("Failed to record failed attempt", "error", err)
+			continue
+		}
+		if d > 0 {
+			logging.FromContext(r.Context()).Warn("Too many failed attempts, key locked", "key", key, "duration", d.String())
+		}
+	}
+}
+
+// resetFailures забывает неудачные попытки keys
+func (s *Server) resetFailures(r *http.Request, keys ...string) {
+	if s.limiter == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := s.rateLimits.Lockout.Reset(r.Context(), s.limiter, key); err != nil {
+			logging.FromContext(r.Context()).Warn("Failed to reset failed attempts", "error", err)
+		}
+	}
+}
+
+// tooManyRequests ставит Retry-After и учитывает отказ в метриках; тело ответа пишет вызывающий
+func tooManyRequests(w http.ResponseWriter, route, reason string, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds(retryAfter), 1)))
+	metrics.RateLimitRejections.WithLabelValues(route, reason).Inc()
+}
+
+// seconds округляет d вверх до целых секунд
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"task-flow-backend/models"
+	"task-flow-backend/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	server, router := newTestServer(t, WithRateLimits(ratelimit.NewMemory(), RateLimits{
+		Default: ratelimit.Limit{Requests: 3, Period: time.Minute},
+		Routes: map[string]ratelimit.Limit{
+			"/api/templates": {Requests: 1, Period: time.Hour},
+		},
+		TrustProxy: true,
+	}))
+	userID := createTestUser(t, server)
+	router.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+
+	get := func(path, ip string, authorized bool) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", path, nil)
+		require.NoError(t, err)
+		req.RemoteAddr = ip + ":40000"
+		if authorized {
+			authorize(t, req, userID)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	for i := 2; i >= 0; i-- {
+		rr := get("/api/boards", "198.51.100.1", false)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "3", rr.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, strconv.Itoa(i), rr.Header().Get("RateLimit-Remaining"))
+	}
+	rr := get("/api/boards", "198.51.100.1", false)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "20", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", rr.Header().Get("RateLimit-Reset"))
+
+	assert.Equal(t, http.StatusOK, get("/api/boards", "198.51.100.2", false).Code, "Expected clients to be limited by IP")
+	assert.Empty(t, get("/health", "198.51.100.1", false).Header().Get("RateLimit-Limit"), "Expected only /api to be limited")
+
+	// С токеном лимит считается на пользователя, с какого бы адреса он ни пришел
+	for i := 0; i < 3; i++ {
+		require.Equal(t, http.StatusOK, get("/api/auth/me", "198.51.100.1", true).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, get("/api/auth/me", "203.0.113.9", true).Code)
+
+	rr = get("/api/templates", "198.51.100.3", false)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Limit"), "Expected headers of the strictest limit")
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+	rr = get("/api/templates", "198.51.100.3", false)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "3600", rr.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, get("/api/boards", "198.51.100.3", false).Code)
+
+	req, err := http.NewRequest("GET", "/api/boards", nil)
+	require.NoError(t, err)
+	req.RemoteAddr = "10.0.0.1:40000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1, 198.51.100.4")
+	assert.Equal(t, "198.51.100.4", server.clientIP(req), "Expected the address added by the nearest proxy")
+}
+
+func TestLoginLockout(t *testing.T) {
+	server, router := newTestServer(t, WithRateLimits(ratelimit.NewMemory(), RateLimits{
+		Lockout: ratelimit.Lockout{Threshold: 2, Base: time.Minute, Max: time.Hour, Window: 15 * time.Minute},
+	}))
+	createTestUser(t, server)
+
+	post := func(path, ip string, body interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req, err := http.NewRequest("POST", path, bytes.NewReader(data))
+		require.NoError(t, err)
+		req.RemoteAddr = ip + ":40000"
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	login := func(ip, username, password string) *httptest.ResponseRecorder {
+		return post("/api/auth/login", ip, models.LoginRequest{Username: username, Password: password})
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, login("198.51.100.1", "testuser", "wrong").Code)
+	require.Equal(t, http.StatusOK, login("198.51.100.1", "testuser", "testpass123").Code)
+	assert.Equal(t, http.StatusUnauthorized, login("198.51.100.1", "testuser", "wrong").Code,
+		"Expected a successful login to reset failures of the username")
+	assert.Equal(t, http.StatusUnauthorized, login("198.51.100.2", "testuser", "wrong").Code)
+
+	rr := login("198.51.100.3", "TestUser", "testpass123")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code, "Expected the username to be locked from any address")
+	assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+	assert.Contains(t, rr.Body.String(), "Too many failed attempts")
+
+	// 198.51.100.1 ошибся дважды (успешный вход не сбрасывает неудачи IP)
+	assert.Equal(t, http.StatusTooManyRequests, login("198.51.100.1", "someone", "secret").Code)
+	assert.Equal(t, http.StatusUnauthorized, login("198.51.100.4", "someone", "secret").Code)
+	assert.Equal(t, http.StatusUnauthorized, login("198.51.100.4", "someone", "secret").Code)
+	rr = login("198.51.100.4", "someone", "secret")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+
+	// Прогрессивная блокировка: неудача после окончания блокировки удваивает срок
+	require.NoError(t, server.limiter.Reset(t.Context(), "lock:login:ip:198.51.100.4", "lock:login:user:someone"))
+	assert.Equal(t, http.StatusUnauthorized, login("198.51.100.4", "someone", "secret").Code)
+	rr = login("198.51.100.4", "someone", "secret")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 120, retryAfter, 1)
+
+	register := func(username, email string) int {
+		return post("/api/auth/register", "198.51.100.5", models.RegisterRequest{Username: username, Email: email, Password: "secret123"}).Code
+	}
+	assert.Equal(t, http.StatusConflict, register("testuser", "probe1@test.com"))
+	assert.Equal(t, http.StatusConflict, register("probe", "testuser@test.com"))
+	assert.Equal(t, http.StatusTooManyRequests, register("newcomer", "newcomer@test.com"),
+		"Expected probing for taken usernames and emails to lock the address")
+	assert.Equal(t, http.StatusCreated, post("/api/auth/register", "198.51.100.6",
+		models.RegisterRequest{Username: "newcomer", Email: "newcomer@test.com", Password: "secret123"}).Code)
+}
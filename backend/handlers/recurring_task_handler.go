@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) GetRecurringTasks(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.repos.Boards.GetByID(r.Context(), boardID); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	recurring, err := s.repos.RecurringTasks.ListByBoard(r.Context(), boardID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if recurring == nil {
+		recurring = []models.RecurringTask{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
+
+// CreateRecurringTask создает шаблон повторяющейся задачи доски. Без
+// starts_at серия начинается с момента создания, и первая задача
+// создается при ближайшем запуске планировщика.
+func (s *Server) CreateRecurringTask(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateRecurringTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	recurring := &models.RecurringTask{
+		BoardID:         boardID,
+		Title:           req.Title,
+		Description:     req.Description,
+		Status:          req.Status,
+		Priority:        req.Priority,
+		Assignee:        req.Assignee,
+		EstimateMinutes: req.EstimateMinutes,
+		RRule:           req.RRule,
+		Timezone:        req.Timezone,
+	}
+	if recurring.Timezone == "" {
+		recurring.Timezone = "UTC"
+	}
+	if userID, ok := userIDFromContext(r.Context()); ok {
+		recurring.CreatedBy = &userID
+	}
+
+	now := time.Now()
+	after := now
+	if req.StartsAt != nil {
+		recurring.StartsAt = *req.StartsAt
+	} else {
+		recurring.StartsAt = now.Truncate(time.Second)
+		after = recurring.StartsAt.Add(-time.Nanosecond)
+	}
+
+	if !s.validateRecurringTask(w, r, recurring) {
+		return
+	}
+	if err := repository.ScheduleRecurringTask(recurring, after); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.repos.RecurringTasks.Create(r.Context(), recurring); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(recurring)
+}
+
+func (s *Server) GetRecurringTask(w http.ResponseWriter, r *http.Request) {
+	recurring, ok := s.recurringTaskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
+
+// UpdateRecurringTask меняет шаблон. Следующее повторение пересчитывается,
+// только если изменились rrule, starts_at или timezone.
+func (s *Server) UpdateRecurringTask(w http.ResponseWriter, r *http.Request) {
+	recurring, ok := s.recurringTaskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateRecurringTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Title != nil {
+		recurring.Title = *req.Title
+	}
+	if req.Description != nil {
+		recurring.Description = *req.Description
+	}
+	if req.Status != nil {
+		recurring.Status = *req.Status
+	}
+	if req.Priority != nil {
+		recurring.Priority = req.Priority
+	}
+	if req.Assignee != nil {
+		recurring.Assignee = req.Assignee
+	}
+	if req.EstimateMinutes != nil {
+		// 0 снимает оценку, как у задач
+		recurring.EstimateMinutes = req.EstimateMinutes
+		if *req.EstimateMinutes == 0 {
+			recurring.EstimateMinutes = nil
+		}
+	}
+
+	reschedule := false
+	if req.RRule != nil {
+		recurring.RRule = *req.RRule
+		reschedule = true
+	}
+	if req.StartsAt != nil {
+		recurring.StartsAt = *req.StartsAt
+		reschedule = true
+	}
+	if req.Timezone != nil {
+		recurring.Timezone = *req.Timezone
+		reschedule = true
+	}
+
+	if !s.validateRecurringTask(w, r, recurring) {
+		return
+	}
+	if reschedule {
+		if err := repository.ScheduleRecurringTask(recurring, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := s.repos.RecurringTasks.Update(r.Context(), recurring); err != nil {
+		writeRepoError(w, err, "Recurring task not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recurring)
+}
+
+func (s *Server) DeleteRecurringTask(w http.ResponseWriter, r *http.Request) {
+	recurring, ok := s.recurringTaskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.repos.RecurringTasks.Delete(r.Context(), recurring.ID); err != nil {
+		writeRepoError(w, err, "Recurring task not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// recurringTaskFromPath читает шаблон из пути и при ошибке сам пишет ответ
+func (s *Server) recurringTaskFromPath(w http.ResponseWriter, r *http.Request) (*models.RecurringTask, bool) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid recurring task ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	recurring, err := s.repos.RecurringTasks.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Recurring task not found")
+		return nil, false
+	}
+	return recurring, true
+}
+
+// validateRecurringTask проверяет поля шаблона и колонку доски; пустой
+// статус заменяется первой колонкой
+func (s *Server) validateRecurringTask(w http.ResponseWriter, r *http.Request, recurring *models.RecurringTask) bool {
+	if strings.TrimSpace(recurring.Title) == "" {
+		http.Error(w, "title is required", http.StatusBadRequest)
+		return false
+	}
+	if recurring.EstimateMinutes != nil && *recurring.EstimateMinutes <= 0 {
+		http.Error(w, "estimate_minutes must be positive", http.StatusBadRequest)
+		return false
+	}
+
+	columns, err := s.repos.Columns.ListByBoard(r.Context(), recurring.BoardID, repository.ListOptions{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if len(columns) == 0 {
+		http.Error(w, "Board not found or has no columns", http.StatusNotFound)
+		return false
+	}
+	if recurring.Status == "" {
+		recurring.Status = columns[0].StatusID
+	}
+	for _, column := range columns {
+		if column.StatusID == recurring.Status {
+			return true
+		}
+	}
+	http.Error(w, repository.ErrUnknownStatus.Error(), http.StatusBadRequest)
+	return false
+}
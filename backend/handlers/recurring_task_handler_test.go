@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-flow-backend/jobs"
+	"task-flow-backend/models"
+	"task-flow-backend/templates"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecurringTasks(t *testing.T) {
+	hub := &recordingHub{}
+	server, router := newTestServer(t, WithHub(hub))
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Ops", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+
+	boardPath := "/api/boards/" + board.ID.String() + "/recurring-tasks"
+
+	t.Run("Validation", func(t *testing.T) {
+		cases := map[string]string{
+			"no title":         `{"rrule":"FREQ=DAILY"}`,
+			"bad rule":         `{"title":"Backup","rrule":"FREQ=HOURLY"}`,
+			"unknown status":   `{"title":"Backup","rrule":"FREQ=DAILY","status":"nope"}`,
+			"unknown timezone": `{"title":"Backup","rrule":"FREQ=DAILY","timezone":"Mars/Olympus"}`,
+		}
+		for name, body := range cases {
+			assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "POST", boardPath, body).Code, name)
+		}
+	})
+
+	var recurring models.RecurringTask
+	t.Run("Create", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", boardPath, `{"title":"Weekly ops review","rrule":"FREQ=WEEKLY","priority":"medium"}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &recurring))
+		assert.Equal(t, "plan", recurring.Status, "Expected first column by default")
+		assert.Equal(t, "UTC", recurring.Timezone)
+		require.NotNil(t, recurring.NextRunAt)
+		assert.False(t, recurring.NextRunAt.After(time.Now()), "Expected series to start now")
+
+		rr = doRequest(t, router, userID, "GET", boardPath, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var list []models.RecurringTask
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+		require.Len(t, list, 1)
+	})
+
+	itemPath := "/api/recurring-tasks/" + recurring.ID.String()
+
+	t.Run("Scheduler creates task", func(t *testing.T) {
+		// Планировщик создает задачу и рассылает task_created через сервер
+		hub.events = nil
+		require.NoError(t, jobs.GenerateRecurringTasks(server.repos, server.TaskCreated)(context.Background()))
+		assert.Equal(t, []recordedEvent{{boardID: board.ID.String(), eventType: "task_created"}}, hub.events)
+
+		tasks, err := server.repos.Tasks.ListByBoard(context.Background(), board.ID, listOptions(httptest.NewRequest("GET", "/", nil)))
+		require.NoError(t, err)
+		found := false
+		for _, task := range tasks {
+			if task.Title == "Weekly ops review" {
+				found = true
+				assert.Equal(t, "plan", task.Status)
+			}
+		}
+		assert.True(t, found, "Expected task created from the template")
+
+		rr := doRequest(t, router, userID, "GET", itemPath, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var stored models.RecurringTask
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &stored))
+		require.NotNil(t, stored.NextRunAt)
+		assert.True(t, stored.NextRunAt.After(time.Now()), "Expected next run to move forward")
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "PUT", itemPath, `{"title":"Ops review","rrule":"FREQ=MONTHLY;BYDAY=1MO","timezone":"Europe/Berlin"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var updated models.RecurringTask
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &updated))
+		assert.Equal(t, "Ops review", updated.Title)
+		assert.Equal(t, "FREQ=MONTHLY;BYDAY=1MO", updated.RRule)
+		require.NotNil(t, updated.NextRunAt)
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		if err == nil {
+			assert.Equal(t, time.Monday, updated.NextRunAt.In(berlin).Weekday())
+		}
+
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "PUT", itemPath, `{"rrule":"FREQ=WEEKLY;BYDAY=2MO"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "PUT", itemPath, `{"estimate_minutes":-1}`).Code)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, doRequest(t, router, userID, "DELETE", itemPath, "").Code)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "GET", itemPath, "").Code)
+	})
+
+	t.Run("Create on missing board", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", "/api/boards/00000000-0000-0000-0000-000000000000/recurring-tasks", `{"title":"x","rrule":"FREQ=DAILY"}`)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
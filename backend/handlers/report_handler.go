@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const dateLayout = "2006-01-02"
+
+// GetTimeReport возвращает учтенное время с фильтрами board_id, user_id,
+// from и to. Даты принимаются как YYYY-MM-DD (to включительно) или RFC3339.
+// С ?format=csv или Accept: text/csv отдает CSV по парам задача-пользователь.
+func (s *Server) GetTimeReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var filter repository.WorklogFilter
+
+	for name, target := range map[string]**uuid.UUID{"board_id": &filter.BoardID, "user_id": &filter.UserID} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			http.Error(w, "Invalid "+name, http.StatusBadRequest)
+			return
+		}
+		*target = &id
+	}
+
+	var err error
+	if filter.From, err = parseReportTime(query.Get("from"), false); err != nil {
+		http.Error(w, "Invalid from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = parseReportTime(query.Get("to"), true); err != nil {
+		http.Error(w, "Invalid to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		http.Error(w, "to must be after from", http.StatusBadRequest)
+		return
+	}
+
+	report, err := s.repos.TimeReport(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if query.Get("format") == "csv" || strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="time-report.csv"`)
+
+		out := csv.NewWriter(w)
+		out.Write([]string{"board_id", "task_id", "task_title", "user_id", "username", "minutes"})
+		for _, entry := range report.Entries {
+			out.Write([]string{
+				entry.BoardID.String(), entry.TaskID.String(), entry.TaskTitle,
+				entry.UserID.String(), entry.Username, strconv.Itoa(entry.Minutes),
+			})
+		}
+		out.Flush()
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// parseReportTime разбирает границу периода. Дата без времени в конце
+// периода означает конец этого дня.
+func parseReportTime(value string, end bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return nil, fmt.Errorf("expected YYYY-MM-DD or RFC3339")
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
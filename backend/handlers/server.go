@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"task-flow-backend/automation"
+	"task-flow-backend/cache"
+	"task-flow-backend/mail"
+	"task-flow-backend/ratelimit"
+	"task-flow-backend/repository"
+	"task-flow-backend/storage"
+
+	"github.com/gorilla/mux"
+)
+
+// Server содержит зависимости HTTP-обработчиков
+type Server struct {
+	repos repository.Repositories
+	cache cache.Store
+	hub   Broadcaster
+	// storage хранит вложения; без него эндпоинты вложений отвечают 503
+	storage          storage.Storage
+	attachmentLimits AttachmentLimits
+	// automation получает события задач; без него правила не выполняются
+	automation *automation.Engine
+	// mail отправляет письма подтверждения и сброса пароля со ссылками на appURL;
+	// без него письма не отправляются
+	mail   mail.Sender
+	appURL string
+	// requireVerifiedEmail запрещает вход до подтверждения email
+	requireVerifiedEmail bool
+	// limiter хранит состояние rateLimits; без него лимиты не действуют
+	limiter    ratelimit.Store
+	rateLimits RateLimits
+}
+
+type Option func(*Server)
+
+func WithCache(store cache.Store) Option {
+	return func(s *Server) {
+		s.cache = store
+	}
+}
+
+func WithHub(hub Broadcaster) Option {
+	return func(s *Server) {
+		s.hub = hub
+	}
+}
+
+func WithStorage(store storage.Storage) Option {
+	return func(s *Server) {
+		s.storage = store
+	}
+}
+
+func WithAttachmentLimits(limits AttachmentLimits) Option {
+	return func(s *Server) {
+		s.attachmentLimits = limits
+	}
+}
+
+func WithAutomation(engine *automation.Engine) Option {
+	return func(s *Server) {
+		s.automation = engine
+	}
+}
+
+func WithMail(sender mail.Sender, appURL string) Option {
+	return func(s *Server) {
+		s.mail = sender
+		if appURL != "" {
+			s.appURL = appURL
+		}
+	}
+}
+
+// WithEmailVerification требует подтвердить email перед первым входом
+func WithEmailVerification(required bool) Option {
+	return func(s *Server) {
+		s.requireVerifiedEmail = required
+	}
+}
+
+func WithRateLimits(store ratelimit.Store, limits RateLimits) Option {
+	return func(s *Server) {
+		s.limiter = store
+		s.rateLimits = limits
+	}
+}
+
+func NewServer(repos repository.Repositories, opts ...Option) *Server {
+	s := &Server{
+		repos: repos,
+		cache: cache.NopStore{},
+
+		attachmentLimits: DefaultAttachmentLimits,
+		appURL:           DefaultAppURL,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Routes регистрирует маршруты REST API. Маршруты на r публичные,
+// маршруты на подроутере /api требуют JWT токен, /api/admin - еще и роль admin.
+// Все запросы /api проходят RateLimitMiddleware.
+func (s *Server) Routes(r *mux.Router) {
+	r.Use(s.RateLimitMiddleware)
+
+	r.HandleFunc("/api/auth/login", s.Login).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/auth/register", s.Register).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/auth/verify-email", s.VerifyEmail).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/auth/verify-email/resend", s.ResendVerification).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/auth/forgot-password", s.ForgotPassword).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/auth/reset-password", s.ResetPassword).Methods("POST", "OPTIONS")
+
+	api := r.PathPrefix("/api").Subrouter()
+	api.Use(s.AuthMiddleware)
+
+	api.HandleFunc("/auth/me", s.GetCurrentUser).Methods("GET", "OPTIONS")
+	api.HandleFunc("/auth/me", s.DeleteCurrentUser).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/auth/me/export", s.ExportCurrentUser).Methods("GET", "OPTIONS")
+
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(s.AdminMiddleware)
+	admin.HandleFunc("/users", s.ListUsers).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/users/{id}", s.GetUser).Methods("GET", "OPTIONS")
+	admin.HandleFunc("/users/{id}", s.UpdateUser).Methods("PATCH", "OPTIONS")
+	admin.HandleFunc("/users/{id}", s.DeleteUser).Methods("DELETE", "OPTIONS")
+	admin.HandleFunc("/users/{id}/revoke-tokens", s.RevokeUserTokens).Methods("POST", "OPTIONS")
+	admin.HandleFunc("/users/{id}/export", s.ExportUser).Methods("GET", "OPTIONS")
+
+	r.HandleFunc("/api/boards", s.GetBoards).Methods("GET", "OPTIONS")
+	api.HandleFunc("/boards", s.CreateBoard).Methods("POST", "OPTIONS")
+	api.HandleFunc("/boards/import", s.ImportBoard).Methods("POST", "OPTIONS")
+	api.HandleFunc("/imports/{id}", s.GetImportJob).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/boards/{id}", s.GetBoard).Methods("GET", "OPTIONS")
+	api.HandleFunc("/boards/{id}", s.UpdateBoard).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/boards/{id}", s.DeleteBoard).Methods("DELETE", "OPTIONS")
+	r.HandleFunc("/api/boards/{id}/members", s.GetBoardMembers).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/boards/{id}/labels", s.GetBoardLabels).Methods("GET", "OPTIONS")
+	api.HandleFunc("/boards/{id}/save-as-template", s.SaveBoardAsTemplate).Methods("POST", "OPTIONS")
+	api.HandleFunc("/boards/{id}/duplicate", s.DuplicateBoard).Methods("POST", "OPTIONS")
+	api.HandleFunc("/boards/{id}/restore", s.RestoreBoard).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/boards/{id}/dependency-graph", s.GetDependencyGraph).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/boards/{id}/analytics", s.GetBoardAnalytics).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/boards/{id}/export", s.GetBoardExport).Methods("GET", "OPTIONS")
+	api.HandleFunc("/boards/{id}/archive-done", s.ArchiveDoneTasks).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/boards/{id}/archive-rules", s.GetArchiveRules).Methods("GET", "OPTIONS")
+	api.HandleFunc("/boards/{id}/archive-rules", s.UpdateArchiveRules).Methods("PUT", "OPTIONS")
+	r.HandleFunc("/api/boards/{id}/workflow", s.GetWorkflow).Methods("GET", "OPTIONS")
+	api.HandleFunc("/boards/{id}/workflow", s.UpdateWorkflow).Methods("PUT", "OPTIONS")
+	r.HandleFunc("/api/boards/{id}/sprints", s.GetSprints).Methods("GET", "OPTIONS")
+	api.HandleFunc("/boards/{id}/sprints", s.CreateSprint).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/boards/{id}/recurring-tasks", s.GetRecurringTasks).Methods("GET", "OPTIONS")
+	api.HandleFunc("/boards/{id}/recurring-tasks", s.CreateRecurringTask).Methods("POST", "OPTIONS")
+	api.HandleFunc("/boards/{id}/automation-rules", s.GetAutomationRules).Methods("GET", "OPTIONS")
+	api.HandleFunc("/boards/{id}/automation-rules", s.CreateAutomationRule).Methods("POST", "OPTIONS")
+	api.HandleFunc("/boards/{id}/automation-runs", s.GetAutomationRuns).Methods("GET", "OPTIONS")
+
+	r.HandleFunc("/api/templates", s.GetTemplates).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/templates/{id}", s.GetTemplate).Methods("GET", "OPTIONS")
+	api.HandleFunc("/templates/{id}", s.DeleteTemplate).Methods("DELETE", "OPTIONS")
+
+	r.HandleFunc("/api/tasks", s.GetTasks).Methods("GET", "OPTIONS")
+	api.HandleFunc("/tasks", s.CreateTask).Methods("POST", "OPTIONS")
+	api.HandleFunc("/tasks/bulk-move", s.BulkMoveTasks).Methods("PATCH", "OPTIONS")
+	r.HandleFunc("/api/tasks/{id}", s.GetTask).Methods("GET", "OPTIONS")
+	api.HandleFunc("/tasks/{id}", s.UpdateTask).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/tasks/{id}", s.DeleteTask).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/move", s.MoveTask).Methods("PATCH", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/transfer", s.TransferTask).Methods("POST", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/restore", s.RestoreTask).Methods("POST", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/archive", s.ArchiveTask).Methods("POST", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/unarchive", s.UnarchiveTask).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tasks/{id}/subtasks", s.GetSubtasks).Methods("GET", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/parent", s.SetTaskParent).Methods("PUT", "OPTIONS")
+	r.HandleFunc("/api/tasks/{id}/links", s.GetTaskLinks).Methods("GET", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/links", s.CreateTaskLink).Methods("POST", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/links/{link_id}", s.DeleteTaskLink).Methods("DELETE", "OPTIONS")
+	r.HandleFunc("/api/tasks/{id}/attachments", s.GetAttachments).Methods("GET", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/attachments", s.UploadAttachment).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tasks/{id}/attachments/{attachment_id}", s.DownloadAttachment).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/tasks/{id}/attachments/{attachment_id}/thumbnail", s.GetAttachmentThumbnail).Methods("GET", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/attachments/{attachment_id}", s.DeleteAttachment).Methods("DELETE", "OPTIONS")
+	r.HandleFunc("/api/tasks/{id}/worklogs", s.GetWorklogs).Methods("GET", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/worklogs", s.CreateWorklog).Methods("POST", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/worklogs/{worklog_id}", s.DeleteWorklog).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/timer/start", s.StartTimer).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tasks/{id}/comments", s.GetComments).Methods("GET", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/comments", s.CreateComment).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/tasks/{id}/checklist", s.GetChecklist).Methods("GET", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/checklist", s.CreateChecklistItem).Methods("POST", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/checklist/order", s.ReorderChecklist).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/checklist/{item_id}", s.UpdateChecklistItem).Methods("PATCH", "OPTIONS")
+	api.HandleFunc("/tasks/{id}/checklist/{item_id}", s.DeleteChecklistItem).Methods("DELETE", "OPTIONS")
+
+	r.HandleFunc("/api/recurring-tasks/{id}", s.GetRecurringTask).Methods("GET", "OPTIONS")
+	api.HandleFunc("/recurring-tasks/{id}", s.UpdateRecurringTask).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/recurring-tasks/{id}", s.DeleteRecurringTask).Methods("DELETE", "OPTIONS")
+
+	api.HandleFunc("/automation-rules/{id}", s.GetAutomationRule).Methods("GET", "OPTIONS")
+	api.HandleFunc("/automation-rules/{id}", s.UpdateAutomationRule).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/automation-rules/{id}", s.DeleteAutomationRule).Methods("DELETE", "OPTIONS")
+
+	r.HandleFunc("/api/sprints/{id}", s.GetSprint).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/sprints/{id}/burndown", s.GetSprintBurndown).Methods("GET", "OPTIONS")
+	api.HandleFunc("/sprints/{id}", s.UpdateSprint).Methods("PUT", "OPTIONS")
+	api.HandleFunc("/sprints/{id}", s.DeleteSprint).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/sprints/{id}/start", s.StartSprint).Methods("POST", "OPTIONS")
+	api.HandleFunc("/sprints/{id}/complete", s.CompleteSprint).Methods("POST", "OPTIONS")
+	api.HandleFunc("/sprints/{id}/tasks", s.AddSprintTasks).Methods("POST", "OPTIONS")
+
+	r.HandleFunc("/api/columns", s.GetColumns).Methods("GET", "OPTIONS")
+	api.HandleFunc("/columns", s.CreateColumn).Methods("POST", "OPTIONS")
+	api.HandleFunc("/columns/{id}", s.DeleteColumn).Methods("DELETE", "OPTIONS")
+	api.HandleFunc("/columns/{id}/restore", s.RestoreColumn).Methods("POST", "OPTIONS")
+	api.HandleFunc("/columns/{id}/archive", s.ArchiveColumn).Methods("POST", "OPTIONS")
+	api.HandleFunc("/columns/{id}/unarchive", s.UnarchiveColumn).Methods("POST", "OPTIONS")
+
+	api.HandleFunc("/timer", s.GetTimer).Methods("GET", "OPTIONS")
+	api.HandleFunc("/timer/stop", s.StopTimer).Methods("POST", "OPTIONS")
+	api.HandleFunc("/reports/time", s.GetTimeReport).Methods("GET", "OPTIONS")
+
+	api.HandleFunc("/trash", s.GetTrash).Methods("GET", "OPTIONS")
+}
+
+// writeRepoError отвечает 404 на repository.ErrNotFound и 500 на остальные ошибки
+func writeRepoError(w http.ResponseWriter, err error, notFoundMessage string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		http.Error(w, notFoundMessage, http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
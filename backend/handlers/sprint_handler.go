@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// GetSprints возвращает спринты доски; ?state= оставляет спринты в одном состоянии
+func (s *Server) GetSprints(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	state := r.URL.Query().Get("state")
+	if state != "" && !validSprintState(state) {
+		http.Error(w, "state must be planned, active or completed", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.repos.Boards.GetByID(r.Context(), boardID); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	sprints, err := s.repos.Sprints.ListByBoard(r.Context(), boardID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	filtered := []models.Sprint{}
+	for _, sprint := range sprints {
+		if state == "" || sprint.State == state {
+			filtered = append(filtered, sprint)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(filtered)
+}
+
+// CreateSprint создает запланированный спринт доски
+func (s *Server) CreateSprint(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateSprintRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	sprint := &models.Sprint{
+		BoardID:  boardID,
+		Name:     strings.TrimSpace(req.Name),
+		Goal:     req.Goal,
+		State:    models.SprintStatePlanned,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	}
+	if userID, ok := userIDFromContext(r.Context()); ok {
+		sprint.CreatedBy = &userID
+	}
+	if !validateSprint(w, sprint) {
+		return
+	}
+
+	if err := s.repos.Sprints.Create(r.Context(), sprint); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	s.broadcast(boardID.String(), "sprint_created", sprint)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sprint)
+}
+
+func (s *Server) GetSprint(w http.ResponseWriter, r *http.Request) {
+	sprint, ok := s.sprintFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sprint)
+}
+
+// UpdateSprint меняет название, цель и даты спринта; завершенный спринт не меняется
+func (s *Server) UpdateSprint(w http.ResponseWriter, r *http.Request) {
+	sprint, ok := s.sprintFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSprintRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if sprint.State == models.SprintStateCompleted {
+		http.Error(w, "Completed sprint cannot be changed", http.StatusConflict)
+		return
+	}
+
+	if req.Name != nil {
+		sprint.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Goal != nil {
+		sprint.Goal = *req.Goal
+	}
+	if req.StartsAt != nil {
+		sprint.StartsAt = req.StartsAt
+	}
+	if req.EndsAt != nil {
+		sprint.EndsAt = req.EndsAt
+	}
+	if !validateSprint(w, sprint) {
+		return
+	}
+
+	if err := s.repos.Sprints.Update(r.Context(), sprint); err != nil {
+		writeRepoError(w, err, "Sprint not found")
+		return
+	}
+
+	s.broadcast(sprint.BoardID.String(), "sprint_updated", sprint)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sprint)
+}
+
+// DeleteSprint удаляет спринт; его задачи возвращаются в бэклог
+func (s *Server) DeleteSprint(w http.ResponseWriter, r *http.Request) {
+	sprint, ok := s.sprintFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.repos.Sprints.Delete(r.Context(), sprint.ID); err != nil {
+		writeRepoError(w, err, "Sprint not found")
+		return
+	}
+
+	s.invalidateTasksCache(r.Context(), sprint.BoardID)
+	s.broadcast(sprint.BoardID.String(), "sprint_deleted", map[string]string{"id": sprint.ID.String()})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StartSprint запускает запланированный спринт; на доске может идти только один спринт
+func (s *Server) StartSprint(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid sprint ID", http.StatusBadRequest)
+		return
+	}
+
+	sprint, err := s.repos.StartSprint(r.Context(), id, time.Now())
+	if errors.Is(err, repository.ErrConflict) {
+		http.Error(w, "Board already has an active sprint", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeSprintError(w, err)
+		return
+	}
+
+	s.broadcast(sprint.BoardID.String(), "sprint_started", sprint)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sprint)
+}
+
+// CompleteSprint завершает активный спринт и переносит незавершенные
+// задачи в следующий спринт или в бэклог (см. models.CompleteSprintRequest)
+func (s *Server) CompleteSprint(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid sprint ID", http.StatusBadRequest)
+		return
+	}
+
+	// Тело необязательно: без него задачи переходят в следующий спринт
+	var req models.CompleteSprintRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	completion, err := s.repos.CompleteSprint(r.Context(), id, req.MoveTo, time.Now())
+	if err != nil {
+		writeSprintError(w, err)
+		return
+	}
+
+	s.invalidateTasksCache(r.Context(), completion.Sprint.BoardID)
+	s.broadcast(completion.Sprint.BoardID.String(), "sprint_completed", completion)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(completion)
+}
+
+// AddSprintTasks переносит задачи доски в спринт
+func (s *Server) AddSprintTasks(w http.ResponseWriter, r *http.Request) {
+	sprint, ok := s.sprintFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.SprintTasksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(req.TaskIDs) == 0 {
+		http.Error(w, "task_ids are required", http.StatusBadRequest)
+		return
+	}
+
+	tasks, err := s.repos.AssignSprint(r.Context(), req.TaskIDs, &sprint.ID)
+	if err != nil {
+		writeSprintError(w, err)
+		return
+	}
+
+	s.invalidateTasksCache(r.Context(), sprint.BoardID)
+	s.broadcast(sprint.BoardID.String(), "sprint_tasks_added", map[string]interface{}{
+		"sprint_id": sprint.ID,
+		"tasks":     tasks,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tasks)
+}
+
+// sprintFromPath читает спринт из пути и при ошибке сам пишет ответ
+func (s *Server) sprintFromPath(w http.ResponseWriter, r *http.Request) (*models.Sprint, bool) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid sprint ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	sprint, err := s.repos.Sprints.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Sprint not found")
+		return nil, false
+	}
+	return sprint, true
+}
+
+// validateSprint проверяет название и даты спринта и при ошибке пишет 400
+func validateSprint(w http.ResponseWriter, sprint *models.Sprint) bool {
+	if sprint.Name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return false
+	}
+	if sprint.StartsAt != nil && sprint.EndsAt != nil && !sprint.EndsAt.After(*sprint.StartsAt) {
+		http.Error(w, "ends_at must be after starts_at", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+func validSprintState(state string) bool {
+	switch state {
+	case models.SprintStatePlanned, models.SprintStateActive, models.SprintStateCompleted:
+		return true
+	}
+	return false
+}
+
+// writeSprintError отвечает 400 на спринт другой доски или неверную цель
+// переноса и 409 на операцию, недопустимую в текущем состоянии спринта
+func writeSprintError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repository.ErrInvalidSprint):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrSprintState):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		writeRepoError(w, err, err.Error())
+	}
+}
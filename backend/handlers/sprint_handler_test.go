@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/templates"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSprints(t *testing.T) {
+	hub := &recordingHub{}
+	server, router := newTestServer(t, WithHub(hub))
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Sprints", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+
+	createSprint := func(t *testing.T, body string) models.Sprint {
+		t.Helper()
+		rr := doRequest(t, router, userID, "POST", "/api/boards/"+board.ID.String()+"/sprints", body)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		var sprint models.Sprint
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &sprint))
+		return sprint
+	}
+	createTask := func(t *testing.T, body string) models.Task {
+		t.Helper()
+		rr := doRequest(t, router, userID, "POST", "/api/tasks", body)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		var task models.Task
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &task))
+		return task
+	}
+	listTasks := func(t *testing.T, query string) []models.Task {
+		t.Helper()
+		rr := doRequest(t, router, userID, "GET", "/api/tasks?"+query, "")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var tasks []models.Task
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &tasks))
+		return tasks
+	}
+
+	t.Run("Validation", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "POST", "/api/boards/"+board.ID.String()+"/sprints", `{"name":" "}`).Code)
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "POST", "/api/boards/"+board.ID.String()+"/sprints", `{"name":"Backwards","starts_at":"2026-03-10T00:00:00Z","ends_at":"2026-03-01T00:00:00Z"}`).Code)
+	})
+
+	var first, second models.Sprint
+	t.Run("Create sprints", func(t *testing.T) {
+		first = createSprint(t, `{"name":"Sprint 1","goal":"Login"}`)
+		assert.Equal(t, models.SprintStatePlanned, first.State)
+		second = createSprint(t, `{"name":"Sprint 2"}`)
+	})
+	sprintPath := "/api/sprints/" + first.ID.String()
+
+	var inSprint, backlog models.Task
+	t.Run("Add tasks to sprint", func(t *testing.T) {
+		inSprint = createTask(t, `{"board_id":"`+board.ID.String()+`","title":"Login form","sprint_id":"`+first.ID.String()+`"}`)
+		require.NotNil(t, inSprint.SprintID)
+		finished := createTask(t, `{"board_id":"`+board.ID.String()+`","title":"Design","status":"closed"}`)
+		backlog = createTask(t, `{"board_id":"`+board.ID.String()+`","title":"Someday"}`)
+
+		rr := doRequest(t, router, userID, "POST", sprintPath+"/tasks", `{"task_ids":["`+finished.ID.String()+`"]}`)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		assert.Len(t, listTasks(t, "sprint_id="+first.ID.String()), 2)
+	})
+
+	t.Run("List backlog", func(t *testing.T) {
+		tasks := listTasks(t, "board_id="+board.ID.String()+"&sprint_id=backlog")
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID.String())
+			assert.Nil(t, task.SprintID)
+		}
+		assert.Contains(t, ids, backlog.ID.String())
+		assert.NotContains(t, ids, inSprint.ID.String())
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "GET", "/api/tasks?sprint_id=backlog", "").Code)
+	})
+
+	t.Run("Start sprint", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", sprintPath+"/complete", "")
+		assert.Equal(t, http.StatusConflict, rr.Code, "Expected planned sprint not to be completable")
+
+		rr = doRequest(t, router, userID, "POST", sprintPath+"/start", "")
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &first))
+		assert.Equal(t, models.SprintStateActive, first.State)
+		require.NotNil(t, first.EndsAt)
+		assert.Equal(t, http.StatusConflict, doRequest(t, router, userID, "POST", "/api/sprints/"+second.ID.String()+"/start", "").Code)
+
+		rr = doRequest(t, router, userID, "GET", "/api/boards/"+board.ID.String()+"/sprints?state=active", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var sprints []models.Sprint
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &sprints))
+		require.Len(t, sprints, 1)
+		assert.Equal(t, first.ID, sprints[0].ID)
+	})
+
+	t.Run("Complete sprint", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "POST", sprintPath+"/complete", `{"move_to":"elsewhere"}`).Code)
+
+		rr := doRequest(t, router, userID, "POST", sprintPath+"/complete", `{"move_to":"next"}`)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var completion models.SprintCompletion
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &completion))
+		assert.Equal(t, models.SprintStateCompleted, completion.Sprint.State)
+		require.NotNil(t, completion.NextSprintID)
+		assert.Equal(t, second.ID, *completion.NextSprintID)
+		require.Len(t, completion.CarriedOver, 1)
+		assert.Equal(t, inSprint.ID, completion.CarriedOver[0])
+
+		tasks := listTasks(t, "sprint_id="+second.ID.String())
+		require.Len(t, tasks, 1)
+		assert.Equal(t, inSprint.ID, tasks[0].ID)
+	})
+
+	t.Run("Completed sprint is read-only", func(t *testing.T) {
+		assert.Equal(t, http.StatusConflict, doRequest(t, router, userID, "PUT", sprintPath, `{"name":"Renamed"}`).Code)
+		assert.Equal(t, http.StatusBadRequest,
+			doRequest(t, router, userID, "PUT", "/api/tasks/"+backlog.ID.String(), `{"sprint_id":"`+first.ID.String()+`"}`).Code,
+			"Expected completed sprint to reject tasks")
+	})
+
+	t.Run("Move task back to backlog", func(t *testing.T) {
+		// Пустая строка возвращает задачу в бэклог
+		rr := doRequest(t, router, userID, "PUT", "/api/tasks/"+inSprint.ID.String(), `{"sprint_id":""}`)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var updated models.Task
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &updated))
+		assert.Nil(t, updated.SprintID)
+	})
+
+	t.Run("Delete sprint", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, doRequest(t, router, userID, "DELETE", "/api/sprints/"+second.ID.String(), "").Code)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "GET", "/api/sprints/"+second.ID.String(), "").Code)
+	})
+
+	t.Run("Sprint events are broadcast", func(t *testing.T) {
+		for _, eventType := range []string{"sprint_created", "sprint_tasks_added", "sprint_started", "sprint_completed", "sprint_deleted"} {
+			assert.Contains(t, hub.events, recordedEvent{boardID: board.ID.String(), eventType: eventType})
+		}
+	})
+}
+
+// failingSprintTasks не может сменить спринт задачи
+type failingSprintTasks struct {
+	repository.TaskRepository
+}
+
+func (failingSprintTasks) SetSprint(ctx context.Context, id uuid.UUID, sprintID *uuid.UUID) error {
+	return errors.New("sprint storage unavailable")
+}
+
+func TestUpdateTaskSprintAtomic(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+	ctx := context.Background()
+
+	board := &models.Board{Name: "Sprints", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(ctx, board, templates.Default()))
+	sprint := &models.Sprint{BoardID: board.ID, Name: "Sprint 1", State: models.SprintStatePlanned}
+	require.NoError(t, server.repos.Sprints.Create(ctx, sprint))
+	task := &models.Task{BoardID: board.ID, Title: "Login form", Status: "plan"}
+	require.NoError(t, server.repos.Tasks.Create(ctx, task))
+	server.repos.Tasks = failingSprintTasks{server.repos.Tasks}
+
+	rr := doRequest(t, router, userID, "PUT", "/api/tasks/"+task.ID.String(), `{"title":"Signup form","sprint_id":"`+sprint.ID.String()+`"}`)
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	stored, err := server.repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Login form", stored.Title, "Expected the field update to be rolled back with the sprint")
+	assert.Nil(t, stored.SprintID)
+}
@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
 	"net/http"
-	"time"
-	"task-flow-backend/cache"
+	"strconv"
+	"task-flow-backend/automation"
+	"task-flow-backend/logging"
+	"task-flow-backend/metrics"
 	"task-flow-backend/models"
 	"task-flow-backend/repository"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
@@ -15,11 +19,42 @@ import (
 
 const cacheExpiration = 5 * time.Minute
 
-func GetTasks(w http.ResponseWriter, r *http.Request) {
+func (s *Server) GetTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 	boardIDStr := r.URL.Query().Get("board_id")
-	
+	opts := listOptions(r)
+	// Кэшируются только списки без архивных задач
+	useCache := !opts.IncludeArchived
+
+	if sprintParam := r.URL.Query().Get("sprint_id"); sprintParam != "" {
+		s.getSprintTasks(w, r, boardIDStr, sprintParam, opts)
+		return
+	}
+
+	if boardIDStr == "" && opts.IncludeArchived {
+		boards, err := s.repos.Boards.List(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		allTasks := []models.Task{}
+		for _, board := range boards {
+			tasks, err := s.repos.Tasks.ListByBoard(ctx, board.ID, opts)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			allTasks = append(allTasks, tasks...)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(allTasks)
+		return
+	}
+
 	if boardIDStr == "" {
-		if cachedData, err := cache.GetAllTasks(); err == nil && cachedData != nil {
+		if cachedData, err := s.cache.GetAllTasks(ctx); err == nil && cachedData != nil {
 			var cachedTasks []models.Task
 			if err := json.Unmarshal(cachedData, &cachedTasks); err == nil {
 				w.Header().Set("Content-Type", "application/json")
@@ -28,7 +63,7 @@ func GetTasks(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 
-		boards, err := repository.GetAllBoards()
+		boards, err := s.repos.Boards.List(ctx)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -36,14 +71,11 @@ func GetTasks(w http.ResponseWriter, r *http.Request) {
 
 		var allTasks []models.Task
 		for _, board := range boards {
-			tasks, err := repository.GetTasksByBoardID(board.ID)
-			if err == nil {
-				allTasks = append(allTasks, tasks...)
-			}
+			allTasks = append(allTasks, board.Tasks...)
 		}
 
-		if err := cache.SetAllTasks(allTasks, cacheExpiration); err != nil {
-			log.Printf("Failed to cache all tasks: %v", err)
+		if err := s.cache.SetAllTasks(ctx, allTasks, cacheExpiration); err != nil {
+			logging.FromContext(ctx).Warn("Failed to cache all tasks", "error", err)
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -58,30 +90,95 @@ func GetTasks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	boardIDStr = boardID.String()
-	if cachedData, err := cache.GetTasksByBoardID(boardIDStr); err == nil && cachedData != nil {
-		var cachedTasks []models.Task
-		if err := json.Unmarshal(cachedData, &cachedTasks); err == nil {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(cachedTasks)
-			return
+	if useCache {
+		if cachedData, err := s.cache.GetTasksByBoardID(ctx, boardIDStr); err == nil && cachedData != nil {
+			var cachedTasks []models.Task
+			if err := json.Unmarshal(cachedData, &cachedTasks); err == nil {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(cachedTasks)
+				return
+			}
 		}
 	}
 
-	tasks, err := repository.GetTasksByBoardID(boardID)
+	tasks, err := s.repos.Tasks.ListByBoard(ctx, boardID, opts)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if err := cache.SetTasksByBoardID(boardIDStr, tasks, cacheExpiration); err != nil {
-		log.Printf("Failed to cache tasks for board %s: %v", boardIDStr, err)
+	if useCache {
+		if err := s.cache.SetTasksByBoardID(ctx, boardIDStr, tasks, cacheExpiration); err != nil {
+			logging.FromContext(ctx).Warn("Failed to cache tasks", "board_id", boardIDStr, "error", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tasks)
+}
+
+// getSprintTasks отвечает на GET /api/tasks?sprint_id=: задачи спринта или,
+// для sprint_id=backlog, задачи доски без спринта. Такие списки не кэшируются.
+func (s *Server) getSprintTasks(w http.ResponseWriter, r *http.Request, boardIDStr, sprintParam string, opts repository.ListOptions) {
+	ctx := r.Context()
+
+	var boardID uuid.UUID
+	if boardIDStr != "" {
+		var err error
+		if boardID, err = uuid.Parse(boardIDStr); err != nil {
+			http.Error(w, "Invalid board ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	tasks := []models.Task{}
+	if sprintParam == repository.SprintMoveBacklog {
+		if boardIDStr == "" {
+			http.Error(w, "board_id is required for the backlog", http.StatusBadRequest)
+			return
+		}
+		boardTasks, err := s.repos.Tasks.ListByBoard(ctx, boardID, opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, task := range boardTasks {
+			if task.SprintID == nil {
+				tasks = append(tasks, task)
+			}
+		}
+	} else {
+		sprintID, err := uuid.Parse(sprintParam)
+		if err != nil {
+			http.Error(w, "sprint_id must be a sprint ID or backlog", http.StatusBadRequest)
+			return
+		}
+		sprint, err := s.repos.Sprints.GetByID(ctx, sprintID)
+		if err != nil {
+			writeRepoError(w, err, "Sprint not found")
+			return
+		}
+		if boardIDStr != "" && sprint.BoardID != boardID {
+			http.Error(w, "Sprint does not belong to the board", http.StatusBadRequest)
+			return
+		}
+		sprintTasks, err := s.repos.Tasks.ListBySprint(ctx, sprintID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, task := range sprintTasks {
+			if opts.IncludeArchived || task.ArchivedAt == nil {
+				tasks = append(tasks, task)
+			}
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(tasks)
 }
 
-func GetTask(w http.ResponseWriter, r *http.Request) {
+func (s *Server) GetTask(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.Parse(vars["id"])
 	if err != nil {
@@ -89,9 +186,9 @@ func GetTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := repository.GetTaskByID(id)
+	task, err := s.repos.Tasks.GetByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Task not found", http.StatusNotFound)
+		writeRepoError(w, err, "Task not found")
 		return
 	}
 
@@ -99,53 +196,87 @@ func GetTask(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(task)
 }
 
-func CreateTask(w http.ResponseWriter, r *http.Request) {
+func (s *Server) CreateTask(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateTaskRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	if req.EstimateMinutes != nil && *req.EstimateMinutes <= 0 {
+		http.Error(w, "estimate_minutes must be positive", http.StatusBadRequest)
+		return
+	}
+	if req.StoryPoints != nil && *req.StoryPoints <= 0 {
+		http.Error(w, "story_points must be positive", http.StatusBadRequest)
+		return
+	}
 
-	userID, ok := r.Context().Value("userID").(uuid.UUID)
 	var taskCreatedBy *uuid.UUID
-	if ok {
+	if userID, ok := userIDFromContext(r.Context()); ok {
 		taskCreatedBy = &userID
 	}
 
 	task := &models.Task{
-		BoardID:     req.BoardID,
-		Title:       req.Title,
-		Description: req.Description,
-		Status:      req.Status,
-		Priority:    req.Priority,
-		Assignee:    req.Assignee,
-		CreatedBy:   taskCreatedBy,
+		BoardID:         req.BoardID,
+		Title:           req.Title,
+		Description:     req.Description,
+		Status:          req.Status,
+		Priority:        req.Priority,
+		Assignee:        req.Assignee,
+		EstimateMinutes: req.EstimateMinutes,
+		StoryPoints:     req.StoryPoints,
+		DueAt:           req.DueAt,
+		CreatedBy:       taskCreatedBy,
+		ParentTaskID:    req.ParentTaskID,
+		SprintID:        req.SprintID,
 	}
 
 	if task.Status == "" {
-		task.Status = "plan"
+		// Без статуса задача попадает в первую колонку доски
+		columns, err := s.repos.Columns.ListByBoard(r.Context(), task.BoardID, repository.ListOptions{})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(columns) > 0 {
+			task.Status = columns[0].StatusID
+		}
 	}
-
-	if err := repository.CreateTask(task); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := s.repos.ValidateStatus(r.Context(), task.BoardID, task.Status); err != nil {
+		writeMoveError(w, err)
 		return
 	}
+	if task.SprintID != nil {
+		if err := s.repos.ValidateSprint(r.Context(), task.BoardID, *task.SprintID); err != nil {
+			writeSprintError(w, err)
+			return
+		}
+	}
 
-	if err := cache.InvalidateBoardTasks(task.BoardID.String()); err != nil {
-		log.Printf("Failed to invalidate cache for board %s: %v", task.BoardID.String(), err)
+	var err error
+	if task.ParentTaskID != nil {
+		err = s.repos.CreateSubtask(r.Context(), task)
+	} else {
+		err = s.repos.Tasks.Create(r.Context(), task)
+	}
+	if errors.Is(err, repository.ErrInvalidParent) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	if err := cache.InvalidateAllTasks(); err != nil {
-		log.Printf("Failed to invalidate all tasks cache: %v", err)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	BroadcastTaskUpdate(task.BoardID.String(), "task_created", task)
+	s.TaskCreated(r.Context(), task)
+	s.broadcastParent(r.Context(), task.ParentTaskID)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(task)
 }
 
-func UpdateTask(w http.ResponseWriter, r *http.Request) {
+func (s *Server) UpdateTask(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.Parse(vars["id"])
 	if err != nil {
@@ -153,9 +284,9 @@ func UpdateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	currentTask, err := repository.GetTaskByID(id)
+	currentTask, err := s.repos.Tasks.GetByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Task not found", http.StatusNotFound)
+		writeRepoError(w, err, "Task not found")
 		return
 	}
 
@@ -165,41 +296,112 @@ func UpdateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	previous := *currentTask
 	if req.Title != nil {
 		currentTask.Title = *req.Title
 	}
 	if req.Description != nil {
 		currentTask.Description = *req.Description
 	}
-	if req.Status != nil {
-		currentTask.Status = *req.Status
-	}
 	if req.Priority != nil {
 		currentTask.Priority = req.Priority
 	}
 	if req.Assignee != nil {
 		currentTask.Assignee = req.Assignee
 	}
-
-	if err := repository.UpdateTask(currentTask); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	if req.EstimateMinutes != nil {
+		switch {
+		case *req.EstimateMinutes < 0:
+			http.Error(w, "estimate_minutes must not be negative", http.StatusBadRequest)
+			return
+		case *req.EstimateMinutes == 0:
+			currentTask.EstimateMinutes = nil
+		default:
+			currentTask.EstimateMinutes = req.EstimateMinutes
+		}
+	}
+	if req.StoryPoints != nil {
+		switch {
+		case *req.StoryPoints < 0:
+			http.Error(w, "story_points must not be negative", http.StatusBadRequest)
+			return
+		case *req.StoryPoints == 0:
+			currentTask.StoryPoints = nil
+		default:
+			currentTask.StoryPoints = req.StoryPoints
+		}
+	}
+	if req.DueAt != nil {
+		// Пустая строка снимает срок
+		if *req.DueAt == "" {
+			currentTask.DueAt = nil
+		} else {
+			dueAt, err := time.Parse(time.RFC3339, *req.DueAt)
+			if err != nil {
+				http.Error(w, "due_at must be an RFC3339 timestamp", http.StatusBadRequest)
+				return
+			}
+			currentTask.DueAt = &dueAt
+		}
+	}
+	var sprintID *uuid.UUID
+	if req.SprintID != nil && *req.SprintID != "" {
+		id, err := uuid.Parse(*req.SprintID)
+		if err != nil {
+			http.Error(w, "sprint_id must be a sprint ID or an empty string", http.StatusBadRequest)
+			return
+		}
+		if err := s.repos.ValidateSprint(r.Context(), currentTask.BoardID, id); err != nil {
+			writeSprintError(w, err)
+			return
+		}
+		sprintID = &id
+	}
+	// Статус проверяется последним: переход может требовать поля,
+	// заполненные этим же запросом
+	if req.Status != nil && *req.Status != currentTask.Status {
+		if err := s.repos.CheckMoveAllowed(r.Context(), currentTask, *req.Status); err != nil {
+			writeMoveError(w, err)
+			return
+		}
+		currentTask.Status = *req.Status
 	}
 
-	if err := cache.InvalidateBoardTasks(currentTask.BoardID.String()); err != nil {
-		log.Printf("Failed to invalidate cache for board %s: %v", currentTask.BoardID.String(), err)
+	// Поля и спринт сохраняются в одной транзакции, чтобы ошибка спринта не
+	// оставила задачу обновленной наполовину
+	notFound := "Task not found"
+	err = s.repos.Tx.WithinTx(r.Context(), func(ctx context.Context) error {
+		if err := s.repos.Tasks.Update(ctx, currentTask); err != nil {
+			return err
+		}
+		// Спринт хранится отдельно от остальных полей, пустая строка - бэклог
+		if req.SprintID != nil {
+			notFound = "Sprint not found"
+			return s.repos.Tasks.SetSprint(ctx, currentTask.ID, sprintID)
+		}
+		return nil
+	})
+	if err != nil {
+		writeRepoError(w, err, notFound)
+		return
 	}
-	if err := cache.InvalidateAllTasks(); err != nil {
-		log.Printf("Failed to invalidate all tasks cache: %v", err)
+	if req.SprintID != nil {
+		currentTask.SprintID = sprintID
 	}
 
-	BroadcastTaskUpdate(currentTask.BoardID.String(), "task_updated", currentTask)
+	s.invalidateTasksCache(r.Context(), currentTask.BoardID)
+
+	s.broadcast(currentTask.BoardID.String(), "task_updated", currentTask)
+	if req.Status != nil {
+		s.broadcastParent(r.Context(), currentTask.ParentTaskID)
+	}
+	s.dispatchAutomation(automation.Changes(&previous, currentTask)...)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(currentTask)
 }
 
-func DeleteTask(w http.ResponseWriter, r *http.Request) {
+func (s *Server) DeleteTask(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.Parse(vars["id"])
 	if err != nil {
@@ -207,30 +409,27 @@ func DeleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := repository.GetTaskByID(id)
+	task, err := s.repos.Tasks.GetByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Task not found", http.StatusNotFound)
+		writeRepoError(w, err, "Task not found")
 		return
 	}
 
-	if err := repository.DeleteTask(id); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if err := s.repos.Tasks.Delete(r.Context(), id); err != nil {
+		writeRepoError(w, err, "Task not found")
 		return
 	}
 
-	if err := cache.InvalidateBoardTasks(task.BoardID.String()); err != nil {
-		log.Printf("Failed to invalidate cache for board %s: %v", task.BoardID.String(), err)
-	}
-	if err := cache.InvalidateAllTasks(); err != nil {
-		log.Printf("Failed to invalidate all tasks cache: %v", err)
-	}
+	s.invalidateTasksCache(r.Context(), task.BoardID)
 
-	BroadcastTaskUpdate(task.BoardID.String(), "task_deleted", map[string]string{"id": id.String()})
+	metrics.TasksDeleted.Inc()
+	s.broadcast(task.BoardID.String(), "task_deleted", map[string]string{"id": id.String()})
+	s.broadcastParent(r.Context(), task.ParentTaskID)
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func MoveTask(w http.ResponseWriter, r *http.Request) {
+func (s *Server) MoveTask(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.Parse(vars["id"])
 	if err != nil {
@@ -246,27 +445,309 @@ func MoveTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := repository.MoveTask(id, req.Status); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	current, err := s.repos.Tasks.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+	if err := s.repos.CheckMoveAllowed(r.Context(), current, req.Status); err != nil {
+		writeMoveError(w, err)
+		return
+	}
+
+	if err := s.repos.Tasks.Move(r.Context(), id, req.Status); err != nil {
+		writeRepoError(w, err, "Task not found")
 		return
 	}
 
-	task, err := repository.GetTaskByID(id)
+	task, err := s.repos.Tasks.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+
+	s.invalidateTasksCache(r.Context(), task.BoardID)
+
+	metrics.TasksMoved.Inc()
+	s.broadcast(task.BoardID.String(), "task_moved", task)
+	s.broadcastParent(r.Context(), task.ParentTaskID)
+	s.dispatchAutomation(automation.Changes(current, task)...)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+func (s *Server) BulkMoveTasks(w http.ResponseWriter, r *http.Request) {
+	var req models.BulkMoveTasksRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.TaskIDs) == 0 || req.Status == "" {
+		http.Error(w, "task_ids and status are required", http.StatusBadRequest)
+		return
+	}
+
+	// Задачи до перемещения нужны правилам автоматизации с триггером task_moved
+	previous := make(map[uuid.UUID]models.Task, len(req.TaskIDs))
+	if s.automation != nil {
+		for _, id := range req.TaskIDs {
+			if task, err := s.repos.Tasks.GetByID(r.Context(), id); err == nil {
+				previous[id] = *task
+			}
+		}
+	}
+
+	tasks, err := s.repos.MoveTasks(r.Context(), req.TaskIDs, req.Status)
+	if err != nil {
+		writeMoveError(w, err)
+		return
+	}
+
+	boards := make(map[uuid.UUID]bool)
+	for i := range tasks {
+		boards[tasks[i].BoardID] = true
+		metrics.TasksMoved.Inc()
+		s.broadcast(tasks[i].BoardID.String(), "task_moved", &tasks[i])
+		s.broadcastParent(r.Context(), tasks[i].ParentTaskID)
+		if before, ok := previous[tasks[i].ID]; ok {
+			s.dispatchAutomation(automation.Changes(&before, &tasks[i])...)
+		}
+	}
+	for boardID := range boards {
+		s.invalidateTasksCache(r.Context(), boardID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tasks)
+}
+
+// TransferTask переносит задачу на другую доску (mode=move) или копирует ее (mode=copy).
+// Исходная доска получает task_deleted, целевая - task_created.
+func (s *Server) TransferTask(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.TransferTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.BoardID == uuid.Nil {
+		http.Error(w, "board_id is required", http.StatusBadRequest)
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = models.TransferModeMove
+	}
+	if req.Mode != models.TransferModeMove && req.Mode != models.TransferModeCopy {
+		http.Error(w, "mode must be move or copy", http.StatusBadRequest)
+		return
+	}
+
+	copyTask := req.Mode == models.TransferModeCopy
+	source, task, err := s.repos.TransferTask(r.Context(), id, req.BoardID, req.Status, copyTask)
+	if err != nil {
+		if errors.Is(err, repository.ErrUnknownStatus) {
+			http.Error(w, "status does not match any column of the target board", http.StatusBadRequest)
+			return
+		}
+		writeRepoError(w, err, "Task or board not found")
+		return
+	}
+
+	s.invalidateTasksCache(r.Context(), task.BoardID)
+	if copyTask {
+		metrics.TasksCreated.Inc()
+	} else {
+		s.invalidateTasksCache(r.Context(), source.BoardID)
+		s.broadcast(source.BoardID.String(), "task_deleted", map[string]string{"id": source.ID.String()})
+	}
+	s.broadcast(task.BoardID.String(), "task_created", task)
+
+	logging.FromContext(r.Context()).Info("Task transferred",
+		"task_id", source.ID, "from_board_id", source.BoardID, "to_board_id", task.BoardID, "mode", req.Mode)
+
+	w.Header().Set("Content-Type", "application/json")
+	if copyTask {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(task)
+}
+
+func (s *Server) GetSubtasks(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.repos.Tasks.GetByID(r.Context(), id); err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+
+	subtasks, err := s.repos.Tasks.ListSubtasks(r.Context(), id)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if subtasks == nil {
+		subtasks = []models.Task{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subtasks)
+}
+
+// SetTaskParent делает задачу подзадачей другой задачи той же доски или отвязывает ее
+func (s *Server) SetTaskParent(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid task ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetParentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
 
-	if err := cache.InvalidateBoardTasks(task.BoardID.String()); err != nil {
-		log.Printf("Failed to invalidate cache for board %s: %v", task.BoardID.String(), err)
+	previous, err := s.repos.Tasks.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
 	}
-	if err := cache.InvalidateAllTasks(); err != nil {
-		log.Printf("Failed to invalidate all tasks cache: %v", err)
+
+	task, err := s.repos.SetTaskParent(r.Context(), id, req.ParentTaskID)
+	switch {
+	case errors.Is(err, repository.ErrInvalidParent):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, repository.ErrTaskCycle):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case err != nil:
+		writeRepoError(w, err, "Task not found")
+		return
 	}
 
-	BroadcastTaskUpdate(task.BoardID.String(), "task_moved", task)
+	s.invalidateTasksCache(r.Context(), task.BoardID)
+	s.broadcast(task.BoardID.String(), "task_updated", task)
+	s.broadcastParent(r.Context(), previous.ParentTaskID)
+	if req.ParentTaskID != nil && (previous.ParentTaskID == nil || *previous.ParentTaskID != *req.ParentTaskID) {
+		s.broadcastParent(r.Context(), req.ParentTaskID)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(task)
 }
 
+// broadcastParent сообщает об изменении прогресса родительской задачи
+func (s *Server) broadcastParent(ctx context.Context, parentID *uuid.UUID) {
+	if parentID == nil {
+		return
+	}
+	parent, err := s.repos.Tasks.GetByID(ctx, *parentID)
+	if err != nil {
+		return
+	}
+	s.broadcast(parent.BoardID.String(), "task_updated", parent)
+}
+
+// TaskCreated сбрасывает кэш задач доски и рассылает task_created. Вызывается
+// и для задач, созданных не через API (например, планировщиком повторений).
+func (s *Server) TaskCreated(ctx context.Context, task *models.Task) {
+	s.invalidateTasksCache(ctx, task.BoardID)
+
+	metrics.TasksCreated.Inc()
+	s.broadcast(task.BoardID.String(), "task_created", task)
+	s.dispatchAutomation(automation.Event{Type: models.AutomationTriggerTaskCreated, Task: *task})
+}
+
+// TaskChanged сбрасывает кэш и рассылает событие об изменении задачи,
+// сделанном правилом автоматизации
+func (s *Server) TaskChanged(ctx context.Context, eventType string, task *models.Task) {
+	s.invalidateTasksCache(ctx, task.BoardID)
+
+	s.broadcast(task.BoardID.String(), eventType, task)
+	if eventType == "task_moved" {
+		s.broadcastParent(ctx, task.ParentTaskID)
+	}
+}
+
+// dispatchAutomation передает события задач правилам автоматизации
+func (s *Server) dispatchAutomation(events ...automation.Event) {
+	if s.automation == nil {
+		return
+	}
+	for _, event := range events {
+		s.automation.Dispatch(event)
+	}
+}
+
+// transitionErrorResponse - тело ответа 422 на недопустимый статус или переход
+type transitionErrorResponse struct {
+	Error   string   `json:"error"`
+	Code    string   `json:"code"`
+	From    string   `json:"from,omitempty"`
+	To      string   `json:"to"`
+	Allowed []string `json:"allowed"`
+	Missing []string `json:"missing_fields,omitempty"`
+}
+
+// writeMoveError отвечает 422 на статус без колонки, запрещенный переход
+// или незаполненные поля и 409 на перемещение заблокированной задачи
+func writeMoveError(w http.ResponseWriter, err error) {
+	var transitionErr *repository.TransitionError
+	if errors.As(err, &transitionErr) {
+		resp := transitionErrorResponse{
+			Error:   err.Error(),
+			Code:    "transition_not_allowed",
+			From:    transitionErr.From,
+			To:      transitionErr.To,
+			Allowed: transitionErr.Allowed,
+			Missing: transitionErr.Missing,
+		}
+		switch {
+		case errors.Is(err, repository.ErrUnknownStatus):
+			resp.Code = "unknown_status"
+		case errors.Is(err, repository.ErrRequiredFieldsMissing):
+			resp.Code = "required_fields_missing"
+		}
+		if resp.Allowed == nil {
+			resp.Allowed = []string{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+	if errors.Is(err, repository.ErrTaskBlocked) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeRepoError(w, err, err.Error())
+}
+
+// listOptions читает общие параметры выборки списков из query-строки
+func listOptions(r *http.Request) repository.ListOptions {
+	includeArchived, _ := strconv.ParseBool(r.URL.Query().Get("include_archived"))
+	return repository.ListOptions{IncludeArchived: includeArchived}
+}
+
+func (s *Server) invalidateTasksCache(ctx context.Context, boardID uuid.UUID) {
+	if err := s.cache.InvalidateBoardTasks(ctx, boardID.String()); err != nil {
+		logging.FromContext(ctx).Warn("Failed to invalidate tasks cache", "board_id", boardID, "error", err)
+	}
+	if err := s.cache.InvalidateAllTasks(ctx); err != nil {
+		logging.FromContext(ctx).Warn("Failed to invalidate all tasks cache", "error", err)
+	}
+}
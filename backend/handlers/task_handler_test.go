@@ -1,27 +1,42 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"testing"
 	"task-flow-backend/models"
-	"task-flow-backend/repository"
+	"task-flow-backend/templates"
+	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+type recordedEvent struct {
+	boardID   string
+	eventType string
+}
+
+type recordingHub struct {
+	events []recordedEvent
+}
+
+func (h *recordingHub) Broadcast(boardID string, messageType string, data interface{}) {
+	h.events = append(h.events, recordedEvent{boardID: boardID, eventType: messageType})
+}
+
 func TestGetTasks(t *testing.T) {
-	setupTestDB(t)
-	
-	userID := createTestUser(t)
+	server, router := newTestServer(t)
+
+	userID := createTestUser(t, server)
 
 	board := &models.Board{
 		Name:   "Test Board for Tasks",
 		UserID: &userID,
 	}
-	err := repository.CreateBoard(board)
+	err := server.repos.Boards.Create(context.Background(), board)
 	require.NoError(t, err, "Failed to create test board")
 
 	task := &models.Task{
@@ -31,7 +46,7 @@ func TestGetTasks(t *testing.T) {
 		Status:      "plan",
 		CreatedBy:   &userID,
 	}
-	err = repository.CreateTask(task)
+	err = server.repos.Tasks.Create(context.Background(), task)
 	require.NoError(t, err, "Failed to create test task")
 
 	t.Run("Get tasks by board_id", func(t *testing.T) {
@@ -39,8 +54,7 @@ func TestGetTasks(t *testing.T) {
 		require.NoError(t, err)
 
 		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(GetTasks)
-		handler.ServeHTTP(rr, req)
+		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusOK, rr.Code, "Expected status 200")
 		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
@@ -49,7 +63,7 @@ func TestGetTasks(t *testing.T) {
 		err = json.Unmarshal(rr.Body.Bytes(), &tasks)
 		require.NoError(t, err, "Failed to unmarshal response")
 		assert.GreaterOrEqual(t, len(tasks), 1, "Expected at least one task")
-		
+
 		found := false
 		for _, taskItem := range tasks {
 			if taskItem.ID == task.ID {
@@ -67,8 +81,7 @@ func TestGetTasks(t *testing.T) {
 		require.NoError(t, err)
 
 		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(GetTasks)
-		handler.ServeHTTP(rr, req)
+		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusOK, rr.Code, "Expected status 200")
 		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
@@ -84,13 +97,154 @@ func TestGetTasks(t *testing.T) {
 		require.NoError(t, err)
 
 		rr := httptest.NewRecorder()
-		handler := http.HandlerFunc(GetTasks)
-		handler.ServeHTTP(rr, req)
+		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusBadRequest, rr.Code, "Expected status 400")
 	})
+}
+
+func TestTaskLifecycle(t *testing.T) {
+	hub := &recordingHub{}
+	server, router := newTestServer(t, WithHub(hub))
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Lifecycle", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+
+	var task models.Task
+	t.Run("Create task", func(t *testing.T) {
+		body := `{"board_id":"` + board.ID.String() + `","title":"New task"}`
+		req, err := http.NewRequest("POST", "/api/tasks", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		authorize(t, req, userID)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusCreated, rr.Code, "Expected status 201")
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &task))
+		assert.Equal(t, "plan", task.Status, "Expected default status")
+		require.NotNil(t, task.CreatedBy)
+		assert.Equal(t, userID, *task.CreatedBy)
+	})
+
+	t.Run("Move task", func(t *testing.T) {
+		req, err := http.NewRequest("PATCH", "/api/tasks/"+task.ID.String()+"/move", bytes.NewBufferString(`{"status":"testing"}`))
+		require.NoError(t, err)
+		authorize(t, req, userID)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code, "Expected status 200")
+		var moved models.Task
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &moved))
+		assert.Equal(t, "testing", moved.Status)
+	})
+
+	t.Run("Delete task", func(t *testing.T) {
+		req, err := http.NewRequest("DELETE", "/api/tasks/"+task.ID.String(), nil)
+		require.NoError(t, err)
+		authorize(t, req, userID)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNoContent, rr.Code, "Expected status 204")
+
+		req, err = http.NewRequest("GET", "/api/tasks/"+task.ID.String(), nil)
+		require.NoError(t, err)
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusNotFound, rr.Code, "Expected status 404")
+	})
 
-	repository.DeleteTask(task.ID)
-	repository.DeleteBoard(board.ID)
+	var eventTypes []string
+	for _, event := range hub.events {
+		assert.Equal(t, board.ID.String(), event.boardID)
+		eventTypes = append(eventTypes, event.eventType)
+	}
+	assert.Equal(t, []string{"task_created", "task_moved", "task_deleted"}, eventTypes)
 }
 
+func TestRequestCancellation(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Cancelled", UserID: &userID}
+	require.NoError(t, server.repos.Boards.Create(context.Background(), board))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "/api/tasks?board_id="+board.ID.String(), nil)
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code, "Expected repository to observe the cancelled context")
+}
+
+func TestTransferTask(t *testing.T) {
+	hub := &recordingHub{}
+	server, router := newTestServer(t, WithHub(hub))
+	userID := createTestUser(t, server)
+	ctx := context.Background()
+
+	source := &models.Board{Name: "Source", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(ctx, source, templates.Default()))
+	kanban, _ := templates.Builtin("kanban", "ru")
+	target := &models.Board{Name: "Target", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(ctx, target, kanban))
+
+	task := &models.Task{BoardID: source.ID, Title: "Closed task", Status: "closed"}
+	require.NoError(t, server.repos.Tasks.Create(ctx, task))
+
+	transfer := func(body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", "/api/tasks/"+task.ID.String()+"/transfer", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		authorize(t, req, userID)
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("Unknown target status", func(t *testing.T) {
+		rr := transfer(`{"board_id":"` + target.ID.String() + `","status":"closed"}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Copy keeps the original", func(t *testing.T) {
+		rr := transfer(`{"board_id":"` + target.ID.String() + `","mode":"copy","status":"done"}`)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+		var copied models.Task
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &copied))
+		assert.NotEqual(t, task.ID, copied.ID)
+		assert.Equal(t, target.ID, copied.BoardID)
+
+		original, err := server.repos.Tasks.GetByID(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, source.ID, original.BoardID)
+	})
+
+	t.Run("Move maps status by column position", func(t *testing.T) {
+		hub.events = nil
+		rr := transfer(`{"board_id":"` + target.ID.String() + `"}`)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var moved models.Task
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &moved))
+		assert.Equal(t, task.ID, moved.ID)
+		assert.Equal(t, target.ID, moved.BoardID)
+		// "Закрыто" - последняя колонка, у Kanban колонок три: задача остается завершенной
+		assert.Equal(t, "done", moved.Status)
+
+		assert.Equal(t, []recordedEvent{
+			{boardID: source.ID.String(), eventType: "task_deleted"},
+			{boardID: target.ID.String(), eventType: "task_created"},
+		}, hub.events)
+	})
+}
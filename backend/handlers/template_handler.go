@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"task-flow-backend/logging"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/templates"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// resolveTemplate находит встроенный шаблон (на языке из Accept-Language)
+// или сохраненный по UUID. Пустой id означает шаблон по умолчанию.
+func (s *Server) resolveTemplate(r *http.Request, id string) (models.BoardTemplate, error) {
+	if id == "" {
+		id = templates.DefaultID
+	}
+	if tpl, ok := templates.Builtin(id, templates.Locale(r.Header.Get("Accept-Language"))); ok {
+		return tpl, nil
+	}
+
+	templateID, err := uuid.Parse(id)
+	if err != nil {
+		return models.BoardTemplate{}, repository.ErrNotFound
+	}
+	tpl, err := s.repos.Templates.GetByID(r.Context(), templateID)
+	if err != nil {
+		return models.BoardTemplate{}, err
+	}
+	return *tpl, nil
+}
+
+func (s *Server) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	stored, err := s.repos.Templates.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := append(templates.Builtins(templates.Locale(r.Header.Get("Accept-Language"))), stored...)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (s *Server) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	tpl, err := s.resolveTemplate(r, mux.Vars(r)["id"])
+	if err != nil {
+		writeRepoError(w, err, "Template not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tpl)
+}
+
+func (s *Server) SaveBoardAsTemplate(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	vars := mux.Vars(r)
+	boardID, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SaveTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "Template name is required", http.StatusBadRequest)
+		return
+	}
+
+	tpl := &models.BoardTemplate{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if userID, ok := userIDFromContext(r.Context()); ok {
+		tpl.CreatedBy = &userID
+	}
+
+	if err := s.repos.SaveBoardAsTemplate(r.Context(), boardID, tpl, req.IncludeTasks); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	logger.Info("Board saved as template", "board_id", boardID, "template_id", tpl.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tpl)
+}
+
+func (s *Server) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if _, ok := templates.Builtin(vars["id"], templates.DefaultLocale); ok {
+		http.Error(w, "Built-in templates cannot be deleted", http.StatusBadRequest)
+		return
+	}
+
+	id, err := uuid.Parse(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.repos.Templates.Delete(r.Context(), id); err != nil {
+		writeRepoError(w, err, "Template not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
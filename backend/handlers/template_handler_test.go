@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-flow-backend/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardTemplates(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+
+	t.Run("List built-in templates in requested language", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/api/templates", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var list []models.BoardTemplate
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+
+		ids := make(map[string]string)
+		for _, tpl := range list {
+			ids[tpl.ID] = tpl.Name
+			assert.True(t, tpl.BuiltIn)
+		}
+		assert.Equal(t, "Bug triage", ids["bug-triage"])
+		assert.Contains(t, ids, "scrum")
+		assert.Contains(t, ids, "kanban")
+	})
+
+	var board models.Board
+	t.Run("Create board from template", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", "/api/boards", `{"name":"Sprint board","template_id":"scrum"}`)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &board))
+
+		rr = doRequest(t, router, userID, "GET", "/api/columns?board_id="+board.ID.String(), "")
+		var columns []models.Column
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &columns))
+		require.Len(t, columns, 5)
+		assert.Equal(t, "Бэклог", columns[0].Title)
+		require.NotNil(t, columns[2].WIPLimit)
+		assert.Equal(t, 5, *columns[2].WIPLimit)
+
+		rr = doRequest(t, router, userID, "GET", "/api/boards/"+board.ID.String()+"/labels", "")
+		var labels []models.Label
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &labels))
+		assert.Len(t, labels, 3)
+	})
+
+	t.Run("Create board with unknown template", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", "/api/boards", `{"name":"Nope","template_id":"waterfall"}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("Save board as template and reuse it", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", "/api/boards/"+board.ID.String()+"/save-as-template", `{"name":"Our sprint"}`)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+		var saved models.BoardTemplate
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &saved))
+		assert.False(t, saved.BuiltIn)
+		assert.Len(t, saved.Columns, 5)
+		assert.Empty(t, saved.Tasks, "Tasks are only captured with include_tasks")
+		require.NotNil(t, saved.CreatedBy)
+		assert.Equal(t, userID, *saved.CreatedBy)
+
+		rr = doRequest(t, router, userID, "POST", "/api/boards", `{"name":"Next sprint","template_id":"`+saved.ID+`"}`)
+		assert.Equal(t, http.StatusCreated, rr.Code)
+
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "DELETE", "/api/templates/scrum", "").Code)
+		assert.Equal(t, http.StatusNoContent, doRequest(t, router, userID, "DELETE", "/api/templates/"+saved.ID, "").Code)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "GET", "/api/templates/"+saved.ID, "").Code)
+	})
+
+	t.Run("Save missing board as template", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", "/api/boards/00000000-0000-0000-0000-000000000000/save-as-template", `{"name":"Ghost"}`)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) GetTrash(w http.ResponseWriter, r *http.Request) {
+	items, err := s.repos.Trash.List(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if items == nil {
+		items = []models.TrashItem{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+func (s *Server) RestoreBoard(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.restore(w, r, models.TrashTypeBoard)
+	if !ok {
+		return
+	}
+
+	board, err := s.repos.Boards.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(board)
+}
+
+func (s *Server) RestoreColumn(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.restore(w, r, models.TrashTypeColumn)
+	if !ok {
+		return
+	}
+
+	column, err := s.repos.Columns.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Column not found")
+		return
+	}
+	s.broadcast(column.BoardID.String(), "column_created", column)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(column)
+}
+
+func (s *Server) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.restore(w, r, models.TrashTypeTask)
+	if !ok {
+		return
+	}
+
+	task, err := s.repos.Tasks.GetByID(r.Context(), id)
+	if err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+	s.invalidateTasksCache(r.Context(), task.BoardID)
+	s.broadcast(task.BoardID.String(), "task_created", task)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+// restore возвращает элемент из корзины и при ошибке сам пишет ответ
+func (s *Server) restore(w http.ResponseWriter, r *http.Request, itemType string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid "+itemType+" ID", http.StatusBadRequest)
+		return uuid.Nil, false
+	}
+
+	if err := s.repos.Trash.Restore(r.Context(), itemType, id); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			http.Error(w, "A column with the same status_id already exists on the board", http.StatusConflict)
+			return uuid.Nil, false
+		}
+		writeRepoError(w, err, "Item not found in trash")
+		return uuid.Nil, false
+	}
+
+	if itemType == models.TrashTypeBoard {
+		s.invalidateTasksCache(r.Context(), id)
+	}
+	return id, true
+}
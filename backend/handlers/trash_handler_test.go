@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"task-flow-backend/models"
+	"task-flow-backend/templates"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash(t *testing.T) {
+	hub := &recordingHub{}
+	server, router := newTestServer(t, WithHub(hub))
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Sprint", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+	task := &models.Task{BoardID: board.ID, Title: "Important", Status: "plan"}
+	require.NoError(t, server.repos.Tasks.Create(context.Background(), task))
+
+	trash := func(t *testing.T) []models.TrashItem {
+		t.Helper()
+		rr := doRequest(t, router, userID, "GET", "/api/trash", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var items []models.TrashItem
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &items))
+		return items
+	}
+	taskPath := "/api/tasks/" + task.ID.String()
+	boardPath := "/api/boards/" + board.ID.String()
+
+	t.Run("Trash starts empty", func(t *testing.T) {
+		assert.Empty(t, trash(t))
+	})
+
+	t.Run("Deleted task goes to trash", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, doRequest(t, router, userID, "DELETE", taskPath, "").Code)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "GET", taskPath, "").Code)
+
+		items := trash(t)
+		require.Len(t, items, 1)
+		assert.Equal(t, models.TrashTypeTask, items[0].Type)
+		assert.Equal(t, "Important", items[0].Title)
+	})
+
+	t.Run("Restore task", func(t *testing.T) {
+		hub.events = nil
+		rr := doRequest(t, router, userID, "POST", taskPath+"/restore", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, []recordedEvent{{boardID: board.ID.String(), eventType: "task_created"}}, hub.events)
+		assert.Equal(t, http.StatusOK, doRequest(t, router, userID, "GET", taskPath, "").Code)
+	})
+
+	t.Run("Restore task that is not deleted", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "POST", taskPath+"/restore", "").Code)
+	})
+
+	t.Run("Deleted board goes to trash", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, doRequest(t, router, userID, "DELETE", boardPath, "").Code)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "GET", boardPath, "").Code)
+
+		items := trash(t)
+		require.Len(t, items, 1)
+		assert.Equal(t, models.TrashTypeBoard, items[0].Type)
+	})
+
+	t.Run("Restored board comes back with its tasks", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", boardPath+"/restore", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var restored models.Board
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &restored))
+		require.Len(t, restored.Tasks, 1)
+		assert.Equal(t, task.ID, restored.Tasks[0].ID)
+		assert.Empty(t, trash(t))
+	})
+}
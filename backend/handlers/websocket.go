@@ -1,27 +1,12 @@
 package handlers
 
-import "task-flow-backend/websocket"
-
-var wsHub *websocket.Hub
-
-func SetWebSocketHub(hub *websocket.Hub) {
-	wsHub = hub
-}
-
-func BroadcastTaskUpdate(boardID string, eventType string, task interface{}) {
-	if wsHub != nil {
-		wsHub.Broadcast(boardID, eventType, task)
-	}
-}
-
-func BroadcastBoardUpdate(boardID string, eventType string, board interface{}) {
-	if wsHub != nil {
-		wsHub.Broadcast(boardID, eventType, board)
-	}
+// Broadcaster рассылает события подписчикам доски; реализуется websocket.Hub
+type Broadcaster interface {
+	Broadcast(boardID string, messageType string, data interface{})
 }
 
-func BroadcastColumnUpdate(boardID string, eventType string, column interface{}) {
-	if wsHub != nil {
-		wsHub.Broadcast(boardID, eventType, column)
+func (s *Server) broadcast(boardID string, eventType string, data interface{}) {
+	if s.hub != nil {
+		s.hub.Broadcast(boardID, eventType, data)
 	}
 }
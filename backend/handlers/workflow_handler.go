@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) GetWorkflow(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.repos.Boards.GetByID(r.Context(), boardID); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	transitions, err := s.repos.Workflows.ListByBoard(r.Context(), boardID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if transitions == nil {
+		transitions = []models.WorkflowTransition{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transitions)
+}
+
+// UpdateWorkflow заменяет все переходы доски; пустой список снимает
+// ограничения на перемещение задач
+func (s *Server) UpdateWorkflow(w http.ResponseWriter, r *http.Request) {
+	boardID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid board ID", http.StatusBadRequest)
+		return
+	}
+
+	var transitions []models.WorkflowTransition
+	if err := json.NewDecoder(r.Body).Decode(&transitions); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := s.repos.Boards.GetByID(r.Context(), boardID); err != nil {
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+	columns, err := s.repos.Columns.ListByBoard(r.Context(), boardID, repository.ListOptions{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	hasColumn := func(status string) bool {
+		return slices.ContainsFunc(columns, func(c models.Column) bool { return c.StatusID == status })
+	}
+
+	for i := range transitions {
+		transition := &transitions[i]
+		if transition.From != repository.AnyStatus && !hasColumn(transition.From) {
+			http.Error(w, "from "+transition.From+" does not match any column of the board", http.StatusBadRequest)
+			return
+		}
+		if !hasColumn(transition.To) {
+			http.Error(w, "to "+transition.To+" does not match any column of the board", http.StatusBadRequest)
+			return
+		}
+		if transition.From == transition.To {
+			http.Error(w, "from and to must differ", http.StatusBadRequest)
+			return
+		}
+		for _, field := range transition.RequiredFields {
+			if !slices.Contains(repository.WorkflowFields, field) {
+				http.Error(w, "unknown required field "+field, http.StatusBadRequest)
+				return
+			}
+		}
+		if transition.RequiredFields == nil {
+			transition.RequiredFields = []string{}
+		}
+		transition.BoardID = boardID
+	}
+
+	if err := s.repos.Workflows.Replace(r.Context(), boardID, transitions); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			http.Error(w, "Duplicate transition in workflow", http.StatusConflict)
+			return
+		}
+		writeRepoError(w, err, "Board not found")
+		return
+	}
+
+	if transitions == nil {
+		transitions = []models.WorkflowTransition{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(transitions)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"task-flow-backend/models"
+	"task-flow-backend/templates"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowTransitions(t *testing.T) {
+	server, router := newTestServer(t)
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Workflow", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+
+	decodeError := func(t *testing.T, rr *httptest.ResponseRecorder) transitionErrorResponse {
+		t.Helper()
+		require.Equal(t, http.StatusUnprocessableEntity, rr.Code, rr.Body.String())
+		var resp transitionErrorResponse
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+		return resp
+	}
+
+	workflowPath := "/api/boards/" + board.ID.String() + "/workflow"
+
+	t.Run("Invalid workflow", func(t *testing.T) {
+		cases := map[string]string{
+			"unknown from":  `[{"from":"nope","to":"analysis"}]`,
+			"unknown to":    `[{"from":"plan","to":"nope"}]`,
+			"same status":   `[{"from":"plan","to":"plan"}]`,
+			"unknown field": `[{"from":"plan","to":"analysis","required_fields":["color"]}]`,
+		}
+		for name, body := range cases {
+			assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "PUT", workflowPath, body).Code, name)
+		}
+		assert.Equal(t, http.StatusConflict, doRequest(t, router, userID, "PUT", workflowPath, `[{"from":"plan","to":"analysis"},{"from":"plan","to":"analysis"}]`).Code)
+	})
+
+	t.Run("Save workflow", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "PUT", workflowPath, `[
+			{"from":"plan","to":"analysis"},
+			{"from":"analysis","to":"development","required_fields":["assignee"]},
+			{"from":"*","to":"plan"}
+		]`)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		rr = doRequest(t, router, userID, "GET", workflowPath, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var transitions []models.WorkflowTransition
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &transitions))
+		assert.Len(t, transitions, 3)
+	})
+
+	t.Run("Create task with unknown status", func(t *testing.T) {
+		resp := decodeError(t, doRequest(t, router, userID, "POST", "/api/tasks", `{"board_id":"`+board.ID.String()+`","title":"Nowhere","status":"nope"}`))
+		assert.Equal(t, "unknown_status", resp.Code)
+		assert.Contains(t, resp.Allowed, "plan")
+	})
+
+	var task models.Task
+	taskPath := func() string { return "/api/tasks/" + task.ID.String() }
+	t.Run("Move outside workflow", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", "/api/tasks", `{"board_id":"`+board.ID.String()+`","title":"Feature"}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &task))
+
+		resp := decodeError(t, doRequest(t, router, userID, "PATCH", taskPath()+"/move", `{"status":"closed"}`))
+		assert.Equal(t, "transition_not_allowed", resp.Code)
+		assert.Equal(t, "plan", resp.From)
+		assert.Equal(t, "closed", resp.To)
+		assert.ElementsMatch(t, []string{"analysis", "plan"}, resp.Allowed)
+		assert.Contains(t, resp.Error, `from "plan" to "closed"`)
+
+		resp = decodeError(t, doRequest(t, router, userID, "PATCH", taskPath()+"/move", `{"status":"nope"}`))
+		assert.Equal(t, "unknown_status", resp.Code)
+
+		require.Equal(t, http.StatusOK, doRequest(t, router, userID, "PATCH", taskPath()+"/move", `{"status":"analysis"}`).Code)
+	})
+
+	t.Run("Required fields", func(t *testing.T) {
+		resp := decodeError(t, doRequest(t, router, userID, "PUT", taskPath(), `{"status":"development"}`))
+		assert.Equal(t, "required_fields_missing", resp.Code)
+		assert.Equal(t, []string{"assignee"}, resp.Missing)
+
+		// Исполнитель, заданный тем же запросом, удовлетворяет переходу
+		rr := doRequest(t, router, userID, "PUT", taskPath(), `{"status":"development","assignee":"dev"}`)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &task))
+		assert.Equal(t, "development", task.Status)
+	})
+
+	t.Run("Bulk move outside workflow", func(t *testing.T) {
+		resp := decodeError(t, doRequest(t, router, userID, "PATCH", "/api/tasks/bulk-move", `{"task_ids":["`+task.ID.String()+`"],"status":"closed"}`))
+		assert.Equal(t, "transition_not_allowed", resp.Code)
+	})
+
+	t.Run("Empty workflow removes restrictions", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, doRequest(t, router, userID, "PUT", workflowPath, `[]`).Code)
+		assert.Equal(t, http.StatusOK, doRequest(t, router, userID, "PATCH", taskPath()+"/move", `{"status":"closed"}`).Code)
+	})
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// maxWorklogMinutes ограничивает одну запись времени сутками
+const maxWorklogMinutes = 24 * 60
+
+func (s *Server) GetWorklogs(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	worklogs, err := s.repos.Worklogs.ListByTask(r.Context(), task.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if worklogs == nil {
+		worklogs = []models.Worklog{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(worklogs)
+}
+
+// CreateWorklog учитывает время текущего пользователя: либо интервал
+// started_at/ended_at, либо duration_minutes, закончившиеся в started_at +
+// duration (по умолчанию - сейчас)
+func (s *Server) CreateWorklog(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateWorklogRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	worklog := &models.Worklog{TaskID: task.ID, UserID: userID, Note: req.Note}
+	now := time.Now()
+
+	switch {
+	case req.DurationMinutes != nil && req.EndedAt != nil:
+		http.Error(w, "Specify either ended_at or duration_minutes", http.StatusBadRequest)
+		return
+	case req.DurationMinutes != nil:
+		if *req.DurationMinutes <= 0 || *req.DurationMinutes > maxWorklogMinutes {
+			http.Error(w, "duration_minutes must be between 1 and 1440", http.StatusBadRequest)
+			return
+		}
+		duration := time.Duration(*req.DurationMinutes) * time.Minute
+		if req.StartedAt != nil {
+			worklog.StartedAt = *req.StartedAt
+		} else {
+			worklog.StartedAt = now.Add(-duration)
+		}
+		endedAt := worklog.StartedAt.Add(duration)
+		worklog.EndedAt = &endedAt
+		worklog.DurationMinutes = *req.DurationMinutes
+	case req.StartedAt != nil && req.EndedAt != nil:
+		if !req.EndedAt.After(*req.StartedAt) {
+			http.Error(w, "ended_at must be after started_at", http.StatusBadRequest)
+			return
+		}
+		worklog.StartedAt = *req.StartedAt
+		worklog.EndedAt = req.EndedAt
+		worklog.DurationMinutes = repository.WorklogMinutes(*req.StartedAt, *req.EndedAt)
+		if worklog.DurationMinutes > maxWorklogMinutes {
+			http.Error(w, "A worklog must not exceed 24 hours", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Either started_at and ended_at or duration_minutes are required", http.StatusBadRequest)
+		return
+	}
+
+	if worklog.EndedAt.After(now) {
+		http.Error(w, "A worklog must not end in the future", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.repos.Worklogs.Create(r.Context(), worklog); err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+
+	s.broadcast(task.BoardID.String(), "worklog_created", worklog)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(worklog)
+}
+
+// DeleteWorklog удаляет запись времени; удалить можно только свою запись
+func (s *Server) DeleteWorklog(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	worklogID, err := uuid.Parse(mux.Vars(r)["worklog_id"])
+	if err != nil {
+		http.Error(w, "Invalid worklog ID", http.StatusBadRequest)
+		return
+	}
+
+	worklog, err := s.repos.Worklogs.GetByID(r.Context(), worklogID)
+	if err == nil && worklog.TaskID != task.ID {
+		err = repository.ErrNotFound
+	}
+	if err != nil {
+		writeRepoError(w, err, "Worklog not found")
+		return
+	}
+	if worklog.UserID != userID {
+		http.Error(w, "Only the author can delete a worklog", http.StatusForbidden)
+		return
+	}
+
+	if err := s.repos.Worklogs.Delete(r.Context(), worklog.ID); err != nil {
+		writeRepoError(w, err, "Worklog not found")
+		return
+	}
+
+	s.broadcast(task.BoardID.String(), "worklog_deleted", map[string]uuid.UUID{"id": worklog.ID, "task_id": task.ID})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTimer возвращает запущенный таймер текущего пользователя или 404
+func (s *Server) GetTimer(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	worklog, err := s.repos.Worklogs.GetRunning(r.Context(), userID)
+	if err != nil {
+		writeRepoError(w, err, "No running timer")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(worklog)
+}
+
+// StartTimer запускает таймер по задаче; 409, если у пользователя уже есть
+// запущенный таймер
+func (s *Server) StartTimer(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	task, ok := s.taskFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	worklog, err := s.repos.StartTimer(r.Context(), task.ID, userID)
+	if errors.Is(err, repository.ErrConflict) {
+		http.Error(w, "A timer is already running", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		writeRepoError(w, err, "Task not found")
+		return
+	}
+
+	s.broadcast(task.BoardID.String(), "timer_started", worklog)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(worklog)
+}
+
+// StopTimer останавливает таймер текущего пользователя и возвращает
+// получившуюся запись времени
+func (s *Server) StopTimer(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// Тело необязательно
+	var req models.StopTimerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	worklog, err := s.repos.StopTimer(r.Context(), userID, req.Note)
+	if err != nil {
+		writeRepoError(w, err, "No running timer")
+		return
+	}
+
+	// Задача могла быть удалена, пока шел таймер: тогда событие не отправляется
+	if task, err := s.repos.Tasks.GetByID(r.Context(), worklog.TaskID); err == nil {
+		s.broadcast(task.BoardID.String(), "worklog_created", worklog)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(worklog)
+}
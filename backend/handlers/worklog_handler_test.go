@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"task-flow-backend/models"
+	"task-flow-backend/templates"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeTracking(t *testing.T) {
+	hub := &recordingHub{}
+	server, router := newTestServer(t, WithHub(hub))
+	userID := createTestUser(t, server)
+
+	board := &models.Board{Name: "Time", UserID: &userID}
+	require.NoError(t, server.repos.CreateBoard(context.Background(), board, templates.Default()))
+
+	var task models.Task
+	t.Run("Create task with estimate", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "POST", "/api/tasks", `{"board_id":"`+board.ID.String()+`","title":"Invoice export","estimate_minutes":120}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &task))
+		require.NotNil(t, task.EstimateMinutes)
+		assert.Equal(t, 120, *task.EstimateMinutes)
+	})
+
+	taskPath := "/api/tasks/" + task.ID.String()
+	t.Run("Negative estimate", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "PUT", taskPath, `{"estimate_minutes":-5}`).Code)
+	})
+
+	worklogsPath := taskPath + "/worklogs"
+	start := time.Now().Add(-3 * time.Hour).UTC().Truncate(time.Second)
+
+	t.Run("Create validation", func(t *testing.T) {
+		cases := map[string]string{
+			"empty":             `{}`,
+			"end and duration":  `{"started_at":"` + start.Format(time.RFC3339) + `","ended_at":"` + start.Add(time.Hour).Format(time.RFC3339) + `","duration_minutes":60}`,
+			"end before start":  `{"started_at":"` + start.Format(time.RFC3339) + `","ended_at":"` + start.Add(-time.Hour).Format(time.RFC3339) + `"}`,
+			"too long":          `{"duration_minutes":1441}`,
+			"zero duration":     `{"duration_minutes":0}`,
+			"ends in future":    `{"started_at":"` + start.Format(time.RFC3339) + `","duration_minutes":600}`,
+			"longer than a day": `{"started_at":"` + start.Add(-48*time.Hour).Format(time.RFC3339) + `","ended_at":"` + start.Format(time.RFC3339) + `"}`,
+		}
+		for name, body := range cases {
+			assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "POST", worklogsPath, body).Code, name)
+		}
+	})
+
+	var interval models.Worklog
+	t.Run("Log time", func(t *testing.T) {
+		hub.events = nil
+		rr := doRequest(t, router, userID, "POST", worklogsPath, `{"started_at":"`+start.Format(time.RFC3339)+`","ended_at":"`+start.Add(90*time.Minute).Format(time.RFC3339)+`","note":"draft"}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &interval))
+		assert.Equal(t, 90, interval.DurationMinutes)
+		assert.Equal(t, userID, interval.UserID)
+		assert.Equal(t, []recordedEvent{{boardID: board.ID.String(), eventType: "worklog_created"}}, hub.events)
+
+		rr = doRequest(t, router, userID, "POST", worklogsPath, `{"duration_minutes":15}`)
+		require.Equal(t, http.StatusCreated, rr.Code)
+
+		rr = doRequest(t, router, userID, "GET", worklogsPath, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var worklogs []models.Worklog
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &worklogs))
+		assert.Len(t, worklogs, 2)
+	})
+
+	t.Run("Timer", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "GET", "/api/timer", "").Code)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "POST", "/api/timer/stop", "").Code)
+
+		hub.events = nil
+		require.Equal(t, http.StatusCreated, doRequest(t, router, userID, "POST", taskPath+"/timer/start", "").Code)
+		assert.Equal(t, http.StatusConflict, doRequest(t, router, userID, "POST", taskPath+"/timer/start", "").Code)
+		assert.Equal(t, []recordedEvent{{boardID: board.ID.String(), eventType: "timer_started"}}, hub.events)
+
+		rr := doRequest(t, router, userID, "GET", "/api/timer", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var running models.Worklog
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &running))
+		assert.Equal(t, task.ID, running.TaskID)
+		assert.Nil(t, running.EndedAt)
+
+		rr = doRequest(t, router, userID, "POST", "/api/timer/stop", `{"note":"call"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var stopped models.Worklog
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &stopped))
+		assert.Equal(t, running.ID, stopped.ID)
+		require.NotNil(t, stopped.EndedAt)
+		assert.Equal(t, 1, stopped.DurationMinutes)
+		require.NotNil(t, stopped.Note)
+		assert.Equal(t, "call", *stopped.Note)
+
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "GET", "/api/timer", "").Code)
+	})
+
+	t.Run("Only author deletes", func(t *testing.T) {
+		other := &models.User{Username: "other", Email: "other@test.com"}
+		require.NoError(t, server.repos.Users.Create(context.Background(), other, "testpass123"))
+
+		path := worklogsPath + "/" + interval.ID.String()
+		assert.Equal(t, http.StatusForbidden, doRequest(t, router, other.ID, "DELETE", path, "").Code)
+		require.Equal(t, http.StatusNoContent, doRequest(t, router, userID, "DELETE", path, "").Code)
+		assert.Equal(t, http.StatusNotFound, doRequest(t, router, userID, "DELETE", path, "").Code)
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "GET", "/api/reports/time?from=yesterday", "").Code)
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, router, userID, "GET", "/api/reports/time?board_id=nope", "").Code)
+
+		today := time.Now().UTC().Format("2006-01-02")
+		query := "/api/reports/time?board_id=" + board.ID.String() + "&from=" + today + "&to=" + today
+		if start.Format("2006-01-02") != today {
+			// Тест запущен вскоре после полуночи: записи начались вчера
+			query = "/api/reports/time?board_id=" + board.ID.String()
+		}
+
+		rr := doRequest(t, router, userID, "GET", query, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		var report models.TimeReport
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+		assert.Equal(t, 16, report.TotalMinutes)
+		require.Len(t, report.Tasks, 1)
+		assert.Equal(t, "Invoice export", report.Tasks[0].Title)
+		require.Len(t, report.Users, 1)
+		assert.Equal(t, "testuser", report.Users[0].Username)
+
+		rr = doRequest(t, router, userID, "GET", query+"&format=csv", "")
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Header().Get("Content-Type"), "text/csv")
+		assert.Contains(t, rr.Header().Get("Content-Disposition"), "time-report.csv")
+		records, err := csv.NewReader(rr.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, []string{"board_id", "task_id", "task_title", "user_id", "username", "minutes"}, records[0])
+		assert.Equal(t, []string{board.ID.String(), task.ID.String(), "Invoice export", userID.String(), "testuser", "16"}, records[1])
+	})
+
+	t.Run("Estimate is cleared with zero", func(t *testing.T) {
+		rr := doRequest(t, router, userID, "PUT", taskPath, `{"estimate_minutes":0}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var updated models.Task
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &updated))
+		assert.Nil(t, updated.EstimateMinutes)
+	})
+}
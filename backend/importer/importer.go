@@ -0,0 +1,572 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"task-flow-backend/export"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FormatTrello   = "trello"
+	FormatJira     = "jira"
+	FormatTaskFlow = "taskflow"
+)
+
+var (
+	ErrUnknownFormat = errors.New("file is not a Trello JSON, Jira CSV or Task Flow JSON export")
+	ErrUserMapping   = errors.New("invalid user mapping")
+)
+
+// Ограничения длины полей в БД
+const (
+	maxTitleLength     = 255
+	maxStatusLength    = 100
+	maxLabelLength     = 100
+	maxChecklistLength = 500
+)
+
+// previewSampleSize - число задач из начала файла в предпросмотре
+const previewSampleSize = 10
+
+// Plan - доска, прочитанная из файла импорта. Пользователи задаются именами
+// источника и сопоставляются с пользователями Task Flow при Apply.
+type Plan struct {
+	Format      string
+	Name        string
+	Description *string
+	Columns     []Column
+	Labels      []models.TemplateLabel
+	Sprints     []Sprint
+	Tasks       []Task
+	// Warnings описывает данные источника, которые не будут импортированы
+	Warnings []string
+}
+
+// Column - колонка плана; архивная колонка архивируется вместе с задачами
+type Column struct {
+	models.TemplateColumn
+	Archived bool
+}
+
+// Sprint - спринт плана; Key - его идентификатор в источнике
+type Sprint struct {
+	Key         string
+	Name        string
+	Goal        string
+	State       string
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	CompletedAt *time.Time
+}
+
+// Task - задача плана. ParentKey и SprintKey ссылаются на Key других задач
+// и спринтов плана, Assignee и авторы комментариев - пользователи источника.
+type Task struct {
+	Key             string
+	ParentKey       string
+	SprintKey       string
+	Title           string
+	Description     string
+	Status          string
+	Priority        *string
+	Assignee        string
+	EstimateMinutes *int
+	StoryPoints     *int
+	DueAt           *time.Time
+	Archived        bool
+	Labels          []string
+	Checklist       []ChecklistItem
+	Comments        []Comment
+}
+
+type ChecklistItem struct {
+	Title   string
+	Checked bool
+}
+
+type Comment struct {
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// Detect определяет формат файла: JSON со схемой taskflow.board - выгрузка
+// Task Flow, JSON со списками и карточками - Trello, остальное - CSV Jira
+func Detect(data []byte) (string, error) {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(data) == 0 {
+		return "", ErrUnknownFormat
+	}
+	if data[0] != '{' {
+		return FormatJira, nil
+	}
+
+	var probe struct {
+		Schema string          `json:"schema"`
+		Lists  json.RawMessage `json:"lists"`
+		Cards  json.RawMessage `json:"cards"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnknownFormat, err)
+	}
+	switch {
+	case probe.Schema == export.Schema:
+		return FormatTaskFlow, nil
+	case probe.Lists != nil && probe.Cards != nil:
+		return FormatTrello, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// Parse читает файл формата format (пустой формат определяется по
+// содержимому) и проверяет план
+func Parse(format string, data []byte) (*Plan, error) {
+	if format == "" {
+		var err error
+		if format, err = Detect(data); err != nil {
+			return nil, err
+		}
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var plan *Plan
+	var err error
+	switch format {
+	case FormatTrello:
+		plan, err = parseTrello(data)
+	case FormatJira:
+		plan, err = parseJira(data)
+	case FormatTaskFlow:
+		plan, err = parseTaskFlow(data)
+	default:
+		return nil, fmt.Errorf("unknown import format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	plan.Format = format
+	if err := plan.normalize(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// normalize обрезает поля до размеров столбцов БД, отбрасывает неизвестные
+// приоритеты и ссылки на отсутствующие задачи и спринты
+func (p *Plan) normalize() error {
+	if len(p.Columns) == 0 {
+		return errors.New("import file has no lists or statuses")
+	}
+	p.Name = truncate(strings.TrimSpace(p.Name), maxTitleLength)
+	if p.Name == "" {
+		p.Name = "Импорт"
+	}
+
+	statuses := make(map[string]bool, len(p.Columns))
+	for i := range p.Columns {
+		column := &p.Columns[i]
+		column.Title = truncate(column.Title, maxTitleLength)
+		statuses[column.StatusID] = true
+	}
+	for i := range p.Labels {
+		p.Labels[i].Name = truncate(p.Labels[i].Name, maxLabelLength)
+	}
+
+	keys := make(map[string]bool, len(p.Tasks))
+	for _, task := range p.Tasks {
+		keys[task.Key] = true
+	}
+	sprints := make(map[string]bool, len(p.Sprints))
+	for i := range p.Sprints {
+		p.Sprints[i].Name = truncate(p.Sprints[i].Name, maxTitleLength)
+		sprints[p.Sprints[i].Key] = true
+	}
+
+	var unknownPriorities, missingParents, labeled int
+	for i := range p.Tasks {
+		task := &p.Tasks[i]
+		if !statuses[task.Status] {
+			return fmt.Errorf("task %q has status %q without a column", task.Title, task.Status)
+		}
+		task.Title = truncate(strings.TrimSpace(task.Title), maxTitleLength)
+		if task.Title == "" {
+			task.Title = "Без названия"
+		}
+		if task.Priority != nil {
+			if priority := normalizePriority(*task.Priority); priority != "" {
+				task.Priority = &priority
+			} else {
+				task.Priority = nil
+				unknownPriorities++
+			}
+		}
+		if task.ParentKey != "" && !keys[task.ParentKey] {
+			task.ParentKey = ""
+			missingParents++
+		}
+		if task.SprintKey != "" && !sprints[task.SprintKey] {
+			task.SprintKey = ""
+		}
+		if len(task.Labels) > 0 {
+			labeled++
+		}
+		for j := range task.Checklist {
+			task.Checklist[j].Title = truncate(task.Checklist[j].Title, maxChecklistLength)
+		}
+	}
+	if unknownPriorities > 0 {
+		p.warnf("%d tasks have a priority other than low, medium or high and are imported without priority", unknownPriorities)
+	}
+	if labeled > 0 {
+		p.warnf("%d tasks have labels: labels are created on the board but are not attached to tasks", labeled)
+	}
+	if missingParents > 0 {
+		p.warnf("%d subtasks reference parents missing from the file and are imported as regular tasks", missingParents)
+	}
+	return nil
+}
+
+func (p *Plan) warnf(format string, args ...any) {
+	p.Warnings = append(p.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Users сопоставляет пользователей источника с пользователями Task Flow;
+// nil - пользователь не сопоставлен
+type Users map[string]*models.User
+
+// SourceUsers возвращает исполнителей и авторов комментариев плана в
+// порядке первого упоминания
+func (p *Plan) SourceUsers() []models.ImportUser {
+	var users []models.ImportUser
+	index := make(map[string]int)
+	add := func(name string) *models.ImportUser {
+		i, ok := index[name]
+		if !ok {
+			i = len(users)
+			index[name] = i
+			users = append(users, models.ImportUser{Source: name})
+		}
+		return &users[i]
+	}
+
+	for _, task := range p.Tasks {
+		if task.Assignee != "" {
+			add(task.Assignee).Tasks++
+		}
+		for _, comment := range task.Comments {
+			if comment.Author != "" {
+				add(comment.Author).Comments++
+			}
+		}
+	}
+	return users
+}
+
+// ResolveUsers сопоставляет пользователей источника: mapping задает имя
+// пользователя Task Flow (пустое - не сопоставлять), остальные сопоставляются
+// с одноименными пользователями. ErrUserMapping, если mapping ссылается на
+// неизвестного пользователя источника или Task Flow.
+func (p *Plan) ResolveUsers(ctx context.Context, repos repository.Repositories, mapping map[string]string) (Users, error) {
+	users := make(Users)
+	for _, source := range p.SourceUsers() {
+		users[source.Source] = nil
+	}
+	for source := range mapping {
+		if _, ok := users[source]; !ok {
+			return nil, fmt.Errorf("%w: %q is not a user of the imported file", ErrUserMapping, source)
+		}
+	}
+
+	for source := range users {
+		username, mapped := mapping[source]
+		if !mapped {
+			username = source
+		}
+		if username == "" {
+			continue
+		}
+		user, err := repos.Users.GetByUsername(ctx, username)
+		if errors.Is(err, repository.ErrNotFound) {
+			if mapped {
+				return nil, fmt.Errorf("%w: unknown user %q", ErrUserMapping, username)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		users[source] = user
+	}
+	return users, nil
+}
+
+// Preview описывает результат импорта плана с сопоставлением users
+func (p *Plan) Preview(users Users) models.ImportPreview {
+	preview := models.ImportPreview{
+		Format:    p.Format,
+		BoardName: p.Name,
+		Columns:   make([]models.TemplateColumn, 0, len(p.Columns)),
+		Labels:    p.Labels,
+		Sprints:   len(p.Sprints),
+		Tasks:     len(p.Tasks),
+		Users:     p.SourceUsers(),
+		Sample:    []models.ImportTaskPreview{},
+		Warnings:  p.Warnings,
+	}
+	if preview.Labels == nil {
+		preview.Labels = []models.TemplateLabel{}
+	}
+	if preview.Users == nil {
+		preview.Users = []models.ImportUser{}
+	}
+	if preview.Warnings == nil {
+		preview.Warnings = []string{}
+	}
+	for _, column := range p.Columns {
+		preview.Columns = append(preview.Columns, column.TemplateColumn)
+	}
+	for i := range preview.Users {
+		if user := users[preview.Users[i].Source]; user != nil {
+			preview.Users[i].Username = &user.Username
+		}
+	}
+
+	for i, task := range p.Tasks {
+		if task.Archived {
+			preview.ArchivedTasks++
+		}
+		preview.ChecklistItems += len(task.Checklist)
+		preview.Comments += len(task.Comments)
+		if i < previewSampleSize {
+			sample := models.ImportTaskPreview{
+				Title:    task.Title,
+				Status:   task.Status,
+				Priority: task.Priority,
+				Labels:   task.Labels,
+				DueAt:    task.DueAt,
+			}
+			if task.Assignee != "" {
+				sample.Assignee = &task.Assignee
+			}
+			preview.Sample = append(preview.Sample, sample)
+		}
+	}
+	return preview
+}
+
+// Apply создает доску board по плану в одной транзакции, поэтому ошибка не
+// оставляет частично импортированной доски. Создатель доски (board.UserID)
+// становится владельцем, сопоставленные пользователи - участниками. progress
+// вызывается после каждой задачи с числом созданных задач.
+func (p *Plan) Apply(ctx context.Context, repos repository.Repositories, board *models.Board, users Users, progress func(imported int)) error {
+	board.Name = p.Name
+	board.Description = p.Description
+
+	return repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		var tpl models.BoardTemplate
+		for _, column := range p.Columns {
+			tpl.Columns = append(tpl.Columns, column.TemplateColumn)
+		}
+		tpl.Labels = p.Labels
+		if err := repos.CreateBoard(ctx, board, tpl); err != nil {
+			return err
+		}
+
+		members := make(map[uuid.UUID]bool)
+		if board.UserID != nil {
+			members[*board.UserID] = true
+		}
+		for _, user := range users {
+			if user == nil || members[user.ID] {
+				continue
+			}
+			members[user.ID] = true
+			member := &models.BoardMember{BoardID: board.ID, UserID: user.ID, Role: models.BoardRoleMember}
+			if err := repos.Members.Add(ctx, member); err != nil {
+				return fmt.Errorf("failed to add member %s: %w", user.Username, err)
+			}
+		}
+
+		sprintIDs := make(map[string]uuid.UUID, len(p.Sprints))
+		for _, s := range p.Sprints {
+			sprint := &models.Sprint{
+				BoardID:     board.ID,
+				Name:        s.Name,
+				Goal:        s.Goal,
+				State:       s.State,
+				StartsAt:    s.StartsAt,
+				EndsAt:      s.EndsAt,
+				CompletedAt: s.CompletedAt,
+				CreatedBy:   board.UserID,
+			}
+			if err := repos.Sprints.Create(ctx, sprint); err != nil {
+				return fmt.Errorf("failed to create sprint %q: %w", s.Name, err)
+			}
+			sprintIDs[s.Key] = sprint.ID
+		}
+
+		taskIDs := make(map[string]uuid.UUID, len(p.Tasks))
+		for i, t := range p.Tasks {
+			id, err := p.createTask(ctx, repos, board, users, sprintIDs, t)
+			if err != nil {
+				return fmt.Errorf("failed to import task %q: %w", t.Title, err)
+			}
+			taskIDs[t.Key] = id
+			if progress != nil {
+				progress(i + 1)
+			}
+		}
+
+		for _, t := range p.Tasks {
+			if t.ParentKey == "" {
+				continue
+			}
+			parentID := taskIDs[t.ParentKey]
+			if err := repos.Tasks.SetParent(ctx, taskIDs[t.Key], &parentID); err != nil {
+				return fmt.Errorf("failed to link subtask %q: %w", t.Title, err)
+			}
+		}
+		for _, t := range p.Tasks {
+			if t.Archived {
+				if err := repos.Tasks.Archive(ctx, taskIDs[t.Key]); err != nil {
+					return fmt.Errorf("failed to archive task %q: %w", t.Title, err)
+				}
+			}
+		}
+		return p.archiveColumns(ctx, repos, board.ID)
+	})
+}
+
+func (p *Plan) createTask(ctx context.Context, repos repository.Repositories, board *models.Board, users Users, sprintIDs map[string]uuid.UUID, t Task) (uuid.UUID, error) {
+	task := &models.Task{
+		BoardID:         board.ID,
+		Title:           t.Title,
+		Description:     t.Description,
+		Status:          t.Status,
+		Priority:        t.Priority,
+		EstimateMinutes: t.EstimateMinutes,
+		StoryPoints:     t.StoryPoints,
+		DueAt:           t.DueAt,
+		CreatedBy:       board.UserID,
+	}
+	if user := users[t.Assignee]; user != nil {
+		task.Assignee = &user.Username
+	}
+	if sprintID, ok := sprintIDs[t.SprintKey]; ok {
+		task.SprintID = &sprintID
+	}
+	if err := repos.Tasks.Create(ctx, task); err != nil {
+		return uuid.Nil, err
+	}
+
+	for _, item := range t.Checklist {
+		if err := repos.Checklists.Create(ctx, &models.ChecklistItem{TaskID: task.ID, Title: item.Title, Checked: item.Checked}); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	for _, c := range t.Comments {
+		comment := &models.TaskComment{TaskID: task.ID, Body: c.Body, CreatedAt: c.CreatedAt.UTC()}
+		if user := users[c.Author]; user != nil {
+			comment.UserID = &user.ID
+		} else {
+			// Комментарий несопоставленного автора пишется от имени импортирующего
+			comment.UserID = board.UserID
+			if c.Author != "" {
+				comment.Body = c.Author + ": " + c.Body
+			}
+		}
+		if err := repos.Comments.Create(ctx, comment); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	return task.ID, nil
+}
+
+// archiveColumns архивирует архивные колонки плана вместе с их задачами
+func (p *Plan) archiveColumns(ctx context.Context, repos repository.Repositories, boardID uuid.UUID) error {
+	archived := make(map[string]bool)
+	for _, column := range p.Columns {
+		if column.Archived {
+			archived[column.StatusID] = true
+		}
+	}
+	if len(archived) == 0 {
+		return nil
+	}
+
+	columns, err := repos.Columns.ListByBoard(ctx, boardID, repository.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if !archived[column.StatusID] {
+			continue
+		}
+		if _, _, err := repos.ArchiveColumn(ctx, column.ID); err != nil {
+			return fmt.Errorf("failed to archive column %q: %w", column.Title, err)
+		}
+	}
+	return nil
+}
+
+// statusIDs выдает уникальные идентификаторы статусов по названиям колонок
+type statusIDs map[string]bool
+
+func (ids statusIDs) next(title string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(strings.TrimSpace(title)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	base := truncate(strings.TrimSuffix(b.String(), "_"), maxStatusLength-4)
+	if base == "" {
+		base = "column"
+	}
+
+	id := base
+	for n := 2; ids[id]; n++ {
+		id = base + "_" + strconv.Itoa(n)
+	}
+	ids[id] = true
+	return id
+}
+
+// normalizePriority приводит приоритет источника к low, medium или high;
+// пустая строка - приоритет неизвестен
+func normalizePriority(priority string) string {
+	switch strings.ToLower(strings.TrimSpace(priority)) {
+	case "highest", "high", "critical", "blocker", "urgent":
+		return "high"
+	case "medium", "normal", "major":
+		return "medium"
+	case "low", "lowest", "minor", "trivial":
+		return "low"
+	}
+	return ""
+}
+
+func truncate(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,238 @@
+package importer
+
+import (
+	"bytes"
+	"context"
+	"task-flow-backend/export"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/repository/memory"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const trelloExport = `{
+	"name": "Roadmap",
+	"desc": "Q3 plans",
+	"lists": [
+		{"id": "l2", "name": "Doing", "closed": false, "pos": 2},
+		{"id": "l1", "name": "To Do", "closed": false, "pos": 1},
+		{"id": "l3", "name": "Old", "closed": true, "pos": 3}
+	],
+	"cards": [
+		{"id": "c1", "name": "Login", "desc": "SSO", "idList": "l1", "pos": 2, "due": "2026-03-01T12:00:00.000Z",
+			"idMembers": ["m1", "m2"], "idLabels": ["lb1"]},
+		{"id": "c2", "name": "Signup", "idList": "l1", "pos": 1, "closed": true},
+		{"id": "c3", "name": "Legacy", "idList": "l3", "pos": 1}
+	],
+	"labels": [{"id": "lb1", "name": "", "color": "red"}],
+	"members": [{"id": "m1", "username": "alice"}, {"id": "m2", "username": "bob"}],
+	"checklists": [{"idCard": "c1", "pos": 1, "checkItems": [
+		{"name": "Callback", "state": "complete", "pos": 2},
+		{"name": "Button", "state": "incomplete", "pos": 1}
+	]}],
+	"actions": [
+		{"type": "commentCard", "date": "2026-02-02T10:00:00.000Z", "data": {"text": "Second", "card": {"id": "c1"}}, "memberCreator": {"username": "bob"}},
+		{"type": "updateCard", "date": "2026-02-01T11:00:00.000Z", "data": {"card": {"id": "c1"}}, "memberCreator": {"username": "alice"}},
+		{"type": "commentCard", "date": "2026-02-01T10:00:00.000Z", "data": {"text": "First", "card": {"id": "c1"}}, "memberCreator": {"username": "alice"}}
+	]
+}`
+
+const jiraExport = "Summary,Issue key,Issue id,Parent id,Issue Type,Status,Status Category,Priority,Assignee,Due date,Labels,Labels,Sprint,Custom field (Story Points),Original Estimate,Comment,Project name\n" +
+	"Checkout,SHOP-1,10001,,Story,Done,Done,Highest,Alice Smith,05/Mar/26 12:00 PM,payments,ui,Sprint 1,5,7200,\"01/Mar/26 9:30 AM;bob;Looks good\",Shop\n" +
+	"Cart,SHOP-2,10002,,Story,In Progress,In Progress,Medium,,,ui,,Sprint 1,2.5,,,Shop\n" +
+	"Tests,SHOP-3,10003,10002,Sub-task,To Do,To Do,Unknown,Alice Smith,someday,,,,,,,Shop\n"
+
+func TestDetect(t *testing.T) {
+	for data, format := range map[string]string{
+		trelloExport: FormatTrello,
+		jiraExport:   FormatJira,
+		`{"schema": "taskflow.board", "version": 1}`: FormatTaskFlow,
+	} {
+		detected, err := Detect([]byte(data))
+		require.NoError(t, err)
+		assert.Equal(t, format, detected)
+	}
+
+	_, err := Detect([]byte(`{"name": "Unknown"}`))
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+	_, err = Detect(nil)
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestParseTrello(t *testing.T) {
+	plan, err := Parse("", []byte(trelloExport))
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatTrello, plan.Format)
+	assert.Equal(t, "Roadmap", plan.Name)
+	require.Len(t, plan.Columns, 3)
+	assert.Equal(t, "to_do", plan.Columns[0].StatusID, "Expected lists ordered by position")
+	assert.Equal(t, "doing", plan.Columns[1].StatusID)
+	assert.True(t, plan.Columns[2].Archived)
+	assert.Equal(t, []models.TemplateLabel{{Name: "red", Color: "#eb5a46"}}, plan.Labels)
+
+	require.Len(t, plan.Tasks, 3)
+	assert.Equal(t, "Signup", plan.Tasks[0].Title, "Expected cards ordered by position")
+	assert.True(t, plan.Tasks[0].Archived)
+	login := plan.Tasks[1]
+	assert.Equal(t, "alice", login.Assignee)
+	assert.Equal(t, []string{"red"}, login.Labels)
+	require.NotNil(t, login.DueAt)
+	assert.Equal(t, []ChecklistItem{{Title: "Button"}, {Title: "Callback", Checked: true}}, login.Checklist)
+	require.Len(t, login.Comments, 2)
+	assert.Equal(t, "First", login.Comments[0].Body, "Expected comments from oldest to newest")
+	assert.Equal(t, "bob", login.Comments[1].Author)
+	assert.Len(t, plan.Warnings, 2, "Expected warnings about extra members and labels")
+}
+
+func TestParseJira(t *testing.T) {
+	plan, err := Parse(FormatJira, []byte(jiraExport))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Shop", plan.Name)
+	require.Len(t, plan.Columns, 3)
+	assert.Equal(t, []string{"To Do", "In Progress", "Done"}, []string{plan.Columns[0].Title, plan.Columns[1].Title, plan.Columns[2].Title})
+	assert.Len(t, plan.Labels, 2)
+	require.Len(t, plan.Sprints, 1)
+	assert.Equal(t, models.SprintStatePlanned, plan.Sprints[0].State)
+
+	require.Len(t, plan.Tasks, 3)
+	checkout := plan.Tasks[0]
+	assert.Equal(t, "done", checkout.Status)
+	require.NotNil(t, checkout.Priority)
+	assert.Equal(t, "high", *checkout.Priority)
+	assert.Equal(t, "Alice Smith", checkout.Assignee)
+	assert.Equal(t, []string{"payments", "ui"}, checkout.Labels)
+	assert.Equal(t, 5, *checkout.StoryPoints)
+	assert.Equal(t, 120, *checkout.EstimateMinutes)
+	assert.Equal(t, "Sprint 1", checkout.SprintKey)
+	require.NotNil(t, checkout.DueAt)
+	require.Len(t, checkout.Comments, 1)
+	assert.Equal(t, Comment{Author: "bob", Body: "Looks good", CreatedAt: checkout.Comments[0].CreatedAt}, checkout.Comments[0])
+	assert.False(t, checkout.Comments[0].CreatedAt.IsZero())
+
+	assert.Equal(t, 3, *plan.Tasks[1].StoryPoints, "Expected fractional points rounded up")
+	subtask := plan.Tasks[2]
+	assert.Equal(t, "10002", subtask.ParentKey)
+	assert.Nil(t, subtask.Priority)
+	assert.Nil(t, subtask.DueAt)
+	assert.Len(t, plan.Warnings, 3)
+
+	_, err = Parse(FormatJira, []byte("Title,State\nA,B\n"))
+	assert.Error(t, err)
+}
+
+func TestApply(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	owner := &models.User{Username: "owner", Email: "owner@example.com"}
+	require.NoError(t, repos.Users.Create(ctx, owner, "password"))
+	alice := &models.User{Username: "alice.smith", Email: "alice@example.com"}
+	require.NoError(t, repos.Users.Create(ctx, alice, "password"))
+
+	plan, err := Parse("", []byte(jiraExport))
+	require.NoError(t, err)
+
+	_, err = plan.ResolveUsers(ctx, repos, map[string]string{"Alice Smith": "nobody"})
+	assert.ErrorIs(t, err, ErrUserMapping)
+	_, err = plan.ResolveUsers(ctx, repos, map[string]string{"Carol": "alice.smith"})
+	assert.ErrorIs(t, err, ErrUserMapping)
+
+	users, err := plan.ResolveUsers(ctx, repos, map[string]string{"Alice Smith": "alice.smith"})
+	require.NoError(t, err)
+	preview := plan.Preview(users)
+	assert.Equal(t, 3, preview.Tasks)
+	assert.Equal(t, 1, preview.Comments)
+	require.Len(t, preview.Users, 2)
+	require.NotNil(t, preview.Users[0].Username)
+	assert.Equal(t, "alice.smith", *preview.Users[0].Username)
+	assert.Nil(t, preview.Users[1].Username, "Expected bob to stay unmapped")
+
+	var progress []int
+	board := &models.Board{UserID: &owner.ID}
+	require.NoError(t, plan.Apply(ctx, repos, board, users, func(imported int) { progress = append(progress, imported) }))
+	assert.Equal(t, []int{1, 2, 3}, progress)
+	assert.Equal(t, "Shop", board.Name)
+
+	members, err := repos.Members.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+	sprints, err := repos.Sprints.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	require.Len(t, sprints, 1)
+
+	tasks, err := repos.Tasks.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	byTitle := make(map[string]models.Task)
+	for _, task := range tasks {
+		byTitle[task.Title] = task
+	}
+	checkout := byTitle["Checkout"]
+	require.NotNil(t, checkout.Assignee)
+	assert.Equal(t, "alice.smith", *checkout.Assignee)
+	assert.Equal(t, sprints[0].ID, *checkout.SprintID)
+	require.NotNil(t, byTitle["Tests"].ParentTaskID)
+	assert.Equal(t, byTitle["Cart"].ID, *byTitle["Tests"].ParentTaskID)
+
+	comments, err := repos.Comments.ListByTask(ctx, checkout.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "bob: Looks good", comments[0].Body, "Expected unmapped author in the comment text")
+	assert.Equal(t, owner.ID, *comments[0].UserID)
+	assert.Equal(t, 2026, comments[0].CreatedAt.Year(), "Expected original comment time")
+}
+
+func TestTaskFlowRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	user := &models.User{Username: "alice", Email: "alice@example.com"}
+	require.NoError(t, repos.Users.Create(ctx, user, "password"))
+
+	plan, err := Parse(FormatTrello, []byte(trelloExport))
+	require.NoError(t, err)
+	users, err := plan.ResolveUsers(ctx, repos, nil)
+	require.NoError(t, err)
+	source := &models.Board{UserID: &user.ID}
+	require.NoError(t, plan.Apply(ctx, repos, source, users, nil))
+
+	loaded, err := export.Load(ctx, repos, source.ID, export.Options{Comments: true})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, loaded.WriteJSON(ctx, &buf))
+
+	copied, err := Parse("", buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, FormatTaskFlow, copied.Format)
+	users, err = copied.ResolveUsers(ctx, repos, nil)
+	require.NoError(t, err)
+	board := &models.Board{UserID: &user.ID}
+	require.NoError(t, copied.Apply(ctx, repos, board, users, nil))
+
+	columns, err := repos.Columns.ListByBoard(ctx, board.ID, repository.ListOptions{IncludeArchived: true})
+	require.NoError(t, err)
+	require.Len(t, columns, 3)
+	assert.NotNil(t, columns[2].ArchivedAt, "Expected archived list to stay archived")
+
+	active, err := repos.Tasks.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	login := active[0]
+	assert.Equal(t, "Login", login.Title)
+	assert.Equal(t, "alice", *login.Assignee)
+	checklist, err := repos.Checklists.ListByTask(ctx, login.ID)
+	require.NoError(t, err)
+	assert.Len(t, checklist, 2)
+	comments, err := repos.Comments.ListByTask(ctx, login.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, user.ID, *comments[0].UserID)
+	assert.Equal(t, "bob: Second", comments[1].Body)
+
+	all, err := repos.Tasks.ListByBoard(ctx, board.ID, repository.ListOptions{IncludeArchived: true})
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+}
@@ -0,0 +1,238 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"task-flow-backend/models"
+	"time"
+)
+
+// jiraTimeLayouts - форматы дат выгрузки Jira: зависят от настроек
+// экземпляра, поэтому перебираются по очереди
+var jiraTimeLayouts = []string{
+	"02/Jan/06 3:04 PM",
+	"02/Jan/06 15:04",
+	"02/Jan/2006 3:04 PM",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05.000-0700",
+	time.RFC3339,
+	"02.01.2006 15:04",
+	"02/Jan/06",
+	"2006-01-02",
+	"02.01.2006",
+}
+
+// jiraCategories упорядочивает колонки по категории статуса Jira
+var jiraCategories = map[string]int{
+	"to do":       0,
+	"in progress": 1,
+	"done":        2,
+}
+
+// jiraRow дает доступ к столбцам строки CSV по заголовку; у Jira повторяются
+// заголовки многозначных полей (Labels, Comment, Sprint)
+type jiraRow struct {
+	header map[string][]int
+	record []string
+}
+
+func (r jiraRow) get(names ...string) string {
+	for _, name := range names {
+		for _, i := range r.header[strings.ToLower(name)] {
+			if i < len(r.record) && strings.TrimSpace(r.record[i]) != "" {
+				return strings.TrimSpace(r.record[i])
+			}
+		}
+	}
+	return ""
+}
+
+func (r jiraRow) all(name string) []string {
+	var values []string
+	for _, i := range r.header[strings.ToLower(name)] {
+		if i < len(r.record) && strings.TrimSpace(r.record[i]) != "" {
+			values = append(values, strings.TrimSpace(r.record[i]))
+		}
+	}
+	return values
+}
+
+// parseJira переносит CSV-выгрузку задач Jira (Filters → Export → CSV (all
+// fields)): статусы становятся колонками в порядке категорий To Do, In
+// Progress, Done, спринты - запланированными спринтами, подзадачи
+// связываются с родителями по Parent id.
+func parseJira(data []byte) (*Plan, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Jira CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("invalid Jira CSV: file is empty")
+	}
+
+	header := make(map[string][]int)
+	for i, name := range records[0] {
+		key := strings.ToLower(strings.TrimSpace(name))
+		header[key] = append(header[key], i)
+	}
+	if _, ok := header["summary"]; !ok {
+		return nil, errors.New("invalid Jira CSV: missing Summary column")
+	}
+	if _, ok := header["status"]; !ok {
+		return nil, errors.New("invalid Jira CSV: missing Status column")
+	}
+
+	plan := &Plan{}
+	type status struct {
+		title    string
+		category int
+		order    int
+	}
+	var statuses []*status
+	byTitle := make(map[string]*status)
+	sprints := make(map[string]bool)
+	labels := make(map[string]bool)
+	var badDates int
+
+	parseTime := func(value string) *time.Time {
+		t := parseJiraTime(value)
+		if t == nil && value != "" {
+			badDates++
+		}
+		return t
+	}
+
+	for _, record := range records[1:] {
+		row := jiraRow{header: header, record: record}
+		title := row.get("Status")
+		if title == "" {
+			title = "To Do"
+		}
+		s, ok := byTitle[title]
+		if !ok {
+			category, known := jiraCategories[strings.ToLower(row.get("Status Category"))]
+			if !known {
+				category = 1
+			}
+			s = &status{title: title, category: category, order: len(statuses)}
+			byTitle[title] = s
+			statuses = append(statuses, s)
+		}
+
+		key := row.get("Issue id", "Issue key")
+		task := Task{
+			Key:         key,
+			ParentKey:   row.get("Parent id", "Parent"),
+			Title:       row.get("Summary"),
+			Description: row.get("Description"),
+			Status:      title,
+			Assignee:    row.get("Assignee"),
+			DueAt:       parseTime(row.get("Due date", "Due Date")),
+			Labels:      row.all("Labels"),
+		}
+		if task.Key == "" {
+			task.Key = "row-" + strconv.Itoa(len(plan.Tasks)+1)
+		}
+		if priority := row.get("Priority"); priority != "" {
+			task.Priority = &priority
+		}
+		if points := row.get("Custom field (Story Points)", "Custom field (Story point estimate)"); points != "" {
+			if value, err := strconv.ParseFloat(points, 64); err == nil && value > 0 {
+				rounded := int(math.Ceil(value))
+				task.StoryPoints = &rounded
+			}
+		}
+		if estimate := row.get("Original Estimate"); estimate != "" {
+			if seconds, err := strconv.Atoi(estimate); err == nil && seconds > 0 {
+				minutes := (seconds + 59) / 60
+				task.EstimateMinutes = &minutes
+			}
+		}
+		if names := row.all("Sprint"); len(names) > 0 {
+			// Задача, переходившая между спринтами, попадает в последний
+			task.SprintKey = names[len(names)-1]
+			if !sprints[task.SprintKey] {
+				sprints[task.SprintKey] = true
+				plan.Sprints = append(plan.Sprints, Sprint{Key: task.SprintKey, Name: task.SprintKey, State: models.SprintStatePlanned})
+			}
+		}
+		for _, label := range task.Labels {
+			if !labels[label] {
+				labels[label] = true
+				plan.Labels = append(plan.Labels, models.TemplateLabel{Name: label, Color: "#6b7280"})
+			}
+		}
+		for _, value := range row.all("Comment") {
+			task.Comments = append(task.Comments, parseJiraComment(value))
+		}
+		plan.Tasks = append(plan.Tasks, task)
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if statuses[i].category != statuses[j].category {
+			return statuses[i].category < statuses[j].category
+		}
+		return statuses[i].order < statuses[j].order
+	})
+	ids := make(statusIDs)
+	statusByTitle := make(map[string]string, len(statuses))
+	for _, s := range statuses {
+		id := ids.next(s.title)
+		statusByTitle[s.title] = id
+		plan.Columns = append(plan.Columns, Column{TemplateColumn: models.TemplateColumn{Title: s.title, StatusID: id}})
+	}
+	for i := range plan.Tasks {
+		plan.Tasks[i].Status = statusByTitle[plan.Tasks[i].Status]
+	}
+
+	if project := firstValue(records, header, "project name"); project != "" {
+		plan.Name = project
+	}
+	if badDates > 0 {
+		plan.warnf("%d dates have an unknown format and are skipped", badDates)
+	}
+	return plan, nil
+}
+
+// parseJiraComment разбирает комментарий выгрузки "дата;автор;текст";
+// значение другого вида целиком становится текстом
+func parseJiraComment(value string) Comment {
+	parts := strings.SplitN(value, ";", 3)
+	if len(parts) == 3 {
+		if createdAt := parseJiraTime(parts[0]); createdAt != nil {
+			return Comment{Author: parts[1], Body: parts[2], CreatedAt: *createdAt}
+		}
+	}
+	return Comment{Body: value}
+}
+
+// parseJiraTime возвращает nil для пустого значения и неизвестного формата
+func parseJiraTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	for _, layout := range jiraTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+func firstValue(records [][]string, header map[string][]int, name string) string {
+	for _, record := range records[1:] {
+		if value := (jiraRow{header: header, record: record}).get(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
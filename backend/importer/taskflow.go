@@ -0,0 +1,87 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"task-flow-backend/export"
+	"task-flow-backend/models"
+)
+
+// parseTaskFlow переносит JSON-выгрузку Task Flow (export.Schema) со всеми
+// полями задач, спринтами, чек-листами и комментариями. Исполнители и авторы
+// комментариев - имена пользователей исходного экземпляра.
+func parseTaskFlow(data []byte) (*Plan, error) {
+	var exported models.BoardExport
+	if err := json.Unmarshal(data, &exported); err != nil {
+		return nil, fmt.Errorf("invalid Task Flow export: %w", err)
+	}
+	if exported.Schema != export.Schema {
+		return nil, fmt.Errorf("invalid Task Flow export: schema %q", exported.Schema)
+	}
+	if exported.Version < 1 || exported.Version > export.SchemaVersion {
+		return nil, fmt.Errorf("unsupported Task Flow export version %d", exported.Version)
+	}
+
+	plan := &Plan{Name: exported.Board.Name, Description: exported.Board.Description}
+	for _, column := range exported.Columns {
+		plan.Columns = append(plan.Columns, Column{
+			TemplateColumn: models.TemplateColumn{Title: column.Title, StatusID: column.StatusID, WIPLimit: column.WIPLimit},
+			Archived:       column.ArchivedAt != nil,
+		})
+	}
+	for _, label := range exported.Labels {
+		plan.Labels = append(plan.Labels, models.TemplateLabel{Name: label.Name, Color: label.Color})
+	}
+
+	active := false
+	for _, sprint := range exported.Sprints {
+		state := sprint.State
+		// На доске может быть только один активный спринт
+		if state == models.SprintStateActive {
+			if active {
+				state = models.SprintStatePlanned
+			}
+			active = true
+		}
+		plan.Sprints = append(plan.Sprints, Sprint{
+			Key:         sprint.ID.String(),
+			Name:        sprint.Name,
+			Goal:        sprint.Goal,
+			State:       state,
+			StartsAt:    sprint.StartsAt,
+			EndsAt:      sprint.EndsAt,
+			CompletedAt: sprint.CompletedAt,
+		})
+	}
+
+	for _, t := range exported.Tasks {
+		task := Task{
+			Key:             t.ID.String(),
+			Title:           t.Title,
+			Description:     t.Description,
+			Status:          t.Status,
+			Priority:        t.Priority,
+			EstimateMinutes: t.EstimateMinutes,
+			StoryPoints:     t.StoryPoints,
+			DueAt:           t.DueAt,
+			Archived:        t.ArchivedAt != nil,
+		}
+		if t.ParentTaskID != nil {
+			task.ParentKey = t.ParentTaskID.String()
+		}
+		if t.SprintID != nil {
+			task.SprintKey = t.SprintID.String()
+		}
+		if t.Assignee != nil {
+			task.Assignee = *t.Assignee
+		}
+		for _, item := range t.Checklist {
+			task.Checklist = append(task.Checklist, ChecklistItem{Title: item.Title, Checked: item.Checked})
+		}
+		for _, comment := range t.Comments {
+			task.Comments = append(task.Comments, Comment{Author: comment.Author, Body: comment.Body, CreatedAt: comment.CreatedAt})
+		}
+		plan.Tasks = append(plan.Tasks, task)
+	}
+	return plan, nil
+}
@@ -0,0 +1,204 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"task-flow-backend/models"
+	"time"
+)
+
+// trelloBoard - поля выгрузки доски Trello (Menu → Print, export and share → JSON),
+// которые переносятся в Task Flow
+type trelloBoard struct {
+	Name string `json:"name"`
+	Desc string `json:"desc"`
+	// Closed отмечает колонки и карточки в архиве Trello
+	Lists []struct {
+		ID     string  `json:"id"`
+		Name   string  `json:"name"`
+		Closed bool    `json:"closed"`
+		Pos    float64 `json:"pos"`
+	} `json:"lists"`
+	Cards []struct {
+		ID        string     `json:"id"`
+		Name      string     `json:"name"`
+		Desc      string     `json:"desc"`
+		IDList    string     `json:"idList"`
+		Closed    bool       `json:"closed"`
+		Pos       float64    `json:"pos"`
+		Due       *time.Time `json:"due"`
+		IDMembers []string   `json:"idMembers"`
+		IDLabels  []string   `json:"idLabels"`
+	} `json:"cards"`
+	Labels []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+	Members []struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"members"`
+	Checklists []struct {
+		IDCard     string  `json:"idCard"`
+		Pos        float64 `json:"pos"`
+		CheckItems []struct {
+			Name  string  `json:"name"`
+			State string  `json:"state"`
+			Pos   float64 `json:"pos"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+	Actions []struct {
+		Type string    `json:"type"`
+		Date time.Time `json:"date"`
+		Data struct {
+			Text string `json:"text"`
+			Card struct {
+				ID string `json:"id"`
+			} `json:"card"`
+		} `json:"data"`
+		MemberCreator struct {
+			Username string `json:"username"`
+		} `json:"memberCreator"`
+	} `json:"actions"`
+}
+
+// trelloColors - цвета меток Trello; остальные оттенки получают серый цвет
+var trelloColors = map[string]string{
+	"green":  "#61bd4f",
+	"yellow": "#f2d600",
+	"orange": "#ff9f1a",
+	"red":    "#eb5a46",
+	"purple": "#c377e0",
+	"blue":   "#0079bf",
+	"sky":    "#00c2e0",
+	"lime":   "#51e898",
+	"pink":   "#ff78cb",
+	"black":  "#344563",
+}
+
+// parseTrello переносит списки в колонки, карточки в задачи, участников
+// карточки - в исполнителя (первый участник), чек-листы и комментарии.
+// Списки и карточки из архива Trello импортируются архивными.
+func parseTrello(data []byte) (*Plan, error) {
+	var board trelloBoard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, fmt.Errorf("invalid Trello export: %w", err)
+	}
+
+	plan := &Plan{Name: board.Name}
+	if board.Desc != "" {
+		plan.Description = &board.Desc
+	}
+
+	lists := board.Lists
+	sort.SliceStable(lists, func(i, j int) bool { return lists[i].Pos < lists[j].Pos })
+	ids := make(statusIDs)
+	statuses := make(map[string]string, len(lists))
+	for _, list := range lists {
+		status := ids.next(list.Name)
+		statuses[list.ID] = status
+		plan.Columns = append(plan.Columns, Column{
+			TemplateColumn: models.TemplateColumn{Title: list.Name, StatusID: status},
+			Archived:       list.Closed,
+		})
+	}
+
+	labels := make(map[string]string, len(board.Labels))
+	for _, label := range board.Labels {
+		name := label.Name
+		if name == "" {
+			name = label.Color
+		}
+		if name == "" {
+			continue
+		}
+		color, ok := trelloColors[label.Color]
+		if !ok {
+			color = "#6b7280"
+		}
+		labels[label.ID] = name
+		plan.Labels = append(plan.Labels, models.TemplateLabel{Name: name, Color: color})
+	}
+
+	members := make(map[string]string, len(board.Members))
+	for _, member := range board.Members {
+		members[member.ID] = member.Username
+	}
+
+	checklists := board.Checklists
+	sort.SliceStable(checklists, func(i, j int) bool { return checklists[i].Pos < checklists[j].Pos })
+	items := make(map[string][]ChecklistItem)
+	for _, checklist := range checklists {
+		checkItems := checklist.CheckItems
+		sort.SliceStable(checkItems, func(i, j int) bool { return checkItems[i].Pos < checkItems[j].Pos })
+		for _, item := range checkItems {
+			items[checklist.IDCard] = append(items[checklist.IDCard], ChecklistItem{Title: item.Name, Checked: item.State == "complete"})
+		}
+	}
+
+	// Действия в выгрузке идут от новых к старым
+	comments := make(map[string][]Comment)
+	for i := len(board.Actions) - 1; i >= 0; i-- {
+		action := board.Actions[i]
+		if action.Type != "commentCard" {
+			continue
+		}
+		cardID := action.Data.Card.ID
+		comments[cardID] = append(comments[cardID], Comment{
+			Author:    action.MemberCreator.Username,
+			Body:      action.Data.Text,
+			CreatedAt: action.Date,
+		})
+	}
+
+	cards := board.Cards
+	listIndex := make(map[string]int, len(lists))
+	for i, list := range lists {
+		listIndex[list.ID] = i
+	}
+	sort.SliceStable(cards, func(i, j int) bool {
+		if listIndex[cards[i].IDList] != listIndex[cards[j].IDList] {
+			return listIndex[cards[i].IDList] < listIndex[cards[j].IDList]
+		}
+		return cards[i].Pos < cards[j].Pos
+	})
+
+	var orphans, extraMembers int
+	for _, card := range cards {
+		status, ok := statuses[card.IDList]
+		if !ok {
+			orphans++
+			continue
+		}
+		task := Task{
+			Key:         card.ID,
+			Title:       card.Name,
+			Description: card.Desc,
+			Status:      status,
+			DueAt:       card.Due,
+			Archived:    card.Closed,
+			Checklist:   items[card.ID],
+			Comments:    comments[card.ID],
+		}
+		if len(card.IDMembers) > 0 {
+			task.Assignee = members[card.IDMembers[0]]
+			extraMembers += len(card.IDMembers) - 1
+		}
+		for _, labelID := range card.IDLabels {
+			if name, ok := labels[labelID]; ok {
+				task.Labels = append(task.Labels, name)
+			}
+		}
+		plan.Tasks = append(plan.Tasks, task)
+	}
+
+	if orphans > 0 {
+		plan.warnf("%d cards reference lists missing from the file and are skipped", orphans)
+	}
+	if extraMembers > 0 {
+		plan.warnf("%d card members besides the first one are skipped: a task has a single assignee", extraMembers)
+	}
+	return plan, nil
+}
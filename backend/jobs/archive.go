@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+	"task-flow-backend/logging"
+	"task-flow-backend/metrics"
+	"task-flow-backend/repository"
+	"time"
+)
+
+const DefaultAutoArchiveInterval = time.Hour
+
+// AutoArchive применяет правила архивации всех досок: задачи в статусе
+// правила, не менявшиеся дольше AfterDays дней, переносятся в архив.
+func AutoArchive(repos repository.Repositories) Func {
+	return func(ctx context.Context) error {
+		rules, err := repos.ArchiveRules.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, rule := range rules {
+			before := now.Add(-time.Duration(rule.AfterDays) * 24 * time.Hour)
+			ids, err := repos.Tasks.ArchiveByStatus(ctx, rule.BoardID, rule.Status, before)
+			if err != nil {
+				return err
+			}
+			if len(ids) > 0 {
+				metrics.TasksAutoArchived.Add(float64(len(ids)))
+				logging.FromContext(ctx).Info("Tasks archived", "board_id", rule.BoardID, "status", rule.Status, "tasks", len(ids))
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"context"
+	"task-flow-backend/logging"
+	"task-flow-backend/metrics"
+	"task-flow-backend/repository"
+	"task-flow-backend/storage"
+	"time"
+)
+
+const DefaultAttachmentCleanupInterval = time.Hour
+
+// CleanupAttachments удаляет из хранилища файлы, на которые не осталось
+// вложений, например после окончательного удаления задач из корзины
+func CleanupAttachments(repos repository.Repositories, store storage.Storage) Func {
+	return func(ctx context.Context) error {
+		blobs, err := repos.Attachments.OrphanBlobs(ctx)
+		if err != nil {
+			return err
+		}
+
+		removed := 0
+		for _, blob := range blobs {
+			// Файл мог снова понадобиться новой загрузке после выборки
+			released, err := repos.ReleaseBlob(ctx, blob.Checksum)
+			if err != nil {
+				return err
+			}
+			if !released {
+				continue
+			}
+			if err := storage.RemoveBlob(ctx, store, blob.Checksum); err != nil {
+				return err
+			}
+			removed++
+		}
+
+		if removed > 0 {
+			metrics.AttachmentBlobsRemoved.Add(float64(removed))
+			logging.FromContext(ctx).Info("Attachment files removed", "files", removed)
+		}
+		return nil
+	}
+}
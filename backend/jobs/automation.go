@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"context"
+	"task-flow-backend/automation"
+	"time"
+)
+
+const (
+	DefaultAutomationDueSoonInterval = 5 * time.Minute
+	DefaultAutomationDelayedInterval = time.Minute
+)
+
+// CheckDueSoon запускает правила автоматизации с триггером due_soon
+func CheckDueSoon(engine *automation.Engine) Func {
+	return func(ctx context.Context) error {
+		return engine.CheckDueSoon(ctx)
+	}
+}
+
+// RunDelayedActions выполняет отложенные действия правил автоматизации
+func RunDelayedActions(engine *automation.Engine) Func {
+	return func(ctx context.Context) error {
+		return engine.RunDelayed(ctx)
+	}
+}
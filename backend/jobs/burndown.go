@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"task-flow-backend/logging"
+	"task-flow-backend/repository"
+	"time"
+)
+
+const DefaultSprintSnapshotInterval = time.Hour
+
+// sprintSnapshotLookback - как долго после завершения спринта задача
+// досохраняет его снимки, если сервер не работал в ночь завершения
+const sprintSnapshotLookback = 7 * 24 * time.Hour
+
+// SnapshotSprints сохраняет снимки burndown за прошедшие дни (UTC) активных
+// и недавно завершенных спринтов. Каждый день снимается один раз, поэтому
+// частый запуск только проверяет, не наступила ли полночь.
+func SnapshotSprints(repos repository.Repositories) Func {
+	return func(ctx context.Context) error {
+		now := time.Now()
+		sprints, err := repos.Sprints.ListInProgress(ctx, now.Add(-sprintSnapshotLookback))
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, sprint := range sprints {
+			n, err := repos.SnapshotSprint(ctx, sprint.ID, now)
+			if err != nil {
+				errs = append(errs, err)
+				logging.FromContext(ctx).Error("Failed to snapshot sprint", "sprint_id", sprint.ID, "error", err)
+				continue
+			}
+			if n > 0 {
+				logging.FromContext(ctx).Info("Sprint snapshots saved", "sprint_id", sprint.ID, "board_id", sprint.BoardID, "days", n)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
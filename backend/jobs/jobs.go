@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"task-flow-backend/logging"
+	"task-flow-backend/metrics"
+	"task-flow-backend/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Func - одна итерация фоновой задачи
+type Func func(ctx context.Context) error
+
+// Run выполняет fn каждые interval, пока не отменен ctx. Ошибка итерации
+// логируется и не останавливает следующие запуски.
+func Run(ctx context.Context, name string, interval time.Duration, fn Func) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			RunOnce(ctx, name, fn)
+		}
+	}
+}
+
+// RunOnce выполняет одну итерацию задачи с логированием, метриками и спаном
+func RunOnce(ctx context.Context, name string, fn Func) error {
+	ctx, span := tracing.Tracer().Start(ctx, "job "+name)
+	defer span.End()
+
+	logger := slog.Default().With("job", name)
+	ctx = logging.NewContext(ctx, logger)
+
+	start := time.Now()
+	err := fn(ctx)
+	metrics.JobDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		metrics.JobRuns.WithLabelValues(name, "error").Inc()
+		span.SetStatus(codes.Error, err.Error())
+		logger.Error("Job failed", "error", err)
+		return err
+	}
+
+	metrics.JobRuns.WithLabelValues(name, "success").Inc()
+	logger.Debug("Job completed", "duration_ms", time.Since(start).Milliseconds())
+	return nil
+}
+
+// DurationFromEnv читает длительность в формате time.ParseDuration
+// (например, 720h); при пустом или некорректном значении возвращает def.
+func DurationFromEnv(key string, def time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		slog.Warn("Invalid duration, using default", "variable", key, "value", value, "default", def.String())
+		return def
+	}
+	return d
+}
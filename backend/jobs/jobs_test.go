@@ -0,0 +1,224 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"task-flow-backend/automation"
+	"task-flow-backend/metrics"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/repository/memory"
+	"task-flow-backend/storage"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunOnceRecordsResult(t *testing.T) {
+	failures := testutil.ToFloat64(metrics.JobRuns.WithLabelValues("test_job", "error"))
+
+	err := RunOnce(context.Background(), "test_job", func(ctx context.Context) error {
+		return errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, failures+1, testutil.ToFloat64(metrics.JobRuns.WithLabelValues("test_job", "error")))
+}
+
+func TestPurgeTrash(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+
+	board := &models.Board{Name: "Old"}
+	require.NoError(t, repos.Boards.Create(ctx, board))
+	require.NoError(t, repos.Boards.Delete(ctx, board.ID))
+
+	require.NoError(t, PurgeTrash(repos.Trash, time.Hour)(ctx))
+	items, err := repos.Trash.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, items, 1, "Expected item within retention to be kept")
+
+	require.NoError(t, PurgeTrash(repos.Trash, -time.Minute)(ctx))
+	items, err = repos.Trash.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestCleanupAttachments(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+	store, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	board := &models.Board{Name: "Bugs"}
+	require.NoError(t, repos.Boards.Create(ctx, board))
+	task := &models.Task{BoardID: board.ID, Title: "Crash", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, task))
+
+	checksum := strings.Repeat("ab", 32)
+	require.NoError(t, store.Put(ctx, storage.BlobKey(checksum), strings.NewReader("log"), "text/plain"))
+	attachment := &models.Attachment{TaskID: task.ID, Filename: "app.log", ContentType: "text/plain", Checksum: checksum}
+	require.NoError(t, repos.AddAttachment(ctx, attachment, &models.AttachmentBlob{Checksum: checksum, Size: 3}))
+
+	require.NoError(t, CleanupAttachments(repos, store)(ctx))
+	_, err = store.Get(ctx, storage.BlobKey(checksum))
+	require.NoError(t, err, "Expected file of existing attachment to be kept")
+
+	require.NoError(t, repos.Tasks.Delete(ctx, task.ID))
+	_, err = repos.Trash.Purge(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	removed := testutil.ToFloat64(metrics.AttachmentBlobsRemoved)
+	require.NoError(t, CleanupAttachments(repos, store)(ctx))
+	_, err = store.Get(ctx, storage.BlobKey(checksum))
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	assert.Equal(t, removed+1, testutil.ToFloat64(metrics.AttachmentBlobsRemoved))
+}
+
+func TestDurationFromEnv(t *testing.T) {
+	t.Setenv("TEST_RETENTION", "48h")
+	assert.Equal(t, 48*time.Hour, DurationFromEnv("TEST_RETENTION", time.Hour))
+
+	t.Setenv("TEST_RETENTION", "two days")
+	assert.Equal(t, time.Hour, DurationFromEnv("TEST_RETENTION", time.Hour))
+}
+
+func TestAutoArchive(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+
+	board := &models.Board{Name: "Rules"}
+	require.NoError(t, repos.Boards.Create(ctx, board))
+	done := &models.Task{BoardID: board.ID, Title: "Done", Status: "done"}
+	require.NoError(t, repos.Tasks.Create(ctx, done))
+	todo := &models.Task{BoardID: board.ID, Title: "Todo", Status: "todo"}
+	require.NoError(t, repos.Tasks.Create(ctx, todo))
+
+	require.NoError(t, repos.ArchiveRules.Replace(ctx, board.ID, []models.ArchiveRule{{BoardID: board.ID, Status: "done", AfterDays: 1}}))
+	require.NoError(t, AutoArchive(repos)(ctx))
+	tasks, err := repos.Tasks.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 2, "Expected recently updated task to stay active")
+
+	require.NoError(t, repos.ArchiveRules.Replace(ctx, board.ID, []models.ArchiveRule{{BoardID: board.ID, Status: "done", AfterDays: 0}}))
+	require.NoError(t, AutoArchive(repos)(ctx))
+	tasks, err = repos.Tasks.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, todo.ID, tasks[0].ID)
+}
+
+// staticElector - Elector с заданным результатом выборов
+type staticElector struct {
+	leader bool
+}
+
+func (e *staticElector) IsLeader(ctx context.Context) (bool, error) {
+	return e.leader, nil
+}
+
+func TestGenerateRecurringTasks(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+
+	board := &models.Board{Name: "Ops"}
+	require.NoError(t, repos.Boards.Create(ctx, board))
+	require.NoError(t, repos.Columns.Create(ctx, &models.Column{BoardID: board.ID, Title: "Todo", StatusID: "todo"}))
+
+	recurring := &models.RecurringTask{
+		BoardID:  board.ID,
+		Title:    "Check backups",
+		Status:   "todo",
+		RRule:    "FREQ=DAILY",
+		StartsAt: time.Now().Add(-time.Hour),
+		Timezone: "UTC",
+	}
+	require.NoError(t, repository.ScheduleRecurringTask(recurring, recurring.StartsAt.Add(-time.Nanosecond)))
+	require.NoError(t, repos.RecurringTasks.Create(ctx, recurring))
+
+	var created []*models.Task
+	generate := GenerateRecurringTasks(repos, func(ctx context.Context, task *models.Task) {
+		created = append(created, task)
+	})
+	elector := &staticElector{}
+
+	require.NoError(t, OnLeader(elector, generate)(ctx))
+	assert.Empty(t, created, "Expected follower to skip the run")
+
+	elector.leader = true
+	require.NoError(t, OnLeader(elector, generate)(ctx))
+	require.Len(t, created, 1)
+	assert.Equal(t, "Check backups", created[0].Title)
+	assert.Equal(t, "todo", created[0].Status)
+
+	// Следующее повторение наступит только завтра
+	require.NoError(t, OnLeader(elector, generate)(ctx))
+	assert.Len(t, created, 1)
+
+	tasks, err := repos.Tasks.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+}
+
+func TestCheckDueSoon(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+
+	board := &models.Board{Name: "Ops"}
+	require.NoError(t, repos.Boards.Create(ctx, board))
+	require.NoError(t, repos.Columns.Create(ctx, &models.Column{BoardID: board.ID, Title: "Todo", StatusID: "todo"}))
+
+	dueAt := time.Now().Add(2 * time.Hour)
+	task := &models.Task{BoardID: board.ID, Title: "Renew certificate", Status: "todo", DueAt: &dueAt}
+	require.NoError(t, repos.Tasks.Create(ctx, task))
+	require.NoError(t, repos.AutomationRules.Create(ctx, &models.AutomationRule{
+		BoardID: board.ID,
+		Name:    "Remind",
+		Enabled: true,
+		Trigger: models.AutomationTrigger{Type: models.AutomationTriggerDueSoon, WithinHours: 24},
+		Actions: []models.AutomationAction{{Type: models.AutomationActionComment, Value: "Due soon"}},
+	}))
+
+	check := CheckDueSoon(automation.NewEngine(repos))
+	require.NoError(t, check(ctx))
+	// Для того же срока правило повторно не выполняется
+	require.NoError(t, check(ctx))
+
+	comments, err := repos.Comments.ListByTask(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "Due soon", comments[0].Body)
+}
+
+func TestSnapshotSprints(t *testing.T) {
+	ctx := context.Background()
+	repos := memory.NewRepositories()
+
+	board := &models.Board{Name: "Team"}
+	require.NoError(t, repos.Boards.Create(ctx, board))
+	require.NoError(t, repos.Columns.Create(ctx, &models.Column{BoardID: board.ID, Title: "Todo", StatusID: "todo"}))
+
+	now := time.Now()
+	startsAt, endsAt := now.Add(-48*time.Hour), now.Add(12*24*time.Hour)
+	active := &models.Sprint{BoardID: board.ID, Name: "Active", State: models.SprintStateActive, StartsAt: &startsAt, EndsAt: &endsAt}
+	require.NoError(t, repos.Sprints.Create(ctx, active))
+	oldStart, oldEnd := now.AddDate(0, -2, 0), now.AddDate(0, -1, 0)
+	old := &models.Sprint{BoardID: board.ID, Name: "Old", State: models.SprintStateCompleted, StartsAt: &oldStart, EndsAt: &oldEnd, CompletedAt: &oldEnd}
+	require.NoError(t, repos.Sprints.Create(ctx, old))
+
+	require.NoError(t, SnapshotSprints(repos)(ctx))
+	snapshots, err := repos.Sprints.ListSnapshots(ctx, active.ID)
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 2, "Expected one snapshot per finished day")
+	snapshots, err = repos.Sprints.ListSnapshots(ctx, old.ID)
+	require.NoError(t, err)
+	assert.Empty(t, snapshots, "Expected sprints completed long ago to be skipped")
+
+	require.NoError(t, SnapshotSprints(repos)(ctx))
+	snapshots, err = repos.Sprints.ListSnapshots(ctx, active.ID)
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+}
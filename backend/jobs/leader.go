@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"task-flow-backend/logging"
+)
+
+// Elector решает, какая из реплик выполняет задачу, которая должна идти
+// в одном экземпляре (например, database.LeaderLock)
+type Elector interface {
+	IsLeader(ctx context.Context) (bool, error)
+}
+
+// OnLeader оборачивает fn так, что итерация выполняется только на
+// реплике-лидере; остальные реплики ее пропускают
+func OnLeader(elector Elector, fn Func) Func {
+	var mu sync.Mutex
+	leading := false
+
+	return func(ctx context.Context) error {
+		leader, err := elector.IsLeader(ctx)
+		if err != nil {
+			return fmt.Errorf("leader election: %w", err)
+		}
+
+		mu.Lock()
+		if leader != leading {
+			leading = leader
+			if leader {
+				logging.FromContext(ctx).Info("Acquired job leadership")
+			} else {
+				logging.FromContext(ctx).Info("Lost job leadership")
+			}
+		}
+		mu.Unlock()
+
+		if !leader {
+			return nil
+		}
+		return fn(ctx)
+	}
+}
@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"task-flow-backend/logging"
+	"task-flow-backend/metrics"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+)
+
+const DefaultRecurringTasksInterval = time.Minute
+
+// GenerateRecurringTasks создает задачи по шаблонам, повторение которых
+// наступило, и передает каждую новую задачу в created (рассылка событий,
+// сброс кэша). Ошибка одного шаблона не мешает обработать остальные.
+func GenerateRecurringTasks(repos repository.Repositories, created func(ctx context.Context, task *models.Task)) Func {
+	return func(ctx context.Context) error {
+		now := time.Now()
+		due, err := repos.RecurringTasks.ListDue(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, recurring := range due {
+			task, err := repos.RunRecurringTask(ctx, recurring.ID, now)
+			if err != nil {
+				errs = append(errs, err)
+				logging.FromContext(ctx).Error("Failed to create recurring task", "recurring_task_id", recurring.ID, "error", err)
+				continue
+			}
+			if task == nil {
+				continue
+			}
+
+			metrics.RecurringTasksCreated.Inc()
+			logging.FromContext(ctx).Info("Recurring task created", "recurring_task_id", recurring.ID, "task_id", task.ID, "board_id", task.BoardID)
+			if created != nil {
+				created(ctx, task)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"context"
+	"task-flow-backend/logging"
+	"task-flow-backend/metrics"
+	"task-flow-backend/repository"
+	"time"
+)
+
+const (
+	DefaultTrashRetention     = 30 * 24 * time.Hour
+	DefaultTrashPurgeInterval = time.Hour
+)
+
+// PurgeTrash окончательно удаляет элементы, пролежавшие в корзине дольше retention
+func PurgeTrash(trash repository.TrashRepository, retention time.Duration) Func {
+	return func(ctx context.Context) error {
+		n, err := trash.Purge(ctx, time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			metrics.TrashPurged.Add(float64(n))
+			logging.FromContext(ctx).Info("Trash purged", "items", n, "retention", retention.String())
+		}
+		return nil
+	}
+}
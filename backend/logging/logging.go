@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys - подстроки имен атрибутов, значения которых не попадают в логи
+var sensitiveKeys = []string{"password", "secret", "token", "authorization", "cookie", "api_key"}
+
+// Setup настраивает глобальный логгер по переменным окружения LOG_LEVEL
+// (debug, info, warn, error) и LOG_FORMAT (json, text).
+func Setup() *slog.Logger {
+	logger := New(os.Stdout, ParseLevel(os.Getenv("LOG_LEVEL")), os.Getenv("LOG_FORMAT"))
+	slog.SetDefault(logger)
+	return logger
+}
+
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(format, "text") {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(handler)
+}
+
+func ParseLevel(s string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+func redact(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return slog.String(a.Key, redacted)
+		}
+	}
+	if a.Value.Kind() == slog.KindString && strings.HasPrefix(a.Value.String(), "Bearer ") {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+type ctxKey struct{}
+
+// holder позволяет внутренним middleware (например, авторизации) дополнять
+// логгер запроса так, чтобы итоговая строка access-лога тоже видела эти поля.
+type holder struct {
+	mu     sync.RWMutex
+	logger *slog.Logger
+}
+
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &holder{logger: logger})
+}
+
+// FromContext возвращает логгер запроса или глобальный логгер
+func FromContext(ctx context.Context) *slog.Logger {
+	if h, ok := ctx.Value(ctxKey{}).(*holder); ok {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+		return h.logger
+	}
+	return slog.Default()
+}
+
+// With добавляет поля к логгеру, сохраненному в контексте
+func With(ctx context.Context, args ...any) {
+	if h, ok := ctx.Value(ctxKey{}).(*holder); ok {
+		h.mu.Lock()
+		h.logger = h.logger.With(args...)
+		h.mu.Unlock()
+	}
+}
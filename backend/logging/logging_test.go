@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, slog.LevelInfo, "json")
+
+	logger.Info("login attempt",
+		"username", "admin",
+		"password", "admin123",
+		"jwt_secret", "secret-key",
+		"header", "Bearer abc.def.ghi",
+		slog.Group("user", slog.String("password_hash", "$2a$10$xyz")),
+	)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "admin", entry["username"])
+	assert.Equal(t, redacted, entry["password"])
+	assert.Equal(t, redacted, entry["jwt_secret"])
+	assert.Equal(t, redacted, entry["header"])
+	assert.Equal(t, redacted, entry["user"].(map[string]any)["password_hash"])
+}
+
+func TestParseLevel(t *testing.T) {
+	assert.Equal(t, slog.LevelDebug, ParseLevel("debug"))
+	assert.Equal(t, slog.LevelWarn, ParseLevel("WARN"))
+	assert.Equal(t, slog.LevelInfo, ParseLevel(""))
+	assert.Equal(t, slog.LevelInfo, ParseLevel("verbose"))
+}
+
+func TestContextLogger(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := NewContext(context.Background(), New(&buf, slog.LevelInfo, "json").With("request_id", "req-1"))
+
+	With(ctx, "user_id", "user-1")
+	FromContext(ctx).Info("done")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "user-1", entry["user_id"])
+
+	assert.Equal(t, slog.Default(), FromContext(context.Background()))
+}
@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// File сохраняет каждое письмо в отдельный .eml файл каталога dir; такие
+// файлы открываются почтовыми клиентами. Только для разработки.
+type File struct {
+	dir  string
+	from string
+}
+
+func NewFile(dir, from string) (*File, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &File{dir: dir, from: from}, nil
+}
+
+func (f *File) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	now := time.Now()
+	data, err := compose(f.from, msg, now)
+	if err != nil {
+		return err
+	}
+	name := filepath.Join(f.dir, now.UTC().Format("20060102-150405")+"-"+randomID()+".eml")
+	return os.WriteFile(name, data, 0o600)
+}
+
+// Log пишет письма в лог вместо отправки. Только для разработки: текст
+// письма со ссылками подтверждения попадает в лог целиком.
+type Log struct {
+	logger *slog.Logger
+}
+
+// NewLog создает Log поверх logger; nil - логгер по умолчанию
+func NewLog(logger *slog.Logger) *Log {
+	return &Log{logger: logger}
+}
+
+func (l *Log) Send(ctx context.Context, msg Message) error {
+	logger := l.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.InfoContext(ctx, "Email not sent (MAIL_BACKEND=log)", "to", msg.To, "subject", msg.Subject, "text", msg.Text)
+	return nil
+}
@@ -0,0 +1,164 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"net"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpStandIn - минимальный SMTP-сервер без TLS и авторизации, принимающий одно письмо
+type smtpStandIn struct {
+	addr       string
+	from       string
+	recipients []string
+	data       chan string
+}
+
+func startSMTPStandIn(t *testing.T) *smtpStandIn {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	server := &smtpStandIn{addr: ln.Addr().String(), data: make(chan string, 1)}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		server.serve(conn)
+	}()
+	return server
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	reply("220 stand-in ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 stand-in")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.from = strings.Trim(strings.TrimSpace(line)[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.recipients = append(s.recipients, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 end with <CRLF>.<CRLF>")
+			var body strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				body.WriteString(strings.TrimPrefix(line, "."))
+			}
+			s.data <- body.String()
+			reply("250 queued")
+		case command == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTP(t *testing.T) {
+	server := startSMTPStandIn(t)
+	host, port, err := net.SplitHostPort(server.addr)
+	require.NoError(t, err)
+	portNumber, err := net.LookupPort("tcp", port)
+	require.NoError(t, err)
+
+	sender, err := NewSMTP(SMTPConfig{Host: host, Port: portNumber, From: "Task Flow <no-reply@example.com>"})
+	require.NoError(t, err)
+
+	msg, err := Render(TemplateResetPassword, "ru", Data{Username: "alice", Link: "https://tf.example.com/reset-password?token=abc", Hours: 1})
+	require.NoError(t, err)
+	msg.To = "Alice <alice@example.com>"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, sender.Send(ctx, msg))
+
+	var data string
+	select {
+	case data = <-server.data:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the stand-in to receive the message")
+	}
+	assert.Equal(t, "no-reply@example.com", server.from)
+	assert.Equal(t, []string{"alice@example.com"}, server.recipients)
+
+	parsed, err := netmail.ReadMessage(strings.NewReader(data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Сброс пароля в Task Flow", subject)
+	assert.Contains(t, parsed.Header.Get("Content-Type"), "multipart/alternative")
+}
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := NewFile(dir, "Task Flow <no-reply@localhost>")
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Send(context.Background(), Message{To: "bob@example.com", Subject: "Hi", Text: "Plain body\n"}))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, ".eml", filepath.Ext(files[0].Name()))
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	parsed, err := netmail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", parsed.Header.Get("To"))
+	body, err := io.ReadAll(parsed.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "Plain body\n", strings.ReplaceAll(string(body), "\r\n", "\n"))
+}
+
+func TestRender(t *testing.T) {
+	data := Data{Username: "<b>eve</b>", Link: "https://tf.example.com/verify-email?token=a&b", Hours: 48}
+
+	msg, err := Render(TemplateVerifyEmail, "en", data)
+	require.NoError(t, err)
+	assert.Equal(t, "Confirm your email address for Task Flow", msg.Subject)
+	assert.Contains(t, msg.Text, "Hello <b>eve</b>,")
+	assert.Contains(t, msg.Text, "48 hours")
+	assert.Contains(t, msg.Text, data.Link)
+	assert.Contains(t, msg.HTML, "&lt;b&gt;eve&lt;/b&gt;", "Expected HTML to escape user data")
+	assert.Contains(t, msg.HTML, `href="https://tf.example.com/verify-email?token=a&amp;b"`)
+
+	msg, err = Render(TemplateVerifyEmail, "de", data)
+	require.NoError(t, err)
+	assert.Contains(t, msg.Subject, "Подтвердите", "Expected unknown locales to fall back to Russian")
+
+	_, err = Render("welcome", "en", data)
+	assert.Error(t, err)
+}
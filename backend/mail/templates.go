@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Шаблоны писем: templates/<name>.<locale>.tmpl с блоками subject, text и html
+const (
+	TemplateVerifyEmail   = "verify_email"
+	TemplateResetPassword = "reset_password"
+)
+
+// DefaultLocale используется, если шаблона на запрошенном языке нет
+const DefaultLocale = "ru"
+
+// Data - поля шаблонов писем
+type Data struct {
+	Username string
+	// Link - ссылка с одноразовым токеном
+	Link string
+	// Hours - срок действия ссылки в часах
+	Hours int
+}
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+type template struct {
+	text *texttemplate.Template
+	// html разбирается html/template, чтобы данные экранировались
+	html *htmltemplate.Template
+}
+
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]template {
+	files, err := templateFS.ReadDir("templates")
+	if err != nil {
+		panic(err)
+	}
+	parsed := make(map[string]template, len(files))
+	for _, file := range files {
+		name := path.Join("templates", file.Name())
+		parsed[strings.TrimSuffix(file.Name(), ".tmpl")] = template{
+			text: texttemplate.Must(texttemplate.ParseFS(templateFS, name)),
+			html: htmltemplate.Must(htmltemplate.ParseFS(templateFS, name)),
+		}
+	}
+	return parsed
+}
+
+// Render заполняет шаблон name на языке locale (или DefaultLocale) и
+// возвращает письмо без получателя
+func Render(name, locale string, data Data) (Message, error) {
+	tpl, ok := templates[name+"."+locale]
+	if !ok {
+		if tpl, ok = templates[name+"."+DefaultLocale]; !ok {
+			return Message{}, fmt.Errorf("unknown mail template %q", name)
+		}
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := tpl.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return Message{}, err
+	}
+	if err := tpl.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    strings.TrimSpace(html.String()) + "\n",
+	}, nil
+}
@@ -1,13 +1,22 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
 	"net/http"
 	"os"
+	"strconv"
+	"task-flow-backend/automation"
 	"task-flow-backend/cache"
 	"task-flow-backend/database"
 	"task-flow-backend/handlers"
+	"task-flow-backend/jobs"
+	"task-flow-backend/logging"
+	"task-flow-backend/mail"
+	"task-flow-backend/metrics"
+	"task-flow-backend/ratelimit"
+	"task-flow-backend/repository/postgres"
+	"task-flow-backend/storage"
+	"task-flow-backend/tracing"
 	"task-flow-backend/websocket"
 
 	"github.com/gorilla/mux"
@@ -15,55 +24,125 @@ import (
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: Error loading .env file: %v. Using system environment variables.", err)
+	envErr := godotenv.Load()
+
+	logger := logging.Setup()
+	if envErr != nil {
+		logger.Warn("Error loading .env file, using system environment variables", "error", envErr)
+	}
+
+	shutdownTracing, err := tracing.Init(context.Background(), "task-flow-backend")
+	if err != nil {
+		logger.Warn("Failed to initialize tracing", "error", err)
+	} else {
+		defer shutdownTracing(context.Background())
 	}
 
 	if err := database.Init(); err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		logger.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
 	}
 	defer database.DB.Close()
 
+	if err := metrics.RegisterDB(database.DB, "taskflow"); err != nil {
+		logger.Warn("Failed to register database metrics", "error", err)
+	}
+
+	wsHub := websocket.NewHub()
+	go wsHub.Run()
+
+	opts := []handlers.Option{handlers.WithHub(wsHub)}
+
+	// Без Redis лимиты запросов считаются отдельно на каждой реплике
+	var limiter ratelimit.Store = ratelimit.NewMemory()
 	if err := cache.Init(); err != nil {
-		log.Printf("Warning: Failed to connect to Redis: %v. Continuing without cache.", err)
+		logger.Warn("Failed to connect to Redis, continuing without cache", "error", err)
 	} else {
-		log.Println("Redis cache initialized successfully")
+		logger.Info("Redis cache initialized successfully")
 		defer cache.Client.Close()
+		opts = append(opts, handlers.WithCache(cache.NewRedisStore(cache.Client)))
+		limiter = ratelimit.NewRedis(cache.Client)
 	}
+	opts = append(opts, handlers.WithRateLimits(limiter, handlers.RateLimitsFromEnv()))
 
-	r := mux.NewRouter()
+	opts = append(opts, handlers.WithAttachmentLimits(handlers.AttachmentLimitsFromEnv()))
+	store, err := storage.FromEnv()
+	if err != nil {
+		logger.Warn("Failed to initialize attachment storage, attachments are disabled", "error", err)
+	} else {
+		opts = append(opts, handlers.WithStorage(store))
+	}
 
-	r.Use(handlers.CORSMiddleware)
+	sender, err := mail.FromEnv()
+	if err != nil {
+		logger.Warn("Failed to initialize mail sender, emails are disabled", "error", err)
+	} else {
+		opts = append(opts, handlers.WithMail(sender, os.Getenv("APP_URL")))
+	}
+	// Пока фронтенд не умеет подтверждать адрес, вход без подтверждения разрешен
+	verificationRequired, _ := strconv.ParseBool(os.Getenv("EMAIL_VERIFICATION_REQUIRED"))
+	opts = append(opts, handlers.WithEmailVerification(verificationRequired))
+
+	repos := postgres.NewRepositories(database.DB)
+	engine := automation.NewEngine(repos)
+	opts = append(opts, handlers.WithAutomation(engine))
+	server := handlers.NewServer(repos, opts...)
+	engine.SetNotifier(server)
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go engine.Run(jobsCtx)
+
+	// Периодические задачи выполняет только реплика, удерживающая advisory-блокировку
+	// задачи, иначе работа и события автоматизации повторялись бы на каждой реплике
+	purgeLeader := database.NewLeaderLock(database.DB, "trash_purge")
+	defer purgeLeader.Release(context.Background())
+	retention := jobs.DurationFromEnv("TRASH_RETENTION", jobs.DefaultTrashRetention)
+	purgeInterval := jobs.DurationFromEnv("TRASH_PURGE_INTERVAL", jobs.DefaultTrashPurgeInterval)
+	go jobs.Run(jobsCtx, "trash_purge", purgeInterval, jobs.OnLeader(purgeLeader, jobs.PurgeTrash(repos.Trash, retention)))
+
+	archiveLeader := database.NewLeaderLock(database.DB, "auto_archive")
+	defer archiveLeader.Release(context.Background())
+	archiveInterval := jobs.DurationFromEnv("AUTO_ARCHIVE_INTERVAL", jobs.DefaultAutoArchiveInterval)
+	go jobs.Run(jobsCtx, "auto_archive", archiveInterval, jobs.OnLeader(archiveLeader, jobs.AutoArchive(repos)))
+
+	if store != nil {
+		cleanupLeader := database.NewLeaderLock(database.DB, "attachment_cleanup")
+		defer cleanupLeader.Release(context.Background())
+		cleanupInterval := jobs.DurationFromEnv("ATTACHMENT_CLEANUP_INTERVAL", jobs.DefaultAttachmentCleanupInterval)
+		go jobs.Run(jobsCtx, "attachment_cleanup", cleanupInterval, jobs.OnLeader(cleanupLeader, jobs.CleanupAttachments(repos, store)))
+	}
 
-	wsHub := websocket.NewHub()
-	go wsHub.Run()
+	recurringLeader := database.NewLeaderLock(database.DB, "recurring_tasks")
+	defer recurringLeader.Release(context.Background())
+	recurringInterval := jobs.DurationFromEnv("RECURRING_TASKS_INTERVAL", jobs.DefaultRecurringTasksInterval)
+	go jobs.Run(jobsCtx, "recurring_tasks", recurringInterval, jobs.OnLeader(recurringLeader, jobs.GenerateRecurringTasks(repos, server.TaskCreated)))
 
-	handlers.SetWebSocketHub(wsHub)
+	dueSoonLeader := database.NewLeaderLock(database.DB, "automation_due_soon")
+	defer dueSoonLeader.Release(context.Background())
+	dueSoonInterval := jobs.DurationFromEnv("AUTOMATION_DUE_SOON_INTERVAL", jobs.DefaultAutomationDueSoonInterval)
+	go jobs.Run(jobsCtx, "automation_due_soon", dueSoonInterval, jobs.OnLeader(dueSoonLeader, jobs.CheckDueSoon(engine)))
 
-	r.HandleFunc("/api/auth/login", handlers.Login).Methods("POST", "OPTIONS")
-	r.HandleFunc("/api/auth/register", handlers.Register).Methods("POST", "OPTIONS")
+	delayedLeader := database.NewLeaderLock(database.DB, "automation_delayed")
+	defer delayedLeader.Release(context.Background())
+	delayedInterval := jobs.DurationFromEnv("AUTOMATION_DELAYED_INTERVAL", jobs.DefaultAutomationDelayedInterval)
+	go jobs.Run(jobsCtx, "automation_delayed", delayedInterval, jobs.OnLeader(delayedLeader, jobs.RunDelayedActions(engine)))
 
-	api := r.PathPrefix("/api").Subrouter()
-	api.Use(handlers.AuthMiddleware)
+	snapshotLeader := database.NewLeaderLock(database.DB, "sprint_snapshots")
+	defer snapshotLeader.Release(context.Background())
+	snapshotInterval := jobs.DurationFromEnv("SPRINT_SNAPSHOT_INTERVAL", jobs.DefaultSprintSnapshotInterval)
+	go jobs.Run(jobsCtx, "sprint_snapshots", snapshotInterval, jobs.OnLeader(snapshotLeader, jobs.SnapshotSprints(repos)))
 
-	api.HandleFunc("/auth/me", handlers.GetCurrentUser).Methods("GET", "OPTIONS")
+	r := mux.NewRouter()
 
-	r.HandleFunc("/api/boards", handlers.GetBoards).Methods("GET", "OPTIONS")
-	api.HandleFunc("/boards", handlers.CreateBoard).Methods("POST", "OPTIONS")
-	r.HandleFunc("/api/boards/{id}", handlers.GetBoard).Methods("GET", "OPTIONS")
-	api.HandleFunc("/boards/{id}", handlers.UpdateBoard).Methods("PUT", "OPTIONS")
-	api.HandleFunc("/boards/{id}", handlers.DeleteBoard).Methods("DELETE", "OPTIONS")
+	r.Use(handlers.MetricsMiddleware)
+	r.Use(handlers.TracingMiddleware)
+	r.Use(handlers.RequestLoggingMiddleware)
+	r.Use(handlers.CORSMiddleware)
 
-	r.HandleFunc("/api/tasks", handlers.GetTasks).Methods("GET", "OPTIONS")
-	api.HandleFunc("/tasks", handlers.CreateTask).Methods("POST", "OPTIONS")
-	r.HandleFunc("/api/tasks/{id}", handlers.GetTask).Methods("GET", "OPTIONS")
-	api.HandleFunc("/tasks/{id}", handlers.UpdateTask).Methods("PUT", "OPTIONS")
-	api.HandleFunc("/tasks/{id}", handlers.DeleteTask).Methods("DELETE", "OPTIONS")
-	api.HandleFunc("/tasks/{id}/move", handlers.MoveTask).Methods("PATCH", "OPTIONS")
+	r.Handle("/metrics", metrics.Handler()).Methods("GET")
 
-	r.HandleFunc("/api/columns", handlers.GetColumns).Methods("GET", "OPTIONS")
-	api.HandleFunc("/columns", handlers.CreateColumn).Methods("POST", "OPTIONS")
-	api.HandleFunc("/columns/{id}", handlers.DeleteColumn).Methods("DELETE", "OPTIONS")
+	server.Routes(r)
 
 	r.HandleFunc("/ws/board/{board_id}", func(w http.ResponseWriter, r *http.Request) {
 		websocket.ServeWS(wsHub, w, r)
@@ -74,6 +153,9 @@ func main() {
 		port = "8080"
 	}
 
-	fmt.Printf("Server starting on port %s\n", port)
-	log.Fatal(http.ListenAndServe(":"+port, r))
+	logger.Info("Server starting", "port", port)
+	if err := http.ListenAndServe(":"+port, r); err != nil {
+		logger.Error("Server stopped", "error", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,185 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "taskflow"
+
+// Registry содержит все метрики приложения, отдаваемые на /metrics
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	HTTPRequestsTotal = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of HTTP requests by route template, method and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route template, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	CacheHits = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "hits_total",
+		Help:      "Number of cache lookups that returned a value.",
+	}, []string{"key"})
+
+	CacheMisses = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "misses_total",
+		Help:      "Number of cache lookups that found nothing.",
+	}, []string{"key"})
+
+	CacheErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "errors_total",
+		Help:      "Number of failed cache operations.",
+	}, []string{"operation"})
+
+	WebSocketClients = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "websocket",
+		Name:      "clients",
+		Help:      "Number of connected WebSocket clients per board.",
+	}, []string{"board_id"})
+
+	WebSocketBroadcastQueueDepth = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "websocket",
+		Name:      "broadcast_queue_depth",
+		Help:      "Number of messages waiting in the hub broadcast queue.",
+	})
+
+	WebSocketMessagesDropped = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "websocket",
+		Name:      "messages_dropped_total",
+		Help:      "Number of WebSocket messages dropped because a queue was full.",
+	}, []string{"reason"})
+
+	BoardsCreated = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "boards_created_total",
+		Help:      "Number of boards created.",
+	})
+
+	TasksCreated = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_created_total",
+		Help:      "Number of tasks created.",
+	})
+
+	TasksMoved = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_moved_total",
+		Help:      "Number of tasks moved between columns.",
+	})
+
+	TasksDeleted = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_deleted_total",
+		Help:      "Number of tasks deleted.",
+	})
+
+	JobRuns = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "jobs",
+		Name:      "runs_total",
+		Help:      "Number of background job runs by job and result.",
+	}, []string{"job", "result"})
+
+	JobDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "jobs",
+		Name:      "duration_seconds",
+		Help:      "Background job run duration.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"job"})
+
+	TrashPurged = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "trash",
+		Name:      "purged_total",
+		Help:      "Number of soft-deleted items permanently removed by the purge job.",
+	})
+
+	TasksAutoArchived = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_auto_archived_total",
+		Help:      "Number of tasks archived by board archive rules.",
+	})
+
+	AttachmentBlobsRemoved = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "attachments",
+		Name:      "blobs_removed_total",
+		Help:      "Number of unreferenced attachment files removed from storage by the cleanup job.",
+	})
+
+	RecurringTasksCreated = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recurring_tasks_created_total",
+		Help:      "Number of tasks created from recurring task templates.",
+	})
+
+	AutomationRuns = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "automation",
+		Name:      "runs_total",
+		Help:      "Number of automation rule runs by status.",
+	}, []string{"status"})
+
+	AutomationEventsDropped = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "automation",
+		Name:      "events_dropped_total",
+		Help:      "Number of task events dropped because the automation queue was full.",
+	})
+
+	RateLimitRejections = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ratelimit",
+		Name:      "rejections_total",
+		Help:      "Number of requests rejected with 429 by route template and reason (limit or lockout).",
+	}, []string{"route", "reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterDB экспортирует статистику пула соединений database/sql
+func RegisterDB(db *sql.DB, dbName string) error {
+	err := Registry.Register(collectors.NewDBStatsCollector(db, dbName))
+	var already prometheus.AlreadyRegisteredError
+	if errors.As(err, &already) {
+		return nil
+	}
+	return err
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
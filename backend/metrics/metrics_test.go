@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	TasksCreated.Inc()
+
+	rr := httptest.NewRecorder()
+	req, err := http.NewRequest("GET", "/metrics", nil)
+	require.NoError(t, err)
+	Handler().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	body := rr.Body.String()
+	assert.True(t, strings.Contains(body, "taskflow_tasks_created_total"), "Expected business counter in output")
+	assert.True(t, strings.Contains(body, "go_goroutines"), "Expected runtime metrics in output")
+}
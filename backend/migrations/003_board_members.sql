@@ -0,0 +1,15 @@
+-- Участники досок
+CREATE TABLE IF NOT EXISTS board_members (
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'member')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (board_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_board_members_user_id ON board_members(user_id);
+
+-- Создатели существующих досок становятся их владельцами
+INSERT INTO board_members (board_id, user_id, role)
+SELECT id, user_id, 'owner' FROM boards WHERE user_id IS NOT NULL
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,24 @@
+-- Ограничение количества задач в колонке (WIP)
+ALTER TABLE columns ADD COLUMN IF NOT EXISTS wip_limit INTEGER CHECK (wip_limit > 0);
+
+-- Метки досок
+CREATE TABLE IF NOT EXISTS labels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    color VARCHAR(20) NOT NULL DEFAULT '#6b7280',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(board_id, name)
+);
+
+-- Пользовательские шаблоны досок; встроенные шаблоны описаны в коде
+CREATE TABLE IF NOT EXISTS board_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    columns JSONB NOT NULL DEFAULT '[]',
+    labels JSONB NOT NULL DEFAULT '[]',
+    tasks JSONB NOT NULL DEFAULT '[]',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
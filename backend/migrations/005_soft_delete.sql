@@ -0,0 +1,13 @@
+-- Мягкое удаление: строки с deleted_at попадают в корзину и удаляются
+-- окончательно фоновой задачей после истечения срока хранения
+ALTER TABLE boards ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE columns ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_boards_deleted_at ON boards(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_columns_deleted_at ON columns(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- status_id уникален только среди неудаленных колонок доски
+ALTER TABLE columns DROP CONSTRAINT IF EXISTS columns_board_id_status_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_columns_board_status_active ON columns(board_id, status_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,14 @@
+-- Архив: задачи и колонки скрываются из списков, но остаются доступны отчетам
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+ALTER TABLE columns ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_board_active ON tasks(board_id) WHERE archived_at IS NULL AND deleted_at IS NULL;
+
+-- Правила автоматической архивации задач доски
+CREATE TABLE IF NOT EXISTS archive_rules (
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    status VARCHAR(100) NOT NULL,
+    after_days INTEGER NOT NULL CHECK (after_days >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (board_id, status)
+);
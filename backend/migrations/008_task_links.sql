@@ -0,0 +1,16 @@
+-- Связи задач: блокирование, связанность, дубликаты
+CREATE TABLE IF NOT EXISTS task_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    target_task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('blocks', 'relates_to', 'duplicates')),
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (source_task_id <> target_task_id),
+    UNIQUE(source_task_id, target_task_id, type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_links_target ON task_links(target_task_id);
+
+-- Запрет переводить заблокированные задачи в последнюю колонку
+ALTER TABLE boards ADD COLUMN IF NOT EXISTS enforce_dependencies BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,21 @@
+-- Содержимое вложений: одинаковые файлы хранятся один раз по контрольной сумме
+CREATE TABLE IF NOT EXISTS attachment_blobs (
+    checksum CHAR(64) PRIMARY KEY,
+    size BIGINT NOT NULL,
+    has_thumbnail BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Вложения задач; файл без вложений удаляется фоновой задачей
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    checksum CHAR(64) NOT NULL REFERENCES attachment_blobs(checksum),
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_task_id ON attachments(task_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_checksum ON attachments(checksum);
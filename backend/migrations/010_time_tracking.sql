@@ -0,0 +1,20 @@
+-- Оценка трудозатрат задачи
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate_minutes INTEGER CHECK (estimate_minutes > 0);
+
+-- Учет времени; запись без ended_at - запущенный таймер
+CREATE TABLE IF NOT EXISTS worklogs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    duration_minutes INTEGER NOT NULL DEFAULT 0 CHECK (duration_minutes >= 0),
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_worklogs_task_id ON worklogs(task_id);
+CREATE INDEX IF NOT EXISTS idx_worklogs_user_started ON worklogs(user_id, started_at);
+-- Не больше одного запущенного таймера на пользователя
+CREATE UNIQUE INDEX IF NOT EXISTS idx_worklogs_running_timer ON worklogs(user_id) WHERE ended_at IS NULL;
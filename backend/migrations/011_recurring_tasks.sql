@@ -0,0 +1,21 @@
+-- Шаблоны повторяющихся задач; next_run_at IS NULL - правило исчерпано
+CREATE TABLE IF NOT EXISTS recurring_tasks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    status VARCHAR(100) NOT NULL,
+    priority VARCHAR(20) CHECK (priority IN ('low', 'medium', 'high')),
+    assignee VARCHAR(255),
+    estimate_minutes INTEGER CHECK (estimate_minutes > 0),
+    rrule TEXT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    next_run_at TIMESTAMP,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_tasks_board_id ON recurring_tasks(board_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_tasks_next_run_at ON recurring_tasks(next_run_at) WHERE next_run_at IS NOT NULL;
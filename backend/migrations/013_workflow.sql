@@ -0,0 +1,10 @@
+-- Разрешенные переходы между статусами задач доски. Доска без переходов
+-- разрешает перемещение между любыми колонками; from_status '*' - из любого статуса
+CREATE TABLE IF NOT EXISTS workflow_transitions (
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    from_status VARCHAR(100) NOT NULL,
+    to_status VARCHAR(100) NOT NULL,
+    required_fields JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (board_id, from_status, to_status)
+);
@@ -0,0 +1,22 @@
+-- Спринты досок; на доске одновременно активен не больше одного спринта
+CREATE TABLE IF NOT EXISTS sprints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    goal TEXT NOT NULL DEFAULT '',
+    state VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK (state IN ('planned', 'active', 'completed')),
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sprints_board_id ON sprints(board_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sprints_board_active ON sprints(board_id) WHERE state = 'active';
+
+-- Задачи без спринта находятся в бэклоге доски
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sprint_id UUID REFERENCES sprints(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_sprint_id ON tasks(sprint_id) WHERE sprint_id IS NOT NULL;
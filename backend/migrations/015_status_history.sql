@@ -0,0 +1,19 @@
+-- История статусов задач: каждая запись - попадание задачи в статус to_status
+-- доски board_id. from_status пуст при создании задачи и переносе на доску.
+CREATE TABLE IF NOT EXISTS task_status_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    board_id UUID NOT NULL REFERENCES boards(id) ON DELETE CASCADE,
+    from_status VARCHAR(100),
+    to_status VARCHAR(100) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_status_changes_board ON task_status_changes(board_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_task_status_changes_task_id ON task_status_changes(task_id);
+
+-- Для задач, созданных до миграции, история начинается с текущего статуса в момент создания
+INSERT INTO task_status_changes (task_id, board_id, to_status, changed_at)
+SELECT t.id, t.board_id, t.status, COALESCE(t.created_at, CURRENT_TIMESTAMP)
+FROM tasks t
+WHERE NOT EXISTS (SELECT 1 FROM task_status_changes c WHERE c.task_id = t.id);
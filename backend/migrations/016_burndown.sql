@@ -0,0 +1,36 @@
+-- Оценка задачи в story points
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS story_points INTEGER CHECK (story_points > 0);
+
+-- История состава спринтов: задача добавлена (added), убрана (removed) или
+-- у задачи в спринте изменились story points (points). points - оценка
+-- задачи после изменения.
+CREATE TABLE IF NOT EXISTS sprint_scope_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sprint_id UUID NOT NULL REFERENCES sprints(id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    change VARCHAR(20) NOT NULL CHECK (change IN ('added', 'removed', 'points')),
+    points INTEGER,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sprint_scope_changes_sprint ON sprint_scope_changes(sprint_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_sprint_scope_changes_task_id ON sprint_scope_changes(task_id);
+
+-- Задачи, попавшие в спринты до миграции, считаются добавленными к началу спринта
+INSERT INTO sprint_scope_changes (sprint_id, task_id, change, points, changed_at)
+SELECT t.sprint_id, t.id, 'added', t.story_points, COALESCE(s.starts_at, t.created_at, CURRENT_TIMESTAMP)
+FROM tasks t
+JOIN sprints s ON s.id = t.sprint_id
+WHERE NOT EXISTS (SELECT 1 FROM sprint_scope_changes c WHERE c.task_id = t.id AND c.sprint_id = t.sprint_id);
+
+-- Ежедневные снимки burndown: состав и выполнение спринта на конец дня (UTC)
+CREATE TABLE IF NOT EXISTS sprint_snapshots (
+    sprint_id UUID NOT NULL REFERENCES sprints(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    scope_points INTEGER NOT NULL,
+    completed_points INTEGER NOT NULL,
+    scope_tasks INTEGER NOT NULL,
+    completed_tasks INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (sprint_id, day)
+);
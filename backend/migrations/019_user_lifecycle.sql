@@ -0,0 +1,8 @@
+-- Временная блокировка учетных записей: статус locked и срок блокировки
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'disabled', 'locked'));
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP;
+
+-- Выгрузка данных и удаление пользователя ищут его задачи и комментарии
+CREATE INDEX IF NOT EXISTS idx_tasks_created_by ON tasks(created_by);
+CREATE INDEX IF NOT EXISTS idx_task_comments_user_id ON task_comments(user_id);
@@ -0,0 +1,15 @@
+-- Отложенные действия правил автоматизации: действия начиная с action_index
+-- выполняются над задачей не раньше run_at. У правила для задачи не больше
+-- одного отложенного запуска, новый заменяет прежний.
+CREATE TABLE IF NOT EXISTS automation_delays (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    rule_id UUID NOT NULL REFERENCES automation_rules(id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    event VARCHAR(32) NOT NULL,
+    action_index INT NOT NULL,
+    run_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (rule_id, task_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_automation_delays_run_at ON automation_delays(run_at);
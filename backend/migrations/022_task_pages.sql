@@ -0,0 +1,2 @@
+-- Постраничное чтение задач доски по (created_at, id), например при выгрузке
+CREATE INDEX IF NOT EXISTS idx_tasks_board_created ON tasks(board_id, created_at, id) WHERE deleted_at IS NULL;
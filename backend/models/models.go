@@ -13,42 +13,685 @@ type Board struct {
 	UserID      *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
-	Tasks       []Task     `json:"tasks,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// EnforceDependencies запрещает переводить в последнюю колонку задачи
+	// с незавершенными блокирующими задачами
+	EnforceDependencies bool   `json:"enforce_dependencies" db:"enforce_dependencies"`
+	Tasks               []Task `json:"tasks,omitempty"`
 }
 
 type Task struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	BoardID      uuid.UUID  `json:"board_id" db:"board_id"`
+	ParentTaskID *uuid.UUID `json:"parent_task_id,omitempty" db:"parent_task_id"`
+	Title        string     `json:"title" db:"title"`
+	Description  string     `json:"description" db:"description"`
+	Status       string     `json:"status" db:"status"`
+	Priority     *string    `json:"priority,omitempty" db:"priority"`
+	Assignee     *string    `json:"assignee,omitempty" db:"assignee"`
+	// EstimateMinutes - оценка трудозатрат в минутах, StoryPoints - в story points
+	EstimateMinutes *int       `json:"estimate_minutes,omitempty" db:"estimate_minutes"`
+	StoryPoints     *int       `json:"story_points,omitempty" db:"story_points"`
+	DueAt           *time.Time `json:"due_at,omitempty" db:"due_at"`
+	SprintID        *uuid.UUID `json:"sprint_id,omitempty" db:"sprint_id"`
+	CreatedBy       *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	ArchivedAt      *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// Progress вычисляется по пунктам чек-листа и подзадачам, в БД не хранится
+	Progress TaskProgress `json:"progress"`
+}
+
+// TaskProgress - выполненные пункты чек-листа и подзадачи из общего числа.
+// Подзадача считается выполненной, если она в последней колонке своей доски.
+type TaskProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+type ChecklistItem struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	TaskID    uuid.UUID `json:"task_id" db:"task_id"`
+	Title     string    `json:"title" db:"title"`
+	Checked   bool      `json:"checked" db:"checked"`
+	Position  int       `json:"position" db:"position"`
+	Assignee  *string   `json:"assignee,omitempty" db:"assignee"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+const (
+	// LinkTypeBlocks - исходная задача блокирует целевую
+	LinkTypeBlocks     = "blocks"
+	LinkTypeRelatesTo  = "relates_to"
+	LinkTypeDuplicates = "duplicates"
+)
+
+// TaskLink - типизированная связь исходной задачи с целевой
+type TaskLink struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	SourceTaskID uuid.UUID  `json:"source_task_id" db:"source_task_id"`
+	TargetTaskID uuid.UUID  `json:"target_task_id" db:"target_task_id"`
+	Type         string     `json:"type" db:"type"`
+	CreatedBy    *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// DependencyGraph - связанные задачи доски и связи между ними.
+// Узлы могут включать задачи других досок, на которые ссылаются связи.
+type DependencyGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+type GraphNode struct {
+	ID      uuid.UUID `json:"id"`
+	BoardID uuid.UUID `json:"board_id"`
+	Title   string    `json:"title"`
+	Status  string    `json:"status"`
+	// Blocked - у задачи есть незавершенные блокирующие задачи
+	Blocked bool `json:"blocked"`
+}
+
+type GraphEdge struct {
+	Source uuid.UUID `json:"source"`
+	Target uuid.UUID `json:"target"`
+	Type   string    `json:"type"`
+}
+
+// Attachment - файл, прикрепленный к задаче. Содержимое хранится в
+// хранилище по контрольной сумме, поэтому одинаковые файлы хранятся один раз.
+type Attachment struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	TaskID      uuid.UUID `json:"task_id" db:"task_id"`
+	Filename    string    `json:"filename" db:"filename"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	// Checksum - SHA-256 содержимого в hex
+	Checksum     string     `json:"checksum" db:"checksum"`
+	HasThumbnail bool       `json:"has_thumbnail" db:"has_thumbnail"`
+	UploadedBy   *uuid.UUID `json:"uploaded_by,omitempty" db:"uploaded_by"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// AttachmentBlob - содержимое файла в хранилище, общее для всех вложений
+// с одинаковой контрольной суммой
+type AttachmentBlob struct {
+	Checksum     string    `json:"checksum" db:"checksum"`
+	Size         int64     `json:"size" db:"size"`
+	HasThumbnail bool      `json:"has_thumbnail" db:"has_thumbnail"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// Worklog - учтенное пользователем время работы над задачей. Запущенный
+// таймер - запись без EndedAt; у пользователя может быть только один такой.
+type Worklog struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	TaskID    uuid.UUID  `json:"task_id" db:"task_id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	StartedAt time.Time  `json:"started_at" db:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty" db:"ended_at"`
+	// DurationMinutes у запущенного таймера равен 0
+	DurationMinutes int       `json:"duration_minutes" db:"duration_minutes"`
+	Note            *string   `json:"note,omitempty" db:"note"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// TimeReport - учтенное время за период: по задачам, по пользователям
+// и по парам задача-пользователь
+type TimeReport struct {
+	From         *time.Time        `json:"from,omitempty"`
+	To           *time.Time        `json:"to,omitempty"`
+	TotalMinutes int               `json:"total_minutes"`
+	Tasks        []TimeReportTask  `json:"tasks"`
+	Users        []TimeReportUser  `json:"users"`
+	Entries      []TimeReportEntry `json:"entries"`
+}
+
+type TimeReportTask struct {
+	TaskID          uuid.UUID `json:"task_id"`
+	BoardID         uuid.UUID `json:"board_id"`
+	Title           string    `json:"title"`
+	EstimateMinutes *int      `json:"estimate_minutes,omitempty"`
+	Minutes         int       `json:"minutes"`
+}
+
+type TimeReportUser struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Minutes  int       `json:"minutes"`
+}
+
+type TimeReportEntry struct {
+	TaskID    uuid.UUID `json:"task_id"`
+	BoardID   uuid.UUID `json:"board_id"`
+	TaskTitle string    `json:"task_title"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	Minutes   int       `json:"minutes"`
+}
+
+// RecurringTask - шаблон повторяющейся задачи: планировщик создает по нему
+// задачу в колонке Status каждый раз, когда наступает повторение правила RRule
+type RecurringTask struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	BoardID         uuid.UUID `json:"board_id" db:"board_id"`
+	Title           string    `json:"title" db:"title"`
+	Description     string    `json:"description" db:"description"`
+	Status          string    `json:"status" db:"status"`
+	Priority        *string   `json:"priority,omitempty" db:"priority"`
+	Assignee        *string   `json:"assignee,omitempty" db:"assignee"`
+	EstimateMinutes *int      `json:"estimate_minutes,omitempty" db:"estimate_minutes"`
+	// RRule - правило повторения RFC 5545, например FREQ=WEEKLY;BYDAY=MO
+	RRule string `json:"rrule" db:"rrule"`
+	// StartsAt (DTSTART) задает первое повторение и время суток всех
+	// повторений в часовом поясе Timezone
+	StartsAt time.Time `json:"starts_at" db:"starts_at"`
+	Timezone string    `json:"timezone" db:"timezone"`
+	// NextRunAt - следующее повторение; nil, если правило исчерпано
+	NextRunAt *time.Time `json:"next_run_at,omitempty" db:"next_run_at"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+const (
+	SprintStatePlanned   = "planned"
+	SprintStateActive    = "active"
+	SprintStateCompleted = "completed"
+)
+
+// Sprint - итерация доски. На доске одновременно активен не больше одного
+// спринта; задачи без спринта находятся в бэклоге.
+type Sprint struct {
 	ID          uuid.UUID  `json:"id" db:"id"`
 	BoardID     uuid.UUID  `json:"board_id" db:"board_id"`
-	Title       string     `json:"title" db:"title"`
-	Description string     `json:"description" db:"description"`
-	Status      string     `json:"status" db:"status"`
-	Priority    *string    `json:"priority,omitempty" db:"priority"`
-	Assignee    *string    `json:"assignee,omitempty" db:"assignee"`
+	Name        string     `json:"name" db:"name"`
+	Goal        string     `json:"goal" db:"goal"`
+	State       string     `json:"state" db:"state"`
+	StartsAt    *time.Time `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at,omitempty" db:"ends_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
 	CreatedBy   *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
 	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
 }
 
-type Column struct {
+// SprintCompletion - результат завершения спринта: незавершенные задачи
+// перенесены в спринт NextSprintID или в бэклог (nil)
+type SprintCompletion struct {
+	Sprint       Sprint      `json:"sprint"`
+	NextSprintID *uuid.UUID  `json:"next_sprint_id"`
+	CarriedOver  []uuid.UUID `json:"carried_over"`
+}
+
+// Изменения состава спринта в истории
+const (
+	SprintScopeAdded   = "added"
+	SprintScopeRemoved = "removed"
+	SprintScopePoints  = "points"
+)
+
+// SprintScopeChange - запись истории состава спринта: задача добавлена,
+// убрана или у задачи в спринте изменились story points. Points - оценка
+// задачи после изменения.
+type SprintScopeChange struct {
 	ID       uuid.UUID `json:"id" db:"id"`
-	BoardID  uuid.UUID `json:"board_id" db:"board_id"`
-	Title    string    `json:"title" db:"title"`
-	StatusID string    `json:"status_id" db:"status_id"`
-	Position int       `json:"position" db:"position"`
+	SprintID uuid.UUID `json:"sprint_id" db:"sprint_id"`
+	TaskID   uuid.UUID `json:"task_id" db:"task_id"`
+	// TaskTitle - текущее название задачи, в истории не хранится
+	TaskTitle string    `json:"task_title" db:"-"`
+	Change    string    `json:"change" db:"change"`
+	Points    *int      `json:"points,omitempty" db:"points"`
+	ChangedAt time.Time `json:"changed_at" db:"changed_at"`
+}
+
+// SprintSnapshot - состав и выполнение спринта на конец дня Day (UTC),
+// сохраненные ночной задачей
+type SprintSnapshot struct {
+	SprintID        uuid.UUID `json:"sprint_id" db:"sprint_id"`
+	Day             time.Time `json:"day" db:"day"`
+	ScopePoints     int       `json:"scope_points" db:"scope_points"`
+	CompletedPoints int       `json:"completed_points" db:"completed_points"`
+	ScopeTasks      int       `json:"scope_tasks" db:"scope_tasks"`
+	CompletedTasks  int       `json:"completed_tasks" db:"completed_tasks"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// SprintBurndown - burndown спринта по дням с начала до конца спринта или
+// текущего дня. Ideal - равномерное сгорание начального объема к EndsAt.
+type SprintBurndown struct {
+	SprintID   uuid.UUID `json:"sprint_id"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	DoneStatus string    `json:"done_status"`
+	// InitialPoints и InitialTasks - объем спринта в момент старта
+	InitialPoints int                   `json:"initial_points"`
+	InitialTasks  int                   `json:"initial_tasks"`
+	Days          []BurndownDay         `json:"days"`
+	Ideal         []BurndownIdealDay    `json:"ideal"`
+	ScopeChanges  []BurndownScopeChange `json:"scope_changes"`
+}
+
+// BurndownDay - объем спринта на конец дня; Snapshot - значения взяты из
+// ночного снимка, а не посчитаны по истории
+type BurndownDay struct {
+	Date            string `json:"date"`
+	ScopePoints     int    `json:"scope_points"`
+	CompletedPoints int    `json:"completed_points"`
+	RemainingPoints int    `json:"remaining_points"`
+	ScopeTasks      int    `json:"scope_tasks"`
+	CompletedTasks  int    `json:"completed_tasks"`
+	RemainingTasks  int    `json:"remaining_tasks"`
+	Snapshot        bool   `json:"snapshot"`
+}
+
+type BurndownIdealDay struct {
+	Date            string  `json:"date"`
+	RemainingPoints float64 `json:"remaining_points"`
+}
+
+// BurndownScopeChange - изменение объема после старта спринта
+type BurndownScopeChange struct {
+	Date        string    `json:"date"`
+	ChangedAt   time.Time `json:"changed_at"`
+	TaskID      uuid.UUID `json:"task_id"`
+	Title       string    `json:"title"`
+	Change      string    `json:"change"`
+	PointsDelta int       `json:"points_delta"`
+}
+
+// BoardExport - полная JSON-выгрузка доски. Schema и Version описывают
+// формат: Version увеличивается при несовместимых изменениях.
+type BoardExport struct {
+	Schema     string         `json:"schema"`
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Board      Board          `json:"board"`
+	Columns    []Column       `json:"columns"`
+	Labels     []Label        `json:"labels"`
+	Sprints    []Sprint       `json:"sprints"`
+	Tasks      []ExportedTask `json:"tasks"`
+}
+
+// ExportedTask - задача выгрузки с чек-листом и, если они запрошены, комментариями
+type ExportedTask struct {
+	Task
+	Checklist []ChecklistItem   `json:"checklist"`
+	Comments  []ExportedComment `json:"comments,omitempty"`
+}
+
+// ExportedComment - комментарий выгрузки; Author - имя автора на момент выгрузки
+type ExportedComment struct {
+	TaskComment
+	Author string `json:"author,omitempty"`
+}
+
+// Состояния задания импорта
+const (
+	ImportJobPending   = "pending"
+	ImportJobRunning   = "running"
+	ImportJobCompleted = "completed"
+	ImportJobFailed    = "failed"
+)
+
+// ImportJob - фоновое задание импорта доски. ImportedTasks растет по мере
+// создания задач, BoardID заполняется после успешного завершения.
+type ImportJob struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	UserID        *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	Format        string     `json:"format" db:"format"`
+	BoardName     string     `json:"board_name" db:"board_name"`
+	Status        string     `json:"status" db:"status"`
+	BoardID       *uuid.UUID `json:"board_id,omitempty" db:"board_id"`
+	TotalTasks    int        `json:"total_tasks" db:"total_tasks"`
+	ImportedTasks int        `json:"imported_tasks" db:"imported_tasks"`
+	Error         *string    `json:"error,omitempty" db:"error"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// ImportPreview описывает, что создаст импорт: колонки, метки, число
+// задач и сопоставление пользователей источника
+type ImportPreview struct {
+	Format         string              `json:"format"`
+	BoardName      string              `json:"board_name"`
+	Columns        []TemplateColumn    `json:"columns"`
+	Labels         []TemplateLabel     `json:"labels"`
+	Sprints        int                 `json:"sprints"`
+	Tasks          int                 `json:"tasks"`
+	ArchivedTasks  int                 `json:"archived_tasks"`
+	ChecklistItems int                 `json:"checklist_items"`
+	Comments       int                 `json:"comments"`
+	Users          []ImportUser        `json:"users"`
+	Sample         []ImportTaskPreview `json:"sample"`
+	Warnings       []string            `json:"warnings"`
+}
+
+// ImportUser - пользователь источника и пользователь Task Flow, на которого
+// он сопоставлен (Username nil - не сопоставлен)
+type ImportUser struct {
+	Source   string  `json:"source"`
+	Username *string `json:"username"`
+	Tasks    int     `json:"tasks"`
+	Comments int     `json:"comments"`
+}
+
+// ImportTaskPreview - задача из начала файла импорта; Assignee - пользователь источника
+type ImportTaskPreview struct {
+	Title    string     `json:"title"`
+	Status   string     `json:"status"`
+	Priority *string    `json:"priority,omitempty"`
+	Assignee *string    `json:"assignee,omitempty"`
+	Labels   []string   `json:"labels,omitempty"`
+	DueAt    *time.Time `json:"due_at,omitempty"`
+}
+
+// TaskStatusChange - запись истории статусов: задача попала в статус ToStatus
+// доски BoardID. FromStatus пуст при создании задачи и переносе на доску.
+type TaskStatusChange struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	TaskID     uuid.UUID `json:"task_id" db:"task_id"`
+	BoardID    uuid.UUID `json:"board_id" db:"board_id"`
+	FromStatus *string   `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   string    `json:"to_status" db:"to_status"`
+	ChangedAt  time.Time `json:"changed_at" db:"changed_at"`
+}
+
+// BoardAnalytics - метрики потока доски за период [From, To). Время
+// выполнения считается по задачам, попавшим в DoneStatus за период;
+// время цикла - с первого попадания в StartStatus или колонку правее.
+type BoardAnalytics struct {
+	BoardID     uuid.UUID `json:"board_id"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	StartStatus string    `json:"start_status"`
+	DoneStatus  string    `json:"done_status"`
+	// Statuses - статусы колонок доски по порядку
+	Statuses       []string            `json:"statuses"`
+	CumulativeFlow []CumulativeFlowDay `json:"cumulative_flow"`
+	LeadTime       FlowTimeStats       `json:"lead_time"`
+	CycleTime      FlowTimeStats       `json:"cycle_time"`
+	Throughput     []ThroughputWeek    `json:"throughput"`
+	Completed      []CompletedTask     `json:"completed"`
+	AgingWIP       []AgingTask         `json:"aging_wip"`
+}
+
+// CumulativeFlowDay - число задач в каждом статусе на конец дня Date (UTC)
+type CumulativeFlowDay struct {
+	Date   string         `json:"date"`
+	Counts map[string]int `json:"counts"`
+}
+
+// FlowTimeStats - перцентили времени в днях
+type FlowTimeStats struct {
+	Count   int     `json:"count"`
+	Average float64 `json:"average_days"`
+	P50     float64 `json:"p50_days"`
+	P75     float64 `json:"p75_days"`
+	P85     float64 `json:"p85_days"`
+	P95     float64 `json:"p95_days"`
+}
+
+// ThroughputWeek - число задач, завершенных за неделю с понедельника WeekStart
+type ThroughputWeek struct {
+	WeekStart string `json:"week_start"`
+	Count     int    `json:"count"`
+}
+
+type CompletedTask struct {
+	TaskID        uuid.UUID `json:"task_id"`
+	Title         string    `json:"title"`
+	CreatedAt     time.Time `json:"created_at"`
+	StartedAt     time.Time `json:"started_at"`
+	DoneAt        time.Time `json:"done_at"`
+	LeadTimeDays  float64   `json:"lead_time_days"`
+	CycleTimeDays float64   `json:"cycle_time_days"`
+}
+
+// AgingTask - незавершенная задача в работе и ее возраст с начала работы
+type AgingTask struct {
+	TaskID    uuid.UUID `json:"task_id"`
+	Title     string    `json:"title"`
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"started_at"`
+	AgeDays   float64   `json:"age_days"`
+}
+
+// TaskComment - комментарий задачи. У комментариев, добавленных правилами
+// автоматизации, заполнен RuleID, а UserID пуст.
+type TaskComment struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	TaskID    uuid.UUID  `json:"task_id" db:"task_id"`
+	UserID    *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	RuleID    *uuid.UUID `json:"rule_id,omitempty" db:"rule_id"`
+	Body      string     `json:"body" db:"body"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+const (
+	AutomationTriggerTaskCreated  = "task_created"
+	AutomationTriggerTaskMoved    = "task_moved"
+	AutomationTriggerFieldChanged = "field_changed"
+	AutomationTriggerDueSoon      = "due_soon"
+)
+
+const (
+	AutomationActionSetField = "set_field"
+	AutomationActionMove     = "move"
+	AutomationActionAssign   = "assign"
+	AutomationActionComment  = "comment"
+	AutomationActionArchive  = "archive"
+	AutomationActionWebhook  = "webhook"
+)
+
+const (
+	AutomationRunSuccess = "success"
+	AutomationRunFailed  = "failed"
+	AutomationRunSkipped = "skipped"
+)
+
+// AutomationRule - правило автоматизации доски: когда происходит Trigger
+// и задача подходит под все Conditions, по порядку выполняются Actions
+type AutomationRule struct {
+	ID         uuid.UUID             `json:"id" db:"id"`
+	BoardID    uuid.UUID             `json:"board_id" db:"board_id"`
+	Name       string                `json:"name" db:"name"`
+	Enabled    bool                  `json:"enabled" db:"enabled"`
+	Trigger    AutomationTrigger     `json:"trigger"`
+	Conditions []AutomationCondition `json:"conditions"`
+	Actions    []AutomationAction    `json:"actions"`
+	CreatedBy  *uuid.UUID            `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt  time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// AutomationTrigger - событие, запускающее правило. From и To ограничивают
+// статусы для task_moved, Field и To - поле и его новое значение для
+// field_changed, WithinHours - за сколько часов до срока срабатывает due_soon.
+type AutomationTrigger struct {
+	Type        string `json:"type"`
+	Field       string `json:"field,omitempty"`
+	From        string `json:"from,omitempty"`
+	To          string `json:"to,omitempty"`
+	WithinHours int    `json:"within_hours,omitempty"`
+}
+
+// AutomationCondition сравнивает поле задачи со значением (equals,
+// not_equals, contains) или проверяет, заполнено ли оно (is_empty, is_not_empty)
+type AutomationCondition struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator"`
+	Value    string `json:"value,omitempty"`
+}
+
+// AutomationAction - действие правила. Field и Value нужны set_field (пустое
+// Value очищает поле), Value - move (статус), assign (исполнитель) и comment
+// (текст), URL - webhook. DelayHours откладывает действие и все следующие
+// за ним на указанное число часов.
+type AutomationAction struct {
+	Type       string `json:"type"`
+	Field      string `json:"field,omitempty"`
+	Value      string `json:"value,omitempty"`
+	URL        string `json:"url,omitempty"`
+	DelayHours int    `json:"delay_hours,omitempty"`
+}
+
+// AutomationDelay - отложенная часть запуска правила: действия начиная
+// с ActionIndex выполняются над задачей не раньше RunAt
+type AutomationDelay struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	RuleID      uuid.UUID `json:"rule_id" db:"rule_id"`
+	TaskID      uuid.UUID `json:"task_id" db:"task_id"`
+	Event       string    `json:"event" db:"event"`
+	ActionIndex int       `json:"action_index" db:"action_index"`
+	RunAt       time.Time `json:"run_at" db:"run_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// AutomationRun - запись журнала выполнения правила
+type AutomationRun struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	RuleID    uuid.UUID  `json:"rule_id" db:"rule_id"`
+	BoardID   uuid.UUID  `json:"board_id" db:"board_id"`
+	TaskID    *uuid.UUID `json:"task_id,omitempty" db:"task_id"`
+	Event     string     `json:"event" db:"event"`
+	Status    string     `json:"status" db:"status"`
+	Message   string     `json:"message,omitempty" db:"message"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+type Column struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	BoardID    uuid.UUID  `json:"board_id" db:"board_id"`
+	Title      string     `json:"title" db:"title"`
+	StatusID   string     `json:"status_id" db:"status_id"`
+	Position   int        `json:"position" db:"position"`
+	WIPLimit   *int       `json:"wip_limit,omitempty" db:"wip_limit"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// ArchiveRule автоматически архивирует задачи доски в статусе Status,
+// которые не менялись дольше AfterDays дней.
+type ArchiveRule struct {
+	BoardID   uuid.UUID `json:"board_id" db:"board_id"`
+	Status    string    `json:"status" db:"status"`
+	AfterDays int       `json:"after_days" db:"after_days"`
+}
+
+// WorkflowTransition разрешает перевод задач доски из статуса From ("*" -
+// из любого статуса) в статус To. RequiredFields - поля задачи, которые
+// должны быть заполнены для перехода.
+type WorkflowTransition struct {
+	BoardID        uuid.UUID `json:"board_id" db:"board_id"`
+	From           string    `json:"from" db:"from_status"`
+	To             string    `json:"to" db:"to_status"`
+	RequiredFields []string  `json:"required_fields" db:"required_fields"`
+}
+
+type ArchiveDoneRequest struct {
+	Status        string `json:"status,omitempty"`
+	OlderThanDays int    `json:"older_than_days"`
+}
+
+const (
+	TrashTypeBoard  = "board"
+	TrashTypeColumn = "column"
+	TrashTypeTask   = "task"
+)
+
+// TrashItem - удаленная доска, колонка или задача, которую еще можно восстановить
+type TrashItem struct {
+	Type      string    `json:"type"`
+	ID        uuid.UUID `json:"id"`
+	BoardID   uuid.UUID `json:"board_id"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type Label struct {
+	ID      uuid.UUID `json:"id" db:"id"`
+	BoardID uuid.UUID `json:"board_id" db:"board_id"`
+	Name    string    `json:"name" db:"name"`
+	Color   string    `json:"color" db:"color"`
+}
+
+// BoardTemplate описывает структуру, по которой создается новая доска.
+// ID встроенных шаблонов - строковые ключи ("scrum"), сохраненных - UUID.
+type BoardTemplate struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description *string          `json:"description,omitempty"`
+	BuiltIn     bool             `json:"built_in"`
+	CreatedBy   *uuid.UUID       `json:"created_by,omitempty"`
+	CreatedAt   time.Time        `json:"created_at,omitzero"`
+	Columns     []TemplateColumn `json:"columns"`
+	Labels      []TemplateLabel  `json:"labels,omitempty"`
+	Tasks       []TemplateTask   `json:"tasks,omitempty"`
+}
+
+type TemplateColumn struct {
+	Title    string `json:"title"`
+	StatusID string `json:"status_id"`
+	WIPLimit *int   `json:"wip_limit,omitempty"`
+}
+
+type TemplateLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type TemplateTask struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"`
+	Status      string  `json:"status"`
+	Priority    *string `json:"priority,omitempty"`
+}
+
+const (
+	BoardRoleOwner  = "owner"
+	BoardRoleMember = "member"
+)
+
+type BoardMember struct {
+	BoardID   uuid.UUID `json:"board_id" db:"board_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Role      string    `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
 }
 
 type CreateBoardRequest struct {
-	Name        string  `json:"name"`
-	Description *string `json:"description,omitempty"`
+	Name                string  `json:"name"`
+	Description         *string `json:"description,omitempty"`
+	TemplateID          string  `json:"template_id,omitempty"`
+	EnforceDependencies *bool   `json:"enforce_dependencies,omitempty"`
+}
+
+type SaveTemplateRequest struct {
+	Name         string  `json:"name"`
+	Description  *string `json:"description,omitempty"`
+	IncludeTasks bool    `json:"include_tasks"`
 }
 
 type CreateTaskRequest struct {
-	BoardID     uuid.UUID `json:"board_id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	Priority    *string   `json:"priority,omitempty"`
-	Assignee    *string   `json:"assignee,omitempty"`
+	BoardID      uuid.UUID  `json:"board_id"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	Status       string     `json:"status"`
+	Priority     *string    `json:"priority,omitempty"`
+	Assignee     *string    `json:"assignee,omitempty"`
+	ParentTaskID *uuid.UUID `json:"parent_task_id,omitempty"`
+	// EstimateMinutes - оценка в минутах
+	EstimateMinutes *int `json:"estimate_minutes,omitempty"`
+	// StoryPoints - оценка в story points
+	StoryPoints *int `json:"story_points,omitempty"`
+	// DueAt - срок выполнения
+	DueAt *time.Time `json:"due_at,omitempty"`
+	// SprintID - спринт доски задачи
+	SprintID *uuid.UUID `json:"sprint_id,omitempty"`
 }
 
 type UpdateTaskRequest struct {
@@ -57,6 +700,150 @@ type UpdateTaskRequest struct {
 	Status      *string `json:"status,omitempty"`
 	Priority    *string `json:"priority,omitempty"`
 	Assignee    *string `json:"assignee,omitempty"`
+	// EstimateMinutes 0 снимает оценку
+	EstimateMinutes *int `json:"estimate_minutes,omitempty"`
+	// StoryPoints 0 снимает оценку в story points
+	StoryPoints *int `json:"story_points,omitempty"`
+	// DueAt - срок в формате RFC3339; пустая строка снимает срок
+	DueAt *string `json:"due_at,omitempty"`
+	// SprintID - спринт задачи; пустая строка возвращает задачу в бэклог
+	SprintID *string `json:"sprint_id,omitempty"`
+}
+
+type CreateTaskLinkRequest struct {
+	TargetTaskID uuid.UUID `json:"target_task_id"`
+	Type         string    `json:"type"`
+}
+
+// SetParentRequest делает задачу подзадачей ParentTaskID; null отвязывает ее от родителя
+type SetParentRequest struct {
+	ParentTaskID *uuid.UUID `json:"parent_task_id"`
+}
+
+// CreateWorklogRequest задает время либо началом и концом, либо
+// длительностью (с необязательным началом)
+type CreateWorklogRequest struct {
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationMinutes *int       `json:"duration_minutes,omitempty"`
+	Note            *string    `json:"note,omitempty"`
+}
+
+type StopTimerRequest struct {
+	Note *string `json:"note,omitempty"`
+}
+
+type CreateRecurringTaskRequest struct {
+	Title           string  `json:"title"`
+	Description     string  `json:"description"`
+	Status          string  `json:"status,omitempty"`
+	Priority        *string `json:"priority,omitempty"`
+	Assignee        *string `json:"assignee,omitempty"`
+	EstimateMinutes *int    `json:"estimate_minutes,omitempty"`
+	RRule           string  `json:"rrule"`
+	// StartsAt по умолчанию - момент создания
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	// Timezone - имя часового пояса IANA, по умолчанию UTC
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type UpdateRecurringTaskRequest struct {
+	Title           *string    `json:"title,omitempty"`
+	Description     *string    `json:"description,omitempty"`
+	Status          *string    `json:"status,omitempty"`
+	Priority        *string    `json:"priority,omitempty"`
+	Assignee        *string    `json:"assignee,omitempty"`
+	EstimateMinutes *int       `json:"estimate_minutes,omitempty"`
+	RRule           *string    `json:"rrule,omitempty"`
+	StartsAt        *time.Time `json:"starts_at,omitempty"`
+	Timezone        *string    `json:"timezone,omitempty"`
+}
+
+type CreateSprintRequest struct {
+	Name     string     `json:"name"`
+	Goal     string     `json:"goal"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+type UpdateSprintRequest struct {
+	Name     *string    `json:"name,omitempty"`
+	Goal     *string    `json:"goal,omitempty"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+// CompleteSprintRequest задает, куда переносятся незавершенные задачи:
+// "next" (по умолчанию) - в ближайший запланированный спринт или в бэклог,
+// если его нет, "backlog" - в бэклог, либо ID запланированного спринта доски
+type CompleteSprintRequest struct {
+	MoveTo string `json:"move_to"`
+}
+
+type SprintTasksRequest struct {
+	TaskIDs []uuid.UUID `json:"task_ids"`
+}
+
+type CreateCommentRequest struct {
+	Body string `json:"body"`
+}
+
+type CreateAutomationRuleRequest struct {
+	Name string `json:"name"`
+	// Enabled по умолчанию true
+	Enabled    *bool                 `json:"enabled,omitempty"`
+	Trigger    AutomationTrigger     `json:"trigger"`
+	Conditions []AutomationCondition `json:"conditions,omitempty"`
+	Actions    []AutomationAction    `json:"actions"`
+}
+
+type UpdateAutomationRuleRequest struct {
+	Name       *string                `json:"name,omitempty"`
+	Enabled    *bool                  `json:"enabled,omitempty"`
+	Trigger    *AutomationTrigger     `json:"trigger,omitempty"`
+	Conditions *[]AutomationCondition `json:"conditions,omitempty"`
+	Actions    *[]AutomationAction    `json:"actions,omitempty"`
+}
+
+type CreateChecklistItemRequest struct {
+	Title    string  `json:"title"`
+	Assignee *string `json:"assignee,omitempty"`
+}
+
+type UpdateChecklistItemRequest struct {
+	Title    *string `json:"title,omitempty"`
+	Checked  *bool   `json:"checked,omitempty"`
+	Assignee *string `json:"assignee,omitempty"`
+}
+
+type ReorderChecklistRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids"`
+}
+
+type DuplicateBoardRequest struct {
+	Name            string `json:"name,omitempty"`
+	IncludeTasks    bool   `json:"include_tasks"`
+	IncludeComments bool   `json:"include_comments"`
+	IncludeLabels   bool   `json:"include_labels"`
+	IncludeMembers  bool   `json:"include_members"`
+}
+
+const (
+	TransferModeMove = "move"
+	TransferModeCopy = "copy"
+)
+
+// TransferTaskRequest переносит или копирует задачу на другую доску.
+// Если Status пуст, статус подбирается по колонкам обеих досок.
+type TransferTaskRequest struct {
+	BoardID uuid.UUID `json:"board_id"`
+	Status  string    `json:"status,omitempty"`
+	Mode    string    `json:"mode,omitempty"`
+}
+
+type BulkMoveTasksRequest struct {
+	TaskIDs []uuid.UUID `json:"task_ids"`
+	Status  string      `json:"status"`
 }
 
 type CreateColumnRequest struct {
@@ -64,6 +851,7 @@ type CreateColumnRequest struct {
 	Title    string    `json:"title"`
 	StatusID string    `json:"status_id"`
 	Position int       `json:"position"`
+	WIPLimit *int      `json:"wip_limit,omitempty"`
 }
 
 type User struct {
@@ -73,6 +861,54 @@ type User struct {
 	PasswordHash string `json:"-" db:"password_hash"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// Role - системная роль (UserRole*), Status - состояние учетной записи
+	Role   string `json:"role" db:"role"`
+	Status string `json:"status" db:"status"`
+	// TokenVersion растет при отзыве токенов: токены с другой версией недействительны
+	TokenVersion int `json:"-" db:"token_version"`
+	// LockedUntil - окончание временной блокировки (status locked); пустое - до снятия администратором
+	LockedUntil *time.Time `json:"locked_until,omitempty" db:"locked_until"`
+	// EmailVerifiedAt - когда пользователь подтвердил email по ссылке из письма
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+}
+
+const (
+	UserRoleUser  = "user"
+	UserRoleAdmin = "admin"
+)
+
+const (
+	UserStatusActive   = "active"
+	UserStatusDisabled = "disabled"
+	UserStatusLocked   = "locked"
+)
+
+// UserToken - одноразовый токен из письма (подтверждение email, сброс
+// пароля). Хранится только SHA-256 токена, сам токен знает лишь получатель.
+type UserToken struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	Purpose   string     `json:"purpose" db:"purpose"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+const (
+	UserTokenVerifyEmail   = "verify_email"
+	UserTokenResetPassword = "reset_password"
+)
+
+// UserDataExport - все данные пользователя для выгрузки по запросу (GDPR)
+type UserDataExport struct {
+	ExportedAt  time.Time     `json:"exported_at"`
+	User        User          `json:"user"`
+	Boards      []Board       `json:"boards"`
+	Memberships []BoardMember `json:"memberships"`
+	Tasks       []Task        `json:"tasks"`
+	Comments    []TaskComment `json:"comments"`
+	Worklogs    []Worklog     `json:"worklogs"`
 }
 
 type LoginRequest struct {
@@ -86,8 +922,39 @@ type RegisterRequest struct {
 	Password string `json:"password"`
 }
 
-type AuthResponse struct {
+// UpdateUserRequest - изменение учетной записи администратором. LockedUntil
+// имеет смысл только для статуса locked; без него блокировка бессрочная.
+type UpdateUserRequest struct {
+	Email       *string    `json:"email,omitempty"`
+	Role        *string    `json:"role,omitempty"`
+	Status      *string    `json:"status,omitempty"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
+
+// EmailRequest - адрес для повторной отправки подтверждения или сброса пароля
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+type VerifyEmailRequest struct {
 	Token string `json:"token"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// DeleteAccountRequest подтверждает удаление своей учетной записи паролем
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+// AuthResponse без токена означает, что вход возможен только после подтверждения email
+type AuthResponse struct {
+	Token string `json:"token,omitempty"`
 	User  User   `json:"user"`
+	// VerificationRequired - на email отправлено письмо со ссылкой подтверждения
+	VerificationRequired bool `json:"verification_required,omitempty"`
 }
 
@@ -0,0 +1,144 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval - как часто Memory удаляет полные корзины и истекшие ключи
+const sweepInterval = time.Minute
+
+// Memory хранит состояние в памяти процесса: у каждой реплики свои лимиты.
+// Подходит для одной реплики и тестов.
+type Memory struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	buckets  map[string]*bucket
+	counters map[string]*counter
+	blocks   map[string]time.Time
+	swept    time.Time
+}
+
+type bucket struct {
+	tokens float64
+	// updated - время последнего пересчета, full - когда корзина наполнится
+	updated time.Time
+	full    time.Time
+}
+
+type counter struct {
+	value   int
+	expires time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		now:      time.Now,
+		buckets:  make(map[string]*bucket),
+		counters: make(map[string]*counter),
+		blocks:   make(map[string]time.Time),
+	}
+}
+
+func (m *Memory) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.sweep()
+
+	capacity := float64(limit.Capacity())
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		m.buckets[key] = b
+	}
+	elapsed := now.Sub(b.updated)
+	b.tokens = math.Min(capacity, b.tokens+float64(elapsed)/float64(limit.interval()))
+	b.updated = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	res := limit.result(allowed, b.tokens)
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+func (m *Memory) Incr(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.sweep()
+
+	c, ok := m.counters[key]
+	if !ok || expired(c.expires, now) {
+		c = &counter{}
+		m.counters[key] = c
+	}
+	c.value++
+	c.expires = time.Time{}
+	if ttl > 0 {
+		c.expires = now.Add(ttl)
+	}
+	return c.value, nil
+}
+
+func (m *Memory) Block(ctx context.Context, key string, d time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blocks[key] = m.sweep().Add(d)
+	return nil
+}
+
+func (m *Memory) Blocked(ctx context.Context, key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.sweep()
+	if until, ok := m.blocks[key]; ok && until.After(now) {
+		return until.Sub(now), nil
+	}
+	return 0, nil
+}
+
+func (m *Memory) Reset(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.buckets, key)
+		delete(m.counters, key)
+		delete(m.blocks, key)
+	}
+	return nil
+}
+
+// sweep не чаще sweepInterval удаляет состояние, которое больше ни на что
+// не влияет, и возвращает текущее время. Вызывается под mu.
+func (m *Memory) sweep() time.Time {
+	now := m.now()
+	if now.Sub(m.swept) < sweepInterval {
+		return now
+	}
+	m.swept = now
+	for key, b := range m.buckets {
+		if !b.full.After(now) {
+			delete(m.buckets, key)
+		}
+	}
+	for key, c := range m.counters {
+		if expired(c.expires, now) {
+			delete(m.counters, key)
+		}
+	}
+	for key, until := range m.blocks {
+		if !until.After(now) {
+			delete(m.blocks, key)
+		}
+	}
+	return now
+}
+
+// expired - истек ли срок expires; нулевой срок не истекает
+func expired(expires, now time.Time) bool {
+	return !expires.IsZero() && !expires.After(now)
+}
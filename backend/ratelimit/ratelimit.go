@@ -0,0 +1,180 @@
+// Package ratelimit ограничивает частоту запросов корзиной токенов и
+// блокирует ключи после повторяющихся неудач. Memory хранит состояние в
+// памяти процесса, Redis - общее для всех реплик.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit - корзина токенов: Burst запросов подряд, дальше Requests за Period.
+// Нулевой Limit ничего не ограничивает.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	// Burst - емкость корзины; 0 - равна Requests
+	Burst int
+}
+
+// ParseLimit разбирает лимит вида "10/1m" или "100/1h+20" (с емкостью
+// корзины после "+"); "off" и "0" отключают лимит
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "off" || value == "0" {
+		return Limit{}, nil
+	}
+	requests, rest, ok := strings.Cut(value, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid limit %q, expected N/duration", value)
+	}
+	period, burst, hasBurst := strings.Cut(rest, "+")
+
+	var limit Limit
+	var err error
+	if limit.Requests, err = strconv.Atoi(requests); err != nil || limit.Requests <= 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q: bad number of requests", value)
+	}
+	if limit.Period, err = time.ParseDuration(period); err != nil || limit.Period <= 0 {
+		return Limit{}, fmt.Errorf("invalid limit %q: bad period", value)
+	}
+	if hasBurst {
+		if limit.Burst, err = strconv.Atoi(burst); err != nil || limit.Burst <= 0 {
+			return Limit{}, fmt.Errorf("invalid limit %q: bad burst", value)
+		}
+	}
+	return limit, nil
+}
+
+func (l Limit) String() string {
+	if !l.Enabled() {
+		return "off"
+	}
+	if l.Burst > 0 && l.Burst != l.Requests {
+		return fmt.Sprintf("%d/%s+%d", l.Requests, l.Period, l.Burst)
+	}
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// Capacity - емкость корзины
+func (l Limit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// interval - время пополнения корзины на один токен
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// result описывает корзину, в которой после запроса осталось tokens токенов
+func (l Limit) result(allowed bool, tokens float64) Result {
+	interval := float64(l.interval())
+	res := Result{
+		Allowed:   allowed,
+		Limit:     l.Capacity(),
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration(math.Ceil((float64(l.Capacity()) - tokens) * interval)),
+	}
+	if !allowed {
+		res.RetryAfter = time.Duration(math.Ceil((1 - tokens) * interval))
+	}
+	return res
+}
+
+// Result - состояние корзины после попытки взять токен
+type Result struct {
+	Allowed bool
+	// Limit - емкость корзины, Remaining - сколько запросов еще можно сделать сразу
+	Limit     int
+	Remaining int
+	// Reset - через сколько корзина наполнится полностью
+	Reset time.Duration
+	// RetryAfter - через сколько появится токен, если запрос отклонен
+	RetryAfter time.Duration
+}
+
+// Store хранит корзины, счетчики неудач и блокировки по строковым ключам
+type Store interface {
+	// Take берет токен из корзины key с лимитом limit
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+	// Incr увеличивает счетчик key и возвращает его значение; счетчик
+	// сбрасывается, если ttl не было ни одного увеличения
+	Incr(ctx context.Context, key string, ttl time.Duration) (int, error)
+	// Block блокирует key на d
+	Block(ctx context.Context, key string, d time.Duration) error
+	// Blocked возвращает, сколько еще заблокирован key; 0 - не заблокирован
+	Blocked(ctx context.Context, key string) (time.Duration, error)
+	// Reset удаляет счетчики и блокировки keys
+	Reset(ctx context.Context, keys ...string) error
+}
+
+// Lockout - прогрессивная блокировка: после Threshold неудач подряд ключ
+// блокируется на Base, каждая следующая неудача удваивает срок до Max
+// (Max меньше Base - срок не растет).
+// Неудачи забываются через Window после последней (плюс Max, чтобы счетчик
+// пережил блокировку). Нулевой Lockout выключен.
+type Lockout struct {
+	Threshold int
+	Base      time.Duration
+	Max       time.Duration
+	Window    time.Duration
+}
+
+func (l Lockout) Enabled() bool {
+	return l.Threshold > 0 && l.Base > 0
+}
+
+// Duration - срок блокировки после failures неудач подряд
+func (l Lockout) Duration(failures int) time.Duration {
+	if !l.Enabled() || failures < l.Threshold {
+		return 0
+	}
+	d := l.Base
+	for i := l.Threshold; i < failures && d < l.Max; i++ {
+		d *= 2
+	}
+	return min(d, max(l.Max, l.Base))
+}
+
+// Remaining возвращает, сколько еще заблокирован key
+func (l Lockout) Remaining(ctx context.Context, store Store, key string) (time.Duration, error) {
+	if !l.Enabled() {
+		return 0, nil
+	}
+	return store.Blocked(ctx, "lock:"+key)
+}
+
+// Fail учитывает неудачу key и возвращает срок наложенной блокировки (0 - без блокировки)
+func (l Lockout) Fail(ctx context.Context, store Store, key string) (time.Duration, error) {
+	if !l.Enabled() {
+		return 0, nil
+	}
+	failures, err := store.Incr(ctx, "fail:"+key, l.Window+l.Max)
+	if err != nil {
+		return 0, err
+	}
+	d := l.Duration(failures)
+	if d > 0 {
+		err = store.Block(ctx, "lock:"+key, d)
+	}
+	return d, err
+}
+
+// Reset забывает неудачи key и снимает его блокировку
+func (l Lockout) Reset(ctx context.Context, store Store, key string) error {
+	if !l.Enabled() {
+		return nil
+	}
+	return store.Reset(ctx, "fail:"+key, "lock:"+key)
+}
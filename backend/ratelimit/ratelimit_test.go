@@ -0,0 +1,170 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("10/1m")
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Requests: 10, Period: time.Minute}, limit)
+	assert.Equal(t, 10, limit.Capacity())
+	assert.Equal(t, "10/1m0s", limit.String())
+
+	limit, err = ParseLimit(" 100/1h+20 ")
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Requests: 100, Period: time.Hour, Burst: 20}, limit)
+	assert.Equal(t, 20, limit.Capacity())
+
+	limit, err = ParseLimit("off")
+	require.NoError(t, err)
+	assert.False(t, limit.Enabled())
+
+	for _, value := range []string{"10", "x/1m", "10/soon", "0/1m", "10/-1m", "10/1m+0"} {
+		_, err := ParseLimit(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestLockoutDuration(t *testing.T) {
+	lockout := Lockout{Threshold: 3, Base: time.Minute, Max: 10 * time.Minute}
+	durations := make([]time.Duration, 0, 8)
+	for failures := 1; failures <= 8; failures++ {
+		durations = append(durations, lockout.Duration(failures))
+	}
+	assert.Equal(t, []time.Duration{
+		0, 0, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute,
+	}, durations)
+
+	assert.Equal(t, time.Minute, Lockout{Threshold: 1, Base: time.Minute}.Duration(5), "Expected no growth without Max")
+	assert.Zero(t, Lockout{}.Duration(100))
+}
+
+func TestMemory(t *testing.T) {
+	testStore(t, NewMemory())
+
+	store := NewMemory()
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+	limit := Limit{Requests: 2, Period: time.Minute}
+
+	for i := 0; i < 2; i++ {
+		res, err := store.Take(ctx, "refill", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	}
+	res, err := store.Take(ctx, "refill", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 30*time.Second, res.RetryAfter)
+	assert.Equal(t, time.Minute, res.Reset)
+
+	now = now.Add(30 * time.Second)
+	res, err = store.Take(ctx, "refill", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "Expected a token to be refilled after Period/Requests")
+	assert.Equal(t, 0, res.Remaining)
+
+	count, err := store.Incr(ctx, "counter", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	now = now.Add(59 * time.Second)
+	count, err = store.Incr(ctx, "counter", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count, "Expected each increment to extend the counter")
+	now = now.Add(time.Minute)
+	count, err = store.Incr(ctx, "counter", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count, "Expected the counter to expire")
+
+	lockout := Lockout{Threshold: 2, Base: time.Minute, Max: 4 * time.Minute, Window: 15 * time.Minute}
+	var locks []time.Duration
+	for i := 0; i < 4; i++ {
+		d, err := lockout.Fail(ctx, store, "login")
+		require.NoError(t, err)
+		locks = append(locks, d)
+	}
+	assert.Equal(t, []time.Duration{0, time.Minute, 2 * time.Minute, 4 * time.Minute}, locks)
+	remaining, err := lockout.Remaining(ctx, store, "login")
+	require.NoError(t, err)
+	assert.Equal(t, 4*time.Minute, remaining)
+
+	now = now.Add(4 * time.Minute)
+	remaining, err = lockout.Remaining(ctx, store, "login")
+	require.NoError(t, err)
+	assert.Zero(t, remaining)
+	d, err := lockout.Fail(ctx, store, "login")
+	require.NoError(t, err)
+	assert.Equal(t, 4*time.Minute, d, "Expected failures to be remembered after the lock ends")
+
+	require.NoError(t, lockout.Reset(ctx, store, "login"))
+	remaining, err = lockout.Remaining(ctx, store, "login")
+	require.NoError(t, err)
+	assert.Zero(t, remaining)
+	d, err = lockout.Fail(ctx, store, "login")
+	require.NoError(t, err)
+	assert.Zero(t, d)
+
+	now = now.Add(time.Hour)
+	store.Take(ctx, "trigger-sweep", limit)
+	assert.Len(t, store.buckets, 1, "Expected full buckets to be swept")
+	assert.Empty(t, store.counters)
+	assert.Empty(t, store.blocks)
+}
+
+// testStore проверяет общие для реализаций свойства Store в реальном времени
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+	prefix := "test:" + uuid.NewString()[:8] + ":"
+	limit := Limit{Requests: 3, Period: time.Hour}
+
+	for i := 2; i >= 0; i-- {
+		res, err := store.Take(ctx, prefix+"bucket", limit)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, i, res.Remaining)
+	}
+	res, err := store.Take(ctx, prefix+"bucket", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.InDelta(t, 20*time.Minute, res.RetryAfter, float64(time.Second))
+	assert.InDelta(t, time.Hour, res.Reset, float64(time.Second))
+
+	res, err = store.Take(ctx, prefix+"other", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "Expected buckets to be independent")
+
+	for i := 1; i <= 3; i++ {
+		count, err := store.Incr(ctx, prefix+"counter", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, i, count)
+	}
+
+	blocked, err := store.Blocked(ctx, prefix+"block")
+	require.NoError(t, err)
+	assert.Zero(t, blocked)
+	require.NoError(t, store.Block(ctx, prefix+"block", time.Minute))
+	blocked, err = store.Blocked(ctx, prefix+"block")
+	require.NoError(t, err)
+	assert.InDelta(t, time.Minute, blocked, float64(time.Second))
+
+	require.NoError(t, store.Reset(ctx, prefix+"counter", prefix+"block", prefix+"bucket"))
+	blocked, err = store.Blocked(ctx, prefix+"block")
+	require.NoError(t, err)
+	assert.Zero(t, blocked)
+	count, err := store.Incr(ctx, prefix+"counter", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	res, err = store.Take(ctx, prefix+"bucket", limit)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Remaining)
+}
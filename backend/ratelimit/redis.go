@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix отделяет ключи лимитов от кэша в той же базе Redis
+const keyPrefix = "ratelimit:"
+
+// takeScript атомарно пополняет корзину по времени сервера Redis (чтобы
+// часы реплик не влияли на лимит) и берет из нее токен. ARGV: емкость
+// корзины и время пополнения одного токена в миллисекундах (tostring в Lua
+// хранит 14 значащих цифр, микросекунд текущего времени больше). Возвращает
+// признак успеха и остаток токенов строкой: дробные числа Lua обрезает.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+	tokens = capacity
+	updated = now
+end
+tokens = math.min(capacity, tokens + math.max(0, now - updated) / interval)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) * interval) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// Redis хранит состояние в Redis, общем для всех реплик
+type Redis struct {
+	client *redis.Client
+}
+
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func (r *Redis) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	interval := float64(limit.interval()) / float64(time.Millisecond)
+	reply, err := takeScript.Run(ctx, r.client, []string{keyPrefix + key}, limit.Capacity(), interval).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	allowed, _ := reply[0].(int64)
+	tokens, err := strconv.ParseFloat(reply[1].(string), 64)
+	if err != nil {
+		return Result{}, err
+	}
+	return limit.result(allowed == 1, tokens), nil
+}
+
+func (r *Redis) Incr(ctx context.Context, key string, ttl time.Duration) (int, error) {
+	var incr *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, keyPrefix+key)
+		if ttl > 0 {
+			pipe.PExpire(ctx, keyPrefix+key, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
+
+func (r *Redis) Block(ctx context.Context, key string, d time.Duration) error {
+	return r.client.Set(ctx, keyPrefix+key, 1, d).Err()
+}
+
+func (r *Redis) Blocked(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.client.PTTL(ctx, keyPrefix+key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// PTTL отрицателен для отсутствующих ключей и ключей без срока; Block
+	// всегда задает срок, поэтому оба случая значат "не заблокирован"
+	return max(ttl, 0), nil
+}
+
+func (r *Redis) Reset(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = keyPrefix + key
+	}
+	return r.client.Del(ctx, prefixed...).Err()
+}
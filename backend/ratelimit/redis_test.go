@@ -0,0 +1,19 @@
+//go:build integration
+
+package ratelimit
+
+import (
+	"task-flow-backend/cache"
+	"testing"
+)
+
+// Для запуска требуется Redis (docker-compose up -d):
+// go test -tags integration ./ratelimit/
+func TestRedis(t *testing.T) {
+	if err := cache.Init(); err != nil {
+		t.Fatalf("failed to connect to Redis: %v", err)
+	}
+	t.Cleanup(func() { cache.Client.Close() })
+
+	testStore(t, NewRedis(cache.Client))
+}
@@ -0,0 +1,250 @@
+// Package recurrence разбирает и вычисляет правила повторения в формате
+// RRULE (RFC 5545). Поддерживается подмножество: FREQ=DAILY|WEEKLY|MONTHLY,
+// INTERVAL, BYDAY (с порядковым номером только для MONTHLY), UNTIL и COUNT.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// maxPeriods ограничивает перебор периодов, чтобы правило, которое больше
+// не дает дат (например, BYDAY=5MO с UNTIL), не перебиралось бесконечно
+const maxPeriods = 100000
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// Weekday - элемент BYDAY: день недели и, для MONTHLY, его номер в месяце
+// (1 - первый, -1 - последний, 0 - каждый)
+type Weekday struct {
+	Day time.Weekday
+	N   int
+}
+
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []Weekday
+	// Until включает последнюю дату; nil - без ограничения
+	Until *time.Time
+	// Count - число повторений начиная с первого; 0 - без ограничения
+	Count int
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Parse разбирает правило вида FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10.
+// Префикс RRULE: необязателен.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{Interval: 1}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		val = strings.ToUpper(strings.TrimSpace(val))
+		if !ok || val == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: duplicate %s", ErrInvalidRule, name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			rule.Freq = Frequency(val)
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly {
+				return nil, fmt.Errorf("%w: unsupported FREQ %s", ErrInvalidRule, val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL must be a positive integer", ErrInvalidRule)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT must be a positive integer", ErrInvalidRule)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for _, item := range strings.Split(val, ",") {
+				day, err := parseWeekday(item)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, name)
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+	for _, day := range rule.ByDay {
+		if day.N != 0 && rule.Freq != Monthly {
+			return nil, fmt.Errorf("%w: numbered BYDAY is only supported with FREQ=MONTHLY", ErrInvalidRule)
+		}
+	}
+	return rule, nil
+}
+
+// parseUntil принимает дату-время в UTC (20240131T090000Z) или дату
+// (20240131); дата включает весь день по UTC
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSSZ", ErrInvalidRule)
+}
+
+func parseWeekday(value string) (Weekday, error) {
+	if len(value) < 2 {
+		return Weekday{}, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRule, value)
+	}
+	day, ok := weekdays[value[len(value)-2:]]
+	if !ok {
+		return Weekday{}, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRule, value)
+	}
+
+	var n int
+	if prefix := value[:len(value)-2]; prefix != "" {
+		var err error
+		n, err = strconv.Atoi(prefix)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return Weekday{}, fmt.Errorf("%w: invalid BYDAY %q", ErrInvalidRule, value)
+		}
+	}
+	return Weekday{Day: day, N: n}, nil
+}
+
+// Next возвращает первое повторение позже after для серии, начинающейся
+// в start. Повторения получают время суток start и вычисляются в его часовом
+// поясе. false - правило больше не дает повторений.
+func (r *Rule) Next(start, after time.Time) (time.Time, bool) {
+	n := 0
+	for period := 0; period < maxPeriods; period++ {
+		for _, candidate := range r.expand(start, period) {
+			if candidate.Before(start) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return time.Time{}, false
+			}
+			n++
+			if r.Count > 0 && n > r.Count {
+				return time.Time{}, false
+			}
+			if candidate.After(after) {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// expand возвращает отсортированные даты периода с номером period
+func (r *Rule) expand(start time.Time, period int) []time.Time {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	}
+	step := period * r.Interval
+
+	var dates []time.Time
+	switch r.Freq {
+	case Daily:
+		date := at(start.Year(), start.Month(), start.Day()+step)
+		if len(r.ByDay) == 0 || r.hasWeekday(date.Weekday()) {
+			dates = append(dates, date)
+		}
+
+	case Weekly:
+		// Неделя начинается с понедельника (WKST=MO)
+		offset := (int(start.Weekday()) + 6) % 7
+		monday := at(start.Year(), start.Month(), start.Day()-offset+7*step)
+		if len(r.ByDay) == 0 {
+			dates = append(dates, monday.AddDate(0, 0, offset))
+		}
+		for _, day := range r.ByDay {
+			dates = append(dates, monday.AddDate(0, 0, (int(day.Day)+6)%7))
+		}
+
+	case Monthly:
+		first := at(start.Year(), start.Month()+time.Month(step), 1)
+		if len(r.ByDay) == 0 {
+			// Месяцы без такого числа (31-е) пропускаются, как в RFC 5545
+			if date := first.AddDate(0, 0, start.Day()-1); date.Month() == first.Month() {
+				dates = append(dates, date)
+			}
+		}
+		for _, day := range r.ByDay {
+			dates = append(dates, monthWeekdays(first, day)...)
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates
+}
+
+func (r *Rule) hasWeekday(day time.Weekday) bool {
+	for _, d := range r.ByDay {
+		if d.Day == day {
+			return true
+		}
+	}
+	return false
+}
+
+// monthWeekdays возвращает дни недели day.Day месяца, начинающегося с first:
+// все или только day.N-й с начала (с конца для отрицательного N)
+func monthWeekdays(first time.Time, day Weekday) []time.Time {
+	var all []time.Time
+	for date := first.AddDate(0, 0, (int(day.Day)-int(first.Weekday())+7)%7); date.Month() == first.Month(); date = date.AddDate(0, 0, 7) {
+		all = append(all, date)
+	}
+
+	switch {
+	case day.N == 0:
+		return all
+	case day.N > 0 && day.N <= len(all):
+		return all[day.N-1 : day.N]
+	case day.N < 0 && -day.N <= len(all):
+		return all[len(all)+day.N : len(all)+day.N+1]
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// occurrences возвращает до limit первых повторений серии
+func occurrences(t *testing.T, rrule string, start time.Time, limit int) []string {
+	t.Helper()
+
+	rule, err := Parse(rrule)
+	require.NoError(t, err)
+
+	var dates []string
+	after := start.Add(-time.Nanosecond)
+	for len(dates) < limit {
+		next, ok := rule.Next(start, after)
+		if !ok {
+			break
+		}
+		dates = append(dates, next.Format("2006-01-02 Mon 15:04"))
+		after = next
+	}
+	return dates
+}
+
+func TestNext(t *testing.T) {
+	// Среда, 10 января 2024
+	start := time.Date(2024, 1, 10, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		rrule string
+		want  []string
+	}{
+		{"Daily", "FREQ=DAILY;COUNT=3", []string{
+			"2024-01-10 Wed 09:30", "2024-01-11 Thu 09:30", "2024-01-12 Fri 09:30",
+		}},
+		{"Daily on weekdays", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", []string{
+			"2024-01-10 Wed 09:30", "2024-01-11 Thu 09:30", "2024-01-12 Fri 09:30", "2024-01-15 Mon 09:30",
+		}},
+		{"Every second week", "RRULE:FREQ=WEEKLY;INTERVAL=2", []string{
+			"2024-01-10 Wed 09:30", "2024-01-24 Wed 09:30", "2024-02-07 Wed 09:30",
+		}},
+		{"Weekly by day skips days before start", "FREQ=WEEKLY;BYDAY=MO,FR", []string{
+			"2024-01-12 Fri 09:30", "2024-01-15 Mon 09:30", "2024-01-19 Fri 09:30",
+		}},
+		{"Weekly until", "FREQ=WEEKLY;BYDAY=MO;UNTIL=20240122", []string{
+			"2024-01-15 Mon 09:30", "2024-01-22 Mon 09:30",
+		}},
+		{"Monthly by month day", "FREQ=MONTHLY;COUNT=2", []string{
+			"2024-01-10 Wed 09:30", "2024-02-10 Sat 09:30",
+		}},
+		{"First Monday of the month", "FREQ=MONTHLY;BYDAY=1MO", []string{
+			"2024-02-05 Mon 09:30", "2024-03-04 Mon 09:30", "2024-04-01 Mon 09:30",
+		}},
+		{"Last Friday of every quarter", "FREQ=MONTHLY;INTERVAL=3;BYDAY=-1FR", []string{
+			"2024-01-26 Fri 09:30", "2024-04-26 Fri 09:30", "2024-07-26 Fri 09:30",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, occurrences(t, tt.rrule, start, len(tt.want)))
+		})
+	}
+
+	t.Run("Count and until end the series", func(t *testing.T) {
+		assert.Len(t, occurrences(t, "FREQ=DAILY;COUNT=3", start, 10), 3)
+		assert.Len(t, occurrences(t, "FREQ=WEEKLY;BYDAY=MO;UNTIL=20240122", start, 10), 2)
+	})
+
+	t.Run("Months without the day are skipped", func(t *testing.T) {
+		start := time.Date(2024, 1, 31, 8, 0, 0, 0, time.UTC)
+		assert.Equal(t, []string{"2024-01-31 Wed 08:00", "2024-03-31 Sun 08:00", "2024-05-31 Fri 08:00"},
+			occurrences(t, "FREQ=MONTHLY", start, 3))
+	})
+
+	t.Run("Time of day is kept across DST", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		if err != nil {
+			t.Skip("tzdata is not available")
+		}
+		start := time.Date(2024, 3, 29, 9, 0, 0, 0, berlin)
+		assert.Equal(t, []string{"2024-03-29 Fri 09:00", "2024-04-05 Fri 09:00"},
+			occurrences(t, "FREQ=WEEKLY", start, 2))
+	})
+
+	t.Run("Next after a later moment", func(t *testing.T) {
+		rule, err := Parse("FREQ=WEEKLY;BYDAY=MO")
+		require.NoError(t, err)
+		next, ok := rule.Next(start, time.Date(2024, 6, 3, 9, 30, 0, 0, time.UTC))
+		require.True(t, ok)
+		assert.Equal(t, time.Date(2024, 6, 10, 9, 30, 0, 0, time.UTC), next)
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, rrule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=1",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;UNTIL=tomorrow",
+	} {
+		_, err := Parse(rrule)
+		assert.ErrorIs(t, err, ErrInvalidRule, rrule)
+	}
+}
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrAnalyticsColumns = errors.New("start column must not be to the right of the done column")
+
+// AnalyticsOptions задает период [From, To) и колонки начала работы и
+// завершения. Пустой StartStatus - вторая колонка доски (или первая, если
+// она одна), пустой DoneStatus - последняя.
+type AnalyticsOptions struct {
+	From        time.Time
+	To          time.Time
+	StartStatus string
+	DoneStatus  string
+	// Now - момент расчета возраста незавершенных задач
+	Now time.Time
+}
+
+// flowTask - задача доски с историей ее статусов на этой доске
+type flowTask struct {
+	task    models.Task
+	changes []models.TaskStatusChange
+}
+
+// BoardAnalytics считает метрики потока доски по истории статусов задач.
+// Учитываются задачи, которые сейчас находятся на доске, включая архивные.
+func (r Repositories) BoardAnalytics(ctx context.Context, boardID uuid.UUID, opts AnalyticsOptions) (*models.BoardAnalytics, error) {
+	if _, err := r.Boards.GetByID(ctx, boardID); err != nil {
+		return nil, err
+	}
+	columns, err := r.Columns.ListByBoard(ctx, boardID, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := &models.BoardAnalytics{
+		BoardID:        boardID,
+		From:           opts.From,
+		To:             opts.To,
+		Statuses:       []string{},
+		CumulativeFlow: []models.CumulativeFlowDay{},
+		Throughput:     []models.ThroughputWeek{},
+		Completed:      []models.CompletedTask{},
+		AgingWIP:       []models.AgingTask{},
+	}
+	position := make(map[string]int, len(columns))
+	for i, column := range columns {
+		analytics.Statuses = append(analytics.Statuses, column.StatusID)
+		position[column.StatusID] = i
+	}
+	if len(columns) == 0 {
+		return analytics, nil
+	}
+
+	analytics.StartStatus, analytics.DoneStatus = opts.StartStatus, opts.DoneStatus
+	if analytics.StartStatus == "" {
+		analytics.StartStatus = columns[min(1, len(columns)-1)].StatusID
+	}
+	if analytics.DoneStatus == "" {
+		analytics.DoneStatus = doneStatus(columns)
+	}
+	for _, status := range []string{analytics.StartStatus, analytics.DoneStatus} {
+		if !hasStatus(columns, status) {
+			return nil, &TransitionError{Err: ErrUnknownStatus, To: status, Allowed: analytics.Statuses}
+		}
+	}
+	startPos, donePos := position[analytics.StartStatus], position[analytics.DoneStatus]
+	if startPos > donePos {
+		return nil, ErrAnalyticsColumns
+	}
+
+	tasks, err := r.flowTasks(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics.CumulativeFlow = cumulativeFlow(tasks, analytics.Statuses, opts)
+
+	var leadTimes, cycleTimes []float64
+	weeks := map[string]int{}
+	for _, item := range tasks {
+		current := item.changes[len(item.changes)-1]
+		createdAt := item.changes[0].ChangedAt
+		startedAt, started := firstEntry(item.changes, position, startPos)
+
+		if current.ToStatus == analytics.DoneStatus {
+			doneAt := current.ChangedAt
+			if doneAt.Before(opts.From) || !doneAt.Before(opts.To) {
+				continue
+			}
+			completed := models.CompletedTask{
+				TaskID:        item.task.ID,
+				Title:         item.task.Title,
+				CreatedAt:     createdAt,
+				StartedAt:     startedAt,
+				DoneAt:        doneAt,
+				LeadTimeDays:  days(doneAt.Sub(createdAt)),
+				CycleTimeDays: days(doneAt.Sub(startedAt)),
+			}
+			analytics.Completed = append(analytics.Completed, completed)
+			leadTimes = append(leadTimes, completed.LeadTimeDays)
+			cycleTimes = append(cycleTimes, completed.CycleTimeDays)
+			weeks[weekStart(doneAt).Format(time.DateOnly)]++
+			continue
+		}
+
+		pos, ok := position[current.ToStatus]
+		if !ok || pos < startPos || pos >= donePos || item.task.ArchivedAt != nil || !started {
+			continue
+		}
+		analytics.AgingWIP = append(analytics.AgingWIP, models.AgingTask{
+			TaskID:    item.task.ID,
+			Title:     item.task.Title,
+			Status:    current.ToStatus,
+			StartedAt: startedAt,
+			AgeDays:   days(opts.Now.Sub(startedAt)),
+		})
+	}
+
+	analytics.LeadTime = flowTimeStats(leadTimes)
+	analytics.CycleTime = flowTimeStats(cycleTimes)
+	for week := weekStart(opts.From); week.Before(opts.To); week = week.AddDate(0, 0, 7) {
+		key := week.Format(time.DateOnly)
+		analytics.Throughput = append(analytics.Throughput, models.ThroughputWeek{WeekStart: key, Count: weeks[key]})
+	}
+	sort.Slice(analytics.Completed, func(i, j int) bool {
+		return analytics.Completed[i].DoneAt.Before(analytics.Completed[j].DoneAt)
+	})
+	sort.Slice(analytics.AgingWIP, func(i, j int) bool {
+		return analytics.AgingWIP[i].AgeDays > analytics.AgingWIP[j].AgeDays
+	})
+
+	return analytics, nil
+}
+
+// flowTasks группирует историю статусов доски по задачам. Задачи без
+// истории (созданные до ее появления) получают запись о текущем статусе
+// в момент создания, как при миграции.
+func (r Repositories) flowTasks(ctx context.Context, boardID uuid.UUID) ([]flowTask, error) {
+	tasks, err := r.Tasks.ListByBoard(ctx, boardID, ListOptions{IncludeArchived: true})
+	if err != nil {
+		return nil, err
+	}
+	changes, err := r.Tasks.ListStatusChanges(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	byTask := make(map[uuid.UUID][]models.TaskStatusChange)
+	for _, change := range changes {
+		byTask[change.TaskID] = append(byTask[change.TaskID], change)
+	}
+
+	flow := make([]flowTask, 0, len(tasks))
+	for _, task := range tasks {
+		history := byTask[task.ID]
+		if len(history) == 0 {
+			history = []models.TaskStatusChange{{TaskID: task.ID, BoardID: boardID, ToStatus: task.Status, ChangedAt: task.CreatedAt}}
+		}
+		flow = append(flow, flowTask{task: task, changes: history})
+	}
+	return flow, nil
+}
+
+// cumulativeFlow считает задачи в каждом статусе на конец каждого дня периода
+func cumulativeFlow(tasks []flowTask, statuses []string, opts AnalyticsOptions) []models.CumulativeFlowDay {
+	flow := []models.CumulativeFlowDay{}
+	for day := opts.From.UTC().Truncate(24 * time.Hour); day.Before(opts.To); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		if end.After(opts.Now) {
+			end = opts.Now
+		}
+		counts := make(map[string]int, len(statuses))
+		for _, status := range statuses {
+			counts[status] = 0
+		}
+		for _, item := range tasks {
+			status := ""
+			for _, change := range item.changes {
+				if change.ChangedAt.After(end) {
+					break
+				}
+				status = change.ToStatus
+			}
+			if _, ok := counts[status]; ok {
+				counts[status]++
+			}
+		}
+		flow = append(flow, models.CumulativeFlowDay{Date: day.Format(time.DateOnly), Counts: counts})
+	}
+	return flow
+}
+
+// firstEntry возвращает время первого попадания задачи в колонку не левее startPos
+func firstEntry(changes []models.TaskStatusChange, position map[string]int, startPos int) (time.Time, bool) {
+	for _, change := range changes {
+		if pos, ok := position[change.ToStatus]; ok && pos >= startPos {
+			return change.ChangedAt, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func flowTimeStats(values []float64) models.FlowTimeStats {
+	stats := models.FlowTimeStats{Count: len(values)}
+	if len(values) == 0 {
+		return stats
+	}
+
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	var total float64
+	for _, value := range sorted {
+		total += value
+	}
+	stats.Average = round2(total / float64(len(sorted)))
+	stats.P50 = percentile(sorted, 50)
+	stats.P75 = percentile(sorted, 75)
+	stats.P85 = percentile(sorted, 85)
+	stats.P95 = percentile(sorted, 95)
+	return stats
+}
+
+// percentile считает перцентиль p отсортированных значений с линейной интерполяцией
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	value := sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+	return round2(value)
+}
+
+// weekStart возвращает начало недели (понедельник, UTC) для t
+func weekStart(t time.Time) time.Time {
+	day := t.UTC().Truncate(24 * time.Hour)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func days(d time.Duration) float64 {
+	return round2(d.Hours() / 24)
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"task-flow-backend/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlowTimeStats(t *testing.T) {
+	stats := flowTimeStats([]float64{4, 1, 3, 2})
+	assert.Equal(t, 4, stats.Count)
+	assert.Equal(t, 2.5, stats.Average)
+	assert.Equal(t, 2.5, stats.P50)
+	assert.Equal(t, 3.25, stats.P75)
+	assert.Equal(t, 3.55, stats.P85)
+
+	assert.Equal(t, models.FlowTimeStats{}, flowTimeStats(nil))
+	assert.Equal(t, 7.0, flowTimeStats([]float64{7}).P95)
+}
+
+func TestCumulativeFlow(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	change := func(status string, offset time.Duration) models.TaskStatusChange {
+		return models.TaskStatusChange{ToStatus: status, ChangedAt: day.Add(offset)}
+	}
+	tasks := []flowTask{
+		{changes: []models.TaskStatusChange{change("plan", time.Hour), change("done", 30*time.Hour)}},
+		{changes: []models.TaskStatusChange{change("plan", 26*time.Hour)}},
+	}
+	opts := AnalyticsOptions{From: day, To: day.AddDate(0, 0, 3), Now: day.Add(50 * time.Hour)}
+
+	flow := cumulativeFlow(tasks, []string{"plan", "done"}, opts)
+	require.Len(t, flow, 3)
+	assert.Equal(t, "2026-03-02", flow[0].Date)
+	assert.Equal(t, map[string]int{"plan": 1, "done": 0}, flow[0].Counts)
+	assert.Equal(t, map[string]int{"plan": 1, "done": 1}, flow[1].Counts)
+	assert.Equal(t, map[string]int{"plan": 1, "done": 1}, flow[2].Counts, "Expected the current day to end now")
+}
+
+func TestWeekStart(t *testing.T) {
+	sunday := time.Date(2026, 3, 8, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), weekStart(sunday))
+	monday := time.Date(2026, 3, 9, 1, 0, 0, 0, time.UTC)
+	assert.Equal(t, monday.Truncate(24*time.Hour), weekStart(monday))
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"task-flow-backend/models"
+)
+
+// AddAttachment сохраняет вложение вместе с учетом его файла. blob - только
+// что записанный в хранилище файл; nil означает, что файл с
+// attachment.Checksum уже учтен. Если такой файл успели удалить как
+// неиспользуемый, возвращается ErrNotFound и файл нужно записать заново.
+func (r Repositories) AddAttachment(ctx context.Context, attachment *models.Attachment, blob *models.AttachmentBlob) error {
+	return r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if blob != nil {
+			if err := r.Attachments.CreateBlob(ctx, blob); err != nil {
+				return err
+			}
+		}
+
+		stored, err := r.Attachments.GetBlob(ctx, attachment.Checksum)
+		if err != nil {
+			return err
+		}
+		attachment.Size = stored.Size
+		attachment.HasThumbnail = stored.HasThumbnail
+
+		return r.Attachments.Create(ctx, attachment)
+	})
+}
+
+// ReleaseBlob удаляет учет файла, если на него не осталось вложений.
+// Возвращает true, если файл можно удалить из хранилища.
+func (r Repositories) ReleaseBlob(ctx context.Context, checksum string) (bool, error) {
+	err := r.Attachments.DeleteBlob(ctx, checksum)
+	if errors.Is(err, ErrConflict) || errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,347 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// burndownTask - задача, побывавшая в спринте, с историей ее участия в
+// спринте и статусов на доске
+type burndownTask struct {
+	scope    []models.SprintScopeChange
+	statuses []models.TaskStatusChange
+}
+
+// sprintTotals - объем и выполнение спринта в момент времени
+type sprintTotals struct {
+	scopePoints     int
+	completedPoints int
+	scopeTasks      int
+	completedTasks  int
+}
+
+// SprintBurndown строит burndown спринта по дням (UTC) с первого дня до
+// конца, завершения спринта или now. Прошедшие дни берутся из ночных
+// снимков, а дни без снимка считаются по истории состава и статусов;
+// текущий день - по текущему состоянию задач. ErrSprintState, если у
+// спринта нет начала или конца.
+func (r Repositories) SprintBurndown(ctx context.Context, sprintID uuid.UUID, now time.Time) (*models.SprintBurndown, error) {
+	sprint, err := r.Sprints.GetByID(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.StartsAt == nil || sprint.EndsAt == nil {
+		return nil, fmt.Errorf("%w: sprint has not started", ErrSprintState)
+	}
+	done, err := r.sprintDoneStatus(ctx, sprint)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := r.Sprints.ListScopeChanges(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := r.Sprints.ListSnapshots(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := sprint.StartsAt.UTC(), sprint.EndsAt.UTC()
+	cutoff := burndownCutoff(sprint, now)
+	initial := burndownTotals(groupScope(scope), "", start)
+	burndown := &models.SprintBurndown{
+		SprintID:      sprint.ID,
+		StartsAt:      start,
+		EndsAt:        end,
+		DoneStatus:    done,
+		InitialPoints: initial.scopePoints,
+		InitialTasks:  initial.scopeTasks,
+		Days:          []models.BurndownDay{},
+		Ideal:         idealLine(start, end, initial.scopePoints),
+		ScopeChanges:  scopeMarkers(scope, start, cutoff),
+	}
+
+	stored := make(map[string]models.SprintSnapshot, len(snapshots))
+	for _, snapshot := range snapshots {
+		stored[snapshot.Day.UTC().Format(time.DateOnly)] = snapshot
+	}
+
+	// История загружается, только если каких-то прошедших дней нет в снимках
+	var history []burndownTask
+	loaded := false
+	for d := start.Truncate(24 * time.Hour); d.Before(cutoff); d = d.AddDate(0, 0, 1) {
+		date := d.Format(time.DateOnly)
+		dayEnd := d.AddDate(0, 0, 1)
+		var totals sprintTotals
+		snapshot, ok := stored[date]
+		switch {
+		case ok:
+			totals = sprintTotals{snapshot.ScopePoints, snapshot.CompletedPoints, snapshot.ScopeTasks, snapshot.CompletedTasks}
+		case dayEnd.After(now) && !cutoff.Before(now):
+			totals, err = r.currentTotals(ctx, sprint.ID, done)
+		default:
+			if !loaded {
+				history, err = r.sprintHistory(ctx, sprint, scope)
+				loaded = true
+			}
+			totals = burndownTotals(history, done, earliest(dayEnd, cutoff))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		burndown.Days = append(burndown.Days, models.BurndownDay{
+			Date:            date,
+			ScopePoints:     totals.scopePoints,
+			CompletedPoints: totals.completedPoints,
+			RemainingPoints: totals.scopePoints - totals.completedPoints,
+			ScopeTasks:      totals.scopeTasks,
+			CompletedTasks:  totals.completedTasks,
+			RemainingTasks:  totals.scopeTasks - totals.completedTasks,
+			Snapshot:        ok,
+		})
+	}
+
+	return burndown, nil
+}
+
+// SnapshotSprint сохраняет снимки прошедших дней спринта, которых еще нет,
+// и возвращает их число. День считается прошедшим, когда наступила
+// полночь UTC после него.
+func (r Repositories) SnapshotSprint(ctx context.Context, sprintID uuid.UUID, now time.Time) (int, error) {
+	sprint, err := r.Sprints.GetByID(ctx, sprintID)
+	if err != nil {
+		return 0, err
+	}
+	if sprint.StartsAt == nil || sprint.EndsAt == nil {
+		return 0, nil
+	}
+	existing, err := r.Sprints.ListSnapshots(ctx, sprintID)
+	if err != nil {
+		return 0, err
+	}
+	stored := make(map[time.Time]bool, len(existing))
+	for _, snapshot := range existing {
+		stored[snapshot.Day.UTC().Truncate(24*time.Hour)] = true
+	}
+
+	cutoff := burndownCutoff(sprint, now)
+	var missing []time.Time
+	for d := sprint.StartsAt.UTC().Truncate(24 * time.Hour); d.Before(cutoff) && !d.AddDate(0, 0, 1).After(now); d = d.AddDate(0, 0, 1) {
+		if !stored[d] {
+			missing = append(missing, d)
+		}
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+
+	done, err := r.sprintDoneStatus(ctx, sprint)
+	if err != nil {
+		return 0, err
+	}
+	scope, err := r.Sprints.ListScopeChanges(ctx, sprintID)
+	if err != nil {
+		return 0, err
+	}
+	history, err := r.sprintHistory(ctx, sprint, scope)
+	if err != nil {
+		return 0, err
+	}
+
+	snapshots := make([]models.SprintSnapshot, 0, len(missing))
+	for _, d := range missing {
+		totals := burndownTotals(history, done, earliest(d.AddDate(0, 0, 1), cutoff))
+		snapshots = append(snapshots, models.SprintSnapshot{
+			SprintID:        sprintID,
+			Day:             d,
+			ScopePoints:     totals.scopePoints,
+			CompletedPoints: totals.completedPoints,
+			ScopeTasks:      totals.scopeTasks,
+			CompletedTasks:  totals.completedTasks,
+		})
+	}
+	if err := r.Sprints.SaveSnapshots(ctx, snapshots); err != nil {
+		return 0, err
+	}
+	return len(snapshots), nil
+}
+
+// sprintDoneStatus - статус последней колонки доски спринта
+func (r Repositories) sprintDoneStatus(ctx context.Context, sprint *models.Sprint) (string, error) {
+	columns, err := r.Columns.ListByBoard(ctx, sprint.BoardID, ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	return doneStatus(columns), nil
+}
+
+// currentTotals считает объем спринта по текущему состоянию его задач
+func (r Repositories) currentTotals(ctx context.Context, sprintID uuid.UUID, done string) (sprintTotals, error) {
+	tasks, err := r.Tasks.ListBySprint(ctx, sprintID)
+	if err != nil {
+		return sprintTotals{}, err
+	}
+	var totals sprintTotals
+	for _, task := range tasks {
+		points := 0
+		if task.StoryPoints != nil {
+			points = *task.StoryPoints
+		}
+		totals.scopeTasks++
+		totals.scopePoints += points
+		if task.Status == done {
+			totals.completedTasks++
+			totals.completedPoints += points
+		}
+	}
+	return totals, nil
+}
+
+// sprintHistory объединяет историю состава спринта с историей статусов
+// его задач на доске спринта
+func (r Repositories) sprintHistory(ctx context.Context, sprint *models.Sprint, scope []models.SprintScopeChange) ([]burndownTask, error) {
+	changes, err := r.Tasks.ListStatusChanges(ctx, sprint.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	tasks := groupScope(scope)
+	index := make(map[uuid.UUID]int, len(tasks))
+	for i, task := range tasks {
+		index[task.scope[0].TaskID] = i
+	}
+	for _, change := range changes {
+		if i, ok := index[change.TaskID]; ok {
+			tasks[i].statuses = append(tasks[i].statuses, change)
+		}
+	}
+	return tasks, nil
+}
+
+// groupScope группирует упорядоченную по времени историю состава по задачам
+func groupScope(scope []models.SprintScopeChange) []burndownTask {
+	var tasks []burndownTask
+	index := make(map[uuid.UUID]int)
+	for _, change := range scope {
+		i, ok := index[change.TaskID]
+		if !ok {
+			i = len(tasks)
+			index[change.TaskID] = i
+			tasks = append(tasks, burndownTask{})
+		}
+		tasks[i].scope = append(tasks[i].scope, change)
+	}
+	return tasks
+}
+
+// burndownTotals считает объем и выполнение спринта в момент at. Задача
+// выполнена, если в этот момент она в статусе done.
+func burndownTotals(tasks []burndownTask, done string, at time.Time) sprintTotals {
+	var totals sprintTotals
+	for _, task := range tasks {
+		inSprint, points := false, 0
+		for _, change := range task.scope {
+			if change.ChangedAt.After(at) {
+				break
+			}
+			inSprint = change.Change != models.SprintScopeRemoved
+			points = 0
+			if change.Points != nil {
+				points = *change.Points
+			}
+		}
+		if !inSprint {
+			continue
+		}
+		totals.scopeTasks++
+		totals.scopePoints += points
+
+		status := ""
+		for _, change := range task.statuses {
+			if change.ChangedAt.After(at) {
+				break
+			}
+			status = change.ToStatus
+		}
+		if done != "" && status == done {
+			totals.completedTasks++
+			totals.completedPoints += points
+		}
+	}
+	return totals
+}
+
+// burndownCutoff - момент последнего состояния спринта: конец, завершение
+// или now, что наступит раньше
+func burndownCutoff(sprint *models.Sprint, now time.Time) time.Time {
+	cutoff := sprint.EndsAt.UTC()
+	if sprint.CompletedAt != nil && sprint.CompletedAt.Before(cutoff) {
+		cutoff = sprint.CompletedAt.UTC()
+	}
+	if now.Before(cutoff) {
+		cutoff = now.UTC()
+	}
+	return cutoff
+}
+
+func earliest(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+// idealLine равномерно сжигает начальный объем points к концу каждого дня
+// спринта, до нуля в последний день
+func idealLine(start, end time.Time, points int) []models.BurndownIdealDay {
+	var days []time.Time
+	for d := start.Truncate(24 * time.Hour); d.Before(end); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+	ideal := make([]models.BurndownIdealDay, 0, len(days))
+	for i, d := range days {
+		remaining := float64(points) * float64(len(days)-i-1) / float64(len(days))
+		ideal = append(ideal, models.BurndownIdealDay{Date: d.Format(time.DateOnly), RemainingPoints: round2(remaining)})
+	}
+	return ideal
+}
+
+// scopeMarkers возвращает изменения объема после старта спринта (start, cutoff]
+// с изменением story points: добавление и удаление задачи меняют объем на
+// ее оценку, переоценка - на разницу оценок.
+func scopeMarkers(scope []models.SprintScopeChange, start, cutoff time.Time) []models.BurndownScopeChange {
+	markers := []models.BurndownScopeChange{}
+	points := make(map[uuid.UUID]int)
+	for _, change := range scope {
+		value := 0
+		if change.Points != nil {
+			value = *change.Points
+		}
+		previous := points[change.TaskID]
+		points[change.TaskID] = value
+		if !change.ChangedAt.After(start) || change.ChangedAt.After(cutoff) {
+			continue
+		}
+
+		delta := value
+		switch change.Change {
+		case models.SprintScopeRemoved:
+			delta = -value
+		case models.SprintScopePoints:
+			delta = value - previous
+		}
+		markers = append(markers, models.BurndownScopeChange{
+			Date:        change.ChangedAt.UTC().Format(time.DateOnly),
+			ChangedAt:   change.ChangedAt,
+			TaskID:      change.TaskID,
+			Title:       change.TaskTitle,
+			Change:      change.Change,
+			PointsDelta: delta,
+		})
+	}
+	return markers
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"task-flow-backend/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBurndownTotals(t *testing.T) {
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	points := func(value int) *int { return &value }
+	scope := func(change string, value *int, offset time.Duration) models.SprintScopeChange {
+		return models.SprintScopeChange{Change: change, Points: value, ChangedAt: day.Add(offset)}
+	}
+	status := func(status string, offset time.Duration) models.TaskStatusChange {
+		return models.TaskStatusChange{ToStatus: status, ChangedAt: day.Add(offset)}
+	}
+	tasks := []burndownTask{
+		{
+			scope:    []models.SprintScopeChange{scope("added", points(3), 0), scope("points", points(5), 30*time.Hour)},
+			statuses: []models.TaskStatusChange{status("plan", 0), status("done", 40*time.Hour)},
+		},
+		{
+			scope:    []models.SprintScopeChange{scope("added", nil, time.Hour), scope("removed", nil, 26*time.Hour)},
+			statuses: []models.TaskStatusChange{status("plan", 0)},
+		},
+	}
+
+	assert.Equal(t, sprintTotals{scopePoints: 3, scopeTasks: 2}, burndownTotals(tasks, "done", day.Add(24*time.Hour)))
+	assert.Equal(t, sprintTotals{scopePoints: 5, scopeTasks: 1}, burndownTotals(tasks, "done", day.Add(36*time.Hour)))
+	assert.Equal(t, sprintTotals{scopePoints: 5, completedPoints: 5, scopeTasks: 1, completedTasks: 1},
+		burndownTotals(tasks, "done", day.Add(48*time.Hour)))
+}
+
+func TestIdealLine(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	ideal := idealLine(start, start.Add(4*24*time.Hour), 10)
+	require.Len(t, ideal, 5, "Expected the partial last day to be included")
+	assert.Equal(t, "2026-03-02", ideal[0].Date)
+	assert.Equal(t, 8.0, ideal[0].RemainingPoints)
+	assert.Equal(t, 0.0, ideal[4].RemainingPoints)
+}
+
+func TestScopeMarkers(t *testing.T) {
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	taskID := uuid.New()
+	points := func(value int) *int { return &value }
+	scope := []models.SprintScopeChange{
+		{TaskID: taskID, Change: "added", Points: points(3), ChangedAt: start.Add(-time.Hour)},
+		{TaskID: taskID, Change: "points", Points: points(5), ChangedAt: start.Add(time.Hour)},
+		{TaskID: taskID, Change: "removed", Points: points(5), ChangedAt: start.Add(2 * time.Hour)},
+		{TaskID: taskID, Change: "added", Points: points(5), ChangedAt: start.Add(48 * time.Hour)},
+	}
+
+	markers := scopeMarkers(scope, start, start.Add(24*time.Hour))
+	require.Len(t, markers, 2, "Expected changes before the start and after the cutoff to be skipped")
+	assert.Equal(t, 2, markers[0].PointsDelta)
+	assert.Equal(t, -5, markers[1].PointsDelta)
+}
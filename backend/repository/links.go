@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"task-flow-backend/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidLink = errors.New("link type must be blocks, relates_to or duplicates and tasks must differ")
+	ErrLinkCycle   = errors.New("blocking link would create a dependency cycle")
+	ErrTaskBlocked = errors.New("task is blocked by unfinished tasks")
+)
+
+// LinkTasks создает связь link. Для blocks проверяется, что целевая задача
+// еще не блокирует исходную (напрямую или через цепочку); relates_to и
+// duplicates считаются повтором, если такая же связь есть в обратную сторону.
+func (r Repositories) LinkTasks(ctx context.Context, link *models.TaskLink) error {
+	switch link.Type {
+	case models.LinkTypeBlocks, models.LinkTypeRelatesTo, models.LinkTypeDuplicates:
+	default:
+		return ErrInvalidLink
+	}
+	if link.SourceTaskID == link.TargetTaskID {
+		return ErrInvalidLink
+	}
+
+	return r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.Tasks.GetByID(ctx, link.SourceTaskID); err != nil {
+			return err
+		}
+		if _, err := r.Tasks.GetByID(ctx, link.TargetTaskID); err != nil {
+			return err
+		}
+
+		if link.Type == models.LinkTypeBlocks {
+			reachable, err := r.blocksPath(ctx, link.TargetTaskID, link.SourceTaskID)
+			if err != nil {
+				return err
+			}
+			if reachable {
+				return ErrLinkCycle
+			}
+		} else {
+			links, err := r.Links.ListByTask(ctx, link.TargetTaskID)
+			if err != nil {
+				return err
+			}
+			for _, existing := range links {
+				if existing.Type == link.Type && existing.SourceTaskID == link.TargetTaskID && existing.TargetTaskID == link.SourceTaskID {
+					return ErrConflict
+				}
+			}
+		}
+
+		return r.Links.Create(ctx, link)
+	})
+}
+
+// blocksPath проверяет, блокирует ли задача from задачу to через цепочку связей blocks
+func (r Repositories) blocksPath(ctx context.Context, from, to uuid.UUID) (bool, error) {
+	visited := map[uuid.UUID]bool{from: true}
+	queue := []uuid.UUID{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		links, err := r.Links.ListByTask(ctx, current)
+		if err != nil {
+			return false, err
+		}
+		for _, link := range links {
+			if link.Type != models.LinkTypeBlocks || link.SourceTaskID != current {
+				continue
+			}
+			if link.TargetTaskID == to {
+				return true, nil
+			}
+			if !visited[link.TargetTaskID] {
+				visited[link.TargetTaskID] = true
+				queue = append(queue, link.TargetTaskID)
+			}
+		}
+	}
+	return false, nil
+}
+
+// CheckMoveAllowed проверяет переход задачи в status по колонкам и переходам
+// доски (см. CheckTransition) и возвращает ErrTaskBlocked, если на доске
+// включен enforce_dependencies, status - последняя колонка доски, а у задачи
+// есть незавершенные блокирующие задачи.
+func (r Repositories) CheckMoveAllowed(ctx context.Context, task *models.Task, status string) error {
+	if err := r.CheckTransition(ctx, task, status); err != nil {
+		return err
+	}
+
+	board, err := r.Boards.GetByID(ctx, task.BoardID)
+	if err != nil {
+		return err
+	}
+	if !board.EnforceDependencies {
+		return nil
+	}
+
+	done := doneStatuses{}
+	last, err := done.get(ctx, r, task.BoardID)
+	if err != nil {
+		return err
+	}
+	if status != last {
+		return nil
+	}
+
+	links, err := r.Links.ListByTask(ctx, task.ID)
+	if err != nil {
+		return err
+	}
+
+	var blockers []string
+	for _, link := range links {
+		if link.Type != models.LinkTypeBlocks || link.TargetTaskID != task.ID {
+			continue
+		}
+		blocker, err := r.Tasks.GetByID(ctx, link.SourceTaskID)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		finished, err := done.finished(ctx, r, blocker)
+		if err != nil {
+			return err
+		}
+		if !finished {
+			blockers = append(blockers, fmt.Sprintf("%q", blocker.Title))
+		}
+	}
+
+	if len(blockers) > 0 {
+		return fmt.Errorf("%w: %s", ErrTaskBlocked, strings.Join(blockers, ", "))
+	}
+	return nil
+}
+
+// DependencyGraph строит граф связей задач доски. В узлы попадают только
+// задачи, у которых есть связи.
+func (r Repositories) DependencyGraph(ctx context.Context, boardID uuid.UUID) (*models.DependencyGraph, error) {
+	if _, err := r.Boards.GetByID(ctx, boardID); err != nil {
+		return nil, err
+	}
+
+	links, err := r.Links.ListByBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &models.DependencyGraph{Nodes: []models.GraphNode{}, Edges: []models.GraphEdge{}}
+	nodes := map[uuid.UUID]int{}
+	// unfinished - незавершенные задачи, блокирующие цели своих связей blocks
+	unfinished := map[uuid.UUID]bool{}
+	done := doneStatuses{}
+
+	addNode := func(id uuid.UUID) error {
+		if _, ok := nodes[id]; ok {
+			return nil
+		}
+		task, err := r.Tasks.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		nodes[id] = len(graph.Nodes)
+		graph.Nodes = append(graph.Nodes, models.GraphNode{ID: task.ID, BoardID: task.BoardID, Title: task.Title, Status: task.Status})
+
+		finished, err := done.finished(ctx, r, task)
+		if err != nil {
+			return err
+		}
+		unfinished[id] = !finished
+		return nil
+	}
+
+	for _, link := range links {
+		if err := addNode(link.SourceTaskID); err != nil {
+			return nil, err
+		}
+		if err := addNode(link.TargetTaskID); err != nil {
+			return nil, err
+		}
+		graph.Edges = append(graph.Edges, models.GraphEdge{Source: link.SourceTaskID, Target: link.TargetTaskID, Type: link.Type})
+	}
+
+	for _, edge := range graph.Edges {
+		if edge.Type == models.LinkTypeBlocks && unfinished[edge.Source] {
+			graph.Nodes[nodes[edge.Target]].Blocked = true
+		}
+	}
+
+	return graph, nil
+}
+
+// doneStatuses кэширует статус последней колонки для каждой доски
+type doneStatuses map[uuid.UUID]string
+
+func (d doneStatuses) get(ctx context.Context, r Repositories, boardID uuid.UUID) (string, error) {
+	if status, ok := d[boardID]; ok {
+		return status, nil
+	}
+	columns, err := r.Columns.ListByBoard(ctx, boardID, ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	d[boardID] = doneStatus(columns)
+	return d[boardID], nil
+}
+
+// finished считает задачу завершенной, если она в последней колонке доски или в архиве
+func (d doneStatuses) finished(ctx context.Context, r Repositories, task *models.Task) (bool, error) {
+	if task.ArchivedAt != nil {
+		return true, nil
+	}
+	status, err := d.get(ctx, r, task.BoardID)
+	if err != nil {
+		return false, err
+	}
+	return status != "" && task.Status == status, nil
+}
+
+// doneStatus возвращает статус последней колонки или пустую строку, если колонок нет
+func doneStatus(columns []models.Column) string {
+	if len(columns) == 0 {
+		return ""
+	}
+	return columns[len(columns)-1].StatusID
+}
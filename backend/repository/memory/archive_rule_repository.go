@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+)
+
+type ArchiveRuleRepository struct {
+	store *Store
+}
+
+func (r *ArchiveRuleRepository) List(ctx context.Context) ([]models.ArchiveRule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rules []models.ArchiveRule
+	for boardID, boardRules := range r.store.archiveRules {
+		if r.store.boardActive(boardID) {
+			rules = append(rules, boardRules...)
+		}
+	}
+	sortRules(rules)
+
+	return rules, nil
+}
+
+func (r *ArchiveRuleRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.ArchiveRule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rules := append([]models.ArchiveRule(nil), r.store.archiveRules[boardID]...)
+	sortRules(rules)
+
+	return rules, nil
+}
+
+func (r *ArchiveRuleRepository) Replace(ctx context.Context, boardID uuid.UUID, rules []models.ArchiveRule) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.boards[boardID]; !ok {
+		return fmt.Errorf("board %s does not exist", boardID)
+	}
+
+	seen := make(map[string]bool)
+	stored := make([]models.ArchiveRule, 0, len(rules))
+	for _, rule := range rules {
+		if seen[rule.Status] {
+			return repository.ErrConflict
+		}
+		seen[rule.Status] = true
+		rule.BoardID = boardID
+		stored = append(stored, rule)
+	}
+	r.store.archiveRules[boardID] = stored
+
+	return nil
+}
+
+func sortRules(rules []models.ArchiveRule) {
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].BoardID != rules[j].BoardID {
+			return rules[i].BoardID.String() < rules[j].BoardID.String()
+		}
+		return rules[i].Status < rules[j].Status
+	})
+}
@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttachmentRepository struct {
+	store *Store
+}
+
+func (r *AttachmentRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.Attachment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if !r.store.taskActive(taskID) {
+		return nil, nil
+	}
+
+	var attachments []models.Attachment
+	for _, attachment := range r.store.attachments {
+		if attachment.TaskID == taskID {
+			attachments = append(attachments, r.store.withBlob(attachment))
+		}
+	}
+	sort.Slice(attachments, func(i, j int) bool {
+		return attachments[i].CreatedAt.Before(attachments[j].CreatedAt)
+	})
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Attachment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	attachment, ok := r.store.attachments[id]
+	if !ok || !r.store.taskActive(attachment.TaskID) {
+		return nil, repository.ErrNotFound
+	}
+	attachment = r.store.withBlob(attachment)
+	return &attachment, nil
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tasks[attachment.TaskID]; !ok {
+		return repository.ErrNotFound
+	}
+	if _, ok := r.store.blobs[attachment.Checksum]; !ok {
+		return repository.ErrNotFound
+	}
+
+	attachment.ID = uuid.New()
+	attachment.CreatedAt = time.Now()
+	r.store.attachments[attachment.ID] = *attachment
+	*attachment = r.store.withBlob(*attachment)
+
+	return nil
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.attachments[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.attachments, id)
+
+	return nil
+}
+
+func (r *AttachmentRepository) GetBlob(ctx context.Context, checksum string) (*models.AttachmentBlob, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	blob, ok := r.store.blobs[checksum]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &blob, nil
+}
+
+func (r *AttachmentRepository) CreateBlob(ctx context.Context, blob *models.AttachmentBlob) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.blobs[blob.Checksum]; ok {
+		return nil
+	}
+	blob.CreatedAt = time.Now()
+	r.store.blobs[blob.Checksum] = *blob
+
+	return nil
+}
+
+func (r *AttachmentRepository) DeleteBlob(ctx context.Context, checksum string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.blobs[checksum]; !ok {
+		return repository.ErrNotFound
+	}
+	for _, attachment := range r.store.attachments {
+		if attachment.Checksum == checksum {
+			return repository.ErrConflict
+		}
+	}
+	delete(r.store.blobs, checksum)
+
+	return nil
+}
+
+func (r *AttachmentRepository) OrphanBlobs(ctx context.Context) ([]models.AttachmentBlob, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	used := map[string]bool{}
+	for _, attachment := range r.store.attachments {
+		used[attachment.Checksum] = true
+	}
+
+	var blobs []models.AttachmentBlob
+	for checksum, blob := range r.store.blobs {
+		if !used[checksum] {
+			blobs = append(blobs, blob)
+		}
+	}
+	sort.Slice(blobs, func(i, j int) bool {
+		return blobs[i].CreatedAt.Before(blobs[j].CreatedAt)
+	})
+	return blobs, nil
+}
+
+// withBlob дополняет вложение размером и признаком миниатюры файла; вызывается под s.mu
+func (s *Store) withBlob(attachment models.Attachment) models.Attachment {
+	blob := s.blobs[attachment.Checksum]
+	attachment.Size = blob.Size
+	attachment.HasThumbnail = blob.HasThumbnail
+	return attachment
+}
@@ -0,0 +1,266 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AutomationRuleRepository struct {
+	store *Store
+}
+
+func (r *AutomationRuleRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.AutomationRule, error) {
+	return r.list(ctx, func(rule models.AutomationRule) bool {
+		return rule.BoardID == boardID
+	})
+}
+
+func (r *AutomationRuleRepository) ListByTrigger(ctx context.Context, triggerType string) ([]models.AutomationRule, error) {
+	return r.list(ctx, func(rule models.AutomationRule) bool {
+		return rule.Enabled && rule.Trigger.Type == triggerType
+	})
+}
+
+func (r *AutomationRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.AutomationRule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rule, ok := r.store.automationRules[id]
+	if !ok || !r.store.boardActive(rule.BoardID) {
+		return nil, repository.ErrNotFound
+	}
+	rule = cloneRule(rule)
+	return &rule, nil
+}
+
+func (r *AutomationRuleRepository) Create(ctx context.Context, rule *models.AutomationRule) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.boardActive(rule.BoardID) {
+		return repository.ErrNotFound
+	}
+
+	rule.ID = uuid.New()
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+	r.store.automationRules[rule.ID] = cloneRule(*rule)
+
+	return nil
+}
+
+func (r *AutomationRuleRepository) Update(ctx context.Context, rule *models.AutomationRule) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.automationRules[rule.ID]
+	if !ok || !r.store.boardActive(stored.BoardID) {
+		return repository.ErrNotFound
+	}
+
+	rule.BoardID = stored.BoardID
+	rule.CreatedBy = stored.CreatedBy
+	rule.CreatedAt = stored.CreatedAt
+	rule.UpdatedAt = time.Now()
+	r.store.automationRules[rule.ID] = cloneRule(*rule)
+
+	return nil
+}
+
+func (r *AutomationRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.automationRules[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.automationRules, id)
+	for runID, run := range r.store.automationRuns {
+		if run.RuleID == id {
+			delete(r.store.automationRuns, runID)
+		}
+	}
+	for delayID, delay := range r.store.automationDelays {
+		if delay.RuleID == id {
+			delete(r.store.automationDelays, delayID)
+		}
+	}
+
+	return nil
+}
+
+func (r *AutomationRuleRepository) list(ctx context.Context, match func(models.AutomationRule) bool) ([]models.AutomationRule, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var rules []models.AutomationRule
+	for _, rule := range r.store.automationRules {
+		if r.store.boardActive(rule.BoardID) && match(rule) {
+			rules = append(rules, cloneRule(rule))
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].CreatedAt.Before(rules[j].CreatedAt)
+	})
+	return rules, nil
+}
+
+// cloneRule копирует срезы правила, чтобы вызывающий код не менял хранилище
+func cloneRule(rule models.AutomationRule) models.AutomationRule {
+	rule.Conditions = slices.Clone(rule.Conditions)
+	rule.Actions = slices.Clone(rule.Actions)
+	return rule
+}
+
+type AutomationRunRepository struct {
+	store *Store
+}
+
+func (r *AutomationRunRepository) Create(ctx context.Context, run *models.AutomationRun) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.automationRules[run.RuleID]; !ok {
+		return repository.ErrNotFound
+	}
+
+	run.ID = uuid.New()
+	run.CreatedAt = time.Now()
+	r.store.automationRuns[run.ID] = *run
+
+	return nil
+}
+
+func (r *AutomationRunRepository) List(ctx context.Context, filter repository.AutomationRunFilter) ([]models.AutomationRun, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var runs []models.AutomationRun
+	for _, run := range r.store.automationRuns {
+		if run.BoardID != filter.BoardID || (filter.RuleID != nil && run.RuleID != *filter.RuleID) {
+			continue
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].CreatedAt.After(runs[j].CreatedAt)
+	})
+	if filter.Limit > 0 && len(runs) > filter.Limit {
+		runs = runs[:filter.Limit]
+	}
+	return runs, nil
+}
+
+func (r *AutomationRunRepository) LastRun(ctx context.Context, ruleID, taskID uuid.UUID) (*time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var last *time.Time
+	for _, run := range r.store.automationRuns {
+		if run.RuleID != ruleID || run.TaskID == nil || *run.TaskID != taskID {
+			continue
+		}
+		if last == nil || run.CreatedAt.After(*last) {
+			createdAt := run.CreatedAt
+			last = &createdAt
+		}
+	}
+	return last, nil
+}
+
+type AutomationDelayRepository struct {
+	store *Store
+}
+
+func (r *AutomationDelayRepository) Schedule(ctx context.Context, delay *models.AutomationDelay) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.automationRules[delay.RuleID]; !ok {
+		return repository.ErrNotFound
+	}
+	if _, ok := r.store.tasks[delay.TaskID]; !ok {
+		return repository.ErrNotFound
+	}
+
+	delay.ID = uuid.New()
+	for id, existing := range r.store.automationDelays {
+		if existing.RuleID == delay.RuleID && existing.TaskID == delay.TaskID {
+			delay.ID = id
+		}
+	}
+	delay.CreatedAt = time.Now()
+	r.store.automationDelays[delay.ID] = *delay
+
+	return nil
+}
+
+func (r *AutomationDelayRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.AutomationDelay, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var delays []models.AutomationDelay
+	for _, delay := range r.store.automationDelays {
+		if !delay.RunAt.After(now) {
+			delays = append(delays, delay)
+		}
+	}
+	sort.Slice(delays, func(i, j int) bool {
+		return delays[i].RunAt.Before(delays[j].RunAt)
+	})
+	if limit > 0 && len(delays) > limit {
+		delays = delays[:limit]
+	}
+	return delays, nil
+}
+
+func (r *AutomationDelayRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.automationDelays[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.automationDelays, id)
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BoardRepository struct {
+	store *Store
+}
+
+func (r *BoardRepository) List(ctx context.Context) ([]models.Board, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	boards := make([]models.Board, 0, len(r.store.boards))
+	for _, board := range r.store.boards {
+		if board.DeletedAt != nil {
+			continue
+		}
+		board.Tasks = r.store.boardTasks(board.ID)
+		boards = append(boards, board)
+	}
+	sort.Slice(boards, func(i, j int) bool {
+		return boards[i].CreatedAt.After(boards[j].CreatedAt)
+	})
+
+	return boards, nil
+}
+
+func (r *BoardRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Board, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	board, ok := r.store.boards[id]
+	if !ok || board.DeletedAt != nil {
+		return nil, repository.ErrNotFound
+	}
+	board.Tasks = r.store.boardTasks(id)
+
+	return &board, nil
+}
+
+func (r *BoardRepository) Create(ctx context.Context, board *models.Board) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	board.ID = uuid.New()
+	board.CreatedAt = time.Now()
+	board.UpdatedAt = board.CreatedAt
+
+	stored := *board
+	stored.Tasks = nil
+	r.store.boards[board.ID] = stored
+
+	return nil
+}
+
+func (r *BoardRepository) Update(ctx context.Context, board *models.Board) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.boards[board.ID]
+	if !ok || stored.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+
+	board.UpdatedAt = time.Now()
+	stored.Name = board.Name
+	stored.Description = board.Description
+	stored.EnforceDependencies = board.EnforceDependencies
+	stored.UpdatedAt = board.UpdatedAt
+	r.store.boards[board.ID] = stored
+
+	return nil
+}
+
+func (r *BoardRepository) SetOwner(ctx context.Context, id, userID uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.boards[id]
+	if !ok || stored.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+	if _, ok := r.store.users[userID]; !ok {
+		return fmt.Errorf("user %s does not exist", userID)
+	}
+
+	stored.UserID = &userID
+	stored.UpdatedAt = time.Now()
+	r.store.boards[id] = stored
+
+	return nil
+}
+
+// Delete переносит доску в корзину вместе с ее колонками и задачами
+func (r *BoardRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	board, ok := r.store.boards[id]
+	if !ok || board.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+
+	now := time.Now()
+	board.DeletedAt = &now
+	r.store.boards[id] = board
+	for columnID, column := range r.store.columns {
+		if column.BoardID == id && column.DeletedAt == nil {
+			column.DeletedAt = &now
+			r.store.columns[columnID] = column
+		}
+	}
+	for taskID, task := range r.store.tasks {
+		if task.BoardID == id && task.DeletedAt == nil {
+			task.DeletedAt = &now
+			r.store.tasks[taskID] = task
+		}
+	}
+
+	return nil
+}
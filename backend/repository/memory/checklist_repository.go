@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ChecklistRepository struct {
+	store *Store
+}
+
+func (r *ChecklistRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.ChecklistItem, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if !r.store.taskActive(taskID) {
+		return nil, nil
+	}
+
+	var items []models.ChecklistItem
+	for _, item := range r.store.checklist {
+		if item.TaskID == taskID {
+			items = append(items, item)
+		}
+	}
+	sortChecklist(items)
+	return items, nil
+}
+
+func (r *ChecklistRepository) ListByTasks(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID][]models.ChecklistItem, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		wanted[id] = true
+	}
+	items := make(map[uuid.UUID][]models.ChecklistItem)
+	for _, item := range r.store.checklist {
+		if wanted[item.TaskID] && r.store.taskActive(item.TaskID) {
+			items[item.TaskID] = append(items[item.TaskID], item)
+		}
+	}
+	for _, taskItems := range items {
+		sortChecklist(taskItems)
+	}
+	return items, nil
+}
+
+func (r *ChecklistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ChecklistItem, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	item, ok := r.store.checklist[id]
+	if !ok || !r.store.taskActive(item.TaskID) {
+		return nil, repository.ErrNotFound
+	}
+	return &item, nil
+}
+
+func (r *ChecklistRepository) Create(ctx context.Context, item *models.ChecklistItem) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.taskActive(item.TaskID) {
+		return repository.ErrNotFound
+	}
+
+	item.Position = 0
+	for _, existing := range r.store.checklist {
+		if existing.TaskID == item.TaskID && existing.Position >= item.Position {
+			item.Position = existing.Position + 1
+		}
+	}
+
+	item.ID = uuid.New()
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = item.CreatedAt
+	r.store.checklist[item.ID] = *item
+
+	return nil
+}
+
+func (r *ChecklistRepository) Update(ctx context.Context, item *models.ChecklistItem) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.checklist[item.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+
+	item.UpdatedAt = time.Now()
+	stored.Title = item.Title
+	stored.Checked = item.Checked
+	stored.Assignee = item.Assignee
+	stored.Position = item.Position
+	stored.UpdatedAt = item.UpdatedAt
+	r.store.checklist[item.ID] = stored
+
+	return nil
+}
+
+func (r *ChecklistRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.checklist[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.checklist, id)
+
+	return nil
+}
+
+// taskActive вызывается под s.mu
+// sortChecklist упорядочивает пункты по позиции, затем по времени создания
+func sortChecklist(items []models.ChecklistItem) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Position != items[j].Position {
+			return items[i].Position < items[j].Position
+		}
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+}
+
+func (s *Store) taskActive(id uuid.UUID) bool {
+	task, ok := s.tasks[id]
+	return ok && task.DeletedAt == nil
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ColumnRepository struct {
+	store *Store
+}
+
+func (r *ColumnRepository) ListByBoard(ctx context.Context, boardID uuid.UUID, opts repository.ListOptions) ([]models.Column, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var columns []models.Column
+	for _, column := range r.store.columns {
+		if column.BoardID == boardID && column.DeletedAt == nil && (opts.IncludeArchived || column.ArchivedAt == nil) {
+			columns = append(columns, column)
+		}
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Position < columns[j].Position
+	})
+
+	return columns, nil
+}
+
+func (r *ColumnRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Column, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	column, ok := r.store.columns[id]
+	if !ok || column.DeletedAt != nil {
+		return nil, repository.ErrNotFound
+	}
+	return &column, nil
+}
+
+func (r *ColumnRepository) Create(ctx context.Context, column *models.Column) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.boards[column.BoardID]; !ok {
+		return fmt.Errorf("board %s does not exist", column.BoardID)
+	}
+	for _, existing := range r.store.columns {
+		if existing.BoardID == column.BoardID && existing.StatusID == column.StatusID && existing.DeletedAt == nil {
+			return repository.ErrConflict
+		}
+	}
+
+	column.ID = uuid.New()
+	r.store.columns[column.ID] = *column
+
+	return nil
+}
+
+func (r *ColumnRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	column, ok := r.store.columns[id]
+	if !ok || column.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+	now := time.Now()
+	column.DeletedAt = &now
+	r.store.columns[id] = column
+
+	return nil
+}
+
+func (r *ColumnRepository) SetArchived(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	column, ok := r.store.columns[id]
+	if !ok || column.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+	column.ArchivedAt = at
+	r.store.columns[id] = column
+
+	return nil
+}
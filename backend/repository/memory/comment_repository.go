@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CommentRepository struct {
+	store *Store
+}
+
+func (r *CommentRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.TaskComment, error) {
+	return r.list(ctx, func(comment models.TaskComment) bool { return comment.TaskID == taskID })
+}
+
+func (r *CommentRepository) ListByTasks(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID][]models.TaskComment, error) {
+	wanted := make(map[uuid.UUID]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		wanted[id] = true
+	}
+	comments, err := r.list(ctx, func(comment models.TaskComment) bool { return wanted[comment.TaskID] })
+	if err != nil {
+		return nil, err
+	}
+	byTask := make(map[uuid.UUID][]models.TaskComment)
+	for _, comment := range comments {
+		byTask[comment.TaskID] = append(byTask[comment.TaskID], comment)
+	}
+	return byTask, nil
+}
+
+func (r *CommentRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.TaskComment, error) {
+	return r.list(ctx, func(comment models.TaskComment) bool {
+		return comment.UserID != nil && *comment.UserID == userID
+	})
+}
+
+func (r *CommentRepository) list(ctx context.Context, match func(models.TaskComment) bool) ([]models.TaskComment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var comments []models.TaskComment
+	for _, comment := range r.store.comments {
+		if match(comment) {
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+	return comments, nil
+}
+
+func (r *CommentRepository) Create(ctx context.Context, comment *models.TaskComment) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if task, ok := r.store.tasks[comment.TaskID]; !ok || task.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+
+	comment.ID = uuid.New()
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now()
+	}
+	r.store.comments[comment.ID] = *comment
+
+	return nil
+}
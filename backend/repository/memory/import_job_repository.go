@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportJobRepository struct {
+	store *Store
+}
+
+func (r *ImportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ImportJob, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	job, ok := r.store.importJobs[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &job, nil
+}
+
+func (r *ImportJobRepository) Create(ctx context.Context, job *models.ImportJob) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job.ID = uuid.New()
+	job.CreatedAt = time.Now()
+	job.UpdatedAt = job.CreatedAt
+	r.store.importJobs[job.ID] = *job
+
+	return nil
+}
+
+func (r *ImportJobRepository) Update(ctx context.Context, job *models.ImportJob) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.importJobs[job.ID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+
+	job.UpdatedAt = time.Now()
+	stored.Status = job.Status
+	stored.BoardID = job.BoardID
+	stored.ImportedTasks = job.ImportedTasks
+	stored.Error = job.Error
+	stored.UpdatedAt = job.UpdatedAt
+	stored.FinishedAt = job.FinishedAt
+	r.store.importJobs[job.ID] = stored
+
+	return nil
+}
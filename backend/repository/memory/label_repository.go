@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+)
+
+type LabelRepository struct {
+	store *Store
+}
+
+func (r *LabelRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.Label, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var labels []models.Label
+	for _, label := range r.store.labels {
+		if label.BoardID == boardID {
+			labels = append(labels, label)
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+
+	return labels, nil
+}
+
+func (r *LabelRepository) Create(ctx context.Context, label *models.Label) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.boards[label.BoardID]; !ok {
+		return fmt.Errorf("board %s does not exist", label.BoardID)
+	}
+	for _, existing := range r.store.labels {
+		if existing.BoardID == label.BoardID && existing.Name == label.Name {
+			return repository.ErrConflict
+		}
+	}
+
+	label.ID = uuid.New()
+	r.store.labels[label.ID] = *label
+
+	return nil
+}
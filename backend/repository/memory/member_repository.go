@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MemberRepository struct {
+	store *Store
+}
+
+func (r *MemberRepository) Add(ctx context.Context, member *models.BoardMember) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.boards[member.BoardID]; !ok {
+		return fmt.Errorf("board %s does not exist", member.BoardID)
+	}
+	if _, ok := r.store.users[member.UserID]; !ok {
+		return fmt.Errorf("user %s does not exist", member.UserID)
+	}
+
+	key := memberKey{boardID: member.BoardID, userID: member.UserID}
+	if _, ok := r.store.members[key]; ok {
+		return repository.ErrConflict
+	}
+
+	member.CreatedAt = time.Now()
+	r.store.members[key] = *member
+
+	return nil
+}
+
+func (r *MemberRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.BoardMember, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var members []models.BoardMember
+	for key, member := range r.store.members {
+		if key.boardID == boardID {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].CreatedAt.Before(members[j].CreatedAt)
+	})
+
+	return members, nil
+}
+
+func (r *MemberRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.BoardMember, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var members []models.BoardMember
+	for key, member := range r.store.members {
+		if key.userID == userID {
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].CreatedAt.Before(members[j].CreatedAt)
+	})
+
+	return members, nil
+}
+
+func (r *MemberRepository) SetRole(ctx context.Context, boardID, userID uuid.UUID, role string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := memberKey{boardID: boardID, userID: userID}
+	member, ok := r.store.members[key]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	member.Role = role
+	r.store.members[key] = member
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type memberKey struct {
+	boardID uuid.UUID
+	userID  uuid.UUID
+}
+
+type snapshotKey struct {
+	sprintID uuid.UUID
+	day      time.Time
+}
+
+// Store хранит данные всех in-memory репозиториев, чтобы доски, колонки
+// и задачи вели себя согласованно (каскадное удаление, уникальность).
+type Store struct {
+	mu      sync.RWMutex
+	boards  map[uuid.UUID]models.Board
+	tasks   map[uuid.UUID]models.Task
+	columns map[uuid.UUID]models.Column
+	users   map[uuid.UUID]models.User
+	members map[memberKey]models.BoardMember
+	labels  map[uuid.UUID]models.Label
+	// userTokens - одноразовые токены из писем
+	userTokens map[uuid.UUID]models.UserToken
+	// archiveRules хранит правила архивации по доскам
+	archiveRules map[uuid.UUID][]models.ArchiveRule
+	checklist    map[uuid.UUID]models.ChecklistItem
+	links        map[uuid.UUID]models.TaskLink
+	attachments  map[uuid.UUID]models.Attachment
+	// blobs - учтенные файлы вложений по контрольной сумме
+	blobs          map[string]models.AttachmentBlob
+	worklogs       map[uuid.UUID]models.Worklog
+	recurringTasks map[uuid.UUID]models.RecurringTask
+	comments       map[uuid.UUID]models.TaskComment
+	// automationRules и automationRuns - правила автоматизации и их журнал
+	automationRules map[uuid.UUID]models.AutomationRule
+	automationRuns  map[uuid.UUID]models.AutomationRun
+	// automationDelays - отложенные действия правил
+	automationDelays map[uuid.UUID]models.AutomationDelay
+	// importJobs - задания импорта досок
+	importJobs map[uuid.UUID]models.ImportJob
+	// workflows хранит разрешенные переходы между статусами по доскам
+	workflows map[uuid.UUID][]models.WorkflowTransition
+	// sprints хранит спринты всех досок
+	sprints map[uuid.UUID]models.Sprint
+	// statusChanges - история статусов задач в порядке записи
+	statusChanges []models.TaskStatusChange
+	// scopeChanges - история состава спринтов в порядке записи
+	scopeChanges []models.SprintScopeChange
+	// snapshots - ежедневные снимки burndown спринтов
+	snapshots map[snapshotKey]models.SprintSnapshot
+	// templates хранятся вместе с порядком создания, чтобы List был стабильным
+	templates []models.BoardTemplate
+
+	// txMu сериализует транзакции; откат восстанавливает снимок данных
+	txMu sync.Mutex
+}
+
+func NewStore() *Store {
+	return &Store{
+		boards:  make(map[uuid.UUID]models.Board),
+		tasks:   make(map[uuid.UUID]models.Task),
+		columns: make(map[uuid.UUID]models.Column),
+		users:   make(map[uuid.UUID]models.User),
+		members: make(map[memberKey]models.BoardMember),
+		labels:  make(map[uuid.UUID]models.Label),
+
+		userTokens:     make(map[uuid.UUID]models.UserToken),
+		archiveRules:   make(map[uuid.UUID][]models.ArchiveRule),
+		workflows:      make(map[uuid.UUID][]models.WorkflowTransition),
+		checklist:      make(map[uuid.UUID]models.ChecklistItem),
+		links:          make(map[uuid.UUID]models.TaskLink),
+		attachments:    make(map[uuid.UUID]models.Attachment),
+		blobs:          make(map[string]models.AttachmentBlob),
+		worklogs:       make(map[uuid.UUID]models.Worklog),
+		recurringTasks: make(map[uuid.UUID]models.RecurringTask),
+		comments:       make(map[uuid.UUID]models.TaskComment),
+
+		automationRules:  make(map[uuid.UUID]models.AutomationRule),
+		automationRuns:   make(map[uuid.UUID]models.AutomationRun),
+		automationDelays: make(map[uuid.UUID]models.AutomationDelay),
+		importJobs:       make(map[uuid.UUID]models.ImportJob),
+
+		sprints:   make(map[uuid.UUID]models.Sprint),
+		snapshots: make(map[snapshotKey]models.SprintSnapshot),
+	}
+}
+
+// NewRepositories создает набор репозиториев поверх нового Store
+func NewRepositories() repository.Repositories {
+	return NewStore().Repositories()
+}
+
+func (s *Store) Repositories() repository.Repositories {
+	return repository.Repositories{
+		Boards:           &BoardRepository{store: s},
+		Tasks:            &TaskRepository{store: s},
+		Columns:          &ColumnRepository{store: s},
+		Users:            &UserRepository{store: s},
+		UserTokens:       &UserTokenRepository{store: s},
+		Members:          &MemberRepository{store: s},
+		Labels:           &LabelRepository{store: s},
+		Templates:        &TemplateRepository{store: s},
+		Trash:            &TrashRepository{store: s},
+		ArchiveRules:     &ArchiveRuleRepository{store: s},
+		Workflows:        &WorkflowRepository{store: s},
+		Sprints:          &SprintRepository{store: s},
+		Checklists:       &ChecklistRepository{store: s},
+		Links:            &TaskLinkRepository{store: s},
+		Attachments:      &AttachmentRepository{store: s},
+		Worklogs:         &WorklogRepository{store: s},
+		RecurringTasks:   &RecurringTaskRepository{store: s},
+		Comments:         &CommentRepository{store: s},
+		AutomationRules:  &AutomationRuleRepository{store: s},
+		AutomationRuns:   &AutomationRunRepository{store: s},
+		AutomationDelays: &AutomationDelayRepository{store: s},
+		ImportJobs:       &ImportJobRepository{store: s},
+		Tx:               s,
+	}
+}
+
+type txKey struct{}
+
+func (s *Store) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	snapshot := s.snapshot()
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		s.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+func (s *Store) snapshot() *Store {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return &Store{
+		boards:    maps.Clone(s.boards),
+		tasks:     maps.Clone(s.tasks),
+		columns:   maps.Clone(s.columns),
+		users:     maps.Clone(s.users),
+		members:   maps.Clone(s.members),
+		labels:    maps.Clone(s.labels),
+		templates: slices.Clone(s.templates),
+
+		userTokens:     maps.Clone(s.userTokens),
+		archiveRules:   maps.Clone(s.archiveRules),
+		workflows:      maps.Clone(s.workflows),
+		checklist:      maps.Clone(s.checklist),
+		links:          maps.Clone(s.links),
+		attachments:    maps.Clone(s.attachments),
+		blobs:          maps.Clone(s.blobs),
+		worklogs:       maps.Clone(s.worklogs),
+		recurringTasks: maps.Clone(s.recurringTasks),
+		comments:       maps.Clone(s.comments),
+
+		automationRules:  maps.Clone(s.automationRules),
+		automationRuns:   maps.Clone(s.automationRuns),
+		automationDelays: maps.Clone(s.automationDelays),
+		importJobs:       maps.Clone(s.importJobs),
+
+		sprints:       maps.Clone(s.sprints),
+		statusChanges: slices.Clone(s.statusChanges),
+		scopeChanges:  slices.Clone(s.scopeChanges),
+		snapshots:     maps.Clone(s.snapshots),
+	}
+}
+
+func (s *Store) restore(snapshot *Store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.boards = snapshot.boards
+	s.tasks = snapshot.tasks
+	s.columns = snapshot.columns
+	s.users = snapshot.users
+	s.userTokens = snapshot.userTokens
+	s.members = snapshot.members
+	s.labels = snapshot.labels
+	s.templates = snapshot.templates
+	s.archiveRules = snapshot.archiveRules
+	s.workflows = snapshot.workflows
+	s.checklist = snapshot.checklist
+	s.links = snapshot.links
+	s.attachments = snapshot.attachments
+	s.blobs = snapshot.blobs
+	s.worklogs = snapshot.worklogs
+	s.recurringTasks = snapshot.recurringTasks
+	s.comments = snapshot.comments
+	s.automationRules = snapshot.automationRules
+	s.automationRuns = snapshot.automationRuns
+	s.automationDelays = snapshot.automationDelays
+	s.importJobs = snapshot.importJobs
+	s.sprints = snapshot.sprints
+	s.statusChanges = snapshot.statusChanges
+	s.scopeChanges = snapshot.scopeChanges
+	s.snapshots = snapshot.snapshots
+}
+
+var (
+	_ repository.BoardRepository           = (*BoardRepository)(nil)
+	_ repository.TaskRepository            = (*TaskRepository)(nil)
+	_ repository.ColumnRepository          = (*ColumnRepository)(nil)
+	_ repository.UserRepository            = (*UserRepository)(nil)
+	_ repository.MemberRepository          = (*MemberRepository)(nil)
+	_ repository.LabelRepository           = (*LabelRepository)(nil)
+	_ repository.TemplateRepository        = (*TemplateRepository)(nil)
+	_ repository.TrashRepository           = (*TrashRepository)(nil)
+	_ repository.ArchiveRuleRepository     = (*ArchiveRuleRepository)(nil)
+	_ repository.WorkflowRepository        = (*WorkflowRepository)(nil)
+	_ repository.SprintRepository          = (*SprintRepository)(nil)
+	_ repository.ChecklistRepository       = (*ChecklistRepository)(nil)
+	_ repository.TaskLinkRepository        = (*TaskLinkRepository)(nil)
+	_ repository.AttachmentRepository      = (*AttachmentRepository)(nil)
+	_ repository.WorklogRepository         = (*WorklogRepository)(nil)
+	_ repository.RecurringTaskRepository   = (*RecurringTaskRepository)(nil)
+	_ repository.CommentRepository         = (*CommentRepository)(nil)
+	_ repository.AutomationRuleRepository  = (*AutomationRuleRepository)(nil)
+	_ repository.AutomationRunRepository   = (*AutomationRunRepository)(nil)
+	_ repository.AutomationDelayRepository = (*AutomationDelayRepository)(nil)
+	_ repository.ImportJobRepository       = (*ImportJobRepository)(nil)
+	_ repository.Transactor                = (*Store)(nil)
+)
@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"task-flow-backend/repository"
+	"task-flow-backend/repository/repotest"
+	"testing"
+)
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repository.Repositories {
+		return NewRepositories()
+	})
+}
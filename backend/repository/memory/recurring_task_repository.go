@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RecurringTaskRepository struct {
+	store *Store
+}
+
+func (r *RecurringTaskRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.RecurringTask, error) {
+	recurring, err := r.list(ctx, func(recurring models.RecurringTask) bool {
+		return recurring.BoardID == boardID
+	})
+	sort.Slice(recurring, func(i, j int) bool {
+		return recurring[i].CreatedAt.Before(recurring[j].CreatedAt)
+	})
+	return recurring, err
+}
+
+func (r *RecurringTaskRepository) ListDue(ctx context.Context, now time.Time) ([]models.RecurringTask, error) {
+	recurring, err := r.list(ctx, func(recurring models.RecurringTask) bool {
+		return recurring.NextRunAt != nil && !recurring.NextRunAt.After(now)
+	})
+	sort.Slice(recurring, func(i, j int) bool {
+		return recurring[i].NextRunAt.Before(*recurring[j].NextRunAt)
+	})
+	return recurring, err
+}
+
+func (r *RecurringTaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RecurringTask, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	recurring, ok := r.store.recurringTasks[id]
+	if !ok || !r.store.boardActive(recurring.BoardID) {
+		return nil, repository.ErrNotFound
+	}
+	return &recurring, nil
+}
+
+func (r *RecurringTaskRepository) Create(ctx context.Context, recurring *models.RecurringTask) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.boardActive(recurring.BoardID) {
+		return repository.ErrNotFound
+	}
+
+	recurring.ID = uuid.New()
+	recurring.CreatedAt = time.Now()
+	recurring.UpdatedAt = recurring.CreatedAt
+	r.store.recurringTasks[recurring.ID] = *recurring
+
+	return nil
+}
+
+func (r *RecurringTaskRepository) Update(ctx context.Context, recurring *models.RecurringTask) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.recurringTasks[recurring.ID]
+	if !ok || !r.store.boardActive(stored.BoardID) {
+		return repository.ErrNotFound
+	}
+
+	recurring.BoardID = stored.BoardID
+	recurring.CreatedBy = stored.CreatedBy
+	recurring.CreatedAt = stored.CreatedAt
+	recurring.UpdatedAt = time.Now()
+	r.store.recurringTasks[recurring.ID] = *recurring
+
+	return nil
+}
+
+func (r *RecurringTaskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.recurringTasks[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.recurringTasks, id)
+
+	return nil
+}
+
+func (r *RecurringTaskRepository) list(ctx context.Context, match func(models.RecurringTask) bool) ([]models.RecurringTask, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var recurring []models.RecurringTask
+	for _, item := range r.store.recurringTasks {
+		if r.store.boardActive(item.BoardID) && match(item) {
+			recurring = append(recurring, item)
+		}
+	}
+	return recurring, nil
+}
@@ -0,0 +1,228 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SprintRepository struct {
+	store *Store
+}
+
+func (r *SprintRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.Sprint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var sprints []models.Sprint
+	for _, sprint := range r.store.sprints {
+		if sprint.BoardID == boardID && r.store.boardActive(boardID) {
+			sprints = append(sprints, sprint)
+		}
+	}
+	sort.Slice(sprints, func(i, j int) bool {
+		a, b := sprints[i], sprints[j]
+		switch {
+		case a.StartsAt != nil && b.StartsAt != nil && !a.StartsAt.Equal(*b.StartsAt):
+			return a.StartsAt.Before(*b.StartsAt)
+		case (a.StartsAt == nil) != (b.StartsAt == nil):
+			return a.StartsAt != nil
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return sprints, nil
+}
+
+func (r *SprintRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Sprint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	sprint, ok := r.store.sprints[id]
+	if !ok || !r.store.boardActive(sprint.BoardID) {
+		return nil, repository.ErrNotFound
+	}
+	return &sprint, nil
+}
+
+func (r *SprintRepository) Create(ctx context.Context, sprint *models.Sprint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.boardActive(sprint.BoardID) {
+		return repository.ErrNotFound
+	}
+	if sprint.State == models.SprintStateActive && r.store.activeSprint(sprint.BoardID, uuid.Nil) {
+		return repository.ErrConflict
+	}
+
+	sprint.ID = uuid.New()
+	sprint.CreatedAt = time.Now()
+	sprint.UpdatedAt = sprint.CreatedAt
+	r.store.sprints[sprint.ID] = *sprint
+
+	return nil
+}
+
+func (r *SprintRepository) Update(ctx context.Context, sprint *models.Sprint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.sprints[sprint.ID]
+	if !ok || !r.store.boardActive(stored.BoardID) {
+		return repository.ErrNotFound
+	}
+	if sprint.State == models.SprintStateActive && r.store.activeSprint(stored.BoardID, sprint.ID) {
+		return repository.ErrConflict
+	}
+
+	sprint.BoardID = stored.BoardID
+	sprint.CreatedBy = stored.CreatedBy
+	sprint.CreatedAt = stored.CreatedAt
+	sprint.UpdatedAt = time.Now()
+	r.store.sprints[sprint.ID] = *sprint
+
+	return nil
+}
+
+func (r *SprintRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.sprints[id]; !ok {
+		return repository.ErrNotFound
+	}
+	r.store.deleteSprint(id)
+	for taskID, task := range r.store.tasks {
+		if task.SprintID != nil && *task.SprintID == id {
+			task.SprintID = nil
+			r.store.tasks[taskID] = task
+		}
+	}
+
+	return nil
+}
+
+func (r *SprintRepository) ListInProgress(ctx context.Context, completedSince time.Time) ([]models.Sprint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var sprints []models.Sprint
+	for _, sprint := range r.store.sprints {
+		if !r.store.boardActive(sprint.BoardID) {
+			continue
+		}
+		completed := sprint.CompletedAt != nil && !sprint.CompletedAt.Before(completedSince)
+		if sprint.State == models.SprintStateActive || completed {
+			sprints = append(sprints, sprint)
+		}
+	}
+	return sprints, nil
+}
+
+func (r *SprintRepository) ListScopeChanges(ctx context.Context, sprintID uuid.UUID) ([]models.SprintScopeChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	changes := []models.SprintScopeChange{}
+	for _, change := range r.store.scopeChanges {
+		task, ok := r.store.tasks[change.TaskID]
+		if change.SprintID != sprintID || !ok || task.DeletedAt != nil {
+			continue
+		}
+		change.TaskTitle = task.Title
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func (r *SprintRepository) ListSnapshots(ctx context.Context, sprintID uuid.UUID) ([]models.SprintSnapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	snapshots := []models.SprintSnapshot{}
+	for key, snapshot := range r.store.snapshots {
+		if key.sprintID == sprintID {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Day.Before(snapshots[j].Day)
+	})
+	return snapshots, nil
+}
+
+func (r *SprintRepository) SaveSnapshots(ctx context.Context, snapshots []models.SprintSnapshot) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	for _, snapshot := range snapshots {
+		if _, ok := r.store.sprints[snapshot.SprintID]; !ok {
+			return repository.ErrNotFound
+		}
+	}
+	for _, snapshot := range snapshots {
+		snapshot.Day = snapshot.Day.UTC().Truncate(24 * time.Hour)
+		snapshot.CreatedAt = now
+		r.store.snapshots[snapshotKey{sprintID: snapshot.SprintID, day: snapshot.Day}] = snapshot
+	}
+
+	return nil
+}
+
+// deleteSprint удаляет спринт с историей состава и снимками, как ON DELETE
+// CASCADE в Postgres. Задачи спринта не меняет. Вызывается под s.mu.
+func (s *Store) deleteSprint(id uuid.UUID) {
+	delete(s.sprints, id)
+	s.scopeChanges = slices.DeleteFunc(s.scopeChanges, func(change models.SprintScopeChange) bool {
+		return change.SprintID == id
+	})
+	for key := range s.snapshots {
+		if key.sprintID == id {
+			delete(s.snapshots, key)
+		}
+	}
+}
+
+// activeSprint сообщает, есть ли на доске активный спринт, кроме except,
+// как уникальный индекс idx_sprints_board_active. Вызывается под s.mu.
+func (s *Store) activeSprint(boardID, except uuid.UUID) bool {
+	for id, sprint := range s.sprints {
+		if id != except && sprint.BoardID == boardID && sprint.State == models.SprintStateActive {
+			return true
+		}
+	}
+	return false
+}
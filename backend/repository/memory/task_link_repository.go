@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskLinkRepository struct {
+	store *Store
+}
+
+func (r *TaskLinkRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.TaskLink, error) {
+	return r.list(ctx, func(link models.TaskLink) bool {
+		return link.SourceTaskID == taskID || link.TargetTaskID == taskID
+	})
+}
+
+func (r *TaskLinkRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.TaskLink, error) {
+	return r.list(ctx, func(link models.TaskLink) bool {
+		return r.store.tasks[link.SourceTaskID].BoardID == boardID || r.store.tasks[link.TargetTaskID].BoardID == boardID
+	})
+}
+
+func (r *TaskLinkRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.TaskLink, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	link, ok := r.store.links[id]
+	if !ok || !r.store.linkActive(link) {
+		return nil, repository.ErrNotFound
+	}
+	return &link, nil
+}
+
+func (r *TaskLinkRepository) Create(ctx context.Context, link *models.TaskLink) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.links {
+		if existing.SourceTaskID == link.SourceTaskID && existing.TargetTaskID == link.TargetTaskID && existing.Type == link.Type {
+			return repository.ErrConflict
+		}
+	}
+
+	link.ID = uuid.New()
+	link.CreatedAt = time.Now()
+	r.store.links[link.ID] = *link
+
+	return nil
+}
+
+func (r *TaskLinkRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.links[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.links, id)
+
+	return nil
+}
+
+func (r *TaskLinkRepository) list(ctx context.Context, match func(models.TaskLink) bool) ([]models.TaskLink, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var links []models.TaskLink
+	for _, link := range r.store.links {
+		if r.store.linkActive(link) && match(link) {
+			links = append(links, link)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].CreatedAt.Before(links[j].CreatedAt)
+	})
+	return links, nil
+}
+
+// linkActive проверяет, что обе задачи связи не удалены; вызывается под s.mu
+func (s *Store) linkActive(link models.TaskLink) bool {
+	return s.taskActive(link.SourceTaskID) && s.taskActive(link.TargetTaskID)
+}
@@ -0,0 +1,544 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskRepository struct {
+	store *Store
+}
+
+func (r *TaskRepository) ListByBoard(ctx context.Context, boardID uuid.UUID, opts repository.ListOptions) ([]models.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.listTasks(boardID, opts), nil
+}
+
+func (r *TaskRepository) ListPage(ctx context.Context, boardID uuid.UUID, after *repository.TaskCursor, limit int, opts repository.ListOptions) ([]models.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tasks := r.store.listTasks(boardID, opts)
+	sort.Slice(tasks, func(i, j int) bool {
+		return cursorLess(taskCursor(tasks[i]), taskCursor(tasks[j]))
+	})
+	if after != nil {
+		tasks = slices.DeleteFunc(tasks, func(task models.Task) bool {
+			return !cursorLess(*after, taskCursor(task))
+		})
+	}
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return tasks, nil
+}
+
+func taskCursor(task models.Task) repository.TaskCursor {
+	return repository.TaskCursor{CreatedAt: task.CreatedAt, ID: task.ID}
+}
+
+// cursorLess сравнивает позиции (created_at, id) так же, как Postgres сравнивает строки
+func cursorLess(a, b repository.TaskCursor) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return bytes.Compare(a.ID[:], b.ID[:]) < 0
+}
+
+func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return nil, repository.ErrNotFound
+	}
+	task = r.store.withProgress(task)
+	return &task, nil
+}
+
+func (r *TaskRepository) Create(ctx context.Context, task *models.Task) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.boards[task.BoardID]; !ok {
+		return fmt.Errorf("board %s does not exist", task.BoardID)
+	}
+
+	task.ID = uuid.New()
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = task.CreatedAt
+	r.store.tasks[task.ID] = *task
+	r.store.recordStatusChange(*task, nil)
+	if task.SprintID != nil {
+		r.store.recordScopeChange(*task, *task.SprintID, models.SprintScopeAdded)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.tasks[task.ID]
+	if !ok || stored.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+
+	task.UpdatedAt = time.Now()
+	previous := stored
+	stored.Title = task.Title
+	stored.Description = task.Description
+	stored.Status = task.Status
+	stored.Priority = task.Priority
+	stored.Assignee = task.Assignee
+	stored.EstimateMinutes = task.EstimateMinutes
+	stored.StoryPoints = task.StoryPoints
+	stored.DueAt = task.DueAt
+	stored.UpdatedAt = task.UpdatedAt
+	r.store.tasks[task.ID] = stored
+	if previous.Status != stored.Status {
+		r.store.recordStatusChange(stored, &previous.Status)
+	}
+	if stored.SprintID != nil && !samePoints(previous.StoryPoints, stored.StoryPoints) {
+		r.store.recordScopeChange(stored, *stored.SprintID, models.SprintScopePoints)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+	now := time.Now()
+	task.DeletedAt = &now
+	r.store.tasks[id] = task
+
+	return nil
+}
+
+func (r *TaskRepository) Move(ctx context.Context, id uuid.UUID, status string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+	previous := task.Status
+	task.Status = status
+	task.UpdatedAt = time.Now()
+	r.store.tasks[id] = task
+	if previous != status {
+		r.store.recordStatusChange(task, &previous)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) MoveToBoard(ctx context.Context, id, boardID uuid.UUID, status string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+	if _, ok := r.store.boards[boardID]; !ok {
+		return fmt.Errorf("board %s does not exist", boardID)
+	}
+	// Спринты принадлежат доске, поэтому при переносе задача уходит в бэклог
+	previous := task
+	if task.BoardID != boardID {
+		task.SprintID = nil
+	}
+	task.BoardID = boardID
+	task.Status = status
+	task.UpdatedAt = time.Now()
+	r.store.tasks[id] = task
+	switch {
+	case previous.BoardID != boardID:
+		r.store.recordStatusChange(task, nil)
+		if previous.SprintID != nil {
+			r.store.recordScopeChange(task, *previous.SprintID, models.SprintScopeRemoved)
+		}
+	case previous.Status != status:
+		r.store.recordStatusChange(task, &previous.Status)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) ReassignStatus(ctx context.Context, boardID uuid.UUID, from, to string) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var n int64
+	now := time.Now()
+	for id, task := range r.store.tasks {
+		if task.BoardID == boardID && task.Status == from && task.DeletedAt == nil {
+			task.Status = to
+			task.UpdatedAt = now
+			r.store.tasks[id] = task
+			r.store.recordStatusChange(task, &from)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+func (r *TaskRepository) Archive(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.DeletedAt != nil || task.ArchivedAt != nil {
+		return repository.ErrNotFound
+	}
+	now := time.Now()
+	task.ArchivedAt = &now
+	r.store.tasks[id] = task
+
+	return nil
+}
+
+func (r *TaskRepository) Unarchive(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.DeletedAt != nil || task.ArchivedAt == nil {
+		return repository.ErrNotFound
+	}
+	task.ArchivedAt = nil
+	r.store.tasks[id] = task
+
+	return nil
+}
+
+func (r *TaskRepository) ArchiveByStatus(ctx context.Context, boardID uuid.UUID, status string, updatedBefore time.Time) ([]uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var ids []uuid.UUID
+	now := time.Now()
+	for id, task := range r.store.tasks {
+		if task.BoardID == boardID && task.Status == status && task.UpdatedAt.Before(updatedBefore) &&
+			task.ArchivedAt == nil && task.DeletedAt == nil {
+			task.ArchivedAt = &now
+			r.store.tasks[id] = task
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+func (r *TaskRepository) UnarchiveByStatus(ctx context.Context, boardID uuid.UUID, status string, archivedSince time.Time) ([]uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var ids []uuid.UUID
+	for id, task := range r.store.tasks {
+		if task.BoardID == boardID && task.Status == status && task.ArchivedAt != nil &&
+			!task.ArchivedAt.Before(archivedSince) && task.DeletedAt == nil {
+			task.ArchivedAt = nil
+			r.store.tasks[id] = task
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+func (r *TaskRepository) ListSubtasks(ctx context.Context, parentID uuid.UUID) ([]models.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tasks []models.Task
+	for _, task := range r.store.tasks {
+		if task.ParentTaskID != nil && *task.ParentTaskID == parentID && task.DeletedAt == nil {
+			tasks = append(tasks, r.store.withProgress(task))
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+	return tasks, nil
+}
+
+func (r *TaskRepository) SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+	if parentID != nil {
+		if _, ok := r.store.tasks[*parentID]; !ok {
+			return fmt.Errorf("task %s does not exist", *parentID)
+		}
+		parent := *parentID
+		parentID = &parent
+	}
+	task.ParentTaskID = parentID
+	task.UpdatedAt = time.Now()
+	r.store.tasks[id] = task
+
+	return nil
+}
+
+func (r *TaskRepository) ListBySprint(ctx context.Context, sprintID uuid.UUID) ([]models.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tasks []models.Task
+	for _, task := range r.store.tasks {
+		if task.SprintID != nil && *task.SprintID == sprintID && task.DeletedAt == nil {
+			tasks = append(tasks, r.store.withProgress(task))
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+	return tasks, nil
+}
+
+func (r *TaskRepository) ListByUser(ctx context.Context, userID uuid.UUID, username string) ([]models.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tasks []models.Task
+	for _, task := range r.store.tasks {
+		if task.DeletedAt != nil {
+			continue
+		}
+		if (task.CreatedBy != nil && *task.CreatedBy == userID) || (task.Assignee != nil && *task.Assignee == username) {
+			tasks = append(tasks, r.store.withProgress(task))
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+	return tasks, nil
+}
+
+func (r *TaskRepository) SetSprint(ctx context.Context, id uuid.UUID, sprintID *uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	task, ok := r.store.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return repository.ErrNotFound
+	}
+	if sprintID != nil {
+		if _, ok := r.store.sprints[*sprintID]; !ok {
+			return repository.ErrNotFound
+		}
+		sprint := *sprintID
+		sprintID = &sprint
+	}
+	previous := task.SprintID
+	task.SprintID = sprintID
+	task.UpdatedAt = time.Now()
+	r.store.tasks[id] = task
+	if previous != nil && (sprintID == nil || *previous != *sprintID) {
+		r.store.recordScopeChange(task, *previous, models.SprintScopeRemoved)
+	}
+	if sprintID != nil && (previous == nil || *previous != *sprintID) {
+		r.store.recordScopeChange(task, *sprintID, models.SprintScopeAdded)
+	}
+
+	return nil
+}
+
+func (r *TaskRepository) ListStatusChanges(ctx context.Context, boardID uuid.UUID) ([]models.TaskStatusChange, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	changes := []models.TaskStatusChange{}
+	for _, change := range r.store.statusChanges {
+		task, ok := r.store.tasks[change.TaskID]
+		if change.BoardID == boardID && ok && task.BoardID == boardID && task.DeletedAt == nil {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// recordStatusChange добавляет в историю попадание task в ее текущий статус,
+// from - прежний статус на той же доске. Вызывается под s.mu.
+func (s *Store) recordStatusChange(task models.Task, from *string) {
+	if from != nil {
+		previous := *from
+		from = &previous
+	}
+	s.statusChanges = append(s.statusChanges, models.TaskStatusChange{
+		ID:         uuid.New(),
+		TaskID:     task.ID,
+		BoardID:    task.BoardID,
+		FromStatus: from,
+		ToStatus:   task.Status,
+		ChangedAt:  task.UpdatedAt,
+	})
+}
+
+// recordScopeChange добавляет в историю состава спринта sprintID изменение
+// change задачи task с ее текущей оценкой. Вызывается под s.mu.
+func (s *Store) recordScopeChange(task models.Task, sprintID uuid.UUID, change string) {
+	var points *int
+	if task.StoryPoints != nil {
+		value := *task.StoryPoints
+		points = &value
+	}
+	s.scopeChanges = append(s.scopeChanges, models.SprintScopeChange{
+		ID:        uuid.New(),
+		SprintID:  sprintID,
+		TaskID:    task.ID,
+		Change:    change,
+		Points:    points,
+		ChangedAt: task.UpdatedAt,
+	})
+}
+
+func samePoints(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// withProgress заполняет task.Progress по чек-листу и подзадачам, вызывается под s.mu
+func (s *Store) withProgress(task models.Task) models.Task {
+	task.Progress = models.TaskProgress{}
+	for _, item := range s.checklist {
+		if item.TaskID == task.ID {
+			task.Progress.Total++
+			if item.Checked {
+				task.Progress.Done++
+			}
+		}
+	}
+	for _, sub := range s.tasks {
+		if sub.ParentTaskID == nil || *sub.ParentTaskID != task.ID || sub.DeletedAt != nil {
+			continue
+		}
+		task.Progress.Total++
+		if done, ok := s.doneStatus(sub.BoardID); ok && sub.Status == done {
+			task.Progress.Done++
+		}
+	}
+	return task
+}
+
+// doneStatus возвращает статус последней активной колонки доски, вызывается под s.mu
+func (s *Store) doneStatus(boardID uuid.UUID) (string, bool) {
+	var last *models.Column
+	for _, column := range s.columns {
+		if column.BoardID != boardID || column.DeletedAt != nil || column.ArchivedAt != nil {
+			continue
+		}
+		if last == nil || column.Position > last.Position {
+			c := column
+			last = &c
+		}
+	}
+	if last == nil {
+		return "", false
+	}
+	return last.StatusID, true
+}
+
+// boardTasks возвращает неархивные задачи доски, вызывается под s.mu
+func (s *Store) boardTasks(boardID uuid.UUID) []models.Task {
+	return s.listTasks(boardID, repository.ListOptions{})
+}
+
+// listTasks вызывается под s.mu
+func (s *Store) listTasks(boardID uuid.UUID, opts repository.ListOptions) []models.Task {
+	var tasks []models.Task
+	for _, task := range s.tasks {
+		if task.BoardID == boardID && task.DeletedAt == nil && (opts.IncludeArchived || task.ArchivedAt == nil) {
+			tasks = append(tasks, s.withProgress(task))
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.After(tasks[j].CreatedAt)
+	})
+	return tasks
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TemplateRepository struct {
+	store *Store
+}
+
+func (r *TemplateRepository) List(ctx context.Context) ([]models.BoardTemplate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	templates := make([]models.BoardTemplate, 0, len(r.store.templates))
+	for _, tpl := range r.store.templates {
+		templates = append(templates, cloneTemplate(tpl))
+	}
+	return templates, nil
+}
+
+func (r *TemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.BoardTemplate, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, tpl := range r.store.templates {
+		if tpl.ID == id.String() {
+			tpl = cloneTemplate(tpl)
+			return &tpl, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *TemplateRepository) Create(ctx context.Context, tpl *models.BoardTemplate) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tpl.ID = uuid.NewString()
+	tpl.BuiltIn = false
+	tpl.CreatedAt = time.Now()
+	r.store.templates = append(r.store.templates, cloneTemplate(*tpl))
+
+	return nil
+}
+
+func (r *TemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for i, tpl := range r.store.templates {
+		if tpl.ID == id.String() {
+			r.store.templates = slices.Delete(slices.Clone(r.store.templates), i, i+1)
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+// cloneTemplate копирует срезы шаблона, чтобы вызывающий не менял данные Store
+func cloneTemplate(tpl models.BoardTemplate) models.BoardTemplate {
+	tpl.Columns = slices.Clone(tpl.Columns)
+	tpl.Labels = slices.Clone(tpl.Labels)
+	tpl.Tasks = slices.Clone(tpl.Tasks)
+	return tpl
+}
@@ -0,0 +1,263 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashRepository struct {
+	store *Store
+}
+
+func (r *TrashRepository) List(ctx context.Context) ([]models.TrashItem, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var items []models.TrashItem
+	for _, board := range r.store.boards {
+		if board.DeletedAt != nil {
+			items = append(items, models.TrashItem{Type: models.TrashTypeBoard, ID: board.ID, BoardID: board.ID, Title: board.Name, DeletedAt: *board.DeletedAt})
+		}
+	}
+	for _, column := range r.store.columns {
+		if column.DeletedAt != nil && r.store.boardActive(column.BoardID) {
+			items = append(items, models.TrashItem{Type: models.TrashTypeColumn, ID: column.ID, BoardID: column.BoardID, Title: column.Title, DeletedAt: *column.DeletedAt})
+		}
+	}
+	for _, task := range r.store.tasks {
+		if task.DeletedAt != nil && r.store.boardActive(task.BoardID) {
+			items = append(items, models.TrashItem{Type: models.TrashTypeTask, ID: task.ID, BoardID: task.BoardID, Title: task.Title, DeletedAt: *task.DeletedAt})
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+
+	return items, nil
+}
+
+func (r *TrashRepository) Restore(ctx context.Context, itemType string, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	switch itemType {
+	case models.TrashTypeBoard:
+		board, ok := r.store.boards[id]
+		if !ok || board.DeletedAt == nil {
+			return repository.ErrNotFound
+		}
+		deletedAt := *board.DeletedAt
+		for columnID, column := range r.store.columns {
+			if column.BoardID == id && column.DeletedAt != nil && column.DeletedAt.Equal(deletedAt) {
+				column.DeletedAt = nil
+				r.store.columns[columnID] = column
+			}
+		}
+		for taskID, task := range r.store.tasks {
+			if task.BoardID == id && task.DeletedAt != nil && task.DeletedAt.Equal(deletedAt) {
+				task.DeletedAt = nil
+				r.store.tasks[taskID] = task
+			}
+		}
+		board.DeletedAt = nil
+		r.store.boards[id] = board
+		return nil
+
+	case models.TrashTypeColumn:
+		column, ok := r.store.columns[id]
+		if !ok || column.DeletedAt == nil || !r.store.boardActive(column.BoardID) {
+			return repository.ErrNotFound
+		}
+		for _, existing := range r.store.columns {
+			if existing.BoardID == column.BoardID && existing.StatusID == column.StatusID && existing.DeletedAt == nil {
+				return repository.ErrConflict
+			}
+		}
+		column.DeletedAt = nil
+		r.store.columns[id] = column
+		return nil
+
+	case models.TrashTypeTask:
+		task, ok := r.store.tasks[id]
+		if !ok || task.DeletedAt == nil || !r.store.boardActive(task.BoardID) {
+			return repository.ErrNotFound
+		}
+		task.DeletedAt = nil
+		r.store.tasks[id] = task
+		return nil
+	}
+
+	return fmt.Errorf("unknown trash item type %q", itemType)
+}
+
+func (r *TrashRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	expired := func(deletedAt *time.Time) bool {
+		return deletedAt != nil && deletedAt.Before(before)
+	}
+
+	var n int64
+	for id, task := range r.store.tasks {
+		if expired(task.DeletedAt) {
+			r.store.deleteTask(id)
+			n++
+		}
+	}
+	for id, column := range r.store.columns {
+		if expired(column.DeletedAt) {
+			delete(r.store.columns, id)
+			n++
+		}
+	}
+	for id, board := range r.store.boards {
+		if expired(board.DeletedAt) {
+			r.store.deleteBoard(id)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// boardActive вызывается под s.mu
+func (s *Store) boardActive(id uuid.UUID) bool {
+	board, ok := s.boards[id]
+	return ok && board.DeletedAt == nil
+}
+
+// deleteBoard окончательно удаляет доску и все связанные с ней данные,
+// как ON DELETE CASCADE в Postgres, и отвязывает от нее задания импорта.
+// Вызывается под s.mu.
+func (s *Store) deleteBoard(id uuid.UUID) {
+	delete(s.boards, id)
+	for columnID, column := range s.columns {
+		if column.BoardID == id {
+			delete(s.columns, columnID)
+		}
+	}
+	for taskID, task := range s.tasks {
+		if task.BoardID == id {
+			s.deleteTask(taskID)
+		}
+	}
+	for key := range s.members {
+		if key.boardID == id {
+			delete(s.members, key)
+		}
+	}
+	for labelID, label := range s.labels {
+		if label.BoardID == id {
+			delete(s.labels, labelID)
+		}
+	}
+	delete(s.archiveRules, id)
+	delete(s.workflows, id)
+	s.statusChanges = slices.DeleteFunc(s.statusChanges, func(change models.TaskStatusChange) bool {
+		return change.BoardID == id
+	})
+	for sprintID, sprint := range s.sprints {
+		if sprint.BoardID == id {
+			s.deleteSprint(sprintID)
+		}
+	}
+	for recurringID, recurring := range s.recurringTasks {
+		if recurring.BoardID == id {
+			delete(s.recurringTasks, recurringID)
+		}
+	}
+	for ruleID, rule := range s.automationRules {
+		if rule.BoardID == id {
+			delete(s.automationRules, ruleID)
+		}
+	}
+	for runID, run := range s.automationRuns {
+		if run.BoardID == id {
+			delete(s.automationRuns, runID)
+		}
+	}
+	for delayID, delay := range s.automationDelays {
+		if _, ok := s.automationRules[delay.RuleID]; !ok {
+			delete(s.automationDelays, delayID)
+		}
+	}
+	for jobID, job := range s.importJobs {
+		if job.BoardID != nil && *job.BoardID == id {
+			job.BoardID = nil
+			s.importJobs[jobID] = job
+		}
+	}
+}
+
+// deleteTask окончательно удаляет задачу со всеми зависимыми данными (чек-лист,
+// связи, вложения, учет времени, комментарии, отложенные действия) и отвязывает подзадачи и записи
+// журнала автоматизации, как ON DELETE CASCADE / SET NULL в Postgres.
+// Вызывается под s.mu.
+func (s *Store) deleteTask(id uuid.UUID) {
+	delete(s.tasks, id)
+	s.statusChanges = slices.DeleteFunc(s.statusChanges, func(change models.TaskStatusChange) bool {
+		return change.TaskID == id
+	})
+	s.scopeChanges = slices.DeleteFunc(s.scopeChanges, func(change models.SprintScopeChange) bool {
+		return change.TaskID == id
+	})
+	for itemID, item := range s.checklist {
+		if item.TaskID == id {
+			delete(s.checklist, itemID)
+		}
+	}
+	for linkID, link := range s.links {
+		if link.SourceTaskID == id || link.TargetTaskID == id {
+			delete(s.links, linkID)
+		}
+	}
+	for attachmentID, attachment := range s.attachments {
+		if attachment.TaskID == id {
+			delete(s.attachments, attachmentID)
+		}
+	}
+	for worklogID, worklog := range s.worklogs {
+		if worklog.TaskID == id {
+			delete(s.worklogs, worklogID)
+		}
+	}
+	for commentID, comment := range s.comments {
+		if comment.TaskID == id {
+			delete(s.comments, commentID)
+		}
+	}
+	for delayID, delay := range s.automationDelays {
+		if delay.TaskID == id {
+			delete(s.automationDelays, delayID)
+		}
+	}
+	for runID, run := range s.automationRuns {
+		if run.TaskID != nil && *run.TaskID == id {
+			run.TaskID = nil
+			s.automationRuns[runID] = run
+		}
+	}
+	for taskID, task := range s.tasks {
+		if task.ParentTaskID != nil && *task.ParentTaskID == id {
+			task.ParentTaskID = nil
+			s.tasks[taskID] = task
+		}
+	}
+}
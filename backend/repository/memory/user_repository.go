@@ -0,0 +1,274 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserRepository struct {
+	store *Store
+}
+
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.find(ctx, func(u models.User) bool { return u.Username == username })
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.find(ctx, func(u models.User) bool { return u.Email == email })
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return r.find(ctx, func(u models.User) bool { return u.ID == id })
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User, password string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	hashedPassword, err := repository.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.users {
+		if existing.Username == user.Username || existing.Email == user.Email {
+			return repository.ErrConflict
+		}
+	}
+
+	user.ID = uuid.New()
+	user.PasswordHash = hashedPassword
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	if user.Role == "" {
+		user.Role = models.UserRoleUser
+	}
+	if user.Status == "" {
+		user.Status = models.UserStatusActive
+	}
+	r.store.users[user.ID] = *user
+
+	return nil
+}
+
+func (r *UserRepository) List(ctx context.Context) ([]models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := make([]models.User, 0, len(r.store.users))
+	for _, user := range r.store.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+
+	return users, nil
+}
+
+// LockAdmins ничего не блокирует: транзакции хранилища и так выполняются по очереди
+func (r *UserRepository) LockAdmins(ctx context.Context) ([]uuid.UUID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var ids []uuid.UUID
+	for _, user := range r.store.users {
+		if user.Role == models.UserRoleAdmin && user.Status != models.UserStatusDisabled {
+			ids = append(ids, user.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	return ids, nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	return r.update(ctx, user.ID, func(stored *models.User) error {
+		for _, existing := range r.store.users {
+			if existing.ID != user.ID && existing.Email == user.Email {
+				return repository.ErrConflict
+			}
+		}
+		stored.Email = user.Email
+		stored.Role = user.Role
+		stored.Status = user.Status
+		stored.LockedUntil = user.LockedUntil
+		user.UpdatedAt = time.Now()
+		stored.UpdatedAt = user.UpdatedAt
+		return nil
+	})
+}
+
+func (r *UserRepository) SetPassword(ctx context.Context, id uuid.UUID, password string) error {
+	hashedPassword, err := repository.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return r.update(ctx, id, func(stored *models.User) error {
+		stored.PasswordHash = hashedPassword
+		stored.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func (r *UserRepository) RevokeTokens(ctx context.Context, id uuid.UUID) error {
+	return r.update(ctx, id, func(stored *models.User) error {
+		stored.TokenVersion++
+		stored.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	return r.update(ctx, id, func(stored *models.User) error {
+		if stored.EmailVerifiedAt == nil {
+			now := time.Now()
+			stored.EmailVerifiedAt = &now
+			stored.UpdatedAt = now
+		}
+		return nil
+	})
+}
+
+// Delete повторяет внешние ключи Postgres: ссылки на пользователя
+// обнуляются, его членства, записи времени и токены из писем удаляются
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+
+	for taskID, task := range s.tasks {
+		task.CreatedBy = unlessUser(task.CreatedBy, id)
+		task.Assignee = unlessAssignee(task.Assignee, user.Username)
+		s.tasks[taskID] = task
+	}
+	for itemID, item := range s.checklist {
+		item.Assignee = unlessAssignee(item.Assignee, user.Username)
+		s.checklist[itemID] = item
+	}
+	for recurringID, recurring := range s.recurringTasks {
+		recurring.CreatedBy = unlessUser(recurring.CreatedBy, id)
+		recurring.Assignee = unlessAssignee(recurring.Assignee, user.Username)
+		s.recurringTasks[recurringID] = recurring
+	}
+	for boardID, board := range s.boards {
+		board.UserID = unlessUser(board.UserID, id)
+		s.boards[boardID] = board
+	}
+	for i := range s.templates {
+		s.templates[i].CreatedBy = unlessUser(s.templates[i].CreatedBy, id)
+	}
+	for linkID, link := range s.links {
+		link.CreatedBy = unlessUser(link.CreatedBy, id)
+		s.links[linkID] = link
+	}
+	for attachmentID, attachment := range s.attachments {
+		attachment.UploadedBy = unlessUser(attachment.UploadedBy, id)
+		s.attachments[attachmentID] = attachment
+	}
+	for commentID, comment := range s.comments {
+		comment.UserID = unlessUser(comment.UserID, id)
+		s.comments[commentID] = comment
+	}
+	for ruleID, rule := range s.automationRules {
+		rule.CreatedBy = unlessUser(rule.CreatedBy, id)
+		s.automationRules[ruleID] = rule
+	}
+	for sprintID, sprint := range s.sprints {
+		sprint.CreatedBy = unlessUser(sprint.CreatedBy, id)
+		s.sprints[sprintID] = sprint
+	}
+	for jobID, job := range s.importJobs {
+		job.UserID = unlessUser(job.UserID, id)
+		s.importJobs[jobID] = job
+	}
+	for key := range s.members {
+		if key.userID == id {
+			delete(s.members, key)
+		}
+	}
+	for worklogID, worklog := range s.worklogs {
+		if worklog.UserID == id {
+			delete(s.worklogs, worklogID)
+		}
+	}
+	for tokenID, token := range s.userTokens {
+		if token.UserID == id {
+			delete(s.userTokens, tokenID)
+		}
+	}
+	delete(s.users, id)
+
+	return nil
+}
+
+// unlessUser обнуляет ссылку на удаляемого пользователя id
+func unlessUser(ref *uuid.UUID, id uuid.UUID) *uuid.UUID {
+	if ref != nil && *ref == id {
+		return nil
+	}
+	return ref
+}
+
+func unlessAssignee(assignee *string, username string) *string {
+	if assignee != nil && *assignee == username {
+		return nil
+	}
+	return assignee
+}
+
+// update применяет change к пользователю id под блокировкой хранилища
+func (r *UserRepository) update(ctx context.Context, id uuid.UUID, change func(*models.User) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.users[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if err := change(&stored); err != nil {
+		return err
+	}
+	r.store.users[id] = stored
+
+	return nil
+}
+
+func (r *UserRepository) find(ctx context.Context, match func(models.User) bool) (*models.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if match(user) {
+			return &user, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserTokenRepository struct {
+	store *Store
+}
+
+func (r *UserTokenRepository) Create(ctx context.Context, token *models.UserToken) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[token.UserID]; !ok {
+		return repository.ErrNotFound
+	}
+	token.CreatedAt = time.Now()
+	for id, existing := range r.store.userTokens {
+		if existing.TokenHash == token.TokenHash {
+			return repository.ErrConflict
+		}
+		if existing.UserID == token.UserID && existing.Purpose == token.Purpose && existing.UsedAt == nil {
+			existing.UsedAt = &token.CreatedAt
+			r.store.userTokens[id] = existing
+		}
+	}
+
+	token.ID = uuid.New()
+	r.store.userTokens[token.ID] = *token
+
+	return nil
+}
+
+func (r *UserTokenRepository) Consume(ctx context.Context, purpose, tokenHash string, now time.Time) (*models.UserToken, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, token := range r.store.userTokens {
+		if token.TokenHash != tokenHash || token.Purpose != purpose {
+			continue
+		}
+		if token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+			return nil, repository.ErrNotFound
+		}
+		token.UsedAt = &now
+		r.store.userTokens[id] = token
+		return &token, nil
+	}
+	return nil, repository.ErrNotFound
+}
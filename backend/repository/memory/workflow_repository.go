@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+)
+
+type WorkflowRepository struct {
+	store *Store
+}
+
+func (r *WorkflowRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.WorkflowTransition, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	transitions := make([]models.WorkflowTransition, 0, len(r.store.workflows[boardID]))
+	for _, transition := range r.store.workflows[boardID] {
+		transition.RequiredFields = slices.Clone(transition.RequiredFields)
+		transitions = append(transitions, transition)
+	}
+	sort.Slice(transitions, func(i, j int) bool {
+		if transitions[i].From != transitions[j].From {
+			return transitions[i].From < transitions[j].From
+		}
+		return transitions[i].To < transitions[j].To
+	})
+
+	return transitions, nil
+}
+
+func (r *WorkflowRepository) Replace(ctx context.Context, boardID uuid.UUID, transitions []models.WorkflowTransition) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.boardActive(boardID) {
+		return repository.ErrNotFound
+	}
+
+	seen := make(map[[2]string]bool)
+	stored := make([]models.WorkflowTransition, 0, len(transitions))
+	for _, transition := range transitions {
+		key := [2]string{transition.From, transition.To}
+		if seen[key] {
+			return repository.ErrConflict
+		}
+		seen[key] = true
+		transition.BoardID = boardID
+		transition.RequiredFields = slices.Clone(transition.RequiredFields)
+		if transition.RequiredFields == nil {
+			transition.RequiredFields = []string{}
+		}
+		stored = append(stored, transition)
+	}
+	r.store.workflows[boardID] = stored
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorklogRepository struct {
+	store *Store
+}
+
+func (r *WorklogRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.Worklog, error) {
+	return r.list(ctx, func(worklog models.Worklog) bool {
+		return worklog.TaskID == taskID
+	})
+}
+
+func (r *WorklogRepository) List(ctx context.Context, filter repository.WorklogFilter) ([]models.Worklog, error) {
+	return r.list(ctx, func(worklog models.Worklog) bool {
+		switch {
+		case worklog.EndedAt == nil:
+			return false
+		case filter.BoardID != nil && r.store.tasks[worklog.TaskID].BoardID != *filter.BoardID:
+			return false
+		case filter.UserID != nil && worklog.UserID != *filter.UserID:
+			return false
+		case filter.From != nil && worklog.StartedAt.Before(*filter.From):
+			return false
+		case filter.To != nil && !worklog.StartedAt.Before(*filter.To):
+			return false
+		}
+		return true
+	})
+}
+
+func (r *WorklogRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Worklog, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	worklog, ok := r.store.worklogs[id]
+	if !ok || !r.store.taskActive(worklog.TaskID) {
+		return nil, repository.ErrNotFound
+	}
+	return &worklog, nil
+}
+
+func (r *WorklogRepository) GetRunning(ctx context.Context, userID uuid.UUID) (*models.Worklog, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, worklog := range r.store.worklogs {
+		if worklog.UserID == userID && worklog.EndedAt == nil {
+			return &worklog, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *WorklogRepository) Create(ctx context.Context, worklog *models.Worklog) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tasks[worklog.TaskID]; !ok {
+		return repository.ErrNotFound
+	}
+	if worklog.EndedAt == nil {
+		for _, existing := range r.store.worklogs {
+			if existing.UserID == worklog.UserID && existing.EndedAt == nil {
+				return repository.ErrConflict
+			}
+		}
+	}
+
+	worklog.ID = uuid.New()
+	worklog.CreatedAt = time.Now()
+	r.store.worklogs[worklog.ID] = *worklog
+
+	return nil
+}
+
+func (r *WorklogRepository) Finish(ctx context.Context, worklog *models.Worklog) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.worklogs[worklog.ID]
+	if !ok || stored.EndedAt != nil {
+		return repository.ErrNotFound
+	}
+	stored.EndedAt = worklog.EndedAt
+	stored.DurationMinutes = worklog.DurationMinutes
+	stored.Note = worklog.Note
+	r.store.worklogs[worklog.ID] = stored
+
+	return nil
+}
+
+func (r *WorklogRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.worklogs[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.worklogs, id)
+
+	return nil
+}
+
+func (r *WorklogRepository) list(ctx context.Context, match func(models.Worklog) bool) ([]models.Worklog, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var worklogs []models.Worklog
+	for _, worklog := range r.store.worklogs {
+		if r.store.taskActive(worklog.TaskID) && match(worklog) {
+			worklogs = append(worklogs, worklog)
+		}
+	}
+	sort.Slice(worklogs, func(i, j int) bool {
+		return worklogs[i].StartedAt.Before(worklogs[j].StartedAt)
+	})
+	return worklogs, nil
+}
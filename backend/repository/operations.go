@@ -0,0 +1,733 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownStatus  = errors.New("status does not match any column of the board")
+	ErrColumnNotEmpty = errors.New("column has tasks and there is no other column to move them to")
+	ErrInvalidParent  = errors.New("parent task must exist on the same board")
+	ErrTaskCycle      = errors.New("task cannot be a subtask of itself or of its subtasks")
+	ErrChecklistOrder = errors.New("item_ids must list every checklist item of the task exactly once")
+)
+
+// CreateBoard атомарно создает доску по шаблону tpl (колонки, метки,
+// примеры задач) и членство создателя.
+func (r Repositories) CreateBoard(ctx context.Context, board *models.Board, tpl models.BoardTemplate) error {
+	return r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.Boards.Create(ctx, board); err != nil {
+			return err
+		}
+
+		for i, c := range tpl.Columns {
+			column := &models.Column{
+				BoardID:  board.ID,
+				Title:    c.Title,
+				StatusID: c.StatusID,
+				Position: i,
+				WIPLimit: c.WIPLimit,
+			}
+			if err := r.Columns.Create(ctx, column); err != nil {
+				return fmt.Errorf("failed to create column %s: %w", c.StatusID, err)
+			}
+		}
+
+		for _, l := range tpl.Labels {
+			label := &models.Label{BoardID: board.ID, Name: l.Name, Color: l.Color}
+			if err := r.Labels.Create(ctx, label); err != nil {
+				return fmt.Errorf("failed to create label %s: %w", l.Name, err)
+			}
+		}
+
+		for _, t := range tpl.Tasks {
+			task := &models.Task{
+				BoardID:     board.ID,
+				Title:       t.Title,
+				Description: t.Description,
+				Status:      t.Status,
+				Priority:    t.Priority,
+				CreatedBy:   board.UserID,
+			}
+			if err := r.Tasks.Create(ctx, task); err != nil {
+				return fmt.Errorf("failed to create task %q: %w", t.Title, err)
+			}
+		}
+
+		if board.UserID != nil {
+			owner := &models.BoardMember{BoardID: board.ID, UserID: *board.UserID, Role: models.BoardRoleOwner}
+			if err := r.Members.Add(ctx, owner); err != nil {
+				return fmt.Errorf("failed to add board owner: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// TransferBoardOwnership делает userID создателем и единственным владельцем
+// доски; прежние владельцы остаются ее участниками.
+func (r Repositories) TransferBoardOwnership(ctx context.Context, boardID, userID uuid.UUID) error {
+	return r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.Users.GetByID(ctx, userID); err != nil {
+			return err
+		}
+		if err := r.Boards.SetOwner(ctx, boardID, userID); err != nil {
+			return err
+		}
+
+		members, err := r.Members.ListByBoard(ctx, boardID)
+		if err != nil {
+			return err
+		}
+		isMember := false
+		for _, member := range members {
+			if member.UserID == userID {
+				isMember = true
+				if member.Role != models.BoardRoleOwner {
+					if err := r.Members.SetRole(ctx, boardID, userID, models.BoardRoleOwner); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if member.Role == models.BoardRoleOwner {
+				if err := r.Members.SetRole(ctx, boardID, member.UserID, models.BoardRoleMember); err != nil {
+					return err
+				}
+			}
+		}
+		if isMember {
+			return nil
+		}
+		owner := &models.BoardMember{BoardID: boardID, UserID: userID, Role: models.BoardRoleOwner}
+		return r.Members.Add(ctx, owner)
+	})
+}
+
+// SaveBoardAsTemplate сохраняет колонки и метки доски (и задачи, если
+// includeTasks) в шаблон tpl. Name и Description шаблона задает вызывающий.
+func (r Repositories) SaveBoardAsTemplate(ctx context.Context, boardID uuid.UUID, tpl *models.BoardTemplate, includeTasks bool) error {
+	return r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.Boards.GetByID(ctx, boardID); err != nil {
+			return err
+		}
+
+		columns, err := r.Columns.ListByBoard(ctx, boardID, ListOptions{})
+		if err != nil {
+			return err
+		}
+		tpl.Columns = make([]models.TemplateColumn, 0, len(columns))
+		for _, c := range columns {
+			tpl.Columns = append(tpl.Columns, models.TemplateColumn{Title: c.Title, StatusID: c.StatusID, WIPLimit: c.WIPLimit})
+		}
+
+		labels, err := r.Labels.ListByBoard(ctx, boardID)
+		if err != nil {
+			return err
+		}
+		tpl.Labels = nil
+		for _, l := range labels {
+			tpl.Labels = append(tpl.Labels, models.TemplateLabel{Name: l.Name, Color: l.Color})
+		}
+
+		tpl.Tasks = nil
+		if includeTasks {
+			tasks, err := r.Tasks.ListByBoard(ctx, boardID, ListOptions{})
+			if err != nil {
+				return err
+			}
+			for _, t := range tasks {
+				tpl.Tasks = append(tpl.Tasks, models.TemplateTask{
+					Title:       t.Title,
+					Description: t.Description,
+					Status:      t.Status,
+					Priority:    t.Priority,
+				})
+			}
+		}
+
+		return r.Templates.Create(ctx, tpl)
+	})
+}
+
+// DeleteColumn удаляет колонку и переводит ее задачи в колонку со статусом targetStatus.
+// Пустой targetStatus означает первую по порядку оставшуюся колонку доски.
+// Возвращает удаленную колонку и перемещенные задачи.
+func (r Repositories) DeleteColumn(ctx context.Context, id uuid.UUID, targetStatus string) (*models.Column, []models.Task, error) {
+	var deleted *models.Column
+	var moved []models.Task
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		column, err := r.Columns.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		columns, err := r.Columns.ListByBoard(ctx, column.BoardID, ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		tasks, err := r.Tasks.ListByBoard(ctx, column.BoardID, ListOptions{IncludeArchived: true})
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if task.Status == column.StatusID {
+				moved = append(moved, task)
+			}
+		}
+
+		if len(moved) > 0 {
+			target, err := reassignTarget(columns, column, targetStatus)
+			if err != nil {
+				return err
+			}
+			if _, err := r.Tasks.ReassignStatus(ctx, column.BoardID, column.StatusID, target); err != nil {
+				return err
+			}
+			for i := range moved {
+				moved[i].Status = target
+			}
+		}
+
+		if err := r.Columns.Delete(ctx, id); err != nil {
+			return err
+		}
+
+		deleted = column
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return deleted, moved, nil
+}
+
+// MoveTasks атомарно переводит задачи в статус status: если хотя бы одна
+// задача не найдена или заблокирована (см. CheckMoveAllowed), ни одна не перемещается.
+func (r Repositories) MoveTasks(ctx context.Context, ids []uuid.UUID, status string) ([]models.Task, error) {
+	var moved []models.Task
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		moved = moved[:0]
+		for _, id := range ids {
+			current, err := r.Tasks.GetByID(ctx, id)
+			if err != nil {
+				return fmt.Errorf("task %s: %w", id, err)
+			}
+			if err := r.CheckMoveAllowed(ctx, current, status); err != nil {
+				return fmt.Errorf("task %s: %w", id, err)
+			}
+			if err := r.Tasks.Move(ctx, id, status); err != nil {
+				return fmt.Errorf("task %s: %w", id, err)
+			}
+			task, err := r.Tasks.GetByID(ctx, id)
+			if err != nil {
+				return fmt.Errorf("task %s: %w", id, err)
+			}
+			moved = append(moved, *task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return moved, nil
+}
+
+// DuplicateOptions определяет, что копируется вместе с колонками доски
+type DuplicateOptions struct {
+	IncludeTasks bool
+	// IncludeComments учитывается только вместе с IncludeTasks
+	IncludeComments bool
+	IncludeLabels   bool
+	IncludeMembers  bool
+}
+
+// DuplicateBoard создает board как копию доски sourceID. Пустое имя
+// заменяется именем исходной доски с пометкой "(копия)". Создатель новой
+// доски становится ее владельцем, остальные участники копируются как member.
+func (r Repositories) DuplicateBoard(ctx context.Context, sourceID uuid.UUID, board *models.Board, opts DuplicateOptions) error {
+	return r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		source, err := r.Boards.GetByID(ctx, sourceID)
+		if err != nil {
+			return err
+		}
+		if board.Name == "" {
+			board.Name = source.Name + " (копия)"
+		}
+		if board.Description == nil {
+			board.Description = source.Description
+		}
+		board.EnforceDependencies = source.EnforceDependencies
+
+		columns, err := r.Columns.ListByBoard(ctx, sourceID, ListOptions{})
+		if err != nil {
+			return err
+		}
+		var tpl models.BoardTemplate
+		for _, c := range columns {
+			tpl.Columns = append(tpl.Columns, models.TemplateColumn{Title: c.Title, StatusID: c.StatusID, WIPLimit: c.WIPLimit})
+		}
+
+		if opts.IncludeLabels {
+			labels, err := r.Labels.ListByBoard(ctx, sourceID)
+			if err != nil {
+				return err
+			}
+			for _, l := range labels {
+				tpl.Labels = append(tpl.Labels, models.TemplateLabel{Name: l.Name, Color: l.Color})
+			}
+		}
+
+		if err := r.CreateBoard(ctx, board, tpl); err != nil {
+			return err
+		}
+
+		if opts.IncludeTasks {
+			tasks, err := r.Tasks.ListByBoard(ctx, sourceID, ListOptions{})
+			if err != nil {
+				return err
+			}
+			// Задачи отсортированы от новых к старым: копируем с конца, чтобы сохранить порядок
+			copies := make(map[uuid.UUID]uuid.UUID, len(tasks))
+			for i := len(tasks) - 1; i >= 0; i-- {
+				task := tasks[i]
+				task.BoardID = board.ID
+				task.ParentTaskID = nil
+				task.SprintID = nil
+				if err := r.Tasks.Create(ctx, &task); err != nil {
+					return fmt.Errorf("failed to copy task %s: %w", tasks[i].ID, err)
+				}
+				copies[tasks[i].ID] = task.ID
+			}
+			// Связи подзадач переносятся между копиями
+			for _, task := range tasks {
+				if task.ParentTaskID == nil {
+					continue
+				}
+				parentID, ok := copies[*task.ParentTaskID]
+				if !ok {
+					continue
+				}
+				if err := r.Tasks.SetParent(ctx, copies[task.ID], &parentID); err != nil {
+					return fmt.Errorf("failed to copy subtask link of %s: %w", task.ID, err)
+				}
+			}
+			if opts.IncludeComments {
+				if err := r.copyComments(ctx, copies); err != nil {
+					return err
+				}
+			}
+		}
+
+		if opts.IncludeMembers {
+			members, err := r.Members.ListByBoard(ctx, sourceID)
+			if err != nil {
+				return err
+			}
+			for _, m := range members {
+				if board.UserID != nil && m.UserID == *board.UserID {
+					continue
+				}
+				member := &models.BoardMember{BoardID: board.ID, UserID: m.UserID, Role: models.BoardRoleMember}
+				if err := r.Members.Add(ctx, member); err != nil {
+					return fmt.Errorf("failed to copy member %s: %w", m.UserID, err)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// copyComments копирует комментарии задач-ключей copies на их копии,
+// сохраняя автора и время создания
+func (r Repositories) copyComments(ctx context.Context, copies map[uuid.UUID]uuid.UUID) error {
+	ids := make([]uuid.UUID, 0, len(copies))
+	for id := range copies {
+		ids = append(ids, id)
+	}
+	comments, err := r.Comments.ListByTasks(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for taskID, list := range comments {
+		for _, c := range list {
+			comment := c
+			comment.ID = uuid.Nil
+			comment.TaskID = copies[taskID]
+			if err := r.Comments.Create(ctx, &comment); err != nil {
+				return fmt.Errorf("failed to copy comment %s: %w", c.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// TransferTask переносит задачу на доску boardID или, если copyTask,
+// создает на ней копию. Пустой status подбирается по колонкам досок
+// (см. mapStatus). Возвращает исходную задачу и задачу на целевой доске.
+func (r Repositories) TransferTask(ctx context.Context, id, boardID uuid.UUID, status string, copyTask bool) (*models.Task, *models.Task, error) {
+	var source, result *models.Task
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		task, err := r.Tasks.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if _, err := r.Boards.GetByID(ctx, boardID); err != nil {
+			return err
+		}
+
+		sourceColumns, err := r.Columns.ListByBoard(ctx, task.BoardID, ListOptions{})
+		if err != nil {
+			return err
+		}
+		targetColumns, err := r.Columns.ListByBoard(ctx, boardID, ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		target := status
+		if target == "" {
+			target, err = mapStatus(sourceColumns, targetColumns, task.Status)
+			if err != nil {
+				return err
+			}
+		} else if !hasStatus(targetColumns, target) {
+			return ErrUnknownStatus
+		}
+
+		source = task
+		moved := *task
+		moved.BoardID = boardID
+		moved.Status = target
+		moved.ParentTaskID = nil
+		// Спринты принадлежат доске
+		if boardID != task.BoardID {
+			moved.SprintID = nil
+		}
+
+		if copyTask {
+			if err := r.Tasks.Create(ctx, &moved); err != nil {
+				return err
+			}
+		} else {
+			// Подзадачи не пересекают границу доски: перенесенная задача
+			// отвязывается и от родителя, и от своих подзадач
+			if task.ParentTaskID != nil && boardID != task.BoardID {
+				if err := r.Tasks.SetParent(ctx, id, nil); err != nil {
+					return err
+				}
+			}
+			if boardID != task.BoardID {
+				subtasks, err := r.Tasks.ListSubtasks(ctx, id)
+				if err != nil {
+					return err
+				}
+				for _, subtask := range subtasks {
+					if err := r.Tasks.SetParent(ctx, subtask.ID, nil); err != nil {
+						return err
+					}
+				}
+			}
+			if err := r.Tasks.MoveToBoard(ctx, id, boardID, target); err != nil {
+				return err
+			}
+		}
+
+		result, err = r.Tasks.GetByID(ctx, moved.ID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return source, result, nil
+}
+
+// mapStatus подбирает колонку целевой доски для статуса status: сначала по
+// совпадению status_id, затем по названию колонки, затем по ее позиции.
+// Последняя колонка везде означает "готово", поэтому завершенная задача
+// попадает в последнюю колонку, а незавершенная - не дальше предпоследней.
+// Если ничего не подошло, задача попадает в первую колонку.
+func mapStatus(from, to []models.Column, status string) (string, error) {
+	if len(to) == 0 {
+		return "", ErrUnknownStatus
+	}
+	if hasStatus(to, status) {
+		return status, nil
+	}
+
+	for i, column := range from {
+		if column.StatusID != status {
+			continue
+		}
+		for _, candidate := range to {
+			if strings.EqualFold(candidate.Title, column.Title) {
+				return candidate.StatusID, nil
+			}
+		}
+		last := len(to) - 1
+		switch {
+		case i == len(from)-1:
+			return to[last].StatusID, nil
+		case i < last:
+			return to[i].StatusID, nil
+		default:
+			return to[max(last-1, 0)].StatusID, nil
+		}
+	}
+
+	return to[0].StatusID, nil
+}
+
+func hasStatus(columns []models.Column, status string) bool {
+	for _, column := range columns {
+		if column.StatusID == status {
+			return true
+		}
+	}
+	return false
+}
+
+func reassignTarget(columns []models.Column, deleted *models.Column, targetStatus string) (string, error) {
+	for _, column := range columns {
+		if column.ID == deleted.ID {
+			continue
+		}
+		if targetStatus == "" || column.StatusID == targetStatus {
+			return column.StatusID, nil
+		}
+	}
+	if targetStatus == "" {
+		return "", ErrColumnNotEmpty
+	}
+	return "", ErrUnknownStatus
+}
+
+// ArchiveColumn архивирует колонку вместе со всеми ее задачами и возвращает
+// идентификаторы заархивированных задач.
+func (r Repositories) ArchiveColumn(ctx context.Context, id uuid.UUID) (*models.Column, []uuid.UUID, error) {
+	var column *models.Column
+	var archived []uuid.UUID
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		column, err = r.Columns.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if column.ArchivedAt != nil {
+			return ErrConflict
+		}
+
+		now := time.Now()
+		if err := r.Columns.SetArchived(ctx, id, &now); err != nil {
+			return err
+		}
+		column.ArchivedAt = &now
+
+		archived, err = r.Tasks.ArchiveByStatus(ctx, column.BoardID, column.StatusID, now)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return column, archived, nil
+}
+
+// UnarchiveColumn возвращает колонку из архива вместе с задачами,
+// заархивированными вместе с ней или позже.
+func (r Repositories) UnarchiveColumn(ctx context.Context, id uuid.UUID) (*models.Column, []uuid.UUID, error) {
+	var column *models.Column
+	var restored []uuid.UUID
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		column, err = r.Columns.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if column.ArchivedAt == nil {
+			return ErrConflict
+		}
+
+		if err := r.Columns.SetArchived(ctx, id, nil); err != nil {
+			return err
+		}
+
+		restored, err = r.Tasks.UnarchiveByStatus(ctx, column.BoardID, column.StatusID, *column.ArchivedAt)
+		column.ArchivedAt = nil
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return column, restored, nil
+}
+
+// ArchiveDone архивирует задачи доски в статусе status, не менявшиеся
+// дольше olderThan. Пустой status означает последнюю колонку доски.
+func (r Repositories) ArchiveDone(ctx context.Context, boardID uuid.UUID, status string, olderThan time.Duration) ([]uuid.UUID, error) {
+	var archived []uuid.UUID
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.Boards.GetByID(ctx, boardID); err != nil {
+			return err
+		}
+
+		columns, err := r.Columns.ListByBoard(ctx, boardID, ListOptions{})
+		if err != nil {
+			return err
+		}
+		if status == "" {
+			if status = doneStatus(columns); status == "" {
+				return ErrUnknownStatus
+			}
+		} else if !hasStatus(columns, status) {
+			return ErrUnknownStatus
+		}
+
+		archived, err = r.Tasks.ArchiveByStatus(ctx, boardID, status, time.Now().Add(-olderThan))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return archived, nil
+}
+
+// CreateSubtask создает задачу task как подзадачу task.ParentTaskID,
+// который должен быть задачей той же доски.
+func (r Repositories) CreateSubtask(ctx context.Context, task *models.Task) error {
+	return r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		parent, err := r.Tasks.GetByID(ctx, *task.ParentTaskID)
+		if errors.Is(err, ErrNotFound) || (err == nil && parent.BoardID != task.BoardID) {
+			return ErrInvalidParent
+		}
+		if err != nil {
+			return err
+		}
+		return r.Tasks.Create(ctx, task)
+	})
+}
+
+// SetTaskParent делает задачу id подзадачей parentID или, если parentID
+// равен nil, отвязывает ее от родителя. Родитель не может быть самой
+// задачей или ее подзадачей любого уровня.
+func (r Repositories) SetTaskParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) (*models.Task, error) {
+	var result *models.Task
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		task, err := r.Tasks.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if parentID != nil {
+			if err := r.checkAncestors(ctx, task, *parentID); err != nil {
+				return err
+			}
+		}
+
+		if err := r.Tasks.SetParent(ctx, id, parentID); err != nil {
+			return err
+		}
+		result, err = r.Tasks.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkAncestors проходит по цепочке родителей от parentID вверх и
+// возвращает ErrTaskCycle, если встречает в ней task.
+func (r Repositories) checkAncestors(ctx context.Context, task *models.Task, parentID uuid.UUID) error {
+	visited := map[uuid.UUID]bool{}
+	for current := &parentID; current != nil; {
+		if *current == task.ID {
+			return ErrTaskCycle
+		}
+		if visited[*current] {
+			// Цикл выше по цепочке уже существует и не проходит через task
+			return nil
+		}
+		visited[*current] = true
+
+		ancestor, err := r.Tasks.GetByID(ctx, *current)
+		if errors.Is(err, ErrNotFound) {
+			if *current == parentID {
+				return ErrInvalidParent
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if *current == parentID && ancestor.BoardID != task.BoardID {
+			return ErrInvalidParent
+		}
+		current = ancestor.ParentTaskID
+	}
+	return nil
+}
+
+// ReorderChecklist расставляет пункты чек-листа задачи в порядке ids
+func (r Repositories) ReorderChecklist(ctx context.Context, taskID uuid.UUID, ids []uuid.UUID) ([]models.ChecklistItem, error) {
+	var items []models.ChecklistItem
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := r.Checklists.ListByTask(ctx, taskID)
+		if err != nil {
+			return err
+		}
+		if len(current) != len(ids) {
+			return ErrChecklistOrder
+		}
+
+		byID := make(map[uuid.UUID]models.ChecklistItem, len(current))
+		for _, item := range current {
+			byID[item.ID] = item
+		}
+
+		items = make([]models.ChecklistItem, 0, len(ids))
+		for position, id := range ids {
+			item, ok := byID[id]
+			if !ok {
+				return ErrChecklistOrder
+			}
+			delete(byID, id)
+
+			item.Position = position
+			if err := r.Checklists.Update(ctx, &item); err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
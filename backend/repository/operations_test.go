@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"task-flow-backend/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapStatus(t *testing.T) {
+	columns := func(statuses ...string) []models.Column {
+		result := make([]models.Column, len(statuses))
+		for i, status := range statuses {
+			result[i] = models.Column{Title: status, StatusID: status, Position: i}
+		}
+		return result
+	}
+	standard := columns("plan", "analysis", "development", "testing", "closed")
+	kanban := columns("todo", "in_progress", "done")
+
+	for status, expected := range map[string]string{
+		"plan":        "todo",
+		"analysis":    "in_progress",
+		"development": "in_progress",
+		"testing":     "in_progress",
+		"closed":      "done",
+		"unknown":     "todo",
+	} {
+		mapped, err := mapStatus(standard, kanban, status)
+		require.NoError(t, err)
+		assert.Equal(t, expected, mapped, status)
+	}
+
+	mapped, err := mapStatus(kanban, columns("done"), "todo")
+	require.NoError(t, err)
+	assert.Equal(t, "done", mapped)
+
+	_, err = mapStatus(standard, nil, "plan")
+	assert.ErrorIs(t, err, ErrUnknownStatus)
+}
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+
+	"github.com/google/uuid"
+)
+
+type ArchiveRuleRepository struct {
+	db *sql.DB
+}
+
+func NewArchiveRuleRepository(db *sql.DB) *ArchiveRuleRepository {
+	return &ArchiveRuleRepository{db: db}
+}
+
+// List возвращает правила всех неудаленных досок
+func (r *ArchiveRuleRepository) List(ctx context.Context) ([]models.ArchiveRule, error) {
+	return r.query(ctx, `
+		SELECT ar.board_id, ar.status, ar.after_days
+		FROM archive_rules ar
+		JOIN boards b ON b.id = ar.board_id
+		WHERE b.deleted_at IS NULL
+		ORDER BY ar.board_id, ar.status
+	`)
+}
+
+func (r *ArchiveRuleRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.ArchiveRule, error) {
+	return r.query(ctx, `
+		SELECT board_id, status, after_days
+		FROM archive_rules
+		WHERE board_id = $1
+		ORDER BY status
+	`, boardID)
+}
+
+func (r *ArchiveRuleRepository) Replace(ctx context.Context, boardID uuid.UUID, rules []models.ArchiveRule) error {
+	return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		db := database.Conn(ctx, r.db)
+
+		if _, err := db.ExecContext(ctx, "DELETE FROM archive_rules WHERE board_id = $1", boardID); err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			_, err := db.ExecContext(ctx, `
+				INSERT INTO archive_rules (board_id, status, after_days)
+				VALUES ($1, $2, $3)
+			`, boardID, rule.Status, rule.AfterDays)
+			if err != nil {
+				return mapError(err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *ArchiveRuleRepository) query(ctx context.Context, query string, args ...any) ([]models.ArchiveRule, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.ArchiveRule
+	for rows.Next() {
+		var rule models.ArchiveRule
+		if err := rows.Scan(&rule.BoardID, &rule.Status, &rule.AfterDays); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// attachmentQuery выбирает вложения неудаленных задач вместе с данными их файлов
+const attachmentQuery = `
+	SELECT a.id, a.task_id, a.filename, a.content_type, b.size, a.checksum, b.has_thumbnail, a.uploaded_by, a.created_at
+	FROM attachments a
+	JOIN attachment_blobs b ON b.checksum = a.checksum
+	JOIN tasks t ON t.id = a.task_id AND t.deleted_at IS NULL
+`
+
+type AttachmentRepository struct {
+	db *sql.DB
+}
+
+func NewAttachmentRepository(db *sql.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.Attachment, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, attachmentQuery+`
+		WHERE a.task_id = $1
+		ORDER BY a.created_at
+	`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, *attachment)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *AttachmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Attachment, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, attachmentQuery+`
+		WHERE a.id = $1
+	`, id)
+
+	attachment, err := scanAttachment(row)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return attachment, nil
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *models.Attachment) error {
+	attachment.CreatedAt = time.Now()
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO attachments (task_id, checksum, filename, content_type, uploaded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, (SELECT size FROM attachment_blobs WHERE checksum = $2), (SELECT has_thumbnail FROM attachment_blobs WHERE checksum = $2)
+	`, attachment.TaskID, attachment.Checksum, attachment.Filename, attachment.ContentType, attachment.UploadedBy, attachment.CreatedAt).
+		Scan(&attachment.ID, &attachment.Size, &attachment.HasThumbnail)
+
+	// Нарушение внешнего ключа: задача или файл уже удалены
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return repository.ErrNotFound
+	}
+	return mapError(err)
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, "DELETE FROM attachments WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *AttachmentRepository) GetBlob(ctx context.Context, checksum string) (*models.AttachmentBlob, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT checksum, size, has_thumbnail, created_at
+		FROM attachment_blobs
+		WHERE checksum = $1
+	`, checksum)
+
+	var blob models.AttachmentBlob
+	if err := row.Scan(&blob.Checksum, &blob.Size, &blob.HasThumbnail, &blob.CreatedAt); err != nil {
+		return nil, mapError(err)
+	}
+	return &blob, nil
+}
+
+func (r *AttachmentRepository) CreateBlob(ctx context.Context, blob *models.AttachmentBlob) error {
+	blob.CreatedAt = time.Now()
+
+	// ON CONFLICT вместо ошибки уникальности, чтобы не прерывать транзакцию
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO attachment_blobs (checksum, size, has_thumbnail, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (checksum) DO NOTHING
+	`, blob.Checksum, blob.Size, blob.HasThumbnail, blob.CreatedAt)
+	return err
+}
+
+func (r *AttachmentRepository) DeleteBlob(ctx context.Context, checksum string) error {
+	db := database.Conn(ctx, r.db)
+
+	res, err := db.ExecContext(ctx, `
+		DELETE FROM attachment_blobs b
+		WHERE b.checksum = $1 AND NOT EXISTS (SELECT 1 FROM attachments a WHERE a.checksum = b.checksum)
+	`, checksum)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	// Ничего не удалено: либо файл используется, либо его уже нет
+	var exists bool
+	if err := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM attachment_blobs WHERE checksum = $1)", checksum).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return repository.ErrConflict
+	}
+	return repository.ErrNotFound
+}
+
+func (r *AttachmentRepository) OrphanBlobs(ctx context.Context) ([]models.AttachmentBlob, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT b.checksum, b.size, b.has_thumbnail, b.created_at
+		FROM attachment_blobs b
+		WHERE NOT EXISTS (SELECT 1 FROM attachments a WHERE a.checksum = b.checksum)
+		ORDER BY b.created_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blobs []models.AttachmentBlob
+	for rows.Next() {
+		var blob models.AttachmentBlob
+		if err := rows.Scan(&blob.Checksum, &blob.Size, &blob.HasThumbnail, &blob.CreatedAt); err != nil {
+			return nil, err
+		}
+		blobs = append(blobs, blob)
+	}
+	return blobs, rows.Err()
+}
+
+func scanAttachment(row rowScanner) (*models.Attachment, error) {
+	var attachment models.Attachment
+	var uploadedBy uuid.NullUUID
+
+	err := row.Scan(&attachment.ID, &attachment.TaskID, &attachment.Filename, &attachment.ContentType, &attachment.Size,
+		&attachment.Checksum, &attachment.HasThumbnail, &uploadedBy, &attachment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if uploadedBy.Valid {
+		attachment.UploadedBy = &uploadedBy.UUID
+	}
+	return &attachment, nil
+}
@@ -0,0 +1,292 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// automationRuleQuery выбирает правила неудаленных досок
+const automationRuleQuery = `
+	SELECT a.id, a.board_id, a.name, a.enabled, a.trigger_config, a.conditions, a.actions, a.created_by, a.created_at, a.updated_at
+	FROM automation_rules a
+	JOIN boards b ON b.id = a.board_id AND b.deleted_at IS NULL
+`
+
+type AutomationRuleRepository struct {
+	db *sql.DB
+}
+
+func NewAutomationRuleRepository(db *sql.DB) *AutomationRuleRepository {
+	return &AutomationRuleRepository{db: db}
+}
+
+func (r *AutomationRuleRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.AutomationRule, error) {
+	return r.query(ctx, automationRuleQuery+`
+		WHERE a.board_id = $1
+		ORDER BY a.created_at
+	`, boardID)
+}
+
+func (r *AutomationRuleRepository) ListByTrigger(ctx context.Context, triggerType string) ([]models.AutomationRule, error) {
+	return r.query(ctx, automationRuleQuery+`
+		WHERE a.trigger_type = $1 AND a.enabled
+		ORDER BY a.board_id, a.created_at
+	`, triggerType)
+}
+
+func (r *AutomationRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.AutomationRule, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, automationRuleQuery+`
+		WHERE a.id = $1
+	`, id)
+
+	rule, err := scanAutomationRule(row)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return rule, nil
+}
+
+func (r *AutomationRuleRepository) Create(ctx context.Context, rule *models.AutomationRule) error {
+	trigger, conditions, actions, err := marshalAutomationRule(rule)
+	if err != nil {
+		return err
+	}
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+
+	err = database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO automation_rules (board_id, name, enabled, trigger_type, trigger_config, conditions, actions, created_by, created_at, updated_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		WHERE EXISTS (SELECT 1 FROM boards WHERE id = $1 AND deleted_at IS NULL)
+		RETURNING id
+	`, rule.BoardID, rule.Name, rule.Enabled, rule.Trigger.Type, trigger, conditions, actions,
+		rule.CreatedBy, rule.CreatedAt, rule.UpdatedAt).Scan(&rule.ID)
+
+	// Нарушение внешнего ключа: автор уже удален
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return repository.ErrNotFound
+	}
+	return mapError(err)
+}
+
+func (r *AutomationRuleRepository) Update(ctx context.Context, rule *models.AutomationRule) error {
+	trigger, conditions, actions, err := marshalAutomationRule(rule)
+	if err != nil {
+		return err
+	}
+	rule.UpdatedAt = time.Now()
+
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE automation_rules
+		SET name = $1, enabled = $2, trigger_type = $3, trigger_config = $4, conditions = $5, actions = $6, updated_at = $7
+		WHERE id = $8 AND board_id IN (SELECT id FROM boards WHERE deleted_at IS NULL)
+	`, rule.Name, rule.Enabled, rule.Trigger.Type, trigger, conditions, actions, rule.UpdatedAt, rule.ID)
+	if err != nil {
+		return mapError(err)
+	}
+	return requireAffected(res)
+}
+
+func (r *AutomationRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, "DELETE FROM automation_rules WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *AutomationRuleRepository) query(ctx context.Context, query string, args ...any) ([]models.AutomationRule, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.AutomationRule
+	for rows.Next() {
+		rule, err := scanAutomationRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func marshalAutomationRule(rule *models.AutomationRule) (trigger, conditions, actions []byte, err error) {
+	if trigger, err = json.Marshal(rule.Trigger); err != nil {
+		return nil, nil, nil, err
+	}
+	if conditions, err = json.Marshal(rule.Conditions); err != nil {
+		return nil, nil, nil, err
+	}
+	if actions, err = json.Marshal(rule.Actions); err != nil {
+		return nil, nil, nil, err
+	}
+	return trigger, conditions, actions, nil
+}
+
+func scanAutomationRule(row rowScanner) (*models.AutomationRule, error) {
+	var rule models.AutomationRule
+	var trigger, conditions, actions []byte
+	var createdBy uuid.NullUUID
+
+	err := row.Scan(&rule.ID, &rule.BoardID, &rule.Name, &rule.Enabled, &trigger, &conditions, &actions,
+		&createdBy, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(trigger, &rule.Trigger); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(conditions, &rule.Conditions); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(actions, &rule.Actions); err != nil {
+		return nil, err
+	}
+	if createdBy.Valid {
+		rule.CreatedBy = &createdBy.UUID
+	}
+	return &rule, nil
+}
+
+type AutomationRunRepository struct {
+	db *sql.DB
+}
+
+func NewAutomationRunRepository(db *sql.DB) *AutomationRunRepository {
+	return &AutomationRunRepository{db: db}
+}
+
+func (r *AutomationRunRepository) Create(ctx context.Context, run *models.AutomationRun) error {
+	run.CreatedAt = time.Now()
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO automation_runs (rule_id, board_id, task_id, event, status, message, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+		RETURNING id
+	`, run.RuleID, run.BoardID, run.TaskID, run.Event, run.Status, run.Message, run.CreatedAt).Scan(&run.ID)
+
+	// Правило или задачу удалили, пока правило выполнялось
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return repository.ErrNotFound
+	}
+	return mapError(err)
+}
+
+func (r *AutomationRunRepository) List(ctx context.Context, filter repository.AutomationRunFilter) ([]models.AutomationRun, error) {
+	limit := sql.NullInt64{Int64: int64(filter.Limit), Valid: filter.Limit > 0}
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, rule_id, board_id, task_id, event, status, message, created_at
+		FROM automation_runs
+		WHERE board_id = $1 AND ($2::uuid IS NULL OR rule_id = $2)
+		ORDER BY created_at DESC, id
+		LIMIT $3
+	`, filter.BoardID, filter.RuleID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []models.AutomationRun
+	for rows.Next() {
+		var run models.AutomationRun
+		var taskID uuid.NullUUID
+		var message sql.NullString
+		if err := rows.Scan(&run.ID, &run.RuleID, &run.BoardID, &taskID, &run.Event, &run.Status, &message, &run.CreatedAt); err != nil {
+			return nil, err
+		}
+		if taskID.Valid {
+			run.TaskID = &taskID.UUID
+		}
+		run.Message = message.String
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *AutomationRunRepository) LastRun(ctx context.Context, ruleID, taskID uuid.UUID) (*time.Time, error) {
+	var last sql.NullTime
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT MAX(created_at) FROM automation_runs WHERE rule_id = $1 AND task_id = $2
+	`, ruleID, taskID).Scan(&last)
+	if err != nil {
+		return nil, err
+	}
+	if !last.Valid {
+		return nil, nil
+	}
+	return &last.Time, nil
+}
+
+type AutomationDelayRepository struct {
+	db *sql.DB
+}
+
+func NewAutomationDelayRepository(db *sql.DB) *AutomationDelayRepository {
+	return &AutomationDelayRepository{db: db}
+}
+
+func (r *AutomationDelayRepository) Schedule(ctx context.Context, delay *models.AutomationDelay) error {
+	delay.CreatedAt = time.Now()
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO automation_delays (rule_id, task_id, event, action_index, run_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (rule_id, task_id) DO UPDATE
+		SET event = EXCLUDED.event, action_index = EXCLUDED.action_index, run_at = EXCLUDED.run_at, created_at = EXCLUDED.created_at
+		RETURNING id
+	`, delay.RuleID, delay.TaskID, delay.Event, delay.ActionIndex, delay.RunAt, delay.CreatedAt).Scan(&delay.ID)
+
+	// Правило или задачу удалили, пока правило выполнялось
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return repository.ErrNotFound
+	}
+	return mapError(err)
+}
+
+func (r *AutomationDelayRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.AutomationDelay, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, rule_id, task_id, event, action_index, run_at, created_at
+		FROM automation_delays
+		WHERE run_at <= $1
+		ORDER BY run_at, id
+		LIMIT $2
+	`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var delays []models.AutomationDelay
+	for rows.Next() {
+		var delay models.AutomationDelay
+		if err := rows.Scan(&delay.ID, &delay.RuleID, &delay.TaskID, &delay.Event, &delay.ActionIndex, &delay.RunAt, &delay.CreatedAt); err != nil {
+			return nil, err
+		}
+		delays = append(delays, delay)
+	}
+	return delays, rows.Err()
+}
+
+func (r *AutomationDelayRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, "DELETE FROM automation_delays WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BoardRepository struct {
+	db *sql.DB
+}
+
+func NewBoardRepository(db *sql.DB) *BoardRepository {
+	return &BoardRepository{db: db}
+}
+
+func (r *BoardRepository) List(ctx context.Context) ([]models.Board, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, name, description, user_id, enforce_dependencies, created_at, updated_at
+		FROM boards
+		WHERE deleted_at IS NULL
+		ORDER BY created_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var boards []models.Board
+	for rows.Next() {
+		board, err := scanBoard(rows)
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, *board)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range boards {
+		tasks, err := listTasks(ctx, database.Conn(ctx, r.db), boards[i].ID, repository.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		boards[i].Tasks = tasks
+	}
+
+	return boards, nil
+}
+
+func (r *BoardRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Board, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, name, description, user_id, enforce_dependencies, created_at, updated_at
+		FROM boards
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id)
+
+	board, err := scanBoard(row)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	tasks, err := listTasks(ctx, database.Conn(ctx, r.db), id, repository.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	board.Tasks = tasks
+
+	return board, nil
+}
+
+func (r *BoardRepository) Create(ctx context.Context, board *models.Board) error {
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO boards (name, description, user_id, enforce_dependencies)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`, board.Name, board.Description, board.UserID, board.EnforceDependencies).Scan(&board.ID, &board.CreatedAt, &board.UpdatedAt)
+
+	return mapError(err)
+}
+
+func (r *BoardRepository) Update(ctx context.Context, board *models.Board) error {
+	board.UpdatedAt = time.Now()
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE boards
+		SET name = $1, description = $2, enforce_dependencies = $3, updated_at = $4
+		WHERE id = $5 AND deleted_at IS NULL
+	`, board.Name, board.Description, board.EnforceDependencies, board.UpdatedAt, board.ID)
+	if err != nil {
+		return mapError(err)
+	}
+	return requireAffected(res)
+}
+
+func (r *BoardRepository) SetOwner(ctx context.Context, id, userID uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE boards SET user_id = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL
+	`, userID, time.Now(), id)
+	if err != nil {
+		return mapError(err)
+	}
+	return requireAffected(res)
+}
+
+// Delete переносит доску в корзину. Ее колонки и задачи помечаются тем же
+// deleted_at, чтобы при восстановлении доски вернуться вместе с ней.
+func (r *BoardRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+
+	return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		db := database.Conn(ctx, r.db)
+
+		res, err := db.ExecContext(ctx, "UPDATE boards SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL", now, id)
+		if err != nil {
+			return err
+		}
+		if err := requireAffected(res); err != nil {
+			return err
+		}
+
+		if _, err := db.ExecContext(ctx, "UPDATE columns SET deleted_at = $1 WHERE board_id = $2 AND deleted_at IS NULL", now, id); err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, "UPDATE tasks SET deleted_at = $1 WHERE board_id = $2 AND deleted_at IS NULL", now, id)
+		return err
+	})
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBoard(row rowScanner) (*models.Board, error) {
+	var board models.Board
+	var description sql.NullString
+	var userID uuid.NullUUID
+
+	err := row.Scan(&board.ID, &board.Name, &description, &userID, &board.EnforceDependencies, &board.CreatedAt, &board.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		board.Description = &description.String
+	}
+	if userID.Valid {
+		board.UserID = &userID.UUID
+	}
+
+	return &board, nil
+}
+
+// requireAffected возвращает ErrNotFound, если запрос не затронул ни одной строки
+func requireAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const checklistColumns = `ci.id, ci.task_id, ci.title, ci.checked, ci.position, ci.assignee, ci.created_at, ci.updated_at`
+
+type ChecklistRepository struct {
+	db *sql.DB
+}
+
+func NewChecklistRepository(db *sql.DB) *ChecklistRepository {
+	return &ChecklistRepository{db: db}
+}
+
+func (r *ChecklistRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.ChecklistItem, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+checklistColumns+`
+		FROM checklist_items ci
+		JOIN tasks t ON t.id = ci.task_id
+		WHERE ci.task_id = $1 AND t.deleted_at IS NULL
+		ORDER BY ci.position, ci.created_at
+	`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.ChecklistItem
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, rows.Err()
+}
+
+func (r *ChecklistRepository) ListByTasks(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID][]models.ChecklistItem, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+checklistColumns+`
+		FROM checklist_items ci
+		JOIN tasks t ON t.id = ci.task_id
+		WHERE ci.task_id = ANY($1::uuid[]) AND t.deleted_at IS NULL
+		ORDER BY ci.task_id, ci.position, ci.created_at
+	`, uuidArray(taskIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make(map[uuid.UUID][]models.ChecklistItem)
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items[item.TaskID] = append(items[item.TaskID], *item)
+	}
+	return items, rows.Err()
+}
+
+func (r *ChecklistRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ChecklistItem, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT `+checklistColumns+`
+		FROM checklist_items ci
+		JOIN tasks t ON t.id = ci.task_id
+		WHERE ci.id = $1 AND t.deleted_at IS NULL
+	`, id)
+
+	item, err := scanChecklistItem(row)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return item, nil
+}
+
+// Create возвращает ErrNotFound, если задача не существует или удалена
+func (r *ChecklistRepository) Create(ctx context.Context, item *models.ChecklistItem) error {
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = item.CreatedAt
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO checklist_items (task_id, title, checked, position, assignee, created_at, updated_at)
+		SELECT $1, $2, $3,
+			(SELECT COALESCE(MAX(position) + 1, 0) FROM checklist_items WHERE task_id = $1),
+			$4, $5, $5
+		FROM tasks
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id, position
+	`, item.TaskID, item.Title, item.Checked, item.Assignee, item.CreatedAt).Scan(&item.ID, &item.Position)
+
+	return mapError(err)
+}
+
+func (r *ChecklistRepository) Update(ctx context.Context, item *models.ChecklistItem) error {
+	item.UpdatedAt = time.Now()
+
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE checklist_items
+		SET title = $1, checked = $2, assignee = $3, position = $4, updated_at = $5
+		WHERE id = $6
+	`, item.Title, item.Checked, item.Assignee, item.Position, item.UpdatedAt, item.ID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *ChecklistRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, "DELETE FROM checklist_items WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func scanChecklistItem(row rowScanner) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	var assignee sql.NullString
+
+	err := row.Scan(&item.ID, &item.TaskID, &item.Title, &item.Checked, &item.Position, &assignee, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if assignee.Valid {
+		item.Assignee = &assignee.String
+	}
+
+	return &item, nil
+}
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ColumnRepository struct {
+	db *sql.DB
+}
+
+func NewColumnRepository(db *sql.DB) *ColumnRepository {
+	return &ColumnRepository{db: db}
+}
+
+func (r *ColumnRepository) ListByBoard(ctx context.Context, boardID uuid.UUID, opts repository.ListOptions) ([]models.Column, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, board_id, title, status_id, position, wip_limit, archived_at
+		FROM columns
+		WHERE board_id = $1 AND deleted_at IS NULL AND ($2 OR archived_at IS NULL)
+		ORDER BY position ASC
+	`, boardID, opts.IncludeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []models.Column
+	for rows.Next() {
+		var column models.Column
+		err := rows.Scan(&column.ID, &column.BoardID, &column.Title, &column.StatusID, &column.Position, &column.WIPLimit, &column.ArchivedAt)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+
+	return columns, rows.Err()
+}
+
+func (r *ColumnRepository) Create(ctx context.Context, column *models.Column) error {
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO columns (board_id, title, status_id, position, wip_limit)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, column.BoardID, column.Title, column.StatusID, column.Position, column.WIPLimit).Scan(&column.ID)
+
+	return mapError(err)
+}
+
+func (r *ColumnRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Column, error) {
+	var column models.Column
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, board_id, title, status_id, position, wip_limit, archived_at
+		FROM columns
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id).Scan(&column.ID, &column.BoardID, &column.Title, &column.StatusID, &column.Position, &column.WIPLimit, &column.ArchivedAt)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &column, nil
+}
+
+func (r *ColumnRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, "UPDATE columns SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL", time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *ColumnRepository) SetArchived(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE columns
+		SET archived_at = $1
+		WHERE id = $2 AND deleted_at IS NULL
+	`, at, id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type CommentRepository struct {
+	db *sql.DB
+}
+
+func NewCommentRepository(db *sql.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+func (r *CommentRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.TaskComment, error) {
+	return r.list(ctx, "task_id = $1", taskID)
+}
+
+func (r *CommentRepository) ListByTasks(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID][]models.TaskComment, error) {
+	comments, err := r.list(ctx, "task_id = ANY($1::uuid[])", uuidArray(taskIDs))
+	if err != nil {
+		return nil, err
+	}
+	byTask := make(map[uuid.UUID][]models.TaskComment)
+	for _, comment := range comments {
+		byTask[comment.TaskID] = append(byTask[comment.TaskID], comment)
+	}
+	return byTask, nil
+}
+
+func (r *CommentRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.TaskComment, error) {
+	return r.list(ctx, "user_id = $1", userID)
+}
+
+// list выбирает комментарии по условию where с одним параметром; where не
+// берется из пользовательского ввода
+func (r *CommentRepository) list(ctx context.Context, where string, arg any) ([]models.TaskComment, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, task_id, user_id, rule_id, body, created_at
+		FROM task_comments
+		WHERE `+where+`
+		ORDER BY created_at, id
+	`, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []models.TaskComment
+	for rows.Next() {
+		var comment models.TaskComment
+		var userID, ruleID uuid.NullUUID
+		if err := rows.Scan(&comment.ID, &comment.TaskID, &userID, &ruleID, &comment.Body, &comment.CreatedAt); err != nil {
+			return nil, err
+		}
+		if userID.Valid {
+			comment.UserID = &userID.UUID
+		}
+		if ruleID.Valid {
+			comment.RuleID = &ruleID.UUID
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+func (r *CommentRepository) Create(ctx context.Context, comment *models.TaskComment) error {
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now()
+	}
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO task_comments (task_id, user_id, rule_id, body, created_at)
+		SELECT $1, $2, $3, $4, $5
+		WHERE EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)
+		RETURNING id
+	`, comment.TaskID, comment.UserID, comment.RuleID, comment.Body, comment.CreatedAt).Scan(&comment.ID)
+
+	// Нарушение внешнего ключа: автор уже удален
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return repository.ErrNotFound
+	}
+	return mapError(err)
+}
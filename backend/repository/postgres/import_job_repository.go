@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ImportJobRepository struct {
+	db *sql.DB
+}
+
+func NewImportJobRepository(db *sql.DB) *ImportJobRepository {
+	return &ImportJobRepository{db: db}
+}
+
+func (r *ImportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ImportJob, error) {
+	var job models.ImportJob
+	var userID, boardID uuid.NullUUID
+	var jobError sql.NullString
+	var finishedAt sql.NullTime
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, user_id, format, board_name, status, board_id, total_tasks, imported_tasks, error,
+			created_at, updated_at, finished_at
+		FROM import_jobs
+		WHERE id = $1
+	`, id).Scan(&job.ID, &userID, &job.Format, &job.BoardName, &job.Status, &boardID, &job.TotalTasks,
+		&job.ImportedTasks, &jobError, &job.CreatedAt, &job.UpdatedAt, &finishedAt)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	if userID.Valid {
+		job.UserID = &userID.UUID
+	}
+	if boardID.Valid {
+		job.BoardID = &boardID.UUID
+	}
+	if jobError.Valid {
+		job.Error = &jobError.String
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return &job, nil
+}
+
+// Create и Update пишут время в UTC: столбцы TIMESTAMP не хранят часовой пояс
+func (r *ImportJobRepository) Create(ctx context.Context, job *models.ImportJob) error {
+	job.CreatedAt = time.Now().UTC()
+	job.UpdatedAt = job.CreatedAt
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO import_jobs (user_id, format, board_name, status, total_tasks, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, job.UserID, job.Format, job.BoardName, job.Status, job.TotalTasks, job.CreatedAt, job.UpdatedAt).Scan(&job.ID)
+	return mapError(err)
+}
+
+func (r *ImportJobRepository) Update(ctx context.Context, job *models.ImportJob) error {
+	job.UpdatedAt = time.Now().UTC()
+
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE import_jobs
+		SET status = $1, board_id = $2, imported_tasks = $3, error = $4, updated_at = $5, finished_at = $6
+		WHERE id = $7
+	`, job.Status, job.BoardID, job.ImportedTasks, job.Error, job.UpdatedAt, utcOrNil(job.FinishedAt), job.ID)
+	if err != nil {
+		return mapError(err)
+	}
+	return requireAffected(res)
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+
+	"github.com/google/uuid"
+)
+
+type LabelRepository struct {
+	db *sql.DB
+}
+
+func NewLabelRepository(db *sql.DB) *LabelRepository {
+	return &LabelRepository{db: db}
+}
+
+func (r *LabelRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.Label, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id, board_id, name, color
+		FROM labels
+		WHERE board_id = $1
+		ORDER BY name ASC
+	`, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var labels []models.Label
+	for rows.Next() {
+		var label models.Label
+		if err := rows.Scan(&label.ID, &label.BoardID, &label.Name, &label.Color); err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+
+	return labels, rows.Err()
+}
+
+func (r *LabelRepository) Create(ctx context.Context, label *models.Label) error {
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO labels (board_id, name, color)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, label.BoardID, label.Name, label.Color).Scan(&label.ID)
+
+	return mapError(err)
+}
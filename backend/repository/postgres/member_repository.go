@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+
+	"github.com/google/uuid"
+)
+
+type MemberRepository struct {
+	db *sql.DB
+}
+
+func NewMemberRepository(db *sql.DB) *MemberRepository {
+	return &MemberRepository{db: db}
+}
+
+func (r *MemberRepository) Add(ctx context.Context, member *models.BoardMember) error {
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO board_members (board_id, user_id, role)
+		VALUES ($1, $2, $3)
+		RETURNING created_at
+	`, member.BoardID, member.UserID, member.Role).Scan(&member.CreatedAt)
+
+	return mapError(err)
+}
+
+func (r *MemberRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.BoardMember, error) {
+	return r.list(ctx, "board_id", boardID)
+}
+
+func (r *MemberRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.BoardMember, error) {
+	return r.list(ctx, "user_id", userID)
+}
+
+// list выбирает членства по board_id или user_id; column не берется из пользовательского ввода
+func (r *MemberRepository) list(ctx context.Context, column string, id uuid.UUID) ([]models.BoardMember, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT board_id, user_id, role, created_at
+		FROM board_members
+		WHERE `+column+` = $1
+		ORDER BY created_at ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []models.BoardMember
+	for rows.Next() {
+		var member models.BoardMember
+		if err := rows.Scan(&member.BoardID, &member.UserID, &member.Role, &member.CreatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+func (r *MemberRepository) SetRole(ctx context.Context, boardID, userID uuid.UUID, role string) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE board_members SET role = $1 WHERE board_id = $2 AND user_id = $3
+	`, role, boardID, userID)
+	if err != nil {
+		return mapError(err)
+	}
+	return requireAffected(res)
+}
+
+// Transactor открывает транзакцию, в которой участвуют все Postgres-репозитории
+type Transactor struct {
+	db *sql.DB
+}
+
+func NewTransactor(db *sql.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return database.WithTx(ctx, t.db, fn)
+}
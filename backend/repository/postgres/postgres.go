@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func NewRepositories(db *sql.DB) repository.Repositories {
+	return repository.Repositories{
+		Boards:           NewBoardRepository(db),
+		Tasks:            NewTaskRepository(db),
+		Columns:          NewColumnRepository(db),
+		Users:            NewUserRepository(db),
+		UserTokens:       NewUserTokenRepository(db),
+		Members:          NewMemberRepository(db),
+		Labels:           NewLabelRepository(db),
+		Templates:        NewTemplateRepository(db),
+		Trash:            NewTrashRepository(db),
+		ArchiveRules:     NewArchiveRuleRepository(db),
+		Workflows:        NewWorkflowRepository(db),
+		Sprints:          NewSprintRepository(db),
+		Checklists:       NewChecklistRepository(db),
+		Links:            NewTaskLinkRepository(db),
+		Attachments:      NewAttachmentRepository(db),
+		Worklogs:         NewWorklogRepository(db),
+		RecurringTasks:   NewRecurringTaskRepository(db),
+		Comments:         NewCommentRepository(db),
+		AutomationRules:  NewAutomationRuleRepository(db),
+		AutomationRuns:   NewAutomationRunRepository(db),
+		AutomationDelays: NewAutomationDelayRepository(db),
+		ImportJobs:       NewImportJobRepository(db),
+		Tx:               NewTransactor(db),
+	}
+}
+
+// uuidArray передает идентификаторы параметром uuid[] (в запросе - $n::uuid[])
+func uuidArray(ids []uuid.UUID) pq.StringArray {
+	values := make(pq.StringArray, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+	return values
+}
+
+// mapError приводит ошибки драйвера к ошибкам пакета repository
+func mapError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return repository.ErrConflict
+	}
+	return err
+}
+
+var (
+	_ repository.BoardRepository           = (*BoardRepository)(nil)
+	_ repository.TaskRepository            = (*TaskRepository)(nil)
+	_ repository.ColumnRepository          = (*ColumnRepository)(nil)
+	_ repository.UserRepository            = (*UserRepository)(nil)
+	_ repository.MemberRepository          = (*MemberRepository)(nil)
+	_ repository.LabelRepository           = (*LabelRepository)(nil)
+	_ repository.TemplateRepository        = (*TemplateRepository)(nil)
+	_ repository.TrashRepository           = (*TrashRepository)(nil)
+	_ repository.ArchiveRuleRepository     = (*ArchiveRuleRepository)(nil)
+	_ repository.WorkflowRepository        = (*WorkflowRepository)(nil)
+	_ repository.SprintRepository          = (*SprintRepository)(nil)
+	_ repository.ChecklistRepository       = (*ChecklistRepository)(nil)
+	_ repository.TaskLinkRepository        = (*TaskLinkRepository)(nil)
+	_ repository.AttachmentRepository      = (*AttachmentRepository)(nil)
+	_ repository.WorklogRepository         = (*WorklogRepository)(nil)
+	_ repository.RecurringTaskRepository   = (*RecurringTaskRepository)(nil)
+	_ repository.CommentRepository         = (*CommentRepository)(nil)
+	_ repository.AutomationRuleRepository  = (*AutomationRuleRepository)(nil)
+	_ repository.AutomationRunRepository   = (*AutomationRunRepository)(nil)
+	_ repository.AutomationDelayRepository = (*AutomationDelayRepository)(nil)
+	_ repository.ImportJobRepository       = (*ImportJobRepository)(nil)
+	_ repository.Transactor                = (*Transactor)(nil)
+)
@@ -0,0 +1,32 @@
+//go:build integration
+
+package postgres
+
+import (
+	"os"
+	"task-flow-backend/database"
+	"task-flow-backend/repository"
+	"task-flow-backend/repository/repotest"
+	"testing"
+)
+
+// Для запуска требуется PostgreSQL (docker-compose up -d):
+// go test -tags integration ./repository/postgres/
+func TestMain(m *testing.M) {
+	// Миграции ищутся относительно корня backend
+	if err := os.Chdir("../.."); err != nil {
+		panic(err)
+	}
+	if err := database.Init(); err != nil {
+		panic("failed to initialize test database: " + err.Error())
+	}
+	code := m.Run()
+	database.DB.Close()
+	os.Exit(code)
+}
+
+func TestRepositories(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repository.Repositories {
+		return NewRepositories(database.DB)
+	})
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// recurringTaskQuery выбирает шаблоны неудаленных досок
+const recurringTaskQuery = `
+	SELECT r.id, r.board_id, r.title, r.description, r.status, r.priority, r.assignee, r.estimate_minutes,
+		r.rrule, r.starts_at, r.timezone, r.next_run_at, r.created_by, r.created_at, r.updated_at
+	FROM recurring_tasks r
+	JOIN boards b ON b.id = r.board_id AND b.deleted_at IS NULL
+`
+
+type RecurringTaskRepository struct {
+	db *sql.DB
+}
+
+func NewRecurringTaskRepository(db *sql.DB) *RecurringTaskRepository {
+	return &RecurringTaskRepository{db: db}
+}
+
+func (r *RecurringTaskRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.RecurringTask, error) {
+	return r.query(ctx, recurringTaskQuery+`
+		WHERE r.board_id = $1
+		ORDER BY r.created_at
+	`, boardID)
+}
+
+func (r *RecurringTaskRepository) ListDue(ctx context.Context, now time.Time) ([]models.RecurringTask, error) {
+	return r.query(ctx, recurringTaskQuery+`
+		WHERE r.next_run_at <= $1
+		ORDER BY r.next_run_at
+	`, now.UTC())
+}
+
+func (r *RecurringTaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RecurringTask, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, recurringTaskQuery+`
+		WHERE r.id = $1
+	`, id)
+
+	recurring, err := scanRecurringTask(row)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return recurring, nil
+}
+
+// Create и Update пишут время в UTC: столбцы TIMESTAMP не хранят часовой пояс
+func (r *RecurringTaskRepository) Create(ctx context.Context, recurring *models.RecurringTask) error {
+	recurring.CreatedAt = time.Now()
+	recurring.UpdatedAt = recurring.CreatedAt
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO recurring_tasks (board_id, title, description, status, priority, assignee, estimate_minutes,
+			rrule, starts_at, timezone, next_run_at, created_by, created_at, updated_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+		WHERE EXISTS (SELECT 1 FROM boards WHERE id = $1 AND deleted_at IS NULL)
+		RETURNING id
+	`, recurring.BoardID, recurring.Title, recurring.Description, recurring.Status, recurring.Priority, recurring.Assignee,
+		recurring.EstimateMinutes, recurring.RRule, recurring.StartsAt.UTC(), recurring.Timezone, utcOrNil(recurring.NextRunAt),
+		recurring.CreatedBy, recurring.CreatedAt, recurring.UpdatedAt).Scan(&recurring.ID)
+
+	// Нарушение внешнего ключа: автор уже удален
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return repository.ErrNotFound
+	}
+	return mapError(err)
+}
+
+func (r *RecurringTaskRepository) Update(ctx context.Context, recurring *models.RecurringTask) error {
+	recurring.UpdatedAt = time.Now()
+
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE recurring_tasks
+		SET title = $1, description = $2, status = $3, priority = $4, assignee = $5, estimate_minutes = $6,
+			rrule = $7, starts_at = $8, timezone = $9, next_run_at = $10, updated_at = $11
+		WHERE id = $12 AND board_id IN (SELECT id FROM boards WHERE deleted_at IS NULL)
+	`, recurring.Title, recurring.Description, recurring.Status, recurring.Priority, recurring.Assignee, recurring.EstimateMinutes,
+		recurring.RRule, recurring.StartsAt.UTC(), recurring.Timezone, utcOrNil(recurring.NextRunAt), recurring.UpdatedAt, recurring.ID)
+	if err != nil {
+		return mapError(err)
+	}
+	return requireAffected(res)
+}
+
+func (r *RecurringTaskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, "DELETE FROM recurring_tasks WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *RecurringTaskRepository) query(ctx context.Context, query string, args ...any) ([]models.RecurringTask, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recurring []models.RecurringTask
+	for rows.Next() {
+		item, err := scanRecurringTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		recurring = append(recurring, *item)
+	}
+	return recurring, rows.Err()
+}
+
+func scanRecurringTask(row rowScanner) (*models.RecurringTask, error) {
+	var recurring models.RecurringTask
+	var description, priority, assignee sql.NullString
+	var estimate sql.NullInt32
+	var nextRunAt sql.NullTime
+	var createdBy uuid.NullUUID
+
+	err := row.Scan(&recurring.ID, &recurring.BoardID, &recurring.Title, &description, &recurring.Status, &priority,
+		&assignee, &estimate, &recurring.RRule, &recurring.StartsAt, &recurring.Timezone, &nextRunAt, &createdBy,
+		&recurring.CreatedAt, &recurring.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	recurring.Description = description.String
+	if priority.Valid {
+		recurring.Priority = &priority.String
+	}
+	if assignee.Valid {
+		recurring.Assignee = &assignee.String
+	}
+	if estimate.Valid {
+		minutes := int(estimate.Int32)
+		recurring.EstimateMinutes = &minutes
+	}
+	if nextRunAt.Valid {
+		recurring.NextRunAt = &nextRunAt.Time
+	}
+	if createdBy.Valid {
+		recurring.CreatedBy = &createdBy.UUID
+	}
+	return &recurring, nil
+}
+
+func utcOrNil(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
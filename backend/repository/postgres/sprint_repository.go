@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// sprintQuery выбирает спринты неудаленных досок
+const sprintQuery = `
+	SELECT s.id, s.board_id, s.name, s.goal, s.state, s.starts_at, s.ends_at, s.completed_at,
+		s.created_by, s.created_at, s.updated_at
+	FROM sprints s
+	JOIN boards b ON b.id = s.board_id AND b.deleted_at IS NULL
+`
+
+type SprintRepository struct {
+	db *sql.DB
+}
+
+func NewSprintRepository(db *sql.DB) *SprintRepository {
+	return &SprintRepository{db: db}
+}
+
+func (r *SprintRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.Sprint, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, sprintQuery+`
+		WHERE s.board_id = $1
+		ORDER BY s.starts_at NULLS LAST, s.created_at
+	`, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sprints []models.Sprint
+	for rows.Next() {
+		sprint, err := scanSprint(rows)
+		if err != nil {
+			return nil, err
+		}
+		sprints = append(sprints, *sprint)
+	}
+	return sprints, rows.Err()
+}
+
+func (r *SprintRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Sprint, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, sprintQuery+`
+		WHERE s.id = $1
+	`, id)
+
+	sprint, err := scanSprint(row)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return sprint, nil
+}
+
+// Create и Update пишут время в UTC: столбцы TIMESTAMP не хранят часовой пояс
+func (r *SprintRepository) Create(ctx context.Context, sprint *models.Sprint) error {
+	sprint.CreatedAt = time.Now()
+	sprint.UpdatedAt = sprint.CreatedAt
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO sprints (board_id, name, goal, state, starts_at, ends_at, completed_at, created_by, created_at, updated_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		WHERE EXISTS (SELECT 1 FROM boards WHERE id = $1 AND deleted_at IS NULL)
+		RETURNING id
+	`, sprint.BoardID, sprint.Name, sprint.Goal, sprint.State, utcOrNil(sprint.StartsAt), utcOrNil(sprint.EndsAt),
+		utcOrNil(sprint.CompletedAt), sprint.CreatedBy, sprint.CreatedAt, sprint.UpdatedAt).Scan(&sprint.ID)
+	return mapError(err)
+}
+
+func (r *SprintRepository) Update(ctx context.Context, sprint *models.Sprint) error {
+	sprint.UpdatedAt = time.Now()
+
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE sprints
+		SET name = $1, goal = $2, state = $3, starts_at = $4, ends_at = $5, completed_at = $6, updated_at = $7
+		WHERE id = $8 AND board_id IN (SELECT id FROM boards WHERE deleted_at IS NULL)
+	`, sprint.Name, sprint.Goal, sprint.State, utcOrNil(sprint.StartsAt), utcOrNil(sprint.EndsAt),
+		utcOrNil(sprint.CompletedAt), sprint.UpdatedAt, sprint.ID)
+	if err != nil {
+		return mapError(err)
+	}
+	return requireAffected(res)
+}
+
+// Delete полагается на ON DELETE SET NULL у tasks.sprint_id и ON DELETE
+// CASCADE у истории состава и снимков спринта
+func (r *SprintRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, "DELETE FROM sprints WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *SprintRepository) ListInProgress(ctx context.Context, completedSince time.Time) ([]models.Sprint, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, sprintQuery+`
+		WHERE s.state = 'active' OR s.completed_at >= $1
+		ORDER BY s.starts_at, s.created_at
+	`, completedSince.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sprints []models.Sprint
+	for rows.Next() {
+		sprint, err := scanSprint(rows)
+		if err != nil {
+			return nil, err
+		}
+		sprints = append(sprints, *sprint)
+	}
+	return sprints, rows.Err()
+}
+
+func (r *SprintRepository) ListScopeChanges(ctx context.Context, sprintID uuid.UUID) ([]models.SprintScopeChange, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT c.id, c.sprint_id, c.task_id, t.title, c.change, c.points, c.changed_at
+		FROM sprint_scope_changes c
+		JOIN tasks t ON t.id = c.task_id AND t.deleted_at IS NULL
+		WHERE c.sprint_id = $1
+		ORDER BY c.changed_at
+	`, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.SprintScopeChange{}
+	for rows.Next() {
+		var change models.SprintScopeChange
+		var points sql.NullInt32
+		if err := rows.Scan(&change.ID, &change.SprintID, &change.TaskID, &change.TaskTitle, &change.Change, &points, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		if points.Valid {
+			value := int(points.Int32)
+			change.Points = &value
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+func (r *SprintRepository) ListSnapshots(ctx context.Context, sprintID uuid.UUID) ([]models.SprintSnapshot, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT sprint_id, day, scope_points, completed_points, scope_tasks, completed_tasks, created_at
+		FROM sprint_snapshots
+		WHERE sprint_id = $1
+		ORDER BY day
+	`, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := []models.SprintSnapshot{}
+	for rows.Next() {
+		var snapshot models.SprintSnapshot
+		err := rows.Scan(&snapshot.SprintID, &snapshot.Day, &snapshot.ScopePoints, &snapshot.CompletedPoints,
+			&snapshot.ScopeTasks, &snapshot.CompletedTasks, &snapshot.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+func (r *SprintRepository) SaveSnapshots(ctx context.Context, snapshots []models.SprintSnapshot) error {
+	now := time.Now()
+
+	return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		for _, snapshot := range snapshots {
+			_, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+				INSERT INTO sprint_snapshots (sprint_id, day, scope_points, completed_points, scope_tasks, completed_tasks, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				ON CONFLICT (sprint_id, day) DO UPDATE
+				SET scope_points = EXCLUDED.scope_points, completed_points = EXCLUDED.completed_points,
+					scope_tasks = EXCLUDED.scope_tasks, completed_tasks = EXCLUDED.completed_tasks,
+					created_at = EXCLUDED.created_at
+			`, snapshot.SprintID, snapshot.Day.UTC().Format(time.DateOnly), snapshot.ScopePoints, snapshot.CompletedPoints,
+				snapshot.ScopeTasks, snapshot.CompletedTasks, now)
+			// Нарушение внешнего ключа: спринт уже удален
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				return repository.ErrNotFound
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func scanSprint(row rowScanner) (*models.Sprint, error) {
+	var sprint models.Sprint
+	var startsAt, endsAt, completedAt sql.NullTime
+	var createdBy uuid.NullUUID
+
+	err := row.Scan(&sprint.ID, &sprint.BoardID, &sprint.Name, &sprint.Goal, &sprint.State, &startsAt, &endsAt,
+		&completedAt, &createdBy, &sprint.CreatedAt, &sprint.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if startsAt.Valid {
+		sprint.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		sprint.EndsAt = &endsAt.Time
+	}
+	if completedAt.Valid {
+		sprint.CompletedAt = &completedAt.Time
+	}
+	if createdBy.Valid {
+		sprint.CreatedBy = &createdBy.UUID
+	}
+	return &sprint, nil
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// taskLinkQuery выбирает связи, обе задачи которых не удалены
+const taskLinkQuery = `
+	SELECT l.id, l.source_task_id, l.target_task_id, l.type, l.created_by, l.created_at
+	FROM task_links l
+	JOIN tasks s ON s.id = l.source_task_id AND s.deleted_at IS NULL
+	JOIN tasks t ON t.id = l.target_task_id AND t.deleted_at IS NULL
+`
+
+type TaskLinkRepository struct {
+	db *sql.DB
+}
+
+func NewTaskLinkRepository(db *sql.DB) *TaskLinkRepository {
+	return &TaskLinkRepository{db: db}
+}
+
+func (r *TaskLinkRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.TaskLink, error) {
+	return r.query(ctx, taskLinkQuery+`
+		WHERE l.source_task_id = $1 OR l.target_task_id = $1
+		ORDER BY l.created_at
+	`, taskID)
+}
+
+func (r *TaskLinkRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.TaskLink, error) {
+	return r.query(ctx, taskLinkQuery+`
+		WHERE s.board_id = $1 OR t.board_id = $1
+		ORDER BY l.created_at
+	`, boardID)
+}
+
+func (r *TaskLinkRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.TaskLink, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, taskLinkQuery+`
+		WHERE l.id = $1
+	`, id)
+
+	link, err := scanTaskLink(row)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return link, nil
+}
+
+func (r *TaskLinkRepository) Create(ctx context.Context, link *models.TaskLink) error {
+	link.CreatedAt = time.Now()
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO task_links (source_task_id, target_task_id, type, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, link.SourceTaskID, link.TargetTaskID, link.Type, link.CreatedBy, link.CreatedAt).Scan(&link.ID)
+
+	return mapError(err)
+}
+
+func (r *TaskLinkRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, "DELETE FROM task_links WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *TaskLinkRepository) query(ctx context.Context, query string, args ...any) ([]models.TaskLink, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []models.TaskLink
+	for rows.Next() {
+		link, err := scanTaskLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *link)
+	}
+	return links, rows.Err()
+}
+
+func scanTaskLink(row rowScanner) (*models.TaskLink, error) {
+	var link models.TaskLink
+	var createdBy uuid.NullUUID
+
+	if err := row.Scan(&link.ID, &link.SourceTaskID, &link.TargetTaskID, &link.Type, &createdBy, &link.CreatedAt); err != nil {
+		return nil, err
+	}
+	if createdBy.Valid {
+		link.CreatedBy = &createdBy.UUID
+	}
+	return &link, nil
+}
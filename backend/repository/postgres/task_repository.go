@@ -0,0 +1,497 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// taskColumns выбирает поля задачи и ее прогресс. Подзадача выполнена,
+// если она в последней колонке своей доски. Запросы должны выбирать из
+// tasks без псевдонима.
+const taskColumns = `id, board_id, parent_task_id, title, description, status, priority, assignee, estimate_minutes, story_points, due_at, sprint_id, created_by, created_at, updated_at, archived_at,
+	(SELECT COUNT(*) FROM checklist_items ci WHERE ci.task_id = tasks.id AND ci.checked)
+		+ (SELECT COUNT(*) FROM tasks sub
+			WHERE sub.parent_task_id = tasks.id AND sub.deleted_at IS NULL
+				AND sub.status = (
+					SELECT c.status_id FROM columns c
+					WHERE c.board_id = sub.board_id AND c.deleted_at IS NULL AND c.archived_at IS NULL
+					ORDER BY c.position DESC
+					LIMIT 1
+				)),
+	(SELECT COUNT(*) FROM checklist_items ci WHERE ci.task_id = tasks.id)
+		+ (SELECT COUNT(*) FROM tasks sub WHERE sub.parent_task_id = tasks.id AND sub.deleted_at IS NULL)`
+
+type TaskRepository struct {
+	db *sql.DB
+}
+
+func NewTaskRepository(db *sql.DB) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+func (r *TaskRepository) ListByBoard(ctx context.Context, boardID uuid.UUID, opts repository.ListOptions) ([]models.Task, error) {
+	return listTasks(ctx, database.Conn(ctx, r.db), boardID, opts)
+}
+
+func (r *TaskRepository) ListPage(ctx context.Context, boardID uuid.UUID, after *repository.TaskCursor, limit int, opts repository.ListOptions) ([]models.Task, error) {
+	var afterCreatedAt sql.NullTime
+	var afterID uuid.UUID
+	if after != nil {
+		afterCreatedAt = sql.NullTime{Time: after.CreatedAt, Valid: true}
+		afterID = after.ID
+	}
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE board_id = $1 AND deleted_at IS NULL AND ($2 OR archived_at IS NULL)
+			AND ($3::timestamp IS NULL OR (created_at, id) > ($3, $4))
+		ORDER BY created_at, id
+		LIMIT $5
+	`, boardID, opts.IncludeArchived, afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanTasks(rows)
+}
+
+func (r *TaskRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Task, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE id = $1 AND deleted_at IS NULL
+	`, id)
+
+	task, err := scanTask(row)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return task, nil
+}
+
+// Create, Update, Move, MoveToBoard и ReassignStatus пишут смену статуса
+// в task_status_changes, а Create, Update, SetSprint и MoveToBoard - смену
+// состава спринта в sprint_scope_changes в той же транзакции
+func (r *TaskRepository) Create(ctx context.Context, task *models.Task) error {
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = task.CreatedAt
+
+	return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+			INSERT INTO tasks (board_id, parent_task_id, title, description, status, priority, assignee, estimate_minutes, story_points, due_at, sprint_id, created_by, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING id
+		`, task.BoardID, task.ParentTaskID, task.Title, task.Description, task.Status, task.Priority, task.Assignee, task.EstimateMinutes, task.StoryPoints, utcOrNil(task.DueAt), task.SprintID, task.CreatedBy, task.CreatedAt, task.UpdatedAt).Scan(&task.ID)
+		if err != nil {
+			return mapError(err)
+		}
+		if task.SprintID != nil {
+			if err := r.recordScopeChange(ctx, *task.SprintID, task.ID, models.SprintScopeAdded, task.CreatedAt); err != nil {
+				return err
+			}
+		}
+		return r.recordStatusChange(ctx, task.ID, task.BoardID, nil, task.Status, task.CreatedAt)
+	})
+}
+
+func (r *TaskRepository) Update(ctx context.Context, task *models.Task) error {
+	task.UpdatedAt = time.Now()
+
+	return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		var from string
+		var boardID uuid.UUID
+		var sprintID uuid.NullUUID
+		var pointsChanged bool
+		err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+			UPDATE tasks
+			SET title = $1, description = $2, status = $3, priority = $4, assignee = $5, estimate_minutes = $6, story_points = $7, due_at = $8, updated_at = $9
+			FROM (SELECT id, status, story_points FROM tasks WHERE id = $10 AND deleted_at IS NULL FOR UPDATE) old
+			WHERE tasks.id = old.id
+			RETURNING old.status, tasks.board_id, tasks.sprint_id, old.story_points IS DISTINCT FROM tasks.story_points
+		`, task.Title, task.Description, task.Status, task.Priority, task.Assignee, task.EstimateMinutes, task.StoryPoints, utcOrNil(task.DueAt), task.UpdatedAt, task.ID).Scan(&from, &boardID, &sprintID, &pointsChanged)
+		if err != nil {
+			return mapError(err)
+		}
+		if sprintID.Valid && pointsChanged {
+			if err := r.recordScopeChange(ctx, sprintID.UUID, task.ID, models.SprintScopePoints, task.UpdatedAt); err != nil {
+				return err
+			}
+		}
+		if from == task.Status {
+			return nil
+		}
+		return r.recordStatusChange(ctx, task.ID, boardID, &from, task.Status, task.UpdatedAt)
+	})
+}
+
+func (r *TaskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, "UPDATE tasks SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL", time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *TaskRepository) Move(ctx context.Context, id uuid.UUID, status string) error {
+	now := time.Now()
+
+	return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		var from string
+		var boardID uuid.UUID
+		err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+			UPDATE tasks
+			SET status = $1, updated_at = $2
+			FROM (SELECT id, status FROM tasks WHERE id = $3 AND deleted_at IS NULL FOR UPDATE) old
+			WHERE tasks.id = old.id
+			RETURNING old.status, tasks.board_id
+		`, status, now, id).Scan(&from, &boardID)
+		if err != nil {
+			return mapError(err)
+		}
+		if from == status {
+			return nil
+		}
+		return r.recordStatusChange(ctx, id, boardID, &from, status, now)
+	})
+}
+
+// MoveToBoard записывает перенос на другую доску как попадание в статус без from_status
+func (r *TaskRepository) MoveToBoard(ctx context.Context, id, boardID uuid.UUID, status string) error {
+	now := time.Now()
+
+	return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		var from string
+		var fromBoardID uuid.UUID
+		var fromSprintID uuid.NullUUID
+		err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+			UPDATE tasks
+			SET board_id = $1, status = $2, updated_at = $3,
+				sprint_id = CASE WHEN old.board_id = $1 THEN tasks.sprint_id END
+			FROM (SELECT id, board_id, status, sprint_id FROM tasks WHERE id = $4 AND deleted_at IS NULL FOR UPDATE) old
+			WHERE tasks.id = old.id
+			RETURNING old.status, old.board_id, old.sprint_id
+		`, boardID, status, now, id).Scan(&from, &fromBoardID, &fromSprintID)
+		if err != nil {
+			return mapError(err)
+		}
+		switch {
+		case fromBoardID != boardID:
+			if fromSprintID.Valid {
+				if err := r.recordScopeChange(ctx, fromSprintID.UUID, id, models.SprintScopeRemoved, now); err != nil {
+					return err
+				}
+			}
+			return r.recordStatusChange(ctx, id, boardID, nil, status, now)
+		case from != status:
+			return r.recordStatusChange(ctx, id, boardID, &from, status, now)
+		}
+		return nil
+	})
+}
+
+// recordStatusChange добавляет в историю попадание задачи в статус to доски boardID
+func (r *TaskRepository) recordStatusChange(ctx context.Context, taskID, boardID uuid.UUID, from *string, to string, at time.Time) error {
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO task_status_changes (task_id, board_id, from_status, to_status, changed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, taskID, boardID, from, to, at)
+	return err
+}
+
+// recordScopeChange добавляет в историю состава спринта sprintID изменение
+// change задачи taskID с ее текущей оценкой
+func (r *TaskRepository) recordScopeChange(ctx context.Context, sprintID, taskID uuid.UUID, change string, at time.Time) error {
+	_, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO sprint_scope_changes (sprint_id, task_id, change, points, changed_at)
+		SELECT $1, id, $2, story_points, $3 FROM tasks WHERE id = $4
+	`, sprintID, change, at, taskID)
+	return err
+}
+
+func (r *TaskRepository) ListStatusChanges(ctx context.Context, boardID uuid.UUID) ([]models.TaskStatusChange, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT c.id, c.task_id, c.board_id, c.from_status, c.to_status, c.changed_at
+		FROM task_status_changes c
+		JOIN tasks t ON t.id = c.task_id AND t.board_id = c.board_id AND t.deleted_at IS NULL
+		WHERE c.board_id = $1
+		ORDER BY c.changed_at
+	`, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.TaskStatusChange{}
+	for rows.Next() {
+		var change models.TaskStatusChange
+		var from sql.NullString
+		if err := rows.Scan(&change.ID, &change.TaskID, &change.BoardID, &from, &change.ToStatus, &change.ChangedAt); err != nil {
+			return nil, err
+		}
+		if from.Valid {
+			change.FromStatus = &from.String
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+func (r *TaskRepository) Archive(ctx context.Context, id uuid.UUID) error {
+	return r.setArchived(ctx, id, "archived_at IS NULL", time.Now())
+}
+
+func (r *TaskRepository) Unarchive(ctx context.Context, id uuid.UUID) error {
+	return r.setArchived(ctx, id, "archived_at IS NOT NULL", nil)
+}
+
+func (r *TaskRepository) setArchived(ctx context.Context, id uuid.UUID, condition string, value any) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE tasks
+		SET archived_at = $1
+		WHERE id = $2 AND deleted_at IS NULL AND `+condition, value, id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *TaskRepository) ArchiveByStatus(ctx context.Context, boardID uuid.UUID, status string, updatedBefore time.Time) ([]uuid.UUID, error) {
+	return queryIDs(ctx, database.Conn(ctx, r.db), `
+		UPDATE tasks
+		SET archived_at = $1
+		WHERE board_id = $2 AND status = $3 AND updated_at < $4
+			AND archived_at IS NULL AND deleted_at IS NULL
+		RETURNING id
+	`, time.Now(), boardID, status, updatedBefore)
+}
+
+func (r *TaskRepository) UnarchiveByStatus(ctx context.Context, boardID uuid.UUID, status string, archivedSince time.Time) ([]uuid.UUID, error) {
+	return queryIDs(ctx, database.Conn(ctx, r.db), `
+		UPDATE tasks
+		SET archived_at = NULL
+		WHERE board_id = $1 AND status = $2 AND archived_at >= $3 AND deleted_at IS NULL
+		RETURNING id
+	`, boardID, status, archivedSince)
+}
+
+func (r *TaskRepository) ListSubtasks(ctx context.Context, parentID uuid.UUID) ([]models.Task, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE parent_task_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at
+	`, parentID)
+	if err != nil {
+		return nil, err
+	}
+	return scanTasks(rows)
+}
+
+// ListBySprint возвращает задачи спринта, включая архивные
+func (r *TaskRepository) ListBySprint(ctx context.Context, sprintID uuid.UUID) ([]models.Task, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE sprint_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at
+	`, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	return scanTasks(rows)
+}
+
+func (r *TaskRepository) ListByUser(ctx context.Context, userID uuid.UUID, username string) ([]models.Task, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE (created_by = $1 OR assignee = $2) AND deleted_at IS NULL
+		ORDER BY created_at
+	`, userID, username)
+	if err != nil {
+		return nil, err
+	}
+	return scanTasks(rows)
+}
+
+func (r *TaskRepository) SetSprint(ctx context.Context, id uuid.UUID, sprintID *uuid.UUID) error {
+	now := time.Now()
+
+	return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		var previous uuid.NullUUID
+		err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+			UPDATE tasks
+			SET sprint_id = $1, updated_at = $2
+			FROM (SELECT id, sprint_id FROM tasks WHERE id = $3 AND deleted_at IS NULL FOR UPDATE) old
+			WHERE tasks.id = old.id
+			RETURNING old.sprint_id
+		`, sprintID, now, id).Scan(&previous)
+		// Нарушение внешнего ключа: спринт уже удален
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return repository.ErrNotFound
+		}
+		if err != nil {
+			return mapError(err)
+		}
+
+		if previous.Valid && (sprintID == nil || previous.UUID != *sprintID) {
+			if err := r.recordScopeChange(ctx, previous.UUID, id, models.SprintScopeRemoved, now); err != nil {
+				return err
+			}
+		}
+		if sprintID != nil && (!previous.Valid || previous.UUID != *sprintID) {
+			return r.recordScopeChange(ctx, *sprintID, id, models.SprintScopeAdded, now)
+		}
+		return nil
+	})
+}
+
+func (r *TaskRepository) SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE tasks
+		SET parent_task_id = $1, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL
+	`, parentID, time.Now(), id)
+	if err != nil {
+		return mapError(err)
+	}
+	return requireAffected(res)
+}
+
+func queryIDs(ctx context.Context, db database.DBTX, query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ReassignStatus одной командой переводит задачи и пишет историю: число
+// вставленных записей истории равно числу перемещенных задач
+func (r *TaskRepository) ReassignStatus(ctx context.Context, boardID uuid.UUID, from, to string) (int64, error) {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		WITH moved AS (
+			UPDATE tasks
+			SET status = $1, updated_at = $2
+			WHERE board_id = $3 AND status = $4 AND deleted_at IS NULL
+			RETURNING id
+		)
+		INSERT INTO task_status_changes (task_id, board_id, from_status, to_status, changed_at)
+		SELECT id, $3, $4, $1, $2 FROM moved
+	`, to, time.Now(), boardID, from)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func listTasks(ctx context.Context, db database.DBTX, boardID uuid.UUID, opts repository.ListOptions) ([]models.Task, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+taskColumns+`
+		FROM tasks
+		WHERE board_id = $1 AND deleted_at IS NULL AND ($2 OR archived_at IS NULL)
+		ORDER BY created_at DESC
+	`, boardID, opts.IncludeArchived)
+	if err != nil {
+		return nil, err
+	}
+	return scanTasks(rows)
+}
+
+func scanTasks(rows *sql.Rows) ([]models.Task, error) {
+	defer rows.Close()
+
+	var tasks []models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, rows.Err()
+}
+
+func scanTask(row rowScanner) (*models.Task, error) {
+	var task models.Task
+	var description, priority, assignee sql.NullString
+	var createdBy, parentTaskID, sprintID uuid.NullUUID
+	var archivedAt, dueAt sql.NullTime
+	var estimate, points sql.NullInt32
+
+	err := row.Scan(
+		&task.ID,
+		&task.BoardID,
+		&parentTaskID,
+		&task.Title,
+		&description,
+		&task.Status,
+		&priority,
+		&assignee,
+		&estimate,
+		&points,
+		&dueAt,
+		&sprintID,
+		&createdBy,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&archivedAt,
+		&task.Progress.Done,
+		&task.Progress.Total,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Description = description.String
+	if priority.Valid {
+		task.Priority = &priority.String
+	}
+	if assignee.Valid {
+		task.Assignee = &assignee.String
+	}
+	if estimate.Valid {
+		minutes := int(estimate.Int32)
+		task.EstimateMinutes = &minutes
+	}
+	if points.Valid {
+		storyPoints := int(points.Int32)
+		task.StoryPoints = &storyPoints
+	}
+	if dueAt.Valid {
+		task.DueAt = &dueAt.Time
+	}
+	if sprintID.Valid {
+		task.SprintID = &sprintID.UUID
+	}
+	if createdBy.Valid {
+		task.CreatedBy = &createdBy.UUID
+	}
+	if archivedAt.Valid {
+		task.ArchivedAt = &archivedAt.Time
+	}
+	if parentTaskID.Valid {
+		task.ParentTaskID = &parentTaskID.UUID
+	}
+
+	return &task, nil
+}
@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+
+	"github.com/google/uuid"
+)
+
+// TemplateRepository хранит колонки, метки и задачи шаблона в JSONB-полях
+type TemplateRepository struct {
+	db *sql.DB
+}
+
+func NewTemplateRepository(db *sql.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+const templateColumns = `id, name, description, created_by, created_at, columns, labels, tasks`
+
+func (r *TemplateRepository) List(ctx context.Context) ([]models.BoardTemplate, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+templateColumns+`
+		FROM board_templates
+		ORDER BY created_at ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.BoardTemplate
+	for rows.Next() {
+		tpl, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *tpl)
+	}
+
+	return templates, rows.Err()
+}
+
+func (r *TemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.BoardTemplate, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT `+templateColumns+`
+		FROM board_templates
+		WHERE id = $1
+	`, id)
+
+	tpl, err := scanTemplate(row)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return tpl, nil
+}
+
+func (r *TemplateRepository) Create(ctx context.Context, tpl *models.BoardTemplate) error {
+	columns, err := json.Marshal(tpl.Columns)
+	if err != nil {
+		return err
+	}
+	labels, err := json.Marshal(tpl.Labels)
+	if err != nil {
+		return err
+	}
+	tasks, err := json.Marshal(tpl.Tasks)
+	if err != nil {
+		return err
+	}
+
+	var id uuid.UUID
+	err = database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO board_templates (name, description, created_by, columns, labels, tasks)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, tpl.Name, tpl.Description, tpl.CreatedBy, columns, labels, tasks).Scan(&id, &tpl.CreatedAt)
+	if err != nil {
+		return mapError(err)
+	}
+
+	tpl.ID = id.String()
+	tpl.BuiltIn = false
+	return nil
+}
+
+func (r *TemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, "DELETE FROM board_templates WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func scanTemplate(row rowScanner) (*models.BoardTemplate, error) {
+	var tpl models.BoardTemplate
+	var id uuid.UUID
+	var columns, labels, tasks []byte
+
+	err := row.Scan(&id, &tpl.Name, &tpl.Description, &tpl.CreatedBy, &tpl.CreatedAt, &columns, &labels, &tasks)
+	if err != nil {
+		return nil, err
+	}
+	tpl.ID = id.String()
+
+	if err := json.Unmarshal(columns, &tpl.Columns); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(labels, &tpl.Labels); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tasks, &tpl.Tasks); err != nil {
+		return nil, err
+	}
+
+	return &tpl, nil
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrashRepository struct {
+	db *sql.DB
+}
+
+func NewTrashRepository(db *sql.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// List возвращает удаленные доски, а также удаленные по отдельности колонки
+// и задачи неудаленных досок; содержимое удаленной доски входит в нее саму.
+func (r *TrashRepository) List(ctx context.Context) ([]models.TrashItem, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT 'board', id, id, name, deleted_at
+		FROM boards
+		WHERE deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'column', c.id, c.board_id, c.title, c.deleted_at
+		FROM columns c
+		JOIN boards b ON b.id = c.board_id
+		WHERE c.deleted_at IS NOT NULL AND b.deleted_at IS NULL
+		UNION ALL
+		SELECT 'task', t.id, t.board_id, t.title, t.deleted_at
+		FROM tasks t
+		JOIN boards b ON b.id = t.board_id
+		WHERE t.deleted_at IS NOT NULL AND b.deleted_at IS NULL
+		ORDER BY 5 DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.TrashItem
+	for rows.Next() {
+		var item models.TrashItem
+		if err := rows.Scan(&item.Type, &item.ID, &item.BoardID, &item.Title, &item.DeletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (r *TrashRepository) Restore(ctx context.Context, itemType string, id uuid.UUID) error {
+	switch itemType {
+	case models.TrashTypeBoard:
+		return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+			db := database.Conn(ctx, r.db)
+
+			// Возвращаем только то, что было удалено вместе с доской
+			for _, table := range []string{"columns", "tasks"} {
+				_, err := db.ExecContext(ctx, `
+					UPDATE `+table+` SET deleted_at = NULL
+					WHERE board_id = $1 AND deleted_at = (SELECT deleted_at FROM boards WHERE id = $1)
+				`, id)
+				if err != nil {
+					return mapError(err)
+				}
+			}
+
+			res, err := db.ExecContext(ctx, "UPDATE boards SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL", id)
+			if err != nil {
+				return err
+			}
+			return requireAffected(res)
+		})
+
+	case models.TrashTypeColumn, models.TrashTypeTask:
+		table := "columns"
+		if itemType == models.TrashTypeTask {
+			table = "tasks"
+		}
+		res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+			UPDATE `+table+` AS item SET deleted_at = NULL
+			FROM boards b
+			WHERE item.id = $1 AND item.deleted_at IS NOT NULL
+				AND b.id = item.board_id AND b.deleted_at IS NULL
+		`, id)
+		if err != nil {
+			return mapError(err)
+		}
+		return requireAffected(res)
+	}
+
+	return fmt.Errorf("unknown trash item type %q", itemType)
+}
+
+// Purge окончательно удаляет элементы, попавшие в корзину раньше before.
+// Колонки и задачи удаленных досок удаляются каскадно вместе с доской.
+func (r *TrashRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+
+	err := database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		for _, table := range []string{"tasks", "columns", "boards"} {
+			res, err := database.Conn(ctx, r.db).ExecContext(ctx,
+				"DELETE FROM "+table+" WHERE deleted_at IS NOT NULL AND deleted_at < $1", before)
+			if err != nil {
+				return err
+			}
+			n, err := res.RowsAffected()
+			if err != nil {
+				return err
+			}
+			total += n
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
@@ -0,0 +1,215 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const userColumns = `id, username, email, password_hash, role, status, token_version, locked_until, email_verified_at, created_at, updated_at`
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	return r.getBy(ctx, "username", username)
+}
+
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.getBy(ctx, "email", email)
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return r.getBy(ctx, "id", id)
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *models.User, password string) error {
+	hashedPassword, err := repository.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	user.PasswordHash = hashedPassword
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	if user.Role == "" {
+		user.Role = models.UserRoleUser
+	}
+	if user.Status == "" {
+		user.Status = models.UserStatusActive
+	}
+
+	err = database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO users (username, email, password_hash, role, status, email_verified_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, token_version
+	`, user.Username, user.Email, user.PasswordHash, user.Role, user.Status, user.EmailVerifiedAt, user.CreatedAt, user.UpdatedAt).Scan(&user.ID, &user.TokenVersion)
+
+	return mapError(err)
+}
+
+func (r *UserRepository) List(ctx context.Context) ([]models.User, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT `+userColumns+`
+		FROM users
+		ORDER BY username ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *UserRepository) LockAdmins(ctx context.Context) ([]uuid.UUID, error) {
+	// Порядок по id исключает взаимоблокировку двух транзакций
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT id
+		FROM users
+		WHERE role = $1 AND status <> $2
+		ORDER BY id
+		FOR UPDATE
+	`, models.UserRoleAdmin, models.UserStatusDisabled)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	user.UpdatedAt = time.Now()
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE users
+		SET email = $1, role = $2, status = $3, locked_until = $4, updated_at = $5
+		WHERE id = $6
+	`, user.Email, user.Role, user.Status, user.LockedUntil, user.UpdatedAt, user.ID)
+	if err != nil {
+		return mapError(err)
+	}
+	return requireAffected(res)
+}
+
+func (r *UserRepository) SetPassword(ctx context.Context, id uuid.UUID, password string) error {
+	hashedPassword, err := repository.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3
+	`, hashedPassword, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *UserRepository) RevokeTokens(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE users SET token_version = token_version + 1, updated_at = $1 WHERE id = $2
+	`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, $1), updated_at = $1
+		WHERE id = $2
+	`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+// Delete снимает пользователя с задач по имени (assignee не ссылается на
+// users), остальное делают внешние ключи: SET NULL для авторства, CASCADE
+// для членства в досках и записей времени
+func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		conn := database.Conn(ctx, r.db)
+
+		var username string
+		err := conn.QueryRowContext(ctx, `SELECT username FROM users WHERE id = $1 FOR UPDATE`, id).Scan(&username)
+		if err != nil {
+			return mapError(err)
+		}
+		for _, table := range []string{"tasks", "checklist_items", "recurring_tasks"} {
+			if _, err := conn.ExecContext(ctx, `UPDATE `+table+` SET assignee = NULL WHERE assignee = $1`, username); err != nil {
+				return err
+			}
+		}
+
+		res, err := conn.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id)
+		if err != nil {
+			return err
+		}
+		return requireAffected(res)
+	})
+}
+
+// getBy выбирает пользователя по одному из уникальных полей; column не берется из пользовательского ввода
+func (r *UserRepository) getBy(ctx context.Context, column string, value interface{}) (*models.User, error) {
+	user, err := scanUser(database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT `+userColumns+`
+		FROM users
+		WHERE `+column+` = $1
+	`, value))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return user, nil
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	var user models.User
+	var lockedUntil, emailVerifiedAt sql.NullTime
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash,
+		&user.Role, &user.Status, &user.TokenVersion, &lockedUntil, &emailVerifiedAt, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if lockedUntil.Valid {
+		user.LockedUntil = &lockedUntil.Time
+	}
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
+	return &user, nil
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"time"
+)
+
+type UserTokenRepository struct {
+	db *sql.DB
+}
+
+func NewUserTokenRepository(db *sql.DB) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+func (r *UserTokenRepository) Create(ctx context.Context, token *models.UserToken) error {
+	token.CreatedAt = time.Now()
+
+	return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		conn := database.Conn(ctx, r.db)
+		_, err := conn.ExecContext(ctx, `
+			UPDATE user_tokens SET used_at = $1
+			WHERE user_id = $2 AND purpose = $3 AND used_at IS NULL
+		`, token.CreatedAt, token.UserID, token.Purpose)
+		if err != nil {
+			return err
+		}
+
+		err = conn.QueryRowContext(ctx, `
+			INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt).Scan(&token.ID)
+		return mapError(err)
+	})
+}
+
+// Consume отмечает токен одним UPDATE, поэтому два одновременных запроса
+// с одним токеном не пройдут оба
+func (r *UserTokenRepository) Consume(ctx context.Context, purpose, tokenHash string, now time.Time) (*models.UserToken, error) {
+	token := models.UserToken{Purpose: purpose, TokenHash: tokenHash, UsedAt: &now}
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		UPDATE user_tokens SET used_at = $1
+		WHERE token_hash = $2 AND purpose = $3 AND used_at IS NULL AND expires_at > $1
+		RETURNING id, user_id, expires_at, created_at
+	`, now, tokenHash, purpose).Scan(&token.ID, &token.UserID, &token.ExpiresAt, &token.CreatedAt)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &token, nil
+}
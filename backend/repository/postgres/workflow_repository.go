@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+
+	"github.com/google/uuid"
+)
+
+type WorkflowRepository struct {
+	db *sql.DB
+}
+
+func NewWorkflowRepository(db *sql.DB) *WorkflowRepository {
+	return &WorkflowRepository{db: db}
+}
+
+func (r *WorkflowRepository) ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.WorkflowTransition, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, `
+		SELECT board_id, from_status, to_status, required_fields
+		FROM workflow_transitions
+		WHERE board_id = $1
+		ORDER BY from_status, to_status
+	`, boardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transitions := []models.WorkflowTransition{}
+	for rows.Next() {
+		var transition models.WorkflowTransition
+		var fields []byte
+		if err := rows.Scan(&transition.BoardID, &transition.From, &transition.To, &fields); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(fields, &transition.RequiredFields); err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, transition)
+	}
+	return transitions, rows.Err()
+}
+
+func (r *WorkflowRepository) Replace(ctx context.Context, boardID uuid.UUID, transitions []models.WorkflowTransition) error {
+	return database.WithTx(ctx, r.db, func(ctx context.Context) error {
+		db := database.Conn(ctx, r.db)
+
+		var exists bool
+		err := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM boards WHERE id = $1 AND deleted_at IS NULL)", boardID).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return repository.ErrNotFound
+		}
+
+		if _, err := db.ExecContext(ctx, "DELETE FROM workflow_transitions WHERE board_id = $1", boardID); err != nil {
+			return err
+		}
+		for _, transition := range transitions {
+			fields := transition.RequiredFields
+			if fields == nil {
+				fields = []string{}
+			}
+			data, err := json.Marshal(fields)
+			if err != nil {
+				return err
+			}
+			_, err = db.ExecContext(ctx, `
+				INSERT INTO workflow_transitions (board_id, from_status, to_status, required_fields)
+				VALUES ($1, $2, $3, $4)
+			`, boardID, transition.From, transition.To, data)
+			if err != nil {
+				return mapError(err)
+			}
+		}
+		return nil
+	})
+}
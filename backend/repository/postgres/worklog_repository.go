@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"task-flow-backend/database"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// worklogQuery выбирает записи времени неудаленных задач
+const worklogQuery = `
+	SELECT w.id, w.task_id, w.user_id, w.started_at, w.ended_at, w.duration_minutes, w.note, w.created_at
+	FROM worklogs w
+	JOIN tasks t ON t.id = w.task_id AND t.deleted_at IS NULL
+`
+
+type WorklogRepository struct {
+	db *sql.DB
+}
+
+func NewWorklogRepository(db *sql.DB) *WorklogRepository {
+	return &WorklogRepository{db: db}
+}
+
+func (r *WorklogRepository) ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.Worklog, error) {
+	return r.query(ctx, worklogQuery+`
+		WHERE w.task_id = $1
+		ORDER BY w.started_at
+	`, taskID)
+}
+
+func (r *WorklogRepository) List(ctx context.Context, filter repository.WorklogFilter) ([]models.Worklog, error) {
+	conditions := []string{"w.ended_at IS NOT NULL"}
+	var args []any
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.BoardID != nil {
+		add("t.board_id = $%d", *filter.BoardID)
+	}
+	if filter.UserID != nil {
+		add("w.user_id = $%d", *filter.UserID)
+	}
+	if filter.From != nil {
+		add("w.started_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("w.started_at < $%d", *filter.To)
+	}
+
+	return r.query(ctx, worklogQuery+`
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY w.started_at
+	`, args...)
+}
+
+func (r *WorklogRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Worklog, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, worklogQuery+`
+		WHERE w.id = $1
+	`, id)
+
+	worklog, err := scanWorklog(row)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return worklog, nil
+}
+
+// GetRunning не проверяет задачу: таймер удаленной задачи тоже можно остановить
+func (r *WorklogRepository) GetRunning(ctx context.Context, userID uuid.UUID) (*models.Worklog, error) {
+	row := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT id, task_id, user_id, started_at, ended_at, duration_minutes, note, created_at
+		FROM worklogs
+		WHERE user_id = $1 AND ended_at IS NULL
+	`, userID)
+
+	worklog, err := scanWorklog(row)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return worklog, nil
+}
+
+func (r *WorklogRepository) Create(ctx context.Context, worklog *models.Worklog) error {
+	worklog.CreatedAt = time.Now()
+
+	err := database.Conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO worklogs (task_id, user_id, started_at, ended_at, duration_minutes, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, worklog.TaskID, worklog.UserID, worklog.StartedAt, worklog.EndedAt, worklog.DurationMinutes, worklog.Note, worklog.CreatedAt).Scan(&worklog.ID)
+
+	// Нарушение внешнего ключа: задача или пользователь уже удалены. Второй
+	// запущенный таймер нарушает уникальный индекс и дает ErrConflict.
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return repository.ErrNotFound
+	}
+	return mapError(err)
+}
+
+func (r *WorklogRepository) Finish(ctx context.Context, worklog *models.Worklog) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE worklogs
+		SET ended_at = $1, duration_minutes = $2, note = $3
+		WHERE id = $4 AND ended_at IS NULL
+	`, worklog.EndedAt, worklog.DurationMinutes, worklog.Note, worklog.ID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *WorklogRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := database.Conn(ctx, r.db).ExecContext(ctx, "DELETE FROM worklogs WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	return requireAffected(res)
+}
+
+func (r *WorklogRepository) query(ctx context.Context, query string, args ...any) ([]models.Worklog, error) {
+	rows, err := database.Conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var worklogs []models.Worklog
+	for rows.Next() {
+		worklog, err := scanWorklog(rows)
+		if err != nil {
+			return nil, err
+		}
+		worklogs = append(worklogs, *worklog)
+	}
+	return worklogs, rows.Err()
+}
+
+func scanWorklog(row rowScanner) (*models.Worklog, error) {
+	var worklog models.Worklog
+	var endedAt sql.NullTime
+	var note sql.NullString
+
+	err := row.Scan(&worklog.ID, &worklog.TaskID, &worklog.UserID, &worklog.StartedAt, &endedAt,
+		&worklog.DurationMinutes, &note, &worklog.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if endedAt.Valid {
+		worklog.EndedAt = &endedAt.Time
+	}
+	if note.Valid {
+		worklog.Note = &note.String
+	}
+	return &worklog, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"task-flow-backend/models"
+	"task-flow-backend/recurrence"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleRecurringTask проверяет правило и часовой пояс шаблона и
+// устанавливает NextRunAt - первое повторение позже after (nil, если
+// повторений больше нет). Ошибки оборачивают recurrence.ErrInvalidRule.
+func ScheduleRecurringTask(recurring *models.RecurringTask, after time.Time) error {
+	rule, err := recurrence.Parse(recurring.RRule)
+	if err != nil {
+		return err
+	}
+	loc, err := time.LoadLocation(recurring.Timezone)
+	if err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", recurrence.ErrInvalidRule, recurring.Timezone)
+	}
+
+	recurring.NextRunAt = nil
+	if next, ok := rule.Next(recurring.StartsAt.In(loc), after); ok {
+		next = next.UTC()
+		recurring.NextRunAt = &next
+	}
+	return nil
+}
+
+// CreateTask создает задачу в колонке task.Status ее доски. Если такой
+// колонки нет (например, она удалена после создания шаблона), задача
+// попадает в первую колонку доски.
+func (r Repositories) CreateTask(ctx context.Context, task *models.Task) error {
+	return r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.Boards.GetByID(ctx, task.BoardID); err != nil {
+			return err
+		}
+		columns, err := r.Columns.ListByBoard(ctx, task.BoardID, ListOptions{})
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			return ErrUnknownStatus
+		}
+		if !hasStatus(columns, task.Status) {
+			task.Status = columns[0].StatusID
+		}
+		return r.Tasks.Create(ctx, task)
+	})
+}
+
+// RunRecurringTask создает задачу по шаблону id, если его повторение
+// наступило к now, и переносит NextRunAt на первое повторение позже now.
+// Пропущенные повторения (например, пока сервис был остановлен) не
+// наверстываются: за запуск создается не больше одной задачи. Возвращает
+// nil, если создавать нечего.
+func (r Repositories) RunRecurringTask(ctx context.Context, id uuid.UUID, now time.Time) (*models.Task, error) {
+	var task *models.Task
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		recurring, err := r.RecurringTasks.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if recurring.NextRunAt == nil || recurring.NextRunAt.After(now) {
+			return nil
+		}
+
+		created := &models.Task{
+			BoardID:         recurring.BoardID,
+			Title:           recurring.Title,
+			Description:     recurring.Description,
+			Status:          recurring.Status,
+			Priority:        recurring.Priority,
+			Assignee:        recurring.Assignee,
+			EstimateMinutes: recurring.EstimateMinutes,
+			CreatedBy:       recurring.CreatedBy,
+		}
+		if err := r.CreateTask(ctx, created); err != nil {
+			return err
+		}
+
+		if err := ScheduleRecurringTask(recurring, now); err != nil {
+			return err
+		}
+		if err := r.RecurringTasks.Update(ctx, recurring); err != nil {
+			return err
+		}
+		task = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
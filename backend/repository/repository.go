@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("already exists")
+)
+
+// ListOptions уточняет выборку задач и колонок доски
+type ListOptions struct {
+	// IncludeArchived добавляет в выборку архивные элементы
+	IncludeArchived bool
+}
+
+type BoardRepository interface {
+	List(ctx context.Context) ([]models.Board, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Board, error)
+	Create(ctx context.Context, board *models.Board) error
+	Update(ctx context.Context, board *models.Board) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// SetOwner меняет создателя доски (user_id); членство не трогает
+	SetOwner(ctx context.Context, id, userID uuid.UUID) error
+}
+
+// TaskCursor - позиция в списке задач доски, упорядоченном по (created_at, id)
+type TaskCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+type TaskRepository interface {
+	ListByBoard(ctx context.Context, boardID uuid.UUID, opts ListOptions) ([]models.Task, error)
+	// ListPage возвращает не больше limit задач доски по возрастанию
+	// (created_at, id), следующих за after (nil - с начала)
+	ListPage(ctx context.Context, boardID uuid.UUID, after *TaskCursor, limit int, opts ListOptions) ([]models.Task, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Task, error)
+	Create(ctx context.Context, task *models.Task) error
+	Update(ctx context.Context, task *models.Task) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Move(ctx context.Context, id uuid.UUID, status string) error
+	// MoveToBoard переносит задачу на другую доску в статус status
+	MoveToBoard(ctx context.Context, id, boardID uuid.UUID, status string) error
+	// ReassignStatus переводит все задачи доски из статуса from в статус to
+	ReassignStatus(ctx context.Context, boardID uuid.UUID, from, to string) (int64, error)
+	Archive(ctx context.Context, id uuid.UUID) error
+	Unarchive(ctx context.Context, id uuid.UUID) error
+	// ArchiveByStatus архивирует задачи доски в статусе status, не менявшиеся
+	// с updatedBefore, и возвращает их идентификаторы
+	ArchiveByStatus(ctx context.Context, boardID uuid.UUID, status string, updatedBefore time.Time) ([]uuid.UUID, error)
+	// UnarchiveByStatus возвращает из архива задачи статуса status,
+	// заархивированные не раньше archivedSince
+	UnarchiveByStatus(ctx context.Context, boardID uuid.UUID, status string, archivedSince time.Time) ([]uuid.UUID, error)
+	// ListSubtasks возвращает непосредственные подзадачи задачи parentID
+	ListSubtasks(ctx context.Context, parentID uuid.UUID) ([]models.Task, error)
+	// SetParent делает задачу подзадачей parentID (nil - отвязывает).
+	// Проверки циклов выполняет Repositories.SetTaskParent.
+	SetParent(ctx context.Context, id uuid.UUID, parentID *uuid.UUID) error
+	// ListBySprint возвращает задачи спринта, включая архивные
+	ListBySprint(ctx context.Context, sprintID uuid.UUID) ([]models.Task, error)
+	// ListByUser возвращает задачи всех досок, созданные userID или
+	// назначенные на username, включая архивные
+	ListByUser(ctx context.Context, userID uuid.UUID, username string) ([]models.Task, error)
+	// SetSprint переносит задачу в спринт sprintID (nil - в бэклог). Проверки
+	// доски и состояния спринта выполняет Repositories.AssignSprint.
+	SetSprint(ctx context.Context, id uuid.UUID, sprintID *uuid.UUID) error
+	// ListStatusChanges возвращает по времени историю статусов задач доски
+	// (включая архивные, без удаленных), записанную на этой доске. Create,
+	// Update, Move, MoveToBoard и ReassignStatus пополняют историю сами.
+	// Create, Update, SetSprint и MoveToBoard так же пишут историю состава
+	// спринтов (SprintRepository.ListScopeChanges).
+	ListStatusChanges(ctx context.Context, boardID uuid.UUID) ([]models.TaskStatusChange, error)
+}
+
+type ColumnRepository interface {
+	ListByBoard(ctx context.Context, boardID uuid.UUID, opts ListOptions) ([]models.Column, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Column, error)
+	Create(ctx context.Context, column *models.Column) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// SetArchived архивирует колонку (at != nil) или возвращает ее из архива
+	SetArchived(ctx context.Context, id uuid.UUID, at *time.Time) error
+}
+
+// ChecklistRepository хранит упорядоченные пункты чек-листов задач
+type ChecklistRepository interface {
+	ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.ChecklistItem, error)
+	// ListByTasks возвращает чек-листы задач taskIDs по задачам в порядке ListByTask
+	ListByTasks(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID][]models.ChecklistItem, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ChecklistItem, error)
+	// Create добавляет пункт в конец чек-листа
+	Create(ctx context.Context, item *models.ChecklistItem) error
+	// Update сохраняет название, отметку, исполнителя и позицию пункта
+	Update(ctx context.Context, item *models.ChecklistItem) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// TaskLinkRepository хранит связи задач. Связи удаленных задач не возвращаются.
+type TaskLinkRepository interface {
+	// ListByTask возвращает связи, в которых задача исходная или целевая
+	ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.TaskLink, error)
+	// ListByBoard возвращает связи, хотя бы одна задача которых на доске
+	ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.TaskLink, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.TaskLink, error)
+	// Create возвращает ErrConflict, если такая связь уже есть
+	Create(ctx context.Context, link *models.TaskLink) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type ArchiveRuleRepository interface {
+	List(ctx context.Context) ([]models.ArchiveRule, error)
+	ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.ArchiveRule, error)
+	// Replace заменяет все правила доски на rules
+	Replace(ctx context.Context, boardID uuid.UUID, rules []models.ArchiveRule) error
+}
+
+// SprintRepository хранит спринты досок
+type SprintRepository interface {
+	// ListByBoard возвращает спринты доски по starts_at (спринты без даты -
+	// в конце), затем по времени создания
+	ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.Sprint, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Sprint, error)
+	// Create возвращает ErrNotFound, если доски нет
+	Create(ctx context.Context, sprint *models.Sprint) error
+	// Update сохраняет все поля спринта, включая состояние; ErrConflict,
+	// если на доске уже есть другой активный спринт
+	Update(ctx context.Context, sprint *models.Sprint) error
+	// Delete удаляет спринт; его задачи возвращаются в бэклог
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListInProgress возвращает активные спринты и спринты, завершенные не
+	// раньше completedSince
+	ListInProgress(ctx context.Context, completedSince time.Time) ([]models.Sprint, error)
+	// ListScopeChanges возвращает по времени историю состава спринта без
+	// удаленных задач. Историю пишет TaskRepository.
+	ListScopeChanges(ctx context.Context, sprintID uuid.UUID) ([]models.SprintScopeChange, error)
+	// ListSnapshots возвращает ежедневные снимки спринта по дням
+	ListSnapshots(ctx context.Context, sprintID uuid.UUID) ([]models.SprintSnapshot, error)
+	// SaveSnapshots сохраняет снимки, заменяя снимки тех же дней
+	SaveSnapshots(ctx context.Context, snapshots []models.SprintSnapshot) error
+}
+
+// WorkflowRepository хранит разрешенные переходы между статусами досок
+type WorkflowRepository interface {
+	ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.WorkflowTransition, error)
+	// Replace заменяет все переходы доски; ErrConflict для повторяющейся пары статусов
+	Replace(ctx context.Context, boardID uuid.UUID, transitions []models.WorkflowTransition) error
+}
+
+type UserRepository interface {
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	// Create заполняет пустые Role и Status значениями user и active;
+	// EmailVerifiedAt сохраняется как задан
+	Create(ctx context.Context, user *models.User, password string) error
+	// List возвращает пользователей по имени
+	List(ctx context.Context) ([]models.User, error)
+	// LockAdmins возвращает id администраторов с незаблокированной учетной
+	// записью по возрастанию. В транзакции их строки блокируются до ее конца.
+	LockAdmins(ctx context.Context) ([]uuid.UUID, error)
+	// Update сохраняет Email, Role, Status и LockedUntil
+	Update(ctx context.Context, user *models.User) error
+	SetPassword(ctx context.Context, id uuid.UUID, password string) error
+	// RevokeTokens увеличивает TokenVersion, делая выданные токены недействительными
+	RevokeTokens(ctx context.Context, id uuid.UUID) error
+	// MarkEmailVerified отмечает email подтвержденным; уже подтвержденный не меняется
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+	// Delete удаляет пользователя и снимает его с задач, пунктов чек-листов и
+	// повторяющихся задач. Ссылки created_by и автор комментариев обнуляются,
+	// членство в досках и записи времени удаляются вместе с ним.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// UserTokenRepository хранит одноразовые токены из писем по их хешу
+type UserTokenRepository interface {
+	// Create сохраняет токен; прежние неиспользованные токены пользователя
+	// с той же целью становятся недействительными
+	Create(ctx context.Context, token *models.UserToken) error
+	// Consume отмечает токен использованным и возвращает его. ErrNotFound,
+	// если токена с таким хешем и целью нет, он использован или истек к now.
+	Consume(ctx context.Context, purpose, tokenHash string, now time.Time) (*models.UserToken, error)
+}
+
+type MemberRepository interface {
+	Add(ctx context.Context, member *models.BoardMember) error
+	ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.BoardMember, error)
+	// ListByUser возвращает членства пользователя во всех досках
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.BoardMember, error)
+	SetRole(ctx context.Context, boardID, userID uuid.UUID, role string) error
+}
+
+type LabelRepository interface {
+	ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.Label, error)
+	Create(ctx context.Context, label *models.Label) error
+}
+
+// TemplateRepository хранит пользовательские шаблоны досок;
+// встроенные шаблоны описаны в пакете templates.
+type TemplateRepository interface {
+	List(ctx context.Context) ([]models.BoardTemplate, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.BoardTemplate, error)
+	Create(ctx context.Context, template *models.BoardTemplate) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// AttachmentRepository хранит вложения задач и учет их файлов (blob).
+// Файл общий для вложений с одинаковой контрольной суммой и удаляется,
+// только когда на него не ссылается ни одно вложение.
+type AttachmentRepository interface {
+	ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.Attachment, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Attachment, error)
+	// Create сохраняет вложение; ErrNotFound, если нет задачи или файла с attachment.Checksum
+	Create(ctx context.Context, attachment *models.Attachment) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetBlob(ctx context.Context, checksum string) (*models.AttachmentBlob, error)
+	// CreateBlob учитывает файл; если он уже учтен, ничего не делает
+	CreateBlob(ctx context.Context, blob *models.AttachmentBlob) error
+	// DeleteBlob удаляет учет файла; ErrConflict, если на него есть вложения
+	DeleteBlob(ctx context.Context, checksum string) error
+	// OrphanBlobs возвращает файлы, на которые не ссылается ни одно вложение
+	OrphanBlobs(ctx context.Context) ([]models.AttachmentBlob, error)
+}
+
+// WorklogFilter отбирает завершенные записи времени. Пустые поля не
+// ограничивают выборку; From и To ограничивают начало записи: From <= started_at < To.
+type WorklogFilter struct {
+	BoardID *uuid.UUID
+	UserID  *uuid.UUID
+	From    *time.Time
+	To      *time.Time
+}
+
+// WorklogRepository хранит учет времени и запущенные таймеры
+type WorklogRepository interface {
+	ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.Worklog, error)
+	// List возвращает завершенные записи неудаленных задач по возрастанию начала
+	List(ctx context.Context, filter WorklogFilter) ([]models.Worklog, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Worklog, error)
+	// GetRunning возвращает запущенный таймер пользователя или ErrNotFound
+	GetRunning(ctx context.Context, userID uuid.UUID) (*models.Worklog, error)
+	// Create возвращает ErrConflict для второго запущенного таймера пользователя
+	Create(ctx context.Context, worklog *models.Worklog) error
+	// Finish завершает запущенный таймер: сохраняет конец, длительность и заметку
+	Finish(ctx context.Context, worklog *models.Worklog) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// RecurringTaskRepository хранит шаблоны повторяющихся задач
+type RecurringTaskRepository interface {
+	ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.RecurringTask, error)
+	// ListDue возвращает шаблоны неудаленных досок, повторение которых
+	// наступило к now, по возрастанию NextRunAt
+	ListDue(ctx context.Context, now time.Time) ([]models.RecurringTask, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.RecurringTask, error)
+	Create(ctx context.Context, recurring *models.RecurringTask) error
+	Update(ctx context.Context, recurring *models.RecurringTask) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// CommentRepository хранит комментарии задач
+type CommentRepository interface {
+	// ListByTask возвращает комментарии по возрастанию времени создания
+	ListByTask(ctx context.Context, taskID uuid.UUID) ([]models.TaskComment, error)
+	// ListByTasks возвращает комментарии задач taskIDs по задачам в порядке ListByTask
+	ListByTasks(ctx context.Context, taskIDs []uuid.UUID) (map[uuid.UUID][]models.TaskComment, error)
+	// ListByUser возвращает комментарии пользователя по возрастанию времени создания
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.TaskComment, error)
+	// Create возвращает ErrNotFound, если задачи нет. Пустое CreatedAt
+	// заменяется текущим временем, заданное сохраняется (импорт)
+	Create(ctx context.Context, comment *models.TaskComment) error
+}
+
+// AutomationRuleRepository хранит правила автоматизации досок
+type AutomationRuleRepository interface {
+	// ListByBoard возвращает правила доски в порядке создания
+	ListByBoard(ctx context.Context, boardID uuid.UUID) ([]models.AutomationRule, error)
+	// ListByTrigger возвращает включенные правила неудаленных досок с триггером triggerType
+	ListByTrigger(ctx context.Context, triggerType string) ([]models.AutomationRule, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.AutomationRule, error)
+	Create(ctx context.Context, rule *models.AutomationRule) error
+	Update(ctx context.Context, rule *models.AutomationRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// AutomationRunFilter отбирает записи журнала доски; RuleID необязателен
+type AutomationRunFilter struct {
+	BoardID uuid.UUID
+	RuleID  *uuid.UUID
+	Limit   int
+}
+
+// AutomationRunRepository хранит журнал выполнения правил автоматизации
+type AutomationRunRepository interface {
+	Create(ctx context.Context, run *models.AutomationRun) error
+	// List возвращает записи от новых к старым, не больше filter.Limit
+	List(ctx context.Context, filter AutomationRunFilter) ([]models.AutomationRun, error)
+	// LastRun возвращает время последнего выполнения правила для задачи
+	// или nil, если правило для нее не выполнялось
+	LastRun(ctx context.Context, ruleID, taskID uuid.UUID) (*time.Time, error)
+}
+
+// AutomationDelayRepository хранит отложенные действия правил автоматизации
+type AutomationDelayRepository interface {
+	// Schedule сохраняет отложенный запуск, заменяя прежний запуск того же
+	// правила для той же задачи; ErrNotFound, если правила или задачи нет
+	Schedule(ctx context.Context, delay *models.AutomationDelay) error
+	// ListDue возвращает запуски со сроком не позже now, от ранних к поздним, не больше limit
+	ListDue(ctx context.Context, now time.Time, limit int) ([]models.AutomationDelay, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// ImportJobRepository хранит задания импорта досок
+type ImportJobRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ImportJob, error)
+	Create(ctx context.Context, job *models.ImportJob) error
+	// Update сохраняет состояние, прогресс, доску и ошибку задания
+	Update(ctx context.Context, job *models.ImportJob) error
+}
+
+// TrashRepository работает с мягко удаленными досками, колонками и задачами
+type TrashRepository interface {
+	List(ctx context.Context) ([]models.TrashItem, error)
+	// Restore возвращает элемент типа models.TrashType* из корзины
+	Restore(ctx context.Context, itemType string, id uuid.UUID) error
+	// Purge окончательно удаляет элементы, удаленные раньше before
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Transactor выполняет fn как единицу работы: все вызовы репозиториев
+// с переданным в fn контекстом фиксируются или откатываются вместе.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Repositories объединяет все хранилища, которые получают обработчики
+type Repositories struct {
+	Boards           BoardRepository
+	Tasks            TaskRepository
+	Columns          ColumnRepository
+	Users            UserRepository
+	UserTokens       UserTokenRepository
+	Members          MemberRepository
+	Labels           LabelRepository
+	Templates        TemplateRepository
+	Trash            TrashRepository
+	ArchiveRules     ArchiveRuleRepository
+	Workflows        WorkflowRepository
+	Sprints          SprintRepository
+	Checklists       ChecklistRepository
+	Links            TaskLinkRepository
+	Attachments      AttachmentRepository
+	Worklogs         WorklogRepository
+	RecurringTasks   RecurringTaskRepository
+	Comments         CommentRepository
+	AutomationRules  AutomationRuleRepository
+	AutomationRuns   AutomationRunRepository
+	AutomationDelays AutomationDelayRepository
+	ImportJobs       ImportJobRepository
+	Tx               Transactor
+}
+
+func HashPassword(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
+
+func VerifyPassword(hashedPassword, password string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	return err == nil
+}
@@ -0,0 +1,1745 @@
+// Package repotest содержит общие проверки контрактов repository, которые
+// прогоняются и для in-memory, и для Postgres реализации.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"task-flow-backend/models"
+	"task-flow-backend/repository"
+	"task-flow-backend/templates"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run выполняет все проверки; newRepos должен возвращать изолированный набор хранилищ
+func Run(t *testing.T, newRepos func(t *testing.T) repository.Repositories) {
+	t.Run("Boards", func(t *testing.T) { testBoards(t, newRepos(t)) })
+	t.Run("Tasks", func(t *testing.T) { testTasks(t, newRepos(t)) })
+	t.Run("TaskPages", func(t *testing.T) { testTaskPages(t, newRepos(t)) })
+	t.Run("Columns", func(t *testing.T) { testColumns(t, newRepos(t)) })
+	t.Run("Users", func(t *testing.T) { testUsers(t, newRepos(t)) })
+	t.Run("TransferOwnership", func(t *testing.T) { testTransferOwnership(t, newRepos(t)) })
+	t.Run("DeleteUser", func(t *testing.T) { testDeleteUser(t, newRepos(t)) })
+	t.Run("LastAdmin", func(t *testing.T) { testLastAdmin(t, newRepos(t)) })
+	t.Run("UserTokens", func(t *testing.T) { testUserTokens(t, newRepos(t)) })
+	t.Run("Transactions", func(t *testing.T) { testTransactions(t, newRepos(t)) })
+	t.Run("DeleteColumn", func(t *testing.T) { testDeleteColumn(t, newRepos(t)) })
+	t.Run("MoveTasks", func(t *testing.T) { testMoveTasks(t, newRepos(t)) })
+	t.Run("Templates", func(t *testing.T) { testTemplates(t, newRepos(t)) })
+	t.Run("DuplicateBoard", func(t *testing.T) { testDuplicateBoard(t, newRepos(t)) })
+	t.Run("Trash", func(t *testing.T) { testTrash(t, newRepos(t)) })
+	t.Run("Archive", func(t *testing.T) { testArchive(t, newRepos(t)) })
+	t.Run("Subtasks", func(t *testing.T) { testSubtasks(t, newRepos(t)) })
+	t.Run("Checklist", func(t *testing.T) { testChecklist(t, newRepos(t)) })
+	t.Run("Links", func(t *testing.T) { testLinks(t, newRepos(t)) })
+	t.Run("Attachments", func(t *testing.T) { testAttachments(t, newRepos(t)) })
+	t.Run("Worklogs", func(t *testing.T) { testWorklogs(t, newRepos(t)) })
+	t.Run("RecurringTasks", func(t *testing.T) { testRecurringTasks(t, newRepos(t)) })
+	t.Run("Automation", func(t *testing.T) { testAutomation(t, newRepos(t)) })
+	t.Run("Workflow", func(t *testing.T) { testWorkflow(t, newRepos(t)) })
+	t.Run("Sprints", func(t *testing.T) { testSprints(t, newRepos(t)) })
+	t.Run("StatusHistory", func(t *testing.T) { testStatusHistory(t, newRepos(t)) })
+	t.Run("Burndown", func(t *testing.T) { testBurndown(t, newRepos(t)) })
+	t.Run("ImportJobs", func(t *testing.T) { testImportJobs(t, newRepos(t)) })
+}
+
+// CreateBoard создает доску для проверок и удаляет ее по окончании теста
+func CreateBoard(t *testing.T, repos repository.Repositories, name string) *models.Board {
+	t.Helper()
+	ctx := context.Background()
+
+	board := &models.Board{Name: name}
+	require.NoError(t, repos.CreateBoard(ctx, board, templates.Default()))
+	t.Cleanup(func() { repos.Boards.Delete(context.Background(), board.ID) })
+
+	return board
+}
+
+func testBoards(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Repository board")
+
+	columns, err := repos.Columns.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	defaults := templates.Default().Columns
+	require.Len(t, columns, len(defaults))
+	for i, column := range columns {
+		assert.Equal(t, defaults[i].StatusID, column.StatusID)
+	}
+
+	board.Name = "Renamed"
+	require.NoError(t, repos.Boards.Update(ctx, board))
+
+	got, err := repos.Boards.GetByID(ctx, board.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", got.Name)
+
+	boards, err := repos.Boards.List(ctx)
+	require.NoError(t, err)
+	assert.True(t, containsBoard(boards, board.ID))
+
+	require.NoError(t, repos.Boards.Delete(ctx, board.ID))
+	_, err = repos.Boards.GetByID(ctx, board.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, repos.Boards.Delete(ctx, board.ID), repository.ErrNotFound)
+	assert.ErrorIs(t, repos.Boards.Update(ctx, board), repository.ErrNotFound)
+}
+
+func testTasks(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Repository tasks")
+
+	priority := "high"
+	task := &models.Task{BoardID: board.ID, Title: "Task", Status: "plan", Priority: &priority}
+	require.NoError(t, repos.Tasks.Create(ctx, task))
+	assert.NotEqual(t, uuid.Nil, task.ID)
+
+	require.NoError(t, repos.Tasks.Move(ctx, task.ID, "testing"))
+	got, err := repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "testing", got.Status)
+	require.NotNil(t, got.Priority)
+	assert.Equal(t, "high", *got.Priority)
+
+	got.Title = "Updated"
+	require.NoError(t, repos.Tasks.Update(ctx, got))
+
+	tasks, err := repos.Tasks.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "Updated", tasks[0].Title)
+
+	board, err = repos.Boards.GetByID(ctx, board.ID)
+	require.NoError(t, err)
+	assert.Len(t, board.Tasks, 1, "Expected board to include its tasks")
+
+	require.NoError(t, repos.Tasks.Delete(ctx, task.ID))
+	_, err = repos.Tasks.GetByID(ctx, task.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, repos.Tasks.Move(ctx, task.ID, "plan"), repository.ErrNotFound)
+}
+
+func testTaskPages(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Repository task pages")
+
+	var created []uuid.UUID
+	for i := 0; i < 5; i++ {
+		task := &models.Task{BoardID: board.ID, Title: fmt.Sprintf("Task %d", i), Status: "plan"}
+		require.NoError(t, repos.Tasks.Create(ctx, task))
+		created = append(created, task.ID)
+	}
+	require.NoError(t, repos.Tasks.Archive(ctx, created[1]))
+	require.NoError(t, repos.Tasks.Delete(ctx, created[3]))
+
+	list := func(opts repository.ListOptions) []uuid.UUID {
+		var ids []uuid.UUID
+		var after *repository.TaskCursor
+		for {
+			page, err := repos.Tasks.ListPage(ctx, board.ID, after, 2, opts)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(page), 2)
+			for _, task := range page {
+				ids = append(ids, task.ID)
+			}
+			if len(page) < 2 {
+				return ids
+			}
+			last := page[len(page)-1]
+			after = &repository.TaskCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+	}
+	assert.Equal(t, []uuid.UUID{created[0], created[1], created[2], created[4]}, list(repository.ListOptions{IncludeArchived: true}),
+		"Expected pages in creation order without deleted tasks")
+	assert.Equal(t, []uuid.UUID{created[0], created[2], created[4]}, list(repository.ListOptions{}))
+
+	require.NoError(t, repos.Checklists.Create(ctx, &models.ChecklistItem{TaskID: created[0], Title: "First"}))
+	require.NoError(t, repos.Checklists.Create(ctx, &models.ChecklistItem{TaskID: created[0], Title: "Second"}))
+	require.NoError(t, repos.Checklists.Create(ctx, &models.ChecklistItem{TaskID: created[2], Title: "Other"}))
+	checklists, err := repos.Checklists.ListByTasks(ctx, []uuid.UUID{created[0], created[1]})
+	require.NoError(t, err)
+	require.Len(t, checklists, 1)
+	require.Len(t, checklists[created[0]], 2)
+	assert.Equal(t, "First", checklists[created[0]][0].Title)
+
+	require.NoError(t, repos.Comments.Create(ctx, &models.TaskComment{TaskID: created[2], Body: "Later"}))
+	require.NoError(t, repos.Comments.Create(ctx, &models.TaskComment{TaskID: created[0], Body: "Only"}))
+	comments, err := repos.Comments.ListByTasks(ctx, []uuid.UUID{created[0]})
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	require.Len(t, comments[created[0]], 1)
+	assert.Equal(t, "Only", comments[created[0]][0].Body)
+}
+
+func testColumns(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Repository columns")
+
+	column := &models.Column{BoardID: board.ID, Title: "Review", StatusID: "review", Position: 10}
+	require.NoError(t, repos.Columns.Create(ctx, column))
+
+	got, err := repos.Columns.GetByID(ctx, column.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "review", got.StatusID)
+
+	duplicate := &models.Column{BoardID: board.ID, Title: "Review again", StatusID: "review"}
+	assert.ErrorIs(t, repos.Columns.Create(ctx, duplicate), repository.ErrConflict)
+
+	require.NoError(t, repos.Columns.Delete(ctx, column.ID))
+	_, err = repos.Columns.GetByID(ctx, column.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testUsers(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+
+	user := &models.User{Username: "repo-" + suffix, Email: "repo-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, user, "secret123"))
+	assert.True(t, repository.VerifyPassword(user.PasswordHash, "secret123"))
+
+	byName, err := repos.Users.GetByUsername(ctx, user.Username)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, byName.ID)
+
+	byEmail, err := repos.Users.GetByEmail(ctx, user.Email)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, byEmail.ID)
+
+	_, err = repos.Users.GetByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	duplicate := &models.User{Username: user.Username, Email: "other-" + suffix + "@test.com"}
+	assert.ErrorIs(t, repos.Users.Create(ctx, duplicate, "secret123"), repository.ErrConflict)
+
+	assert.Equal(t, models.UserRoleUser, user.Role)
+	assert.Equal(t, models.UserStatusActive, user.Status)
+
+	other := &models.User{Username: "repo-other-" + suffix, Email: "repo-other-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, other, "secret123"))
+	users, err := repos.Users.List(ctx)
+	require.NoError(t, err)
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		names = append(names, u.Username)
+	}
+	assert.Subset(t, names, []string{user.Username, other.Username})
+	assert.IsNonDecreasing(t, names)
+
+	user.Role = models.UserRoleAdmin
+	user.Status = models.UserStatusDisabled
+	require.NoError(t, repos.Users.Update(ctx, user))
+	require.NoError(t, repos.Users.SetPassword(ctx, user.ID, "changed123"))
+	require.NoError(t, repos.Users.RevokeTokens(ctx, user.ID))
+	updated, err := repos.Users.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.UserRoleAdmin, updated.Role)
+	assert.Equal(t, models.UserStatusDisabled, updated.Status)
+	assert.Equal(t, user.TokenVersion+1, updated.TokenVersion)
+	assert.True(t, repository.VerifyPassword(updated.PasswordHash, "changed123"))
+
+	other.Email = user.Email
+	assert.ErrorIs(t, repos.Users.Update(ctx, other), repository.ErrConflict)
+	assert.ErrorIs(t, repos.Users.RevokeTokens(ctx, uuid.New()), repository.ErrNotFound)
+
+	lockedUntil := time.Now().Add(time.Hour).Truncate(time.Second)
+	user.Status = models.UserStatusLocked
+	user.LockedUntil = &lockedUntil
+	require.NoError(t, repos.Users.Update(ctx, user))
+	locked, err := repos.Users.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.UserStatusLocked, locked.Status)
+	require.NotNil(t, locked.LockedUntil)
+	assert.WithinDuration(t, lockedUntil, *locked.LockedUntil, time.Second)
+}
+
+func testTransferOwnership(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+
+	owner := &models.User{Username: "from-" + suffix, Email: "from-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, owner, "secret123"))
+	heir := &models.User{Username: "to-" + suffix, Email: "to-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, heir, "secret123"))
+
+	board := &models.Board{Name: "Handed over", UserID: &owner.ID}
+	require.NoError(t, repos.CreateBoard(ctx, board, templates.Default()))
+	t.Cleanup(func() { repos.Boards.Delete(context.Background(), board.ID) })
+
+	require.NoError(t, repos.TransferBoardOwnership(ctx, board.ID, heir.ID))
+	// Повторная передача тому же пользователю ничего не меняет
+	require.NoError(t, repos.TransferBoardOwnership(ctx, board.ID, heir.ID))
+
+	stored, err := repos.Boards.GetByID(ctx, board.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.UserID)
+	assert.Equal(t, heir.ID, *stored.UserID)
+
+	members, err := repos.Members.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	roles := make(map[uuid.UUID]string)
+	for _, member := range members {
+		roles[member.UserID] = member.Role
+	}
+	assert.Equal(t, map[uuid.UUID]string{
+		owner.ID: models.BoardRoleMember,
+		heir.ID:  models.BoardRoleOwner,
+	}, roles)
+
+	assert.ErrorIs(t, repos.TransferBoardOwnership(ctx, board.ID, uuid.New()), repository.ErrNotFound)
+	assert.ErrorIs(t, repos.TransferBoardOwnership(ctx, uuid.New(), heir.ID), repository.ErrNotFound)
+}
+
+func testDeleteUser(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+
+	leaver := &models.User{Username: "leaver-" + suffix, Email: "leaver-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, leaver, "secret123"))
+	heir := &models.User{Username: "heir-" + suffix, Email: "heir-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, heir, "secret123"))
+
+	board := &models.Board{Name: "Left behind", UserID: &leaver.ID}
+	require.NoError(t, repos.CreateBoard(ctx, board, templates.Default()))
+	t.Cleanup(func() { repos.Boards.Delete(context.Background(), board.ID) })
+	foreign := CreateBoard(t, repos, "Foreign")
+
+	created := &models.Task{BoardID: board.ID, Title: "Created", Status: "plan", CreatedBy: &leaver.ID}
+	require.NoError(t, repos.Tasks.Create(ctx, created))
+	assigned := &models.Task{BoardID: foreign.ID, Title: "Assigned", Status: "plan", Assignee: &leaver.Username}
+	require.NoError(t, repos.Tasks.Create(ctx, assigned))
+	untouched := &models.Task{BoardID: foreign.ID, Title: "Untouched", Status: "plan", Assignee: &heir.Username}
+	require.NoError(t, repos.Tasks.Create(ctx, untouched))
+
+	item := &models.ChecklistItem{TaskID: untouched.ID, Title: "Check", Assignee: &leaver.Username}
+	require.NoError(t, repos.Checklists.Create(ctx, item))
+	comment := &models.TaskComment{TaskID: assigned.ID, UserID: &leaver.ID, Body: "On it"}
+	require.NoError(t, repos.Comments.Create(ctx, comment))
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	worklog := &models.Worklog{TaskID: assigned.ID, UserID: leaver.ID, StartedAt: start, EndedAt: &end, DurationMinutes: 60}
+	require.NoError(t, repos.Worklogs.Create(ctx, worklog))
+
+	export, err := repos.ExportUserData(ctx, leaver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, leaver.Username, export.User.Username)
+	assert.Empty(t, export.User.PasswordHash)
+	require.Len(t, export.Boards, 1)
+	assert.Equal(t, board.ID, export.Boards[0].ID)
+	require.Len(t, export.Memberships, 1)
+	assert.Equal(t, board.ID, export.Memberships[0].BoardID)
+	taskIDs := make([]uuid.UUID, 0, len(export.Tasks))
+	for _, task := range export.Tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+	assert.ElementsMatch(t, []uuid.UUID{created.ID, assigned.ID}, taskIDs)
+	require.Len(t, export.Comments, 1)
+	assert.Equal(t, "On it", export.Comments[0].Body)
+	require.Len(t, export.Worklogs, 1)
+	assert.Equal(t, worklog.ID, export.Worklogs[0].ID)
+
+	require.NoError(t, repos.DeleteUser(ctx, leaver.ID, &heir.ID))
+
+	_, err = repos.Users.GetByID(ctx, leaver.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	stored, err := repos.Boards.GetByID(ctx, board.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.UserID)
+	assert.Equal(t, heir.ID, *stored.UserID)
+	members, err := repos.Members.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, heir.ID, members[0].UserID)
+
+	task, err := repos.Tasks.GetByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Nil(t, task.CreatedBy)
+	task, err = repos.Tasks.GetByID(ctx, assigned.ID)
+	require.NoError(t, err)
+	assert.Nil(t, task.Assignee)
+	task, err = repos.Tasks.GetByID(ctx, untouched.ID)
+	require.NoError(t, err)
+	require.NotNil(t, task.Assignee)
+	assert.Equal(t, heir.Username, *task.Assignee)
+
+	storedItem, err := repos.Checklists.GetByID(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Nil(t, storedItem.Assignee)
+	comments, err := repos.Comments.ListByTask(ctx, assigned.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Nil(t, comments[0].UserID, "Expected the comment to stay without an author")
+	worklogs, err := repos.Worklogs.ListByTask(ctx, assigned.ID)
+	require.NoError(t, err)
+	assert.Empty(t, worklogs)
+
+	assert.ErrorIs(t, repos.DeleteUser(ctx, leaver.ID, nil), repository.ErrNotFound)
+}
+
+func testLastAdmin(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+
+	// Администраторы, оставшиеся в базе от других тестов
+	existing, err := repos.Users.LockAdmins(ctx)
+	require.NoError(t, err)
+
+	var admins []*models.User
+	for _, name := range []string{"first", "second"} {
+		admin := &models.User{Username: "admin-" + name + "-" + suffix, Email: "admin-" + name + "-" + suffix + "@test.com", Role: models.UserRoleAdmin}
+		require.NoError(t, repos.Users.Create(ctx, admin, "secret123"))
+		t.Cleanup(func() { repos.Users.Delete(context.Background(), admin.ID) })
+		admins = append(admins, admin)
+	}
+	user := &models.User{Username: "not-admin-" + suffix, Email: "not-admin-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, user, "secret123"))
+	t.Cleanup(func() { repos.Users.Delete(context.Background(), user.ID) })
+
+	locked, err := repos.Users.LockAdmins(ctx)
+	require.NoError(t, err)
+	assert.Subset(t, locked, []uuid.UUID{admins[0].ID, admins[1].ID})
+	assert.NotContains(t, locked, user.ID)
+	assert.NoError(t, repos.RequireOtherAdmin(ctx, user.ID))
+	assert.NoError(t, repos.RequireOtherAdmin(ctx, admins[0].ID))
+
+	// Администраторы одновременно понижают друг друга: проверка и понижение
+	// в одной транзакции не дают обоим пройти
+	results := make(chan error, len(admins))
+	for _, admin := range admins {
+		go func() {
+			results <- repos.Tx.WithinTx(ctx, func(ctx context.Context) error {
+				if err := repos.RequireOtherAdmin(ctx, admin.ID); err != nil {
+					return err
+				}
+				demoted := *admin
+				demoted.Role = models.UserRoleUser
+				return repos.Users.Update(ctx, &demoted)
+			})
+		}()
+	}
+	var lastAdmin int
+	for range admins {
+		err := <-results
+		if errors.Is(err, repository.ErrLastAdmin) {
+			lastAdmin++
+			continue
+		}
+		assert.NoError(t, err)
+	}
+
+	remaining, err := repos.Users.LockAdmins(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, remaining)
+	if len(existing) == 0 {
+		assert.Equal(t, 1, lastAdmin)
+		require.Len(t, remaining, 1)
+		assert.ErrorIs(t, repos.RequireOtherAdmin(ctx, remaining[0]), repository.ErrLastAdmin)
+	}
+}
+
+func testUserTokens(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+
+	user := &models.User{Username: "tokens-" + suffix, Email: "tokens-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, user, "secret123"))
+	assert.Nil(t, user.EmailVerifiedAt)
+
+	stale, err := repos.IssueUserToken(ctx, user.ID, models.UserTokenVerifyEmail, time.Hour)
+	require.NoError(t, err)
+	fresh, err := repos.IssueUserToken(ctx, user.ID, models.UserTokenVerifyEmail, time.Hour)
+	require.NoError(t, err)
+	reset, err := repos.IssueUserToken(ctx, user.ID, models.UserTokenResetPassword, time.Hour)
+	require.NoError(t, err)
+
+	_, err = repos.VerifyEmail(ctx, stale)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "Expected a newer token to invalidate the previous one")
+	_, err = repos.VerifyEmail(ctx, reset)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "Expected tokens to be bound to their purpose")
+
+	verified, err := repos.VerifyEmail(ctx, fresh)
+	require.NoError(t, err)
+	require.NotNil(t, verified.EmailVerifiedAt)
+	_, err = repos.VerifyEmail(ctx, fresh)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "Expected tokens to be single-use")
+
+	updated, err := repos.ResetPassword(ctx, reset, "changed123")
+	require.NoError(t, err)
+	assert.True(t, repository.VerifyPassword(updated.PasswordHash, "changed123"))
+	assert.Equal(t, verified.TokenVersion+1, updated.TokenVersion)
+	_, err = repos.ResetPassword(ctx, reset, "again123")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	expired := &models.UserToken{
+		UserID:    user.ID,
+		Purpose:   models.UserTokenResetPassword,
+		TokenHash: repository.HashUserToken("expired-" + suffix),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+	require.NoError(t, repos.UserTokens.Create(ctx, expired))
+	_, err = repos.ResetPassword(ctx, "expired-"+suffix, "again123")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	assert.ErrorIs(t, repos.Users.MarkEmailVerified(ctx, uuid.New()), repository.ErrNotFound)
+}
+
+func testTransactions(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+
+	user := &models.User{Username: "owner-" + suffix, Email: "owner-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, user, "secret123"))
+
+	board := &models.Board{Name: "Owned", UserID: &user.ID}
+	require.NoError(t, repos.CreateBoard(ctx, board, templates.Default()))
+	t.Cleanup(func() { repos.Boards.Delete(context.Background(), board.ID) })
+
+	members, err := repos.Members.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, user.ID, members[0].UserID)
+	assert.Equal(t, models.BoardRoleOwner, members[0].Role)
+
+	// Дублирующийся status_id ломает создание колонок: доска не должна остаться без колонок
+	broken := &models.Board{Name: "Broken " + suffix}
+	tpl := models.BoardTemplate{Columns: []models.TemplateColumn{
+		{Title: "A", StatusID: "same"},
+		{Title: "B", StatusID: "same"},
+	}}
+	err = repos.CreateBoard(ctx, broken, tpl)
+	require.ErrorIs(t, err, repository.ErrConflict)
+
+	boards, err := repos.Boards.List(ctx)
+	require.NoError(t, err)
+	for _, b := range boards {
+		assert.NotEqual(t, "Broken "+suffix, b.Name, "Expected board creation to be rolled back")
+	}
+}
+
+func testDeleteColumn(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Column deletion")
+
+	columns, err := repos.Columns.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+
+	task := &models.Task{BoardID: board.ID, Title: "In analysis", Status: "analysis"}
+	require.NoError(t, repos.Tasks.Create(ctx, task))
+
+	analysis := columns[1]
+	_, _, err = repos.DeleteColumn(ctx, analysis.ID, "missing")
+	assert.ErrorIs(t, err, repository.ErrUnknownStatus)
+	_, err = repos.Columns.GetByID(ctx, analysis.ID)
+	require.NoError(t, err, "Expected column to survive a failed deletion")
+
+	deleted, moved, err := repos.DeleteColumn(ctx, analysis.ID, "testing")
+	require.NoError(t, err)
+	assert.Equal(t, analysis.ID, deleted.ID)
+	require.Len(t, moved, 1)
+	assert.Equal(t, "testing", moved[0].Status)
+
+	got, err := repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "testing", got.Status)
+
+	for _, column := range columns {
+		if column.ID == analysis.ID {
+			continue
+		}
+		if column.StatusID == "testing" {
+			continue
+		}
+		_, _, err := repos.DeleteColumn(ctx, column.ID, "")
+		require.NoError(t, err)
+	}
+	remaining, err := repos.Columns.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+
+	_, _, err = repos.DeleteColumn(ctx, remaining[0].ID, "")
+	assert.ErrorIs(t, err, repository.ErrColumnNotEmpty)
+}
+
+func testMoveTasks(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Bulk move")
+
+	first := &models.Task{BoardID: board.ID, Title: "First", Status: "plan"}
+	second := &models.Task{BoardID: board.ID, Title: "Second", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, first))
+	require.NoError(t, repos.Tasks.Create(ctx, second))
+
+	_, err := repos.MoveTasks(ctx, []uuid.UUID{first.ID, uuid.New()}, "closed")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	got, err := repos.Tasks.GetByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "plan", got.Status, "Expected bulk move to be rolled back")
+
+	moved, err := repos.MoveTasks(ctx, []uuid.UUID{first.ID, second.ID}, "closed")
+	require.NoError(t, err)
+	require.Len(t, moved, 2)
+	for _, task := range moved {
+		assert.Equal(t, "closed", task.Status)
+	}
+}
+
+func testTemplates(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+
+	scrum, ok := templates.Builtin("scrum", "en")
+	require.True(t, ok)
+
+	board := &models.Board{Name: "From scrum"}
+	require.NoError(t, repos.CreateBoard(ctx, board, scrum))
+	t.Cleanup(func() { repos.Boards.Delete(context.Background(), board.ID) })
+
+	columns, err := repos.Columns.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, columns, len(scrum.Columns))
+	for i, column := range columns {
+		assert.Equal(t, scrum.Columns[i].Title, column.Title)
+		assert.Equal(t, scrum.Columns[i].WIPLimit, column.WIPLimit)
+	}
+
+	labels, err := repos.Labels.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	assert.Len(t, labels, len(scrum.Labels))
+
+	tasks, err := repos.Tasks.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, len(scrum.Tasks))
+
+	saved := &models.BoardTemplate{Name: "Saved scrum"}
+	require.NoError(t, repos.SaveBoardAsTemplate(ctx, board.ID, saved, true))
+	require.NotEmpty(t, saved.ID)
+	id, err := uuid.Parse(saved.ID)
+	require.NoError(t, err)
+	t.Cleanup(func() { repos.Templates.Delete(context.Background(), id) })
+
+	got, err := repos.Templates.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Saved scrum", got.Name)
+	assert.False(t, got.BuiltIn)
+	assert.Equal(t, scrum.Columns, got.Columns)
+	assert.ElementsMatch(t, scrum.Labels, got.Labels)
+	assert.Len(t, got.Tasks, len(scrum.Tasks))
+
+	list, err := repos.Templates.List(ctx)
+	require.NoError(t, err)
+	found := false
+	for _, tpl := range list {
+		found = found || tpl.ID == saved.ID
+	}
+	assert.True(t, found, "Expected saved template in list")
+
+	boardCopy := &models.Board{Name: "From saved"}
+	require.NoError(t, repos.CreateBoard(ctx, boardCopy, *got))
+	t.Cleanup(func() { repos.Boards.Delete(context.Background(), boardCopy.ID) })
+	copyColumns, err := repos.Columns.ListByBoard(ctx, boardCopy.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, copyColumns, len(scrum.Columns))
+
+	err = repos.SaveBoardAsTemplate(ctx, uuid.New(), &models.BoardTemplate{Name: "Missing"}, false)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	require.NoError(t, repos.Templates.Delete(ctx, id))
+	_, err = repos.Templates.GetByID(ctx, id)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testDuplicateBoard(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+
+	owner := &models.User{Username: "dup-owner-" + suffix, Email: "dup-owner-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, owner, "secret123"))
+	member := &models.User{Username: "dup-member-" + suffix, Email: "dup-member-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, member, "secret123"))
+
+	scrum, _ := templates.Builtin("scrum", "ru")
+	source := &models.Board{Name: "Project " + suffix, UserID: &owner.ID}
+	require.NoError(t, repos.CreateBoard(ctx, source, scrum))
+	t.Cleanup(func() { repos.Boards.Delete(context.Background(), source.ID) })
+	require.NoError(t, repos.Members.Add(ctx, &models.BoardMember{BoardID: source.ID, UserID: member.ID, Role: models.BoardRoleMember}))
+	sourceTasks, err := repos.Tasks.ListByBoard(ctx, source.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, sourceTasks)
+	commented := sourceTasks[0]
+	createdAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	for _, body := range []string{"First", "Second"} {
+		comment := &models.TaskComment{TaskID: commented.ID, UserID: &owner.ID, Body: body, CreatedAt: createdAt}
+		require.NoError(t, repos.Comments.Create(ctx, comment))
+		createdAt = createdAt.Add(time.Minute)
+	}
+
+	columnsOnly := &models.Board{UserID: &member.ID}
+	require.NoError(t, repos.DuplicateBoard(ctx, source.ID, columnsOnly, repository.DuplicateOptions{}))
+	t.Cleanup(func() { repos.Boards.Delete(context.Background(), columnsOnly.ID) })
+	assert.Equal(t, "Project "+suffix+" (копия)", columnsOnly.Name)
+
+	columns, err := repos.Columns.ListByBoard(ctx, columnsOnly.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, columns, len(scrum.Columns))
+	tasks, err := repos.Tasks.ListByBoard(ctx, columnsOnly.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+	labels, err := repos.Labels.ListByBoard(ctx, columnsOnly.ID)
+	require.NoError(t, err)
+	assert.Empty(t, labels)
+
+	full := &models.Board{Name: "Full copy", UserID: &member.ID}
+	opts := repository.DuplicateOptions{IncludeTasks: true, IncludeComments: true, IncludeLabels: true, IncludeMembers: true}
+	require.NoError(t, repos.DuplicateBoard(ctx, source.ID, full, opts))
+	t.Cleanup(func() { repos.Boards.Delete(context.Background(), full.ID) })
+
+	tasks, err = repos.Tasks.ListByBoard(ctx, full.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, len(scrum.Tasks))
+
+	// Комментарии переносятся на копию задачи с прежними автором и временем
+	var copied *models.Task
+	for i := range tasks {
+		if tasks[i].Title == commented.Title {
+			copied = &tasks[i]
+		}
+	}
+	require.NotNil(t, copied)
+	comments, err := repos.Comments.ListByTask(ctx, copied.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	for i, body := range []string{"First", "Second"} {
+		assert.Equal(t, body, comments[i].Body)
+		assert.Equal(t, &owner.ID, comments[i].UserID)
+		assert.True(t, comments[i].CreatedAt.Equal(createdAt.Add(time.Duration(i-2)*time.Minute)))
+	}
+	comments, err = repos.Comments.ListByTask(ctx, commented.ID)
+	require.NoError(t, err)
+	assert.Len(t, comments, 2, "Expected the source comments to stay in place")
+
+	withoutComments := &models.Board{UserID: &member.ID}
+	require.NoError(t, repos.DuplicateBoard(ctx, source.ID, withoutComments, repository.DuplicateOptions{IncludeTasks: true}))
+	t.Cleanup(func() { repos.Boards.Delete(context.Background(), withoutComments.ID) })
+	tasks, err = repos.Tasks.ListByBoard(ctx, withoutComments.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	ids := make([]uuid.UUID, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	byTask, err := repos.Comments.ListByTasks(ctx, ids)
+	require.NoError(t, err)
+	assert.Empty(t, byTask)
+	labels, err = repos.Labels.ListByBoard(ctx, full.ID)
+	require.NoError(t, err)
+	assert.Len(t, labels, len(scrum.Labels))
+
+	members, err := repos.Members.ListByBoard(ctx, full.ID)
+	require.NoError(t, err)
+	roles := make(map[uuid.UUID]string)
+	for _, m := range members {
+		roles[m.UserID] = m.Role
+	}
+	assert.Equal(t, map[uuid.UUID]string{
+		member.ID: models.BoardRoleOwner,
+		owner.ID:  models.BoardRoleMember,
+	}, roles)
+
+	err = repos.DuplicateBoard(ctx, uuid.New(), &models.Board{}, repository.DuplicateOptions{})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testTrash(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Trash "+uuid.NewString()[:8])
+
+	columns, err := repos.Columns.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	kept := &models.Task{BoardID: board.ID, Title: "Kept", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, kept))
+	removed := &models.Task{BoardID: board.ID, Title: "Removed earlier", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, removed))
+
+	require.NoError(t, repos.Tasks.Delete(ctx, removed.ID))
+	_, err = repos.Tasks.GetByID(ctx, removed.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, repos.Tasks.Delete(ctx, removed.ID), repository.ErrNotFound)
+	assert.ErrorIs(t, repos.Tasks.Move(ctx, removed.ID, "closed"), repository.ErrNotFound)
+
+	// Удаленная колонка освобождает status_id
+	qa := columns[3]
+	require.NoError(t, repos.Columns.Delete(ctx, qa.ID))
+	replacement := &models.Column{BoardID: board.ID, Title: "QA", StatusID: qa.StatusID, Position: 3}
+	require.NoError(t, repos.Columns.Create(ctx, replacement))
+	assert.ErrorIs(t, repos.Trash.Restore(ctx, models.TrashTypeColumn, qa.ID), repository.ErrConflict)
+	require.NoError(t, repos.Columns.Delete(ctx, replacement.ID))
+	require.NoError(t, repos.Trash.Restore(ctx, models.TrashTypeColumn, qa.ID))
+
+	items, err := repos.Trash.List(ctx)
+	require.NoError(t, err)
+	assert.True(t, containsTrashItem(items, models.TrashTypeTask, removed.ID))
+	assert.True(t, containsTrashItem(items, models.TrashTypeColumn, replacement.ID))
+
+	require.NoError(t, repos.Boards.Delete(ctx, board.ID))
+	_, err = repos.Boards.GetByID(ctx, board.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = repos.Tasks.GetByID(ctx, kept.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	boards, err := repos.Boards.List(ctx)
+	require.NoError(t, err)
+	assert.False(t, containsBoard(boards, board.ID))
+
+	items, err = repos.Trash.List(ctx)
+	require.NoError(t, err)
+	assert.True(t, containsTrashItem(items, models.TrashTypeBoard, board.ID))
+	assert.False(t, containsTrashItem(items, models.TrashTypeTask, removed.ID), "Board contents are listed as part of the board")
+	assert.ErrorIs(t, repos.Trash.Restore(ctx, models.TrashTypeTask, removed.ID), repository.ErrNotFound)
+
+	require.NoError(t, repos.Trash.Restore(ctx, models.TrashTypeBoard, board.ID))
+	_, err = repos.Tasks.GetByID(ctx, kept.ID)
+	require.NoError(t, err, "Expected task deleted with the board to be restored")
+	_, err = repos.Tasks.GetByID(ctx, removed.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "Expected task deleted before the board to stay in trash")
+	restoredColumns, err := repos.Columns.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, restoredColumns, len(columns))
+	assert.ErrorIs(t, repos.Trash.Restore(ctx, models.TrashTypeBoard, board.ID), repository.ErrNotFound)
+
+	require.NoError(t, repos.Trash.Restore(ctx, models.TrashTypeTask, removed.ID))
+	require.NoError(t, repos.Tasks.Delete(ctx, removed.ID))
+
+	n, err := repos.Trash.Purge(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, n, "Expected recently deleted items to survive the purge")
+
+	n, err = repos.Trash.Purge(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, int64(2))
+	assert.ErrorIs(t, repos.Trash.Restore(ctx, models.TrashTypeTask, removed.ID), repository.ErrNotFound)
+
+	items, err = repos.Trash.List(ctx)
+	require.NoError(t, err)
+	assert.False(t, containsTrashItem(items, models.TrashTypeColumn, replacement.ID))
+}
+
+func testArchive(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Archive "+uuid.NewString()[:8])
+
+	done := &models.Task{BoardID: board.ID, Title: "Done", Status: "closed"}
+	require.NoError(t, repos.Tasks.Create(ctx, done))
+	active := &models.Task{BoardID: board.ID, Title: "Active", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, active))
+
+	require.NoError(t, repos.Tasks.Archive(ctx, active.ID))
+	assert.ErrorIs(t, repos.Tasks.Archive(ctx, active.ID), repository.ErrNotFound)
+	archived, err := repos.Tasks.GetByID(ctx, active.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, archived.ArchivedAt)
+
+	tasks, err := repos.Tasks.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1, "Archived tasks are hidden by default")
+	tasks, err = repos.Tasks.ListByBoard(ctx, board.ID, repository.ListOptions{IncludeArchived: true})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	require.NoError(t, repos.Tasks.Unarchive(ctx, active.ID))
+	assert.ErrorIs(t, repos.Tasks.Unarchive(ctx, active.ID), repository.ErrNotFound)
+
+	ids, err := repos.ArchiveDone(ctx, board.ID, "", time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, ids, "Recently updated tasks are not archived")
+	ids, err = repos.ArchiveDone(ctx, board.ID, "", -time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{done.ID}, ids, "Empty status means the last column")
+	_, err = repos.ArchiveDone(ctx, board.ID, "unknown", 0)
+	assert.ErrorIs(t, err, repository.ErrUnknownStatus)
+
+	columns, err := repos.Columns.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	plan := columns[0]
+	column, ids, err := repos.ArchiveColumn(ctx, plan.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, column.ArchivedAt)
+	assert.Equal(t, []uuid.UUID{active.ID}, ids)
+	_, _, err = repos.ArchiveColumn(ctx, plan.ID)
+	assert.ErrorIs(t, err, repository.ErrConflict)
+
+	visible, err := repos.Columns.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, visible, len(columns)-1)
+
+	// Задачи, заархивированные раньше колонки, остаются в архиве
+	_, ids, err = repos.UnarchiveColumn(ctx, plan.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{active.ID}, ids)
+	restored, err := repos.Tasks.GetByID(ctx, done.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, restored.ArchivedAt)
+
+	rules := []models.ArchiveRule{{BoardID: board.ID, Status: "closed", AfterDays: 14}}
+	require.NoError(t, repos.ArchiveRules.Replace(ctx, board.ID, rules))
+	duplicate := append(rules, models.ArchiveRule{BoardID: board.ID, Status: "closed", AfterDays: 1})
+	assert.ErrorIs(t, repos.ArchiveRules.Replace(ctx, board.ID, duplicate), repository.ErrConflict)
+	stored, err := repos.ArchiveRules.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	assert.Equal(t, rules, stored)
+}
+
+func testSubtasks(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Subtasks "+uuid.NewString()[:8])
+	other := CreateBoard(t, repos, "Other "+uuid.NewString()[:8])
+
+	parent := &models.Task{BoardID: board.ID, Title: "Epic", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, parent))
+	child := &models.Task{BoardID: board.ID, Title: "Step", Status: "plan", ParentTaskID: &parent.ID}
+	require.NoError(t, repos.CreateSubtask(ctx, child))
+	grandchild := &models.Task{BoardID: board.ID, Title: "Detail", Status: "closed", ParentTaskID: &child.ID}
+	require.NoError(t, repos.CreateSubtask(ctx, grandchild))
+
+	foreign := &models.Task{BoardID: other.ID, Title: "Foreign", Status: "plan", ParentTaskID: &parent.ID}
+	assert.ErrorIs(t, repos.CreateSubtask(ctx, foreign), repository.ErrInvalidParent)
+
+	subtasks, err := repos.Tasks.ListSubtasks(ctx, parent.ID)
+	require.NoError(t, err)
+	require.Len(t, subtasks, 1)
+	assert.Equal(t, child.ID, subtasks[0].ID)
+	assert.Equal(t, models.TaskProgress{Done: 1, Total: 1}, subtasks[0].Progress, "Subtask in the last column is done")
+
+	loaded, err := repos.Tasks.GetByID(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskProgress{Done: 0, Total: 1}, loaded.Progress)
+
+	_, err = repos.SetTaskParent(ctx, parent.ID, &grandchild.ID)
+	assert.ErrorIs(t, err, repository.ErrTaskCycle)
+	_, err = repos.SetTaskParent(ctx, parent.ID, &parent.ID)
+	assert.ErrorIs(t, err, repository.ErrTaskCycle)
+
+	moved, err := repos.SetTaskParent(ctx, grandchild.ID, &parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, parent.ID, *moved.ParentTaskID)
+	loaded, err = repos.Tasks.GetByID(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskProgress{Done: 1, Total: 2}, loaded.Progress)
+
+	detached, err := repos.SetTaskParent(ctx, grandchild.ID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, detached.ParentTaskID)
+
+	// Перенос на другую доску отвязывает задачу от родителя
+	_, transferred, err := repos.TransferTask(ctx, child.ID, other.ID, "", false)
+	require.NoError(t, err)
+	assert.Nil(t, transferred.ParentTaskID)
+	subtasks, err = repos.Tasks.ListSubtasks(ctx, parent.ID)
+	require.NoError(t, err)
+	assert.Empty(t, subtasks)
+}
+
+func testChecklist(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Checklist "+uuid.NewString()[:8])
+	task := &models.Task{BoardID: board.ID, Title: "Release", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, task))
+
+	var items []*models.ChecklistItem
+	for _, title := range []string{"Build", "Tag", "Announce"} {
+		item := &models.ChecklistItem{TaskID: task.ID, Title: title}
+		require.NoError(t, repos.Checklists.Create(ctx, item))
+		items = append(items, item)
+	}
+	assert.Equal(t, []int{0, 1, 2}, []int{items[0].Position, items[1].Position, items[2].Position})
+	assert.ErrorIs(t, repos.Checklists.Create(ctx, &models.ChecklistItem{TaskID: uuid.New(), Title: "Orphan"}), repository.ErrNotFound)
+
+	items[1].Checked = true
+	require.NoError(t, repos.Checklists.Update(ctx, items[1]))
+	loaded, err := repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TaskProgress{Done: 1, Total: 3}, loaded.Progress)
+
+	_, err = repos.ReorderChecklist(ctx, task.ID, []uuid.UUID{items[2].ID, items[0].ID})
+	assert.ErrorIs(t, err, repository.ErrChecklistOrder)
+	_, err = repos.ReorderChecklist(ctx, task.ID, []uuid.UUID{items[2].ID, items[0].ID, items[0].ID})
+	assert.ErrorIs(t, err, repository.ErrChecklistOrder)
+	_, err = repos.ReorderChecklist(ctx, task.ID, []uuid.UUID{items[2].ID, items[0].ID, items[1].ID})
+	require.NoError(t, err)
+
+	list, err := repos.Checklists.ListByTask(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, []string{"Announce", "Build", "Tag"}, []string{list[0].Title, list[1].Title, list[2].Title})
+
+	require.NoError(t, repos.Checklists.Delete(ctx, items[0].ID))
+	assert.ErrorIs(t, repos.Checklists.Delete(ctx, items[0].ID), repository.ErrNotFound)
+
+	// Пункты удаленной задачи недоступны
+	require.NoError(t, repos.Tasks.Delete(ctx, task.ID))
+	_, err = repos.Checklists.GetByID(ctx, items[1].ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testLinks(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Links "+uuid.NewString()[:8])
+
+	newTask := func(title string) *models.Task {
+		task := &models.Task{BoardID: board.ID, Title: title, Status: "plan"}
+		require.NoError(t, repos.Tasks.Create(ctx, task))
+		return task
+	}
+	design, build, release := newTask("Design"), newTask("Build"), newTask("Release")
+
+	link := func(source, target *models.Task, linkType string) error {
+		return repos.LinkTasks(ctx, &models.TaskLink{SourceTaskID: source.ID, TargetTaskID: target.ID, Type: linkType})
+	}
+	require.NoError(t, link(design, build, models.LinkTypeBlocks))
+	require.NoError(t, link(build, release, models.LinkTypeBlocks))
+	assert.ErrorIs(t, link(design, build, models.LinkTypeBlocks), repository.ErrConflict)
+	assert.ErrorIs(t, link(release, design, models.LinkTypeBlocks), repository.ErrLinkCycle)
+	assert.ErrorIs(t, link(design, design, models.LinkTypeRelatesTo), repository.ErrInvalidLink)
+	assert.ErrorIs(t, link(design, release, "follows"), repository.ErrInvalidLink)
+	require.NoError(t, link(release, design, models.LinkTypeRelatesTo))
+	assert.ErrorIs(t, link(design, release, models.LinkTypeRelatesTo), repository.ErrConflict)
+
+	links, err := repos.Links.ListByTask(ctx, build.ID)
+	require.NoError(t, err)
+	assert.Len(t, links, 2)
+
+	graph, err := repos.DependencyGraph(ctx, board.ID)
+	require.NoError(t, err)
+	assert.Len(t, graph.Nodes, 3)
+	assert.Len(t, graph.Edges, 3)
+	for _, node := range graph.Nodes {
+		assert.Equal(t, node.ID != design.ID, node.Blocked, node.Title)
+	}
+
+	// Проверка блокировок включается настройкой доски
+	require.NoError(t, repos.CheckMoveAllowed(ctx, build, "closed"))
+	board.EnforceDependencies = true
+	require.NoError(t, repos.Boards.Update(ctx, board))
+	assert.ErrorIs(t, repos.CheckMoveAllowed(ctx, build, "closed"), repository.ErrTaskBlocked)
+	require.NoError(t, repos.CheckMoveAllowed(ctx, build, "testing"), "Only the last column is guarded")
+	_, err = repos.MoveTasks(ctx, []uuid.UUID{build.ID, design.ID}, "closed")
+	assert.ErrorIs(t, err, repository.ErrTaskBlocked)
+	unchanged, err := repos.Tasks.GetByID(ctx, design.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "plan", unchanged.Status)
+
+	// Блокирующая задача раньше в том же пакете успевает завершиться
+	_, err = repos.MoveTasks(ctx, []uuid.UUID{design.ID, build.ID}, "closed")
+	require.NoError(t, err)
+
+	// Связи удаленной задачи скрыты
+	require.NoError(t, repos.Tasks.Delete(ctx, release.ID))
+	links, err = repos.Links.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	_, err = repos.Links.GetByID(ctx, links[0].ID)
+	require.NoError(t, err)
+	require.NoError(t, repos.Links.Delete(ctx, links[0].ID))
+	assert.ErrorIs(t, repos.Links.Delete(ctx, links[0].ID), repository.ErrNotFound)
+}
+
+func containsTrashItem(items []models.TrashItem, itemType string, id uuid.UUID) bool {
+	for _, item := range items {
+		if item.Type == itemType && item.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsBoard(boards []models.Board, id uuid.UUID) bool {
+	for _, board := range boards {
+		if board.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func testAttachments(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Attachments "+uuid.NewString()[:8])
+
+	task := &models.Task{BoardID: board.ID, Title: "Crash on save", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, task))
+	other := &models.Task{BoardID: board.ID, Title: "Crash on load", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, other))
+
+	// Контрольные суммы уникальны, чтобы не пересекаться с другими прогонами в общей базе
+	checksum := strings.ReplaceAll(uuid.NewString()+uuid.NewString(), "-", "")
+
+	orphaned := func() bool {
+		blobs, err := repos.Attachments.OrphanBlobs(ctx)
+		require.NoError(t, err)
+		for _, blob := range blobs {
+			if blob.Checksum == checksum {
+				return true
+			}
+		}
+		return false
+	}
+
+	screenshot := &models.Attachment{TaskID: task.ID, Filename: "screen.png", ContentType: "image/png", Checksum: checksum}
+	assert.ErrorIs(t, repos.AddAttachment(ctx, screenshot, nil), repository.ErrNotFound, "Expected unknown blob to be rejected")
+
+	blob := &models.AttachmentBlob{Checksum: checksum, Size: 2048, HasThumbnail: true}
+	require.NoError(t, repos.AddAttachment(ctx, screenshot, blob))
+	assert.Equal(t, int64(2048), screenshot.Size)
+	assert.True(t, screenshot.HasThumbnail)
+
+	// Тот же файл у другой задачи переиспользует учтенный blob
+	copyOf := &models.Attachment{TaskID: other.ID, Filename: "copy.png", ContentType: "image/png", Checksum: checksum}
+	require.NoError(t, repos.AddAttachment(ctx, copyOf, &models.AttachmentBlob{Checksum: checksum, Size: 1}))
+	assert.Equal(t, int64(2048), copyOf.Size, "Expected existing blob to be kept")
+
+	attachments, err := repos.Attachments.ListByTask(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "screen.png", attachments[0].Filename)
+	assert.Equal(t, int64(2048), attachments[0].Size)
+
+	found, err := repos.Attachments.GetByID(ctx, copyOf.ID)
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, found.TaskID)
+
+	released, err := repos.ReleaseBlob(ctx, checksum)
+	require.NoError(t, err)
+	assert.False(t, released, "Expected blob in use to be kept")
+
+	require.NoError(t, repos.Attachments.Delete(ctx, copyOf.ID))
+	assert.ErrorIs(t, repos.Attachments.Delete(ctx, copyOf.ID), repository.ErrNotFound)
+	assert.False(t, orphaned())
+
+	// Окончательное удаление задачи удаляет ее вложения, и файл становится ничьим
+	require.NoError(t, repos.Tasks.Delete(ctx, task.ID))
+	_, err = repos.Attachments.GetByID(ctx, screenshot.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "Expected attachments of deleted task to be hidden")
+	assert.False(t, orphaned(), "Expected soft-deleted task to keep its attachments")
+
+	_, err = repos.Trash.Purge(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, orphaned())
+
+	released, err = repos.ReleaseBlob(ctx, checksum)
+	require.NoError(t, err)
+	assert.True(t, released)
+	_, err = repos.Attachments.GetBlob(ctx, checksum)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testWorklogs(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+	board := CreateBoard(t, repos, "Worklogs "+suffix)
+
+	user := &models.User{Username: "worklog-" + suffix, Email: "worklog-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, user, "secret123"))
+
+	estimate := 90
+	task := &models.Task{BoardID: board.ID, Title: "Write report", Status: "plan", EstimateMinutes: &estimate}
+	require.NoError(t, repos.Tasks.Create(ctx, task))
+	other := &models.Task{BoardID: board.ID, Title: "Review report", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, other))
+
+	stored, err := repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.EstimateMinutes)
+	assert.Equal(t, 90, *stored.EstimateMinutes)
+
+	day := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	logged := func(taskID uuid.UUID, start time.Time, minutes int) *models.Worklog {
+		end := start.Add(time.Duration(minutes) * time.Minute)
+		worklog := &models.Worklog{TaskID: taskID, UserID: user.ID, StartedAt: start, EndedAt: &end, DurationMinutes: minutes}
+		require.NoError(t, repos.Worklogs.Create(ctx, worklog))
+		return worklog
+	}
+	first := logged(task.ID, day, 30)
+	logged(task.ID, day.Add(2*time.Hour), 45)
+	logged(other.ID, day.Add(24*time.Hour), 20)
+
+	worklogs, err := repos.Worklogs.ListByTask(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, worklogs, 2)
+	assert.Equal(t, first.ID, worklogs[0].ID)
+
+	// Таймер: второй запущенный таймер пользователя запрещен
+	running, err := repos.StartTimer(ctx, other.ID, user.ID)
+	require.NoError(t, err)
+	_, err = repos.StartTimer(ctx, task.ID, user.ID)
+	assert.ErrorIs(t, err, repository.ErrConflict)
+
+	current, err := repos.Worklogs.GetRunning(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, running.ID, current.ID)
+	assert.Nil(t, current.EndedAt)
+
+	note := "done"
+	stopped, err := repos.StopTimer(ctx, user.ID, &note)
+	require.NoError(t, err)
+	require.NotNil(t, stopped.EndedAt)
+	assert.Equal(t, 1, stopped.DurationMinutes, "Expected partial minute to round up")
+	_, err = repos.StopTimer(ctx, user.ID, nil)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	// Отчет за первый день по доске
+	from, to := day.Add(-time.Hour), day.Add(23*time.Hour)
+	report, err := repos.TimeReport(ctx, repository.WorklogFilter{BoardID: &board.ID, From: &from, To: &to})
+	require.NoError(t, err)
+	assert.Equal(t, 75, report.TotalMinutes)
+	require.Len(t, report.Tasks, 1)
+	assert.Equal(t, task.ID, report.Tasks[0].TaskID)
+	require.NotNil(t, report.Tasks[0].EstimateMinutes)
+	assert.Equal(t, 90, *report.Tasks[0].EstimateMinutes)
+	require.Len(t, report.Users, 1)
+	assert.Equal(t, user.Username, report.Users[0].Username)
+	require.Len(t, report.Entries, 1)
+	assert.Equal(t, 75, report.Entries[0].Minutes)
+
+	report, err = repos.TimeReport(ctx, repository.WorklogFilter{UserID: &user.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 75+20+stopped.DurationMinutes, report.TotalMinutes)
+	require.Len(t, report.Tasks, 2)
+	assert.Equal(t, task.ID, report.Tasks[0].TaskID, "Expected tasks sorted by logged time")
+
+	require.NoError(t, repos.Worklogs.Delete(ctx, first.ID))
+	assert.ErrorIs(t, repos.Worklogs.Delete(ctx, first.ID), repository.ErrNotFound)
+
+	// Записи удаленной задачи не попадают в отчет
+	require.NoError(t, repos.Tasks.Delete(ctx, task.ID))
+	report, err = repos.TimeReport(ctx, repository.WorklogFilter{UserID: &user.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 20+stopped.DurationMinutes, report.TotalMinutes)
+}
+
+func testRecurringTasks(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Recurring "+uuid.NewString()[:8])
+
+	priority := "high"
+	recurring := &models.RecurringTask{
+		BoardID:  board.ID,
+		Title:    "Rotate logs",
+		Status:   "analysis",
+		Priority: &priority,
+		RRule:    "FREQ=WEEKLY;BYDAY=MO",
+		StartsAt: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		Timezone: "UTC",
+	}
+	require.NoError(t, repository.ScheduleRecurringTask(recurring, recurring.StartsAt.Add(-time.Nanosecond)))
+	require.NoError(t, repos.RecurringTasks.Create(ctx, recurring))
+	require.NotNil(t, recurring.NextRunAt)
+	assert.True(t, recurring.NextRunAt.Equal(recurring.StartsAt))
+
+	list, err := repos.RecurringTasks.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO", list[0].RRule)
+	require.NotNil(t, list[0].Priority)
+	assert.Equal(t, "high", *list[0].Priority)
+
+	missing := &models.RecurringTask{BoardID: uuid.New(), Title: "Nowhere", Status: "plan", RRule: "FREQ=DAILY", StartsAt: time.Now(), Timezone: "UTC"}
+	assert.ErrorIs(t, repos.RecurringTasks.Create(ctx, missing), repository.ErrNotFound)
+
+	// Запуск до наступления повторения ничего не создает
+	task, err := repos.RunRecurringTask(ctx, recurring.ID, recurring.StartsAt.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, task)
+
+	// После простоя создается одна задача, а следующее повторение - позже now
+	now := time.Date(2024, 1, 17, 12, 0, 0, 0, time.UTC)
+	due, err := repos.RecurringTasks.ListDue(ctx, now)
+	require.NoError(t, err)
+	assert.True(t, containsRecurringTask(due, recurring.ID))
+
+	task, err = repos.RunRecurringTask(ctx, recurring.ID, now)
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, "Rotate logs", task.Title)
+	assert.Equal(t, "analysis", task.Status)
+	assert.Equal(t, board.ID, task.BoardID)
+
+	stored, err := repos.RecurringTasks.GetByID(ctx, recurring.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.NextRunAt)
+	assert.True(t, stored.NextRunAt.Equal(time.Date(2024, 1, 22, 9, 0, 0, 0, time.UTC)), "got %s", stored.NextRunAt)
+
+	task, err = repos.RunRecurringTask(ctx, recurring.ID, now)
+	require.NoError(t, err)
+	assert.Nil(t, task, "Expected no second task for the same occurrence")
+
+	// Если колонки шаблона больше нет, задача попадает в первую колонку
+	columns, err := repos.Columns.ListByBoard(ctx, board.ID, repository.ListOptions{})
+	require.NoError(t, err)
+	orphan := &models.Task{BoardID: board.ID, Title: "Orphan", Status: "missing"}
+	require.NoError(t, repos.CreateTask(ctx, orphan))
+	assert.Equal(t, columns[0].StatusID, orphan.Status)
+
+	require.NoError(t, repos.RecurringTasks.Delete(ctx, recurring.ID))
+	_, err = repos.RecurringTasks.GetByID(ctx, recurring.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func containsRecurringTask(items []models.RecurringTask, id uuid.UUID) bool {
+	for _, item := range items {
+		if item.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func testAutomation(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Automation "+uuid.NewString()[:8])
+
+	dueAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	task := &models.Task{BoardID: board.ID, Title: "Release", Status: "plan", DueAt: &dueAt}
+	require.NoError(t, repos.Tasks.Create(ctx, task))
+	got, err := repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.DueAt)
+	assert.True(t, got.DueAt.Equal(dueAt))
+
+	got.DueAt = nil
+	require.NoError(t, repos.Tasks.Update(ctx, got))
+	got, err = repos.Tasks.GetByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.DueAt)
+
+	rule := &models.AutomationRule{
+		BoardID:    board.ID,
+		Name:       "Assign QA",
+		Enabled:    true,
+		Trigger:    models.AutomationTrigger{Type: models.AutomationTriggerTaskMoved, To: "testing"},
+		Conditions: []models.AutomationCondition{{Field: "priority", Operator: "equals", Value: "high"}},
+		Actions:    []models.AutomationAction{{Type: models.AutomationActionAssign, Value: "qa"}},
+	}
+	require.NoError(t, repos.AutomationRules.Create(ctx, rule))
+	assert.NotEqual(t, uuid.Nil, rule.ID)
+
+	stored, err := repos.AutomationRules.GetByID(ctx, rule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, rule.Trigger, stored.Trigger)
+	assert.Equal(t, rule.Conditions, stored.Conditions)
+	assert.Equal(t, rule.Actions, stored.Actions)
+
+	stored.Enabled = false
+	require.NoError(t, repos.AutomationRules.Update(ctx, stored))
+	byTrigger, err := repos.AutomationRules.ListByTrigger(ctx, models.AutomationTriggerTaskMoved)
+	require.NoError(t, err)
+	for _, r := range byTrigger {
+		assert.NotEqual(t, rule.ID, r.ID, "Expected disabled rule to be skipped")
+	}
+
+	rules, err := repos.AutomationRules.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.False(t, rules[0].Enabled)
+
+	missing := &models.AutomationRule{BoardID: uuid.New(), Name: "Nowhere", Trigger: rule.Trigger, Actions: rule.Actions}
+	assert.ErrorIs(t, repos.AutomationRules.Create(ctx, missing), repository.ErrNotFound)
+
+	comment := &models.TaskComment{TaskID: task.ID, RuleID: &rule.ID, Body: "Moved to QA"}
+	require.NoError(t, repos.Comments.Create(ctx, comment))
+	comments, err := repos.Comments.ListByTask(ctx, task.ID)
+	require.NoError(t, err)
+	require.Len(t, comments, 1)
+	assert.Equal(t, "Moved to QA", comments[0].Body)
+	assert.ErrorIs(t, repos.Comments.Create(ctx, &models.TaskComment{TaskID: uuid.New(), Body: "Lost"}), repository.ErrNotFound)
+
+	last, err := repos.AutomationRuns.LastRun(ctx, rule.ID, task.ID)
+	require.NoError(t, err)
+	assert.Nil(t, last)
+
+	for _, status := range []string{models.AutomationRunSuccess, models.AutomationRunFailed} {
+		run := &models.AutomationRun{RuleID: rule.ID, BoardID: board.ID, TaskID: &task.ID, Event: "task_moved", Status: status}
+		require.NoError(t, repos.AutomationRuns.Create(ctx, run))
+	}
+	runs, err := repos.AutomationRuns.List(ctx, repository.AutomationRunFilter{BoardID: board.ID, RuleID: &rule.ID, Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+	runs, err = repos.AutomationRuns.List(ctx, repository.AutomationRunFilter{BoardID: board.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, runs, 2)
+
+	last, err = repos.AutomationRuns.LastRun(ctx, rule.ID, task.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, last)
+
+	// Отложенные запуски; now далеко в прошлом, чтобы не задеть запуски других тестов
+	now := time.Date(2001, 1, 1, 12, 0, 0, 0, time.UTC)
+	delay := &models.AutomationDelay{RuleID: rule.ID, TaskID: task.ID, Event: "task_moved", ActionIndex: 1, RunAt: now.Add(time.Hour)}
+	require.NoError(t, repos.AutomationDelays.Schedule(ctx, delay))
+	due, err := repos.AutomationDelays.ListDue(ctx, now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	rescheduled := &models.AutomationDelay{RuleID: rule.ID, TaskID: task.ID, Event: "task_moved", ActionIndex: 0, RunAt: now.Add(-time.Hour)}
+	require.NoError(t, repos.AutomationDelays.Schedule(ctx, rescheduled))
+	due, err = repos.AutomationDelays.ListDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1, "Expected a new delay to replace the previous one")
+	assert.Equal(t, rescheduled.ID, due[0].ID)
+	assert.Equal(t, 0, due[0].ActionIndex)
+	assert.True(t, due[0].RunAt.Equal(now.Add(-time.Hour)))
+
+	require.NoError(t, repos.AutomationDelays.Delete(ctx, rescheduled.ID))
+	assert.ErrorIs(t, repos.AutomationDelays.Delete(ctx, rescheduled.ID), repository.ErrNotFound)
+	missingTask := &models.AutomationDelay{RuleID: rule.ID, TaskID: uuid.New(), Event: "task_moved", RunAt: now}
+	assert.ErrorIs(t, repos.AutomationDelays.Schedule(ctx, missingTask), repository.ErrNotFound)
+	require.NoError(t, repos.AutomationDelays.Schedule(ctx, delay))
+
+	require.NoError(t, repos.AutomationRules.Delete(ctx, rule.ID))
+	_, err = repos.AutomationRules.GetByID(ctx, rule.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	runs, err = repos.AutomationRuns.List(ctx, repository.AutomationRunFilter{BoardID: board.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, runs, "Expected runs to be deleted with the rule")
+	due, err = repos.AutomationDelays.ListDue(ctx, now.Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due, "Expected delays to be deleted with the rule")
+}
+
+func testWorkflow(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Workflow "+uuid.NewString()[:8])
+
+	task := &models.Task{BoardID: board.ID, Title: "Feature", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, task))
+
+	// Без переходов разрешено перемещение в любую колонку доски
+	require.NoError(t, repos.CheckMoveAllowed(ctx, task, "closed"))
+	var transitionErr *repository.TransitionError
+	err := repos.CheckMoveAllowed(ctx, task, "missing")
+	assert.ErrorIs(t, err, repository.ErrUnknownStatus)
+	require.ErrorAs(t, err, &transitionErr)
+	assert.Contains(t, transitionErr.Allowed, "plan")
+
+	transitions := []models.WorkflowTransition{
+		{From: "plan", To: "analysis"},
+		{From: "analysis", To: "development", RequiredFields: []string{"assignee"}},
+		{From: repository.AnyStatus, To: "plan"},
+	}
+	require.NoError(t, repos.Workflows.Replace(ctx, board.ID, transitions))
+
+	stored, err := repos.Workflows.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	require.Len(t, stored, 3)
+	assert.Equal(t, board.ID, stored[0].BoardID)
+
+	err = repos.CheckMoveAllowed(ctx, task, "closed")
+	assert.ErrorIs(t, err, repository.ErrTransitionNotAllowed)
+	require.ErrorAs(t, err, &transitionErr)
+	assert.ElementsMatch(t, []string{"analysis", "plan"}, transitionErr.Allowed)
+	require.NoError(t, repos.CheckMoveAllowed(ctx, task, "analysis"))
+
+	task.Status = "analysis"
+	err = repos.CheckMoveAllowed(ctx, task, "development")
+	assert.ErrorIs(t, err, repository.ErrRequiredFieldsMissing)
+	require.ErrorAs(t, err, &transitionErr)
+	assert.Equal(t, []string{"assignee"}, transitionErr.Missing)
+
+	assignee := "dev"
+	task.Assignee = &assignee
+	require.NoError(t, repos.CheckMoveAllowed(ctx, task, "development"))
+	require.NoError(t, repos.CheckMoveAllowed(ctx, task, "plan"), "Expected wildcard transition")
+
+	duplicate := []models.WorkflowTransition{{From: "plan", To: "analysis"}, {From: "plan", To: "analysis"}}
+	assert.ErrorIs(t, repos.Workflows.Replace(ctx, board.ID, duplicate), repository.ErrConflict)
+	stored, err = repos.Workflows.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored, 3, "Expected failed replace to keep the previous workflow")
+
+	require.NoError(t, repos.Workflows.Replace(ctx, board.ID, nil))
+	stored, err = repos.Workflows.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+	assert.ErrorIs(t, repos.Workflows.Replace(ctx, uuid.New(), nil), repository.ErrNotFound)
+}
+
+func testSprints(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Sprints "+uuid.NewString()[:8])
+	other := CreateBoard(t, repos, "Other "+uuid.NewString()[:8])
+
+	now := time.Now().UTC().Truncate(time.Second)
+	first := &models.Sprint{BoardID: board.ID, Name: "Sprint 1", State: models.SprintStatePlanned}
+	require.NoError(t, repos.Sprints.Create(ctx, first))
+	nextStart := now.Add(14 * 24 * time.Hour)
+	second := &models.Sprint{BoardID: board.ID, Name: "Sprint 2", State: models.SprintStatePlanned, StartsAt: &nextStart}
+	require.NoError(t, repos.Sprints.Create(ctx, second))
+	assert.ErrorIs(t, repos.Sprints.Create(ctx, &models.Sprint{BoardID: uuid.New(), Name: "Orphan", State: models.SprintStatePlanned}), repository.ErrNotFound)
+
+	sprints, err := repos.Sprints.ListByBoard(ctx, board.ID)
+	require.NoError(t, err)
+	require.Len(t, sprints, 2)
+	assert.Equal(t, second.ID, sprints[0].ID, "Expected sprints without start date to be listed last")
+
+	started, err := repos.StartSprint(ctx, first.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, models.SprintStateActive, started.State)
+	require.NotNil(t, started.EndsAt)
+	assert.WithinDuration(t, now.Add(repository.DefaultSprintDuration), *started.EndsAt, time.Second)
+	_, err = repos.StartSprint(ctx, first.ID, now)
+	assert.ErrorIs(t, err, repository.ErrSprintState)
+	_, err = repos.StartSprint(ctx, second.ID, now)
+	assert.ErrorIs(t, err, repository.ErrConflict, "Expected only one active sprint per board")
+
+	done := &models.Task{BoardID: board.ID, Title: "Done", Status: "closed"}
+	open := &models.Task{BoardID: board.ID, Title: "Open", Status: "development"}
+	foreign := &models.Task{BoardID: other.ID, Title: "Foreign", Status: "plan"}
+	for _, task := range []*models.Task{done, open, foreign} {
+		require.NoError(t, repos.Tasks.Create(ctx, task))
+	}
+
+	_, err = repos.AssignSprint(ctx, []uuid.UUID{open.ID, foreign.ID}, &first.ID)
+	assert.ErrorIs(t, err, repository.ErrInvalidSprint)
+	got, err := repos.Tasks.GetByID(ctx, open.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.SprintID, "Expected failed assignment to be rolled back")
+
+	assigned, err := repos.AssignSprint(ctx, []uuid.UUID{done.ID, open.ID}, &first.ID)
+	require.NoError(t, err)
+	require.Len(t, assigned, 2)
+	require.NotNil(t, assigned[0].SprintID)
+	assert.Equal(t, first.ID, *assigned[0].SprintID)
+
+	tasks, err := repos.Tasks.ListBySprint(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 2)
+
+	_, err = repos.CompleteSprint(ctx, first.ID, uuid.NewString(), now)
+	assert.ErrorIs(t, err, repository.ErrInvalidSprint)
+
+	completion, err := repos.CompleteSprint(ctx, first.ID, "", now)
+	require.NoError(t, err)
+	assert.Equal(t, models.SprintStateCompleted, completion.Sprint.State)
+	require.NotNil(t, completion.NextSprintID)
+	assert.Equal(t, second.ID, *completion.NextSprintID)
+	assert.Equal(t, []uuid.UUID{open.ID}, completion.CarriedOver)
+
+	got, err = repos.Tasks.GetByID(ctx, open.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.SprintID)
+	assert.Equal(t, second.ID, *got.SprintID)
+	got, err = repos.Tasks.GetByID(ctx, done.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.SprintID)
+	assert.Equal(t, first.ID, *got.SprintID, "Expected finished task to stay in the completed sprint")
+
+	_, err = repos.CompleteSprint(ctx, first.ID, "", now)
+	assert.ErrorIs(t, err, repository.ErrSprintState)
+	assert.ErrorIs(t, repos.ValidateSprint(ctx, board.ID, first.ID), repository.ErrInvalidSprint)
+
+	// Перенос на другую доску и удаление спринта возвращают задачи в бэклог
+	_, _, err = repos.TransferTask(ctx, done.ID, other.ID, "plan", false)
+	require.NoError(t, err)
+	got, err = repos.Tasks.GetByID(ctx, done.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.SprintID)
+
+	require.NoError(t, repos.Sprints.Delete(ctx, second.ID))
+	got, err = repos.Tasks.GetByID(ctx, open.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got.SprintID)
+	_, err = repos.Sprints.GetByID(ctx, second.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testStatusHistory(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "History "+uuid.NewString()[:8])
+	other := CreateBoard(t, repos, "Other "+uuid.NewString()[:8])
+
+	task := &models.Task{BoardID: board.ID, Title: "Flow", Status: "plan"}
+	require.NoError(t, repos.Tasks.Create(ctx, task))
+	require.NoError(t, repos.Tasks.Move(ctx, task.ID, "analysis"))
+	require.NoError(t, repos.Tasks.Move(ctx, task.ID, "analysis"), "Expected move to the same status to succeed")
+	task.Status = "development"
+	require.NoError(t, repos.Tasks.Update(ctx, task))
+	task.Title = "Renamed"
+	require.NoError(t, repos.Tasks.Update(ctx, task))
+	_, err := repos.Tasks.ReassignStatus(ctx, board.ID, "development", "testing")
+	require.NoError(t, err)
+	assert.ErrorIs(t, repos.Tasks.Move(ctx, uuid.New(), "plan"), repository.ErrNotFound)
+
+	changes, err := repos.Tasks.ListStatusChanges(ctx, board.ID)
+	require.NoError(t, err)
+	var statuses []string
+	for _, change := range changes {
+		if change.TaskID == task.ID {
+			statuses = append(statuses, change.ToStatus)
+		}
+	}
+	assert.Equal(t, []string{"plan", "analysis", "development", "testing"}, statuses)
+	last := changes[len(changes)-1]
+	require.NotNil(t, last.FromStatus)
+	assert.Equal(t, "development", *last.FromStatus)
+
+	// После переноса история задачи пишется на новую доску, старая ее не показывает
+	require.NoError(t, repos.Tasks.MoveToBoard(ctx, task.ID, other.ID, "plan"))
+	changes, err = repos.Tasks.ListStatusChanges(ctx, other.ID)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Nil(t, changes[0].FromStatus)
+	assert.Equal(t, "plan", changes[0].ToStatus)
+
+	changes, err = repos.Tasks.ListStatusChanges(ctx, board.ID)
+	require.NoError(t, err)
+	for _, change := range changes {
+		assert.NotEqual(t, task.ID, change.TaskID)
+	}
+}
+
+func testBurndown(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	board := CreateBoard(t, repos, "Burndown "+uuid.NewString()[:8])
+	other := CreateBoard(t, repos, "Other "+uuid.NewString()[:8])
+
+	now := time.Now().UTC().Truncate(time.Second)
+	startsAt, endsAt := now.Add(-72*time.Hour), now.Add(11*24*time.Hour)
+	sprint := &models.Sprint{BoardID: board.ID, Name: "Sprint", State: models.SprintStateActive, StartsAt: &startsAt, EndsAt: &endsAt}
+	require.NoError(t, repos.Sprints.Create(ctx, sprint))
+
+	points := func(value int) *int { return &value }
+	login := &models.Task{BoardID: board.ID, Title: "Login", Status: "plan", StoryPoints: points(3), SprintID: &sprint.ID}
+	signup := &models.Task{BoardID: board.ID, Title: "Signup", Status: "plan", StoryPoints: points(5)}
+	dropped := &models.Task{BoardID: board.ID, Title: "Dropped", Status: "plan", SprintID: &sprint.ID}
+	moved := &models.Task{BoardID: board.ID, Title: "Moved", Status: "plan", StoryPoints: points(2), SprintID: &sprint.ID}
+	for _, task := range []*models.Task{login, signup, dropped, moved} {
+		require.NoError(t, repos.Tasks.Create(ctx, task))
+	}
+
+	stored, err := repos.Tasks.GetByID(ctx, login.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.StoryPoints)
+	assert.Equal(t, 3, *stored.StoryPoints)
+
+	require.NoError(t, repos.Tasks.SetSprint(ctx, signup.ID, &sprint.ID))
+	require.NoError(t, repos.Tasks.SetSprint(ctx, signup.ID, &sprint.ID), "Expected repeated assignment not to change the scope")
+	signup.SprintID = &sprint.ID
+	signup.StoryPoints = points(8)
+	require.NoError(t, repos.Tasks.Update(ctx, signup))
+	signup.Title = "Sign up"
+	require.NoError(t, repos.Tasks.Update(ctx, signup))
+	require.NoError(t, repos.Tasks.SetSprint(ctx, dropped.ID, nil))
+	require.NoError(t, repos.Tasks.MoveToBoard(ctx, moved.ID, other.ID, "plan"))
+
+	changes, err := repos.Sprints.ListScopeChanges(ctx, sprint.ID)
+	require.NoError(t, err)
+	type scopeChange struct {
+		title, change string
+		points        int
+	}
+	var got []scopeChange
+	for _, change := range changes {
+		value := 0
+		if change.Points != nil {
+			value = *change.Points
+		}
+		got = append(got, scopeChange{change.TaskTitle, change.Change, value})
+	}
+	assert.ElementsMatch(t, []scopeChange{
+		{"Login", models.SprintScopeAdded, 3},
+		{"Sign up", models.SprintScopeAdded, 5},
+		{"Sign up", models.SprintScopePoints, 8},
+		{"Dropped", models.SprintScopeAdded, 0},
+		{"Dropped", models.SprintScopeRemoved, 0},
+		{"Moved", models.SprintScopeAdded, 2},
+		{"Moved", models.SprintScopeRemoved, 2},
+	}, got)
+
+	inProgress, err := repos.Sprints.ListInProgress(ctx, now)
+	require.NoError(t, err)
+	require.Len(t, inProgress, 1)
+	assert.Equal(t, sprint.ID, inProgress[0].ID)
+
+	// Задачи появились сегодня, поэтому прошедшие дни пусты
+	n, err := repos.SnapshotSprint(ctx, sprint.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	n, err = repos.SnapshotSprint(ctx, sprint.ID, now)
+	require.NoError(t, err)
+	assert.Zero(t, n, "Expected stored days not to be snapshotted again")
+
+	snapshots, err := repos.Sprints.ListSnapshots(ctx, sprint.ID)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3)
+	assert.Equal(t, startsAt.Truncate(24*time.Hour), snapshots[0].Day.UTC())
+	assert.Zero(t, snapshots[2].ScopeTasks)
+
+	snapshots[0].ScopePoints = 42
+	require.NoError(t, repos.Sprints.SaveSnapshots(ctx, snapshots[:1]))
+	snapshots, err = repos.Sprints.ListSnapshots(ctx, sprint.ID)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 3)
+	assert.Equal(t, 42, snapshots[0].ScopePoints)
+	assert.ErrorIs(t, repos.Sprints.SaveSnapshots(ctx, []models.SprintSnapshot{{SprintID: uuid.New(), Day: now}}), repository.ErrNotFound)
+
+	require.NoError(t, repos.Tasks.Move(ctx, login.ID, "closed"))
+	burndown, err := repos.SprintBurndown(ctx, sprint.ID, time.Now())
+	require.NoError(t, err)
+	require.Len(t, burndown.Days, 4)
+	assert.True(t, burndown.Days[0].Snapshot)
+	assert.Equal(t, 42, burndown.Days[0].ScopePoints)
+	today := burndown.Days[3]
+	assert.False(t, today.Snapshot)
+	assert.Equal(t, 11, today.ScopePoints)
+	assert.Equal(t, 3, today.CompletedPoints)
+	assert.Equal(t, 8, today.RemainingPoints)
+	assert.Equal(t, 1, today.RemainingTasks)
+	assert.Len(t, burndown.ScopeChanges, 7)
+	assert.Zero(t, burndown.InitialPoints)
+
+	require.NoError(t, repos.Sprints.Delete(ctx, sprint.ID))
+	snapshots, err = repos.Sprints.ListSnapshots(ctx, sprint.ID)
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+	changes, err = repos.Sprints.ListScopeChanges(ctx, sprint.ID)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func testImportJobs(t *testing.T, repos repository.Repositories) {
+	ctx := context.Background()
+	suffix := uuid.NewString()[:8]
+
+	user := &models.User{Username: "importer-" + suffix, Email: "importer-" + suffix + "@test.com"}
+	require.NoError(t, repos.Users.Create(ctx, user, "secret123"))
+
+	job := &models.ImportJob{UserID: &user.ID, Format: "trello", BoardName: "Imported", Status: models.ImportJobPending, TotalTasks: 10}
+	require.NoError(t, repos.ImportJobs.Create(ctx, job))
+	assert.NotEqual(t, uuid.Nil, job.ID)
+
+	board := CreateBoard(t, repos, "Imported")
+	finishedAt := time.Now().UTC().Truncate(time.Second)
+	job.Status = models.ImportJobCompleted
+	job.BoardID = &board.ID
+	job.ImportedTasks = 10
+	job.FinishedAt = &finishedAt
+	require.NoError(t, repos.ImportJobs.Update(ctx, job))
+
+	stored, err := repos.ImportJobs.GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportJobCompleted, stored.Status)
+	assert.Equal(t, board.ID, *stored.BoardID)
+	assert.Equal(t, user.ID, *stored.UserID)
+	assert.Equal(t, 10, stored.ImportedTasks)
+	assert.Equal(t, "Imported", stored.BoardName)
+	require.NotNil(t, stored.FinishedAt)
+	assert.True(t, finishedAt.Equal(stored.FinishedAt.UTC()))
+	assert.Nil(t, stored.Error)
+
+	_, err = repos.ImportJobs.GetByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	assert.ErrorIs(t, repos.ImportJobs.Update(ctx, &models.ImportJob{ID: uuid.New(), Status: models.ImportJobFailed}), repository.ErrNotFound)
+}
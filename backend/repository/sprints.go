@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSprintState   = errors.New("operation is not allowed in the current sprint state")
+	ErrInvalidSprint = errors.New("sprint must be a planned or active sprint of the task board")
+)
+
+// DefaultSprintDuration - длительность спринта, у которого при старте не задан конец
+const DefaultSprintDuration = 14 * 24 * time.Hour
+
+// Цели переноса незавершенных задач при завершении спринта
+const (
+	SprintMoveNext    = "next"
+	SprintMoveBacklog = "backlog"
+)
+
+// ValidateSprint проверяет, что в спринт sprintID можно добавлять задачи
+// доски boardID: спринт принадлежит доске и еще не завершен.
+func (r Repositories) ValidateSprint(ctx context.Context, boardID, sprintID uuid.UUID) error {
+	sprint, err := r.Sprints.GetByID(ctx, sprintID)
+	if errors.Is(err, ErrNotFound) {
+		return ErrInvalidSprint
+	}
+	if err != nil {
+		return err
+	}
+	if sprint.BoardID != boardID || sprint.State == models.SprintStateCompleted {
+		return ErrInvalidSprint
+	}
+	return nil
+}
+
+// AssignSprint атомарно переносит задачи в спринт sprintID или, если он
+// равен nil, в бэклог. Все задачи должны быть на доске спринта.
+func (r Repositories) AssignSprint(ctx context.Context, ids []uuid.UUID, sprintID *uuid.UUID) ([]models.Task, error) {
+	var assigned []models.Task
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		assigned = assigned[:0]
+		for _, id := range ids {
+			task, err := r.Tasks.GetByID(ctx, id)
+			if err != nil {
+				return fmt.Errorf("task %s: %w", id, err)
+			}
+			if sprintID != nil {
+				if err := r.ValidateSprint(ctx, task.BoardID, *sprintID); err != nil {
+					return fmt.Errorf("task %s: %w", id, err)
+				}
+			}
+			if err := r.Tasks.SetSprint(ctx, id, sprintID); err != nil {
+				return fmt.Errorf("task %s: %w", id, err)
+			}
+			task, err = r.Tasks.GetByID(ctx, id)
+			if err != nil {
+				return fmt.Errorf("task %s: %w", id, err)
+			}
+			assigned = append(assigned, *task)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return assigned, nil
+}
+
+// StartSprint делает запланированный спринт активным. Пустое начало
+// заменяется на now, пустой конец - на начало плюс DefaultSprintDuration.
+// ErrConflict, если на доске уже идет другой спринт.
+func (r Repositories) StartSprint(ctx context.Context, id uuid.UUID, now time.Time) (*models.Sprint, error) {
+	var sprint *models.Sprint
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		sprint, err = r.Sprints.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if sprint.State != models.SprintStatePlanned {
+			return fmt.Errorf("%w: sprint is %s", ErrSprintState, sprint.State)
+		}
+
+		if sprint.StartsAt == nil {
+			sprint.StartsAt = &now
+		}
+		if sprint.EndsAt == nil {
+			endsAt := sprint.StartsAt.Add(DefaultSprintDuration)
+			sprint.EndsAt = &endsAt
+		}
+		sprint.State = models.SprintStateActive
+		return r.Sprints.Update(ctx, sprint)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+// CompleteSprint завершает активный спринт и переносит его незавершенные
+// задачи (не в последней колонке и не в архиве). moveTo - SprintMoveNext
+// или пустая строка (ближайший запланированный спринт доски, иначе бэклог),
+// SprintMoveBacklog или ID запланированного спринта той же доски.
+func (r Repositories) CompleteSprint(ctx context.Context, id uuid.UUID, moveTo string, now time.Time) (*models.SprintCompletion, error) {
+	var completion *models.SprintCompletion
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		sprint, err := r.Sprints.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if sprint.State != models.SprintStateActive {
+			return fmt.Errorf("%w: sprint is %s", ErrSprintState, sprint.State)
+		}
+
+		target, err := r.carryOverTarget(ctx, sprint, moveTo)
+		if err != nil {
+			return err
+		}
+
+		tasks, err := r.Tasks.ListBySprint(ctx, id)
+		if err != nil {
+			return err
+		}
+		completion = &models.SprintCompletion{NextSprintID: target, CarriedOver: []uuid.UUID{}}
+		done := doneStatuses{}
+		for _, task := range tasks {
+			finished, err := done.finished(ctx, r, &task)
+			if err != nil {
+				return err
+			}
+			if finished {
+				continue
+			}
+			if err := r.Tasks.SetSprint(ctx, task.ID, target); err != nil {
+				return fmt.Errorf("task %s: %w", task.ID, err)
+			}
+			completion.CarriedOver = append(completion.CarriedOver, task.ID)
+		}
+
+		sprint.State = models.SprintStateCompleted
+		sprint.CompletedAt = &now
+		if err := r.Sprints.Update(ctx, sprint); err != nil {
+			return err
+		}
+		completion.Sprint = *sprint
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return completion, nil
+}
+
+// carryOverTarget выбирает спринт для незавершенных задач sprint (nil - бэклог)
+func (r Repositories) carryOverTarget(ctx context.Context, sprint *models.Sprint, moveTo string) (*uuid.UUID, error) {
+	switch moveTo {
+	case SprintMoveBacklog:
+		return nil, nil
+	case "", SprintMoveNext:
+		sprints, err := r.Sprints.ListByBoard(ctx, sprint.BoardID)
+		if err != nil {
+			return nil, err
+		}
+		for _, next := range sprints {
+			if next.State == models.SprintStatePlanned {
+				return &next.ID, nil
+			}
+		}
+		return nil, nil
+	}
+
+	targetID, err := uuid.Parse(moveTo)
+	if err != nil {
+		return nil, fmt.Errorf("%w: move_to must be %q, %q or a sprint id", ErrInvalidSprint, SprintMoveNext, SprintMoveBacklog)
+	}
+	target, err := r.Sprints.GetByID(ctx, targetID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrInvalidSprint
+	}
+	if err != nil {
+		return nil, err
+	}
+	if target.BoardID != sprint.BoardID || target.State != models.SprintStatePlanned {
+		return nil, fmt.Errorf("%w: move_to must be a planned sprint of the same board", ErrInvalidSprint)
+	}
+	return &target.ID, nil
+}
@@ -1,90 +0,0 @@
-package repository
-
-import (
-	"database/sql"
-	"task-flow-backend/database"
-	"task-flow-backend/models"
-	"time"
-
-	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
-)
-
-func GetUserByUsername(username string) (*models.User, error) {
-	var user models.User
-	err := database.DB.QueryRow(`
-		SELECT id, username, email, password_hash, created_at, updated_at 
-		FROM users 
-		WHERE username = $1
-	`, username).Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
-
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	return &user, nil
-}
-
-func GetUserByEmail(email string) (*models.User, error) {
-	var user models.User
-	err := database.DB.QueryRow(`
-		SELECT id, username, email, password_hash, created_at, updated_at 
-		FROM users 
-		WHERE email = $1
-	`, email).Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
-
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	return &user, nil
-}
-
-func GetUserByID(id uuid.UUID) (*models.User, error) {
-	var user models.User
-	err := database.DB.QueryRow(`
-		SELECT id, username, email, password_hash, created_at, updated_at 
-		FROM users 
-		WHERE id = $1
-	`, id).Scan(&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt)
-
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	return &user, nil
-}
-
-func CreateUser(user *models.User, password string) error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return err
-	}
-
-	user.PasswordHash = string(hashedPassword)
-	user.CreatedAt = time.Now()
-	user.UpdatedAt = time.Now()
-
-	err = database.DB.QueryRow(`
-		INSERT INTO users (username, email, password_hash, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5) 
-		RETURNING id
-	`, user.Username, user.Email, user.PasswordHash, user.CreatedAt, user.UpdatedAt).Scan(&user.ID)
-
-	return err
-}
-
-func VerifyPassword(hashedPassword, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err == nil
-}
-
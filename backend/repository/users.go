@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrLastAdmin = errors.New("the last admin cannot be demoted, disabled or deleted")
+
+// HashUserToken - SHA-256 токена из письма; токены случайные, поэтому соль не нужна
+func HashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IssueUserToken создает одноразовый токен с целью purpose, действующий ttl,
+// и возвращает сам токен для ссылки в письме. Прежние токены с той же целью
+// перестают действовать.
+func (r Repositories) IssueUserToken(ctx context.Context, userID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	plain := base64.RawURLEncoding.EncodeToString(buf)
+
+	token := &models.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: HashUserToken(plain),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := r.UserTokens.Create(ctx, token); err != nil {
+		return "", err
+	}
+	return plain, nil
+}
+
+// VerifyEmail гасит токен подтверждения и отмечает email пользователя
+// подтвержденным; ErrNotFound, если токен недействителен
+func (r Repositories) VerifyEmail(ctx context.Context, plain string) (*models.User, error) {
+	var user *models.User
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		token, err := r.UserTokens.Consume(ctx, models.UserTokenVerifyEmail, HashUserToken(plain), time.Now())
+		if err != nil {
+			return err
+		}
+		if err := r.Users.MarkEmailVerified(ctx, token.UserID); err != nil {
+			return err
+		}
+		user, err = r.Users.GetByID(ctx, token.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ResetPassword гасит токен сброса, задает новый пароль и отзывает
+// выданные JWT. Письмо со ссылкой доказывает владение адресом, поэтому
+// email заодно считается подтвержденным.
+func (r Repositories) ResetPassword(ctx context.Context, plain, password string) (*models.User, error) {
+	var user *models.User
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		token, err := r.UserTokens.Consume(ctx, models.UserTokenResetPassword, HashUserToken(plain), time.Now())
+		if err != nil {
+			return err
+		}
+		if err := r.Users.SetPassword(ctx, token.UserID, password); err != nil {
+			return err
+		}
+		if err := r.Users.RevokeTokens(ctx, token.UserID); err != nil {
+			return err
+		}
+		if err := r.Users.MarkEmailVerified(ctx, token.UserID); err != nil {
+			return err
+		}
+		user, err = r.Users.GetByID(ctx, token.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// RequireOtherAdmin возвращает ErrLastAdmin, если пользователь id -
+// администратор и кроме него не остается ни одного администратора с
+// незаблокированной учетной записью. Вызывается в той же транзакции, что и
+// понижение, блокировка или удаление: строки администраторов остаются
+// заблокированными до ее конца, и встречные изменения не обойдут проверку.
+func (r Repositories) RequireOtherAdmin(ctx context.Context, id uuid.UUID) error {
+	admins, err := r.Users.LockAdmins(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(admins, id) || len(admins) > 1 {
+		return nil
+	}
+	return ErrLastAdmin
+}
+
+// ExportUserData собирает данные пользователя: профиль, созданные им доски,
+// членства, созданные и назначенные на него задачи, комментарии и записи времени
+func (r Repositories) ExportUserData(ctx context.Context, id uuid.UUID) (*models.UserDataExport, error) {
+	user, err := r.Users.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	user.PasswordHash = ""
+
+	export := &models.UserDataExport{
+		ExportedAt:  time.Now().UTC(),
+		User:        *user,
+		Boards:      []models.Board{},
+		Memberships: []models.BoardMember{},
+		Tasks:       []models.Task{},
+		Comments:    []models.TaskComment{},
+		Worklogs:    []models.Worklog{},
+	}
+
+	boards, err := r.Boards.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, board := range boards {
+		if board.UserID != nil && *board.UserID == id {
+			export.Boards = append(export.Boards, board)
+		}
+	}
+
+	members, err := r.Members.ListByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := r.Tasks.ListByUser(ctx, id, user.Username)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := r.Comments.ListByUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	worklogs, err := r.Worklogs.List(ctx, WorklogFilter{UserID: &id})
+	if err != nil {
+		return nil, err
+	}
+	export.Memberships = append(export.Memberships, members...)
+	export.Tasks = append(export.Tasks, tasks...)
+	export.Comments = append(export.Comments, comments...)
+	export.Worklogs = append(export.Worklogs, worklogs...)
+
+	return export, nil
+}
+
+// DeleteUser удаляет пользователя (см. UserRepository.Delete). Если heirID
+// задан, созданные пользователем доски сначала передаются heirID, иначе
+// они остаются без владельца.
+func (r Repositories) DeleteUser(ctx context.Context, id uuid.UUID, heirID *uuid.UUID) error {
+	return r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.Users.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if heirID != nil {
+			boards, err := r.Boards.List(ctx)
+			if err != nil {
+				return err
+			}
+			for _, board := range boards {
+				if board.UserID == nil || *board.UserID != id {
+					continue
+				}
+				if err := r.TransferBoardOwnership(ctx, board.ID, *heirID); err != nil {
+					return err
+				}
+			}
+		}
+		return r.Users.Delete(ctx, id)
+	})
+}
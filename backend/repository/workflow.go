@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"task-flow-backend/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTransitionNotAllowed  = errors.New("transition is not allowed by the board workflow")
+	ErrRequiredFieldsMissing = errors.New("required fields are missing")
+)
+
+// AnyStatus в поле From перехода разрешает переход из любого статуса
+const AnyStatus = "*"
+
+// WorkflowFields - поля задачи, заполнения которых может требовать переход
+var WorkflowFields = []string{"assignee", "priority", "description", "estimate_minutes", "due_at"}
+
+// TransitionError объясняет, почему задачу нельзя перевести в статус To.
+// Err - ErrUnknownStatus, ErrTransitionNotAllowed или ErrRequiredFieldsMissing.
+type TransitionError struct {
+	Err  error
+	From string
+	To   string
+	// Allowed - статусы, в которые можно перейти из From; для
+	// ErrUnknownStatus - статусы всех колонок доски
+	Allowed []string
+	// Missing - незаполненные поля, которых требует переход
+	Missing []string
+}
+
+func (e *TransitionError) Error() string {
+	switch {
+	case errors.Is(e.Err, ErrUnknownStatus):
+		return fmt.Sprintf("status %q does not match any column of the board; available statuses: %s", e.To, listOrNone(e.Allowed))
+	case errors.Is(e.Err, ErrRequiredFieldsMissing):
+		return fmt.Sprintf("moving from %q to %q requires fields: %s", e.From, e.To, strings.Join(e.Missing, ", "))
+	}
+	return fmt.Sprintf("transition from %q to %q is not allowed by the board workflow; allowed targets: %s", e.From, e.To, listOrNone(e.Allowed))
+}
+
+func (e *TransitionError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateStatus возвращает TransitionError с ErrUnknownStatus, если status
+// не совпадает ни с одной неархивной колонкой доски
+func (r Repositories) ValidateStatus(ctx context.Context, boardID uuid.UUID, status string) error {
+	columns, err := r.Columns.ListByBoard(ctx, boardID, ListOptions{})
+	if err != nil {
+		return err
+	}
+	if hasStatus(columns, status) {
+		return nil
+	}
+
+	statuses := make([]string, 0, len(columns))
+	for _, column := range columns {
+		statuses = append(statuses, column.StatusID)
+	}
+	return &TransitionError{Err: ErrUnknownStatus, To: status, Allowed: statuses}
+}
+
+// CheckTransition проверяет, что status - колонка доски задачи и переход в
+// нее разрешен переходами доски. Доска без переходов разрешает любые
+// перемещения. Поля, которых требует переход, проверяются у task, поэтому
+// вызывающий код передает задачу с уже примененными изменениями, кроме статуса.
+func (r Repositories) CheckTransition(ctx context.Context, task *models.Task, status string) error {
+	if err := r.ValidateStatus(ctx, task.BoardID, status); err != nil {
+		return err
+	}
+	if status == task.Status {
+		return nil
+	}
+
+	transitions, err := r.Workflows.ListByBoard(ctx, task.BoardID)
+	if err != nil {
+		return err
+	}
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	var allowed, missing []string
+	matched := false
+	for _, transition := range transitions {
+		if transition.From != task.Status && transition.From != AnyStatus {
+			continue
+		}
+		if !slices.Contains(allowed, transition.To) {
+			allowed = append(allowed, transition.To)
+		}
+		if transition.To != status {
+			continue
+		}
+		matched = true
+		for _, field := range transition.RequiredFields {
+			if fieldEmpty(task, field) && !slices.Contains(missing, field) {
+				missing = append(missing, field)
+			}
+		}
+	}
+
+	if !matched {
+		return &TransitionError{Err: ErrTransitionNotAllowed, From: task.Status, To: status, Allowed: allowed}
+	}
+	if len(missing) > 0 {
+		return &TransitionError{Err: ErrRequiredFieldsMissing, From: task.Status, To: status, Allowed: allowed, Missing: missing}
+	}
+	return nil
+}
+
+// fieldEmpty сообщает, что поле field из WorkflowFields у задачи не заполнено
+func fieldEmpty(task *models.Task, field string) bool {
+	var value string
+	switch field {
+	case "assignee":
+		value = deref(task.Assignee)
+	case "priority":
+		value = deref(task.Priority)
+	case "description":
+		value = task.Description
+	case "estimate_minutes":
+		if task.EstimateMinutes != nil {
+			value = strconv.Itoa(*task.EstimateMinutes)
+		}
+	case "due_at":
+		if task.DueAt != nil {
+			value = task.DueAt.String()
+		}
+	}
+	return strings.TrimSpace(value) == ""
+}
+
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
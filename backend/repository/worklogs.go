@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"task-flow-backend/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorklogMinutes - длительность интервала в минутах; неполная минута округляется вверх
+func WorklogMinutes(start, end time.Time) int {
+	d := end.Sub(start)
+	if d <= 0 {
+		return 0
+	}
+	return int((d + time.Minute - 1) / time.Minute)
+}
+
+// StartTimer запускает таймер пользователя по задаче taskID. Если у
+// пользователя уже есть запущенный таймер, возвращает ErrConflict.
+func (r Repositories) StartTimer(ctx context.Context, taskID, userID uuid.UUID) (*models.Worklog, error) {
+	worklog := &models.Worklog{TaskID: taskID, UserID: userID, StartedAt: time.Now()}
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.Tasks.GetByID(ctx, taskID); err != nil {
+			return err
+		}
+		return r.Worklogs.Create(ctx, worklog)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return worklog, nil
+}
+
+// StopTimer останавливает запущенный таймер пользователя и превращает его
+// в запись времени; ErrNotFound, если таймер не запущен
+func (r Repositories) StopTimer(ctx context.Context, userID uuid.UUID, note *string) (*models.Worklog, error) {
+	var worklog *models.Worklog
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		running, err := r.Worklogs.GetRunning(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		running.EndedAt = &now
+		running.DurationMinutes = WorklogMinutes(running.StartedAt, now)
+		if note != nil {
+			running.Note = note
+		}
+		if err := r.Worklogs.Finish(ctx, running); err != nil {
+			return err
+		}
+		worklog = running
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return worklog, nil
+}
+
+// TimeReport суммирует завершенные записи времени по задачам, пользователям
+// и парам задача-пользователь. Группы отсортированы по убыванию времени.
+func (r Repositories) TimeReport(ctx context.Context, filter WorklogFilter) (*models.TimeReport, error) {
+	worklogs, err := r.Worklogs.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.TimeReport{
+		From:    filter.From,
+		To:      filter.To,
+		Tasks:   []models.TimeReportTask{},
+		Users:   []models.TimeReportUser{},
+		Entries: []models.TimeReportEntry{},
+	}
+
+	type entryKey struct{ taskID, userID uuid.UUID }
+	tasks := map[uuid.UUID]int{}
+	users := map[uuid.UUID]int{}
+	entries := map[entryKey]int{}
+
+	for _, worklog := range worklogs {
+		report.TotalMinutes += worklog.DurationMinutes
+
+		if _, ok := tasks[worklog.TaskID]; !ok {
+			task, err := r.Tasks.GetByID(ctx, worklog.TaskID)
+			if err != nil {
+				return nil, err
+			}
+			tasks[worklog.TaskID] = len(report.Tasks)
+			report.Tasks = append(report.Tasks, models.TimeReportTask{
+				TaskID: task.ID, BoardID: task.BoardID, Title: task.Title, EstimateMinutes: task.EstimateMinutes,
+			})
+		}
+		task := &report.Tasks[tasks[worklog.TaskID]]
+		task.Minutes += worklog.DurationMinutes
+
+		if _, ok := users[worklog.UserID]; !ok {
+			user, err := r.Users.GetByID(ctx, worklog.UserID)
+			if err != nil {
+				return nil, err
+			}
+			users[worklog.UserID] = len(report.Users)
+			report.Users = append(report.Users, models.TimeReportUser{UserID: user.ID, Username: user.Username})
+		}
+		user := &report.Users[users[worklog.UserID]]
+		user.Minutes += worklog.DurationMinutes
+
+		key := entryKey{worklog.TaskID, worklog.UserID}
+		if _, ok := entries[key]; !ok {
+			entries[key] = len(report.Entries)
+			report.Entries = append(report.Entries, models.TimeReportEntry{
+				TaskID: task.TaskID, BoardID: task.BoardID, TaskTitle: task.Title, UserID: user.UserID, Username: user.Username,
+			})
+		}
+		report.Entries[entries[key]].Minutes += worklog.DurationMinutes
+	}
+
+	sort.SliceStable(report.Tasks, func(i, j int) bool { return report.Tasks[i].Minutes > report.Tasks[j].Minutes })
+	sort.SliceStable(report.Users, func(i, j int) bool { return report.Users[i].Minutes > report.Users[j].Minutes })
+	sort.SliceStable(report.Entries, func(i, j int) bool { return report.Entries[i].Minutes > report.Entries[j].Minutes })
+
+	return report, nil
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Config - параметры S3-совместимого хранилища
+type S3Config struct {
+	// Endpoint - адрес сервиса, например https://s3.eu-central-1.amazonaws.com или http://localhost:9000
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle адресует бакет путем (endpoint/bucket/key), как требуют MinIO
+	// и большинство S3-совместимых сервисов; иначе - поддоменом bucket.endpoint
+	PathStyle bool
+	// HTTPClient по умолчанию http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// S3 хранит объекты в бакете S3-совместимого хранилища. Запросы подписываются
+// AWS Signature Version 4 без зависимости от AWS SDK.
+type S3 struct {
+	endpoint  *url.URL
+	bucket    string
+	pathStyle bool
+	creds     credentials
+	client    *http.Client
+	now       func() time.Time
+}
+
+func NewS3(cfg S3Config) (*S3, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("S3 endpoint and bucket are required")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("S3 access key and secret key are required")
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	client := cfg.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &S3{
+		endpoint:  endpoint,
+		bucket:    cfg.Bucket,
+		pathStyle: cfg.PathStyle,
+		creds:     credentials{accessKey: cfg.AccessKey, secretKey: cfg.SecretKey, region: cfg.Region},
+		client:    client,
+		now:       time.Now,
+	}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error {
+	// Подписываем содержимое целиком, поэтому тело читается дважды
+	hash := sha256.New()
+	size, err := io.Copy(hash, body)
+	if err != nil {
+		return err
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, io.NopCloser(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req, hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(req, resp)
+	}
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, s3Error(req, resp)
+	}
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return s3Error(req, resp)
+	}
+}
+
+// objectURL возвращает адрес объекта в path-style или virtual-hosted-style
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	base := strings.TrimSuffix(u.Path, "/")
+	if s.pathStyle {
+		u.Path = base + "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = base + "/" + key
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	return &u
+}
+
+func (s *S3) newRequest(ctx context.Context, method, key string, body io.ReadCloser) (*http.Request, error) {
+	if key == "" {
+		return nil, errors.New("empty storage key")
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL = s.objectURL(key)
+	req.Host = req.URL.Host
+	req.Body = body
+	return req, nil
+}
+
+func (s *S3) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.creds.sign(req, payloadHash, s.now())
+	return s.client.Do(req)
+}
+
+// s3Error формирует ошибку из XML-ответа S3 вида <Error><Code>...</Code><Message>...</Message></Error>
+func s3Error(req *http.Request, resp *http.Response) error {
+	var body struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err := xml.Unmarshal(data, &body); err != nil || body.Code == "" {
+		return fmt.Errorf("s3 %s %s: unexpected status %s", req.Method, req.URL.Path, resp.Status)
+	}
+	return fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, body.Code, body.Message)
+}
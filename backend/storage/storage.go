@@ -0,0 +1,74 @@
+// Package storage хранит содержимое вложений задач: Local - каталог на
+// диске, S3 - любое S3-совместимое хранилище (AWS S3, MinIO).
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// Storage - хранилище объектов по ключу вида "blobs/ab/abcdef..."
+type Storage interface {
+	// Put сохраняет объект, перезаписывая существующий с тем же ключом
+	Put(ctx context.Context, key string, body io.ReadSeeker, contentType string) error
+	// Get возвращает содержимое объекта или ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete удаляет объект; отсутствие объекта не считается ошибкой
+	Delete(ctx context.Context, key string) error
+}
+
+// FromEnv создает хранилище по STORAGE_BACKEND: local (по умолчанию) или s3
+func FromEnv() (Storage, error) {
+	switch backend := os.Getenv("STORAGE_BACKEND"); backend {
+	case "", "local":
+		dir := os.Getenv("STORAGE_LOCAL_DIR")
+		if dir == "" {
+			dir = "./data/attachments"
+		}
+		local, err := NewLocal(dir)
+		if err != nil {
+			return nil, err
+		}
+		return local, nil
+	case "s3":
+		pathStyle, _ := strconv.ParseBool(os.Getenv("S3_PATH_STYLE"))
+		s3, err := NewS3(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PathStyle: pathStyle,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return s3, nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", backend)
+	}
+}
+
+// BlobKey - ключ содержимого файла с контрольной суммой SHA-256 checksum
+func BlobKey(checksum string) string {
+	return "blobs/" + checksum[:2] + "/" + checksum
+}
+
+// ThumbnailKey - ключ PNG-миниатюры файла
+func ThumbnailKey(checksum string) string {
+	return "thumbnails/" + checksum[:2] + "/" + checksum + ".png"
+}
+
+// RemoveBlob удаляет файл и его миниатюру
+func RemoveBlob(ctx context.Context, s Storage, checksum string) error {
+	if err := s.Delete(ctx, ThumbnailKey(checksum)); err != nil {
+		return err
+	}
+	return s.Delete(ctx, BlobKey(checksum))
+}
@@ -0,0 +1,212 @@
+package templates
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"task-flow-backend/models"
+)
+
+const (
+	// DefaultID - шаблон, по которому создается доска без template_id
+	DefaultID     = "default"
+	DefaultLocale = "ru"
+)
+
+var supportedLocales = map[string]bool{"ru": true, "en": true}
+
+// text - строка в нескольких локализациях
+type text map[string]string
+
+func (t text) in(locale string) string {
+	if s, ok := t[locale]; ok {
+		return s
+	}
+	return t[DefaultLocale]
+}
+
+type column struct {
+	title    text
+	statusID string
+	wipLimit int
+}
+
+type label struct {
+	name  text
+	color string
+}
+
+type task struct {
+	title    text
+	status   string
+	priority string
+}
+
+type builtin struct {
+	id          string
+	name        text
+	description text
+	columns     []column
+	labels      []label
+	tasks       []task
+}
+
+var builtins = []builtin{
+	{
+		id:          DefaultID,
+		name:        text{"ru": "Стандартный процесс", "en": "Standard workflow"},
+		description: text{"ru": "Пять колонок от планирования до закрытия", "en": "Five columns from planning to done"},
+		columns: []column{
+			{title: text{"ru": "План", "en": "Plan"}, statusID: "plan"},
+			{title: text{"ru": "Анализ", "en": "Analysis"}, statusID: "analysis"},
+			{title: text{"ru": "Разработка", "en": "Development"}, statusID: "development"},
+			{title: text{"ru": "Тестирование", "en": "Testing"}, statusID: "testing"},
+			{title: text{"ru": "Закрыто", "en": "Closed"}, statusID: "closed"},
+		},
+	},
+	{
+		id:          "kanban",
+		name:        text{"ru": "Простой Kanban", "en": "Simple Kanban"},
+		description: text{"ru": "Три колонки с ограничением задач в работе", "en": "Three columns with a work-in-progress limit"},
+		columns: []column{
+			{title: text{"ru": "К выполнению", "en": "To do"}, statusID: "todo"},
+			{title: text{"ru": "В работе", "en": "In progress"}, statusID: "in_progress", wipLimit: 3},
+			{title: text{"ru": "Готово", "en": "Done"}, statusID: "done"},
+		},
+	},
+	{
+		id:          "scrum",
+		name:        text{"ru": "Scrum", "en": "Scrum"},
+		description: text{"ru": "Бэклог продукта, спринт, ревью", "en": "Product backlog, sprint and review"},
+		columns: []column{
+			{title: text{"ru": "Бэклог", "en": "Backlog"}, statusID: "backlog"},
+			{title: text{"ru": "Спринт", "en": "Sprint backlog"}, statusID: "sprint"},
+			{title: text{"ru": "В работе", "en": "In progress"}, statusID: "in_progress", wipLimit: 5},
+			{title: text{"ru": "Ревью", "en": "Review"}, statusID: "review", wipLimit: 3},
+			{title: text{"ru": "Готово", "en": "Done"}, statusID: "done"},
+		},
+		labels: []label{
+			{name: text{"ru": "История", "en": "Story"}, color: "#2563eb"},
+			{name: text{"ru": "Ошибка", "en": "Bug"}, color: "#dc2626"},
+			{name: text{"ru": "Техдолг", "en": "Tech debt"}, color: "#6b7280"},
+		},
+		tasks: []task{
+			{title: text{"ru": "Сформулировать цель спринта", "en": "Define the sprint goal"}, status: "sprint", priority: "high"},
+			{title: text{"ru": "Провести ретроспективу", "en": "Hold the retrospective"}, status: "backlog"},
+		},
+	},
+	{
+		id:          "bug-triage",
+		name:        text{"ru": "Разбор ошибок", "en": "Bug triage"},
+		description: text{"ru": "Прием, подтверждение и исправление ошибок", "en": "Intake, confirmation and fixing of bugs"},
+		columns: []column{
+			{title: text{"ru": "Новые", "en": "New"}, statusID: "new"},
+			{title: text{"ru": "Разбор", "en": "Triage"}, statusID: "triage"},
+			{title: text{"ru": "Подтверждено", "en": "Confirmed"}, statusID: "confirmed"},
+			{title: text{"ru": "Исправляется", "en": "Fixing"}, statusID: "fixing", wipLimit: 5},
+			{title: text{"ru": "Проверка", "en": "Verification"}, statusID: "verification"},
+			{title: text{"ru": "Закрыто", "en": "Closed"}, statusID: "closed"},
+		},
+		labels: []label{
+			{name: text{"ru": "Критично", "en": "Critical"}, color: "#dc2626"},
+			{name: text{"ru": "Серьезно", "en": "Major"}, color: "#f97316"},
+			{name: text{"ru": "Незначительно", "en": "Minor"}, color: "#eab308"},
+			{name: text{"ru": "Не воспроизводится", "en": "Cannot reproduce"}, color: "#6b7280"},
+		},
+	},
+}
+
+// Builtins возвращает встроенные шаблоны с названиями на языке locale
+func Builtins(locale string) []models.BoardTemplate {
+	result := make([]models.BoardTemplate, 0, len(builtins))
+	for _, b := range builtins {
+		result = append(result, b.localize(locale))
+	}
+	return result
+}
+
+// Builtin возвращает встроенный шаблон по ключу
+func Builtin(id, locale string) (models.BoardTemplate, bool) {
+	for _, b := range builtins {
+		if b.id == id {
+			return b.localize(locale), true
+		}
+	}
+	return models.BoardTemplate{}, false
+}
+
+// Default возвращает шаблон доски по умолчанию на языке по умолчанию
+func Default() models.BoardTemplate {
+	tpl, _ := Builtin(DefaultID, DefaultLocale)
+	return tpl
+}
+
+func (b builtin) localize(locale string) models.BoardTemplate {
+	description := b.description.in(locale)
+	tpl := models.BoardTemplate{
+		ID:          b.id,
+		Name:        b.name.in(locale),
+		Description: &description,
+		BuiltIn:     true,
+	}
+
+	for _, c := range b.columns {
+		column := models.TemplateColumn{Title: c.title.in(locale), StatusID: c.statusID}
+		if c.wipLimit > 0 {
+			limit := c.wipLimit
+			column.WIPLimit = &limit
+		}
+		tpl.Columns = append(tpl.Columns, column)
+	}
+	for _, l := range b.labels {
+		tpl.Labels = append(tpl.Labels, models.TemplateLabel{Name: l.name.in(locale), Color: l.color})
+	}
+	for _, t := range b.tasks {
+		task := models.TemplateTask{Title: t.title.in(locale), Status: t.status}
+		if t.priority != "" {
+			priority := t.priority
+			task.Priority = &priority
+		}
+		tpl.Tasks = append(tpl.Tasks, task)
+	}
+
+	return tpl
+}
+
+// Locale выбирает поддерживаемый язык из заголовка Accept-Language
+// с учетом весов q; если подходящего нет, возвращает DefaultLocale.
+func Locale(acceptLanguage string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !supportedLocales[primary] {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale: primary, q: q})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	if len(candidates) > 0 {
+		return candidates[0].locale
+	}
+	return DefaultLocale
+}
@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinsAreConsistent(t *testing.T) {
+	for _, locale := range []string{"ru", "en"} {
+		for _, tpl := range Builtins(locale) {
+			statuses := make(map[string]bool)
+			for _, column := range tpl.Columns {
+				assert.NotEmpty(t, column.Title, "%s/%s: empty column title", locale, tpl.ID)
+				assert.False(t, statuses[column.StatusID], "%s/%s: duplicate status %s", locale, tpl.ID, column.StatusID)
+				statuses[column.StatusID] = true
+			}
+			for _, task := range tpl.Tasks {
+				assert.True(t, statuses[task.Status], "%s/%s: task %q has unknown status", locale, tpl.ID, task.Title)
+			}
+			for _, label := range tpl.Labels {
+				assert.NotEmpty(t, label.Name, "%s/%s: empty label name", locale, tpl.ID)
+			}
+		}
+	}
+}
+
+func TestBuiltinLocalization(t *testing.T) {
+	ru, ok := Builtin("kanban", "ru")
+	assert.True(t, ok)
+	en, _ := Builtin("kanban", "en")
+	fallback, _ := Builtin("kanban", "de")
+
+	assert.Equal(t, "Готово", ru.Columns[2].Title)
+	assert.Equal(t, "Done", en.Columns[2].Title)
+	assert.Equal(t, ru.Columns, fallback.Columns)
+
+	_, ok = Builtin("missing", "ru")
+	assert.False(t, ok)
+}
+
+func TestLocale(t *testing.T) {
+	tests := map[string]string{
+		"":                          "ru",
+		"en-US,en;q=0.9":            "en",
+		"de-DE,en;q=0.8,ru;q=0.9":   "ru",
+		"fr":                        "ru",
+		"ru;q=0,en":                 "en",
+		"EN":                        "en",
+		"en;q=0.5, ru-RU;q=invalid": "en",
+	}
+	for header, want := range tests {
+		assert.Equal(t, want, Locale(header), "Accept-Language: %q", header)
+	}
+}
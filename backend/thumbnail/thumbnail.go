@@ -0,0 +1,72 @@
+// Package thumbnail уменьшает изображения вложений для предпросмотра
+package thumbnail
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+
+	_ "image/gif"
+	_ "image/jpeg"
+
+	"golang.org/x/image/draw"
+)
+
+// Size - наибольшая сторона миниатюры в пикселях
+const Size = 256
+
+// maxPixels защищает от изображений, которые при декодировании займут слишком много памяти
+const maxPixels = 40_000_000
+
+var ErrTooLarge = errors.New("image is too large for a thumbnail")
+
+// Supported сообщает, умеет ли Generate обрабатывать изображения типа mediaType
+func Supported(mediaType string) bool {
+	switch mediaType {
+	case "image/png", "image/jpeg", "image/gif":
+		return true
+	}
+	return false
+}
+
+// Generate возвращает PNG-миниатюру, вписанную в квадрат Size x Size с
+// сохранением пропорций. Изображения меньше Size не увеличиваются.
+func Generate(r io.ReadSeeker) ([]byte, error) {
+	config, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, ErrTooLarge
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	src, _, err := image.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := fit(src.Bounds().Dx(), src.Bounds().Dy())
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, dst); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fit(width, height int) (int, int) {
+	if width <= Size && height <= Size {
+		return width, height
+	}
+	if width >= height {
+		return Size, max(1, height*Size/width)
+	}
+	return max(1, width*Size/height), Size
+}
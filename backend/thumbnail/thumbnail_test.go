@@ -0,0 +1,60 @@
+package thumbnail
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name                  string
+		width, height         int
+		wantWidth, wantHeight int
+	}{
+		{"Landscape", 1000, 500, 256, 128},
+		{"Portrait", 300, 1200, 64, 256},
+		{"Small image is not upscaled", 40, 30, 40, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, tt.width, tt.height))
+			for x := 0; x < tt.width; x++ {
+				img.Set(x, 0, color.RGBA{R: 255, A: 255})
+			}
+			var buf bytes.Buffer
+			require.NoError(t, jpeg.Encode(&buf, img, nil))
+
+			data, err := Generate(bytes.NewReader(buf.Bytes()))
+			require.NoError(t, err)
+
+			thumb, err := png.Decode(bytes.NewReader(data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWidth, thumb.Bounds().Dx())
+			assert.Equal(t, tt.wantHeight, thumb.Bounds().Dy())
+		})
+	}
+}
+
+func TestGenerateRejectsInvalidImages(t *testing.T) {
+	_, err := Generate(bytes.NewReader([]byte("not an image")))
+	assert.Error(t, err)
+
+	// Заголовок PNG 100000x100000 без данных: отклоняется до декодирования
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))))
+	header := buf.Bytes()
+	copy(header[16:24], []byte{0, 1, 0x86, 0xa0, 0, 1, 0x86, 0xa0})
+	binary.BigEndian.PutUint32(header[29:33], crc32.ChecksumIEEE(header[12:29]))
+	_, err = Generate(bytes.NewReader(header))
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
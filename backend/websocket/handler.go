@@ -1,8 +1,8 @@
 package websocket
 
 import (
-	"log"
 	"net/http"
+	"task-flow-backend/logging"
 
 	"github.com/gorilla/mux"
 )
@@ -18,7 +18,7 @@ func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
 
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("WebSocket upgrade error: %v", err)
+		logging.FromContext(r.Context()).Warn("WebSocket upgrade failed", "board_id", boardID, "error", err)
 		return
 	}
 
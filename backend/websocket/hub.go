@@ -2,8 +2,9 @@ package websocket
 
 import (
 	"encoding/json"
-	"log"
+	"log/slog"
 	"sync"
+	"task-flow-backend/metrics"
 )
 
 type Hub struct {
@@ -38,8 +39,9 @@ func (h *Hub) Run() {
 				h.clients[client.boardID] = make(map[*Client]bool)
 			}
 			h.clients[client.boardID][client] = true
+			metrics.WebSocketClients.WithLabelValues(client.boardID).Set(float64(len(h.clients[client.boardID])))
 			h.mu.Unlock()
-			log.Printf("Client registered for board %s. Total clients: %d", client.boardID, len(h.clients[client.boardID]))
+			slog.Debug("WebSocket client registered", "board_id", client.boardID)
 
 		case client := <-h.unregister:
 			h.mu.Lock()
@@ -47,36 +49,37 @@ func (h *Hub) Run() {
 				if _, ok := clients[client]; ok {
 					delete(clients, client)
 					close(client.send)
-					if len(clients) == 0 {
-						delete(h.clients, client.boardID)
-					}
+					h.updateClientGauge(client.boardID)
 				}
 			}
 			h.mu.Unlock()
-			log.Printf("Client unregistered for board %s. Total clients: %d", client.boardID, len(h.clients[client.boardID]))
+			slog.Debug("WebSocket client unregistered", "board_id", client.boardID)
 
 		case message := <-h.broadcast:
-			h.mu.RLock()
+			metrics.WebSocketBroadcastQueueDepth.Set(float64(len(h.broadcast)))
+			h.mu.Lock()
 			clients, ok := h.clients[message.BoardID]
 			if !ok {
-				h.mu.RUnlock()
+				h.mu.Unlock()
 				continue
 			}
 			data, err := json.Marshal(message)
 			if err != nil {
-				log.Printf("Error marshaling message: %v", err)
-				h.mu.RUnlock()
+				slog.Error("Failed to marshal WebSocket message", "board_id", message.BoardID, "type", message.Type, "error", err)
+				h.mu.Unlock()
 				continue
 			}
 			for client := range clients {
 				select {
 				case client.send <- data:
 				default:
+					metrics.WebSocketMessagesDropped.WithLabelValues("client_buffer_full").Inc()
 					close(client.send)
 					delete(clients, client)
 				}
 			}
-			h.mu.RUnlock()
+			h.updateClientGauge(message.BoardID)
+			h.mu.Unlock()
 		}
 	}
 }
@@ -89,7 +92,20 @@ func (h *Hub) Broadcast(boardID string, messageType string, data interface{}) {
 	}
 	select {
 	case h.broadcast <- message:
+		metrics.WebSocketBroadcastQueueDepth.Set(float64(len(h.broadcast)))
 	default:
-		log.Printf("Broadcast channel full, dropping message")
+		metrics.WebSocketMessagesDropped.WithLabelValues("broadcast_queue_full").Inc()
+		slog.Warn("Broadcast channel full, dropping message", "board_id", boardID, "type", messageType)
+	}
+}
+
+// updateClientGauge вызывается под h.mu
+func (h *Hub) updateClientGauge(boardID string) {
+	clients := h.clients[boardID]
+	if len(clients) == 0 {
+		delete(h.clients, boardID)
+		metrics.WebSocketClients.DeleteLabelValues(boardID)
+		return
 	}
+	metrics.WebSocketClients.WithLabelValues(boardID).Set(float64(len(clients)))
 }